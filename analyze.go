@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+
+	"github.com/spf13/cobra"
+)
+
+var analyzeJSON bool
+
+var analyzeCmd = &cobra.Command{
+	Use:   "analyze [input.pdf]",
+	Short: "Show what the size of a PDF is spent on",
+	Long: `Break the size of a PDF down into images, fonts, content streams,
+attachments, metadata and file structure, and list every image with its
+pixel size, the resolution it is shown at, its compression and its size,
+and every font with the size of its embedded font program.
+
+This shows why a file is big before compressing it: high-resolution images
+call for compress or optimize with a lower DPI, large fonts for subsetting,
+and attachments for removing them.
+
+Use --json for machine-readable output.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		report, err := pdftool.AnalyzeSize(cmd.Context(), args[0])
+		if err != nil {
+			return fmt.Errorf("analysis failed: %w", err)
+		}
+
+		if analyzeJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(report)
+		}
+
+		printSizeReport(os.Stdout, report)
+		return nil
+	},
+}
+
+func init() {
+	analyzeCmd.Flags().BoolVar(&analyzeJSON, "json", false, "Print the analysis as JSON")
+
+	rootCmd.AddCommand(analyzeCmd)
+}
+
+// printSizeReport writes a size breakdown in human-readable form
+func printSizeReport(w io.Writer, report *pdftool.SizeReport) {
+	fmt.Fprintf(w, "File: %s (%s)\n\n", filepath.Base(report.File), pdftool.FormatSize(report.FileSize))
+
+	for _, c := range report.Categories {
+		share := 0.0
+		if report.FileSize > 0 {
+			share = float64(c.Bytes) / float64(report.FileSize) * 100
+		}
+		fmt.Fprintf(w, "  %-12s %12s %5.1f%%", c.Name, pdftool.FormatSize(c.Bytes), share)
+		if c.Name != pdftool.SizeStructure {
+			fmt.Fprintf(w, "  %d objects", c.Objects)
+		}
+		fmt.Fprintln(w)
+	}
+
+	if len(report.Images) > 0 {
+		fmt.Fprintf(w, "\nImages (largest first):\n")
+		fmt.Fprintf(w, "  %6s  %-11s  %5s  %-10s  %-15s  %12s  %s\n", "Object", "Pixels", "DPI", "Color", "Filter", "Size", "Pages")
+		for _, img := range report.Images {
+			dpi := "-"
+			if img.DPI > 0 {
+				dpi = fmt.Sprintf("%.0f", img.DPI)
+			}
+			fmt.Fprintf(w, "  %6d  %-11s  %5s  %-10s  %-15s  %12s  %s\n", img.Object, fmt.Sprintf("%dx%d", img.Width, img.Height),
+				dpi, img.ColorSpace, img.Filter, pdftool.FormatSize(img.Bytes), formatPageList(img.Pages))
+		}
+	}
+
+	if len(report.Fonts) > 0 {
+		fmt.Fprintf(w, "\nFonts (largest first):\n")
+		for _, font := range report.Fonts {
+			embedded := "not embedded"
+			if font.Embedded {
+				embedded = pdftool.FormatSize(font.Bytes)
+			}
+			fmt.Fprintf(w, "  %-40s %-12s %s\n", font.Name, font.Type, embedded)
+		}
+	}
+}
+
+// formatPageList formats page numbers like "1-3,7", or "-" if there are none
+func formatPageList(pages []int) string {
+	if len(pages) == 0 {
+		return "-"
+	}
+	var parts []string
+	for i := 0; i < len(pages); {
+		j := i
+		for j+1 < len(pages) && pages[j+1] == pages[j]+1 {
+			j++
+		}
+		if j > i {
+			parts = append(parts, fmt.Sprintf("%d-%d", pages[i], pages[j]))
+		} else {
+			parts = append(parts, fmt.Sprint(pages[i]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+
+	"github.com/spf13/cobra"
+)
+
+var attachOpts pdftool.AttachOptions
+
+var attachCmd = &cobra.Command{
+	Use:   "attach [input.pdf] [output.pdf] [file...]",
+	Short: "Embed files into a PDF",
+	Long: `Embed files such as the XML of a ZUGFeRD/Factur-X invoice or a CSV export
+into a PDF. Attachments of the same name are replaced.
+
+The MIME type is detected from the file extension unless --mime is given.
+--mime and --description take one value for all files or can be repeated
+once per file. Use --relationship (Source, Data, Alternative, Supplement or
+Unspecified) to also list the files as associated files of the document, as
+PDF/A-3 based invoice formats require.`,
+	Args: cobra.MinimumNArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputFile := args[0]
+		outputFile := args[1]
+		files := args[2:]
+
+		fmt.Printf("🔄 Attaching %d files: %s -> %s\n", len(files), inputFile, outputFile)
+
+		if err := pdftool.AttachFiles(cmd.Context(), inputFile, outputFile, files, attachOpts); err != nil {
+			return fmt.Errorf("attach failed: %w", err)
+		}
+
+		fmt.Println("✅ Attach completed successfully!")
+		return nil
+	},
+}
+
+func init() {
+	attachCmd.Flags().StringArrayVar(&attachOpts.MimeTypes, "mime", nil, "MIME type, e.g. text/xml (default: from the file extension)")
+	attachCmd.Flags().StringArrayVar(&attachOpts.Descriptions, "description", nil, "Description of the attachment")
+	attachCmd.Flags().StringArrayVar(&attachOpts.Descriptions, "desc", nil, "Description of the attachment")
+	attachCmd.Flags().MarkDeprecated("desc", "use --description instead")
+	attachCmd.Flags().StringVar(&attachOpts.Relationship, "relationship", "", "Associated file relationship, e.g. Alternative or Data")
+
+	rootCmd.AddCommand(attachCmd)
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+)
+
+// Batch settings of compress for directory inputs
+var (
+	compressWorkers int
+	compressRetries int
+)
+
+// compressDir compresses every PDF in inputDir into outputDir with
+// compressOpts, printing a line per file and the totals. Either may be a
+// remote prefix like s3://bucket/scans/.
+func compressDir(ctx context.Context, inputDir, outputDir string) error {
+	var jobs []pdftool.Job
+	var run func(context.Context, pdftool.Job, func(context.Context, string, string) (*pdftool.Result, error)) (*pdftool.Result, error)
+	var err error
+	if isRemote(inputDir) || isRemote(outputDir) {
+		jobs, err = remoteDirJobs(ctx, inputDir, outputDir)
+		run = func(ctx context.Context, job pdftool.Job, compress func(context.Context, string, string) (*pdftool.Result, error)) (*pdftool.Result, error) {
+			return compressURI(ctx, job.InputFile, job.OutputFile, compress)
+		}
+	} else {
+		jobs, err = pdftool.DirJobs(inputDir, outputDir)
+	}
+	if err != nil {
+		return fmt.Errorf("compression failed: %w", err)
+	}
+
+	fmt.Printf("🔄 Compressing %d PDFs: %s -> %s (Quality: %d%%)\n", len(jobs), redactURI(inputDir), redactURI(outputDir), compressOpts.Quality)
+
+	batch, err := pdftool.Batch(ctx, jobs, pdftool.BatchOptions{
+		Compress: compressOpts,
+		Workers:  compressWorkers,
+		Retries:  compressRetries,
+		OnResult: printJobResult,
+		Run:      run,
+	})
+	if batch != nil && batch.InputSize > 0 {
+		fmt.Printf("\n📊 Compressed %d of %d PDFs in %s: %.2f MB -> %.2f MB (%.1f%% saved)\n",
+			batch.Succeeded, len(jobs), batch.Duration.Round(time.Millisecond),
+			float64(batch.InputSize)/(1024*1024), float64(batch.OutputSize)/(1024*1024),
+			float64(batch.InputSize-batch.OutputSize)/float64(batch.InputSize)*100)
+	}
+	if err != nil {
+		return fmt.Errorf("compression failed: %w", err)
+	}
+
+	fmt.Println("✅ PDF compression completed successfully!")
+	return nil
+}
+
+// printJobResult prints the outcome of one file of a batch
+func printJobResult(job pdftool.JobResult) {
+	name := remoteBase(job.InputFile)
+	retried := ""
+	if job.Attempts > 1 {
+		retried = fmt.Sprintf(" after %d attempts", job.Attempts)
+	}
+
+	if job.Err != nil {
+		fmt.Printf("❌ %s: %v%s\n", name, job.Err, retried)
+		return
+	}
+	fmt.Printf("   %s: %.2f KB -> %.2f KB (%.1f%% saved)%s\n", name,
+		float64(job.Result.InputSize)/1024, float64(job.Result.OutputSize)/1024, (1-job.Result.Ratio)*100, retried)
+	if !rootQuiet {
+		for _, warning := range job.Result.Warnings {
+			fmt.Printf("   ⚠️  %s: %s\n", name, warning)
+		}
+	}
+}
+
+// remoteDirJobs returns a Job for every PDF directly inside inputDir, where
+// either folder may be a remote prefix
+func remoteDirJobs(ctx context.Context, inputDir, outputDir string) ([]pdftool.Job, error) {
+	var inputs []string
+	if isRemote(inputDir) {
+		listed, err := listRemote(ctx, inputDir)
+		if err != nil {
+			return nil, err
+		}
+		for _, object := range listed {
+			if hasExt(object, []string{".pdf"}) {
+				inputs = append(inputs, object)
+			}
+		}
+	} else {
+		entries, err := os.ReadDir(inputDir)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if entry.Type().IsRegular() && hasExt(entry.Name(), []string{".pdf"}) {
+				inputs = append(inputs, filepath.Join(inputDir, entry.Name()))
+			}
+		}
+	}
+	if len(inputs) == 0 {
+		return nil, fmt.Errorf("no PDF files found in %s", redactURI(inputDir))
+	}
+
+	if !isRemote(outputDir) {
+		if err := os.MkdirAll(outputDir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create output directory: %w", err)
+		}
+	}
+	jobs := make([]pdftool.Job, len(inputs))
+	for i, input := range inputs {
+		jobs[i] = pdftool.Job{InputFile: input, OutputFile: joinOutput(outputDir, remoteBase(input))}
+	}
+	return jobs, nil
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+
+	"github.com/spf13/cobra"
+)
+
+var bookletOpts pdftool.BookletOptions
+
+var bookletCmd = &cobra.Command{
+	Use:   "booklet [input.pdf] [output.pdf]",
+	Short: "Impose pages for saddle-stitch booklet printing",
+	Long: `Reorder pages and place them two per side on landscape sheets, so that the
+printed sheets, stacked and folded in the middle, form a booklet. Blank pages
+are added at the end when the page count is not a multiple of four.
+
+Print the result double-sided, flipping on the short edge.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputFile := args[0]
+		outputFile := args[1]
+
+		fmt.Printf("🔄 Creating booklet on %s sheets: %s -> %s\n", bookletOpts.Sheet, inputFile, outputFile)
+
+		if err := pdftool.MakeBooklet(cmd.Context(), inputFile, outputFile, bookletOpts); err != nil {
+			return fmt.Errorf("booklet failed: %w", err)
+		}
+
+		fmt.Println("✅ Booklet created successfully!")
+		return nil
+	},
+}
+
+func init() {
+	bookletCmd.Flags().StringVar(&bookletOpts.Sheet, "sheet", "A4", "Sheet paper size, e.g. A4, A3, Letter")
+	bookletCmd.Flags().Float64Var(&bookletOpts.Margin, "margin", 0, "Space around each page in points")
+
+	rootCmd.AddCommand(bookletCmd)
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+
+	"github.com/spf13/cobra"
+)
+
+var bookmarksCmd = &cobra.Command{
+	Use:   "bookmarks",
+	Short: "Export and import PDF bookmarks",
+	Long: `Export the bookmarks (outline) of a PDF to JSON and import them again, for
+example to restore bookmarks after compression or to script a table of
+contents.`,
+}
+
+var bookmarksExportCmd = &cobra.Command{
+	Use:   "export [input.pdf] [toc.json]",
+	Short: "Write the bookmarks of a PDF to a JSON file",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputFile := args[0]
+		jsonFile := args[1]
+
+		fmt.Printf("🔄 Exporting bookmarks: %s -> %s\n", inputFile, jsonFile)
+
+		if err := pdftool.ExportBookmarks(cmd.Context(), inputFile, jsonFile); err != nil {
+			return fmt.Errorf("bookmark export failed: %w", err)
+		}
+
+		fmt.Println("✅ Bookmark export completed successfully!")
+		return nil
+	},
+}
+
+var bookmarksImportCmd = &cobra.Command{
+	Use:   "import [input.pdf] [toc.json] [output.pdf]",
+	Short: "Replace the bookmarks of a PDF with those of a JSON file",
+	Long: `Replace the bookmarks of a PDF with those of a JSON file as written by
+bookmarks export:
+
+  {"bookmarks": [
+    {"title": "Introduction", "page": 1},
+    {"title": "Results", "page": 4, "kids": [{"title": "Costs", "page": 5}]}
+  ]}`,
+	Args: cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputFile := args[0]
+		jsonFile := args[1]
+		outputFile := args[2]
+
+		fmt.Printf("🔄 Importing bookmarks: %s + %s -> %s\n", inputFile, jsonFile, outputFile)
+
+		if err := pdftool.ImportBookmarks(cmd.Context(), inputFile, jsonFile, outputFile); err != nil {
+			return fmt.Errorf("bookmark import failed: %w", err)
+		}
+
+		fmt.Println("✅ Bookmark import completed successfully!")
+		return nil
+	},
+}
+
+func init() {
+	bookmarksCmd.AddCommand(bookmarksExportCmd)
+	bookmarksCmd.AddCommand(bookmarksImportCmd)
+	rootCmd.AddCommand(bookmarksCmd)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+
+	"github.com/spf13/cobra"
+)
+
+var blankOpts pdftool.BlankOptions
+
+var cleanBlankCmd = &cobra.Command{
+	Use:   "clean-blank [input.pdf] [output.pdf]",
+	Short: "Remove blank pages",
+	Long: `Remove (nearly) blank pages, such as the empty backs of duplex scans. Pages
+are rendered at a low resolution and count as blank when at least --threshold
+of their pixels are white; edges, where scans often show dark borders, are
+ignored. Lower the threshold to also remove pages with specks of dust or
+bleed-through.
+
+Rendering uses Ghostscript. Without it, only scanned pages consisting of a
+single image can be checked.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputFile := args[0]
+		outputFile := args[1]
+
+		fmt.Printf("🔄 Removing blank pages: %s -> %s\n", inputFile, outputFile)
+
+		if err := pdftool.RemoveBlankPages(cmd.Context(), inputFile, outputFile, blankOpts); err != nil {
+			return fmt.Errorf("blank page removal failed: %w", err)
+		}
+
+		fmt.Println("✅ Blank page removal completed successfully!")
+		return nil
+	},
+}
+
+func init() {
+	cleanBlankCmd.Flags().Float64Var(&blankOpts.Threshold, "threshold", 0.995, "Fraction of white pixels from which a page is blank (0-1)")
+	cleanBlankCmd.Flags().IntVar(&blankOpts.DPI, "dpi", 30, "Rendering resolution for the check")
+
+	rootCmd.AddCommand(cleanBlankCmd)
+}
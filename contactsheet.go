@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+
+	"github.com/spf13/cobra"
+)
+
+var contactSheetOpts pdftool.ContactSheetOptions
+
+var contactSheetGrid string
+
+var contactSheetCmd = &cobra.Command{
+	Use:   "contact-sheet [input.png/jpg/dir/zip/pdf]... [output.pdf]",
+	Short: "Create a thumbnail grid of images or PDF pages",
+	Long: `Create a contact sheet: a grid of captioned thumbnails for quickly reviewing
+large batches of scans or photos.
+
+Inputs are images, directories or ZIP archives of images (ordered by --sort)
+or a single PDF.
+PDF pages are rendered with Ghostscript; without it, pages are laid out in a
+grid without captions.`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		outputFile := args[len(args)-1]
+
+		cols, rows, err := pdftool.ParseGrid(contactSheetGrid)
+		if err != nil {
+			return err
+		}
+		contactSheetOpts.Columns, contactSheetOpts.Rows = cols, rows
+
+		fmt.Printf("🔄 Creating contact sheet: %s\n", outputFile)
+
+		if err := pdftool.CreateContactSheet(cmd.Context(), args[:len(args)-1], outputFile, contactSheetOpts); err != nil {
+			return fmt.Errorf("contact sheet failed: %w", err)
+		}
+
+		fmt.Println("✅ Contact sheet completed successfully!")
+		return nil
+	},
+}
+
+func init() {
+	contactSheetCmd.Flags().StringVar(&contactSheetGrid, "grid", "4x5", "Thumbnails per page as COLSxROWS")
+	contactSheetCmd.Flags().StringVar(&contactSheetOpts.Caption, "caption", "", "Caption template (default \"{filename}\", or \"Page {index}\" for PDFs)")
+	contactSheetCmd.Flags().StringVar(&contactSheetOpts.Sort, "sort", pdftool.SortNatural, "Order of images read from directories and archives: name, natural, mtime, exif-date, none")
+	contactSheetCmd.Flags().IntVar(&contactSheetOpts.ThumbnailSize, "thumb-size", 400, "Longer thumbnail side in pixels")
+	contactSheetCmd.Flags().IntVar(&contactSheetOpts.DPI, "dpi", 50, "Resolution for rendering PDF pages")
+
+	rootCmd.AddCommand(contactSheetCmd)
+}
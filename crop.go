@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+
+	"github.com/spf13/cobra"
+)
+
+var cropOpts pdftool.CropOptions
+
+var cropCmd = &cobra.Command{
+	Use:   "crop [input.pdf] [output.pdf]",
+	Short: "Crop PDF pages to a box or trim their margins",
+	Long: `Crop pages by setting their CropBox, for example to hide the black edges a
+scanner leaves around pages. Content outside the box is hidden, not removed.
+
+--box takes the visible area as x0,y0,x1,y1 measured from the bottom-left
+corner of the page. --trim cuts a margin off the current visible area: one
+length for all sides, two for top/bottom and left/right, or four for top,
+right, bottom and left (before any page rotation). Lengths are in points unless
+they end in mm, cm or in.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputFile := args[0]
+		outputFile := args[1]
+
+		fmt.Printf("🔄 Cropping PDF: %s -> %s\n", inputFile, outputFile)
+
+		if err := pdftool.CropPages(cmd.Context(), inputFile, outputFile, cropOpts); err != nil {
+			return fmt.Errorf("crop failed: %w", err)
+		}
+
+		fmt.Println("✅ Crop completed successfully!")
+		return nil
+	},
+}
+
+func init() {
+	cropCmd.Flags().StringVar(&cropOpts.Box, "box", "", "Visible area as x0,y0,x1,y1, e.g. 10,10,585,832")
+	cropCmd.Flags().StringVar(&cropOpts.Trim, "trim", "", "Margin to cut off, e.g. 5mm or 10mm,5mm")
+	cropCmd.Flags().StringVar(&cropOpts.Pages, "pages", "all", "Pages to crop, e.g. 1-3,7 or all")
+	cropCmd.MarkFlagsMutuallyExclusive("box", "trim")
+
+	rootCmd.AddCommand(cropCmd)
+}
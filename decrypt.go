@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+
+	"github.com/spf13/cobra"
+)
+
+var decryptPassword string
+
+var decryptCmd = &cobra.Command{
+	Use:   "decrypt [input.pdf|input-dir] [output.pdf|output-dir]",
+	Short: "Remove password protection from a PDF",
+	Long: `Remove the encryption from a PDF given its user or owner password.
+
+When the input is a directory, every PDF in it is decrypted into the output
+directory with the same password. PDFs that are not encrypted are skipped.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		input := args[0]
+		output := args[1]
+
+		fmt.Printf("🔄 Decrypting: %s -> %s\n", input, output)
+
+		var err error
+		if info, statErr := os.Stat(input); statErr == nil && info.IsDir() {
+			err = pdftool.DecryptDir(cmd.Context(), input, output, decryptPassword)
+		} else {
+			err = pdftool.DecryptPDF(cmd.Context(), input, output, decryptPassword)
+		}
+		if err != nil {
+			return fmt.Errorf("decryption failed: %w", err)
+		}
+
+		fmt.Println("✅ Decryption completed successfully!")
+		return nil
+	},
+}
+
+func init() {
+	decryptCmd.Flags().StringVar(&decryptPassword, "password", "", "User or owner password")
+
+	rootCmd.AddCommand(decryptCmd)
+}
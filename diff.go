@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+
+	"github.com/spf13/cobra"
+)
+
+var diffOpts pdftool.DiffOptions
+
+var diffCmd = &cobra.Command{
+	Use:   "diff [a.pdf] [b.pdf]",
+	Short: "Compare two PDFs page by page",
+	Long: `Compare two PDFs page by page and list the pages that differ, for example to
+check what compression changed. The command fails if the PDFs differ, so it
+can gate automated pipelines.
+
+Modes:
+  visual: render the pages and compare pixels (default, requires Ghostscript)
+  text:   compare the extracted text
+
+With --out, a report directory receives an image of each changed page with
+the differences in red, or a line diff of its text. --threshold ignores pages
+where at most the given percentage of pixels changed.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fileA := args[0]
+		fileB := args[1]
+
+		if diffOpts.DPI < 1 {
+			return fmt.Errorf("dpi must be positive, got: %d", diffOpts.DPI)
+		}
+
+		fmt.Printf("🔄 Comparing PDFs (%s): %s <-> %s\n", diffOpts.Mode, fileA, fileB)
+
+		result, err := pdftool.DiffPDF(cmd.Context(), fileA, fileB, diffOpts)
+		if err != nil {
+			return fmt.Errorf("comparison failed: %w", err)
+		}
+
+		if result.PageCountA != result.PageCountB {
+			fmt.Printf("Page count differs: %d vs %d\n", result.PageCountA, result.PageCountB)
+		}
+		for _, page := range result.Changed {
+			fmt.Printf("   Page %d: %s\n", page.Page, page.Summary)
+			if page.File != "" {
+				fmt.Printf("      %s\n", page.File)
+			}
+		}
+
+		if len(result.Changed) > 0 {
+			return fmt.Errorf("%d pages differ", len(result.Changed))
+		}
+
+		fmt.Println("✅ No differences found!")
+		return nil
+	},
+}
+
+func init() {
+	diffCmd.Flags().StringVar(&diffOpts.Mode, "mode", pdftool.DiffVisual, "Comparison mode: text or visual")
+	diffCmd.Flags().StringVar(&diffOpts.OutputDir, "out", "", "Report directory for diff images and text diffs")
+	diffCmd.Flags().IntVar(&diffOpts.DPI, "dpi", 72, "Rendering resolution for visual comparison")
+	diffCmd.Flags().Float64Var(&diffOpts.Threshold, "threshold", 0, "Percentage of pixels that may change before a page counts as different")
+
+	rootCmd.AddCommand(diffCmd)
+}
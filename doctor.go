@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+
+	"github.com/spf13/cobra"
+)
+
+// doctorDevices are the Ghostscript devices the commands rely on
+var doctorDevices = []string{"pdfwrite", "png16m", "pnggray", "jpeg", "jpeggray", "tiff24nc"}
+
+// doctorPrograms are the optional programs besides Ghostscript and the
+// compression engines, with the commands that need them
+var doctorPrograms = []struct {
+	names   []string // Alternatives, the first found is used
+	purpose string
+}{
+	{[]string{"tesseract"}, "OCR (convert --ocr or --rotate auto, ocr)"},
+	{[]string{"magick", "convert"}, "decoding unusual JPEGs"},
+}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check which external programs are installed",
+	Long: `Show the installed Ghostscript with its version and the devices and
+filters the commands rely on, which compression engines are available, and
+whether the optional programs for OCR and image decoding are installed.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fmt.Println("🔍 Checking external programs...")
+		if pdftool.PureGo() {
+			fmt.Println("⚠️  Pure-Go mode is on: no external programs are run")
+		}
+
+		info, err := pdftool.DetectGhostscript()
+		if err != nil {
+			fmt.Printf("⚠️  Ghostscript: %v; compression falls back to other engines and rendering is unavailable\n", err)
+		} else {
+			fmt.Printf("✅ Ghostscript %s (%s)\n", info.Version, info.Path)
+			if len(info.Devices) > 0 {
+				var missing []string
+				for _, device := range doctorDevices {
+					if !info.HasDevice(device) {
+						missing = append(missing, device)
+					}
+				}
+				fmt.Printf("   Devices: %d", len(info.Devices))
+				if len(missing) > 0 {
+					fmt.Printf(", missing %s", strings.Join(missing, ", "))
+				}
+				fmt.Println()
+			}
+			if len(info.Filters) > 0 {
+				fmt.Printf("   Filters: %s\n", strings.Join(info.Filters, ", "))
+			}
+			var parts []string
+			for part := 1; part <= 3; part++ {
+				if info.SupportsPDFA(part) {
+					parts = append(parts, fmt.Sprintf("%d", part))
+				}
+			}
+			if len(parts) == 0 {
+				parts = []string{"none"}
+			}
+			fmt.Printf("   PDF/A: %s\n", strings.Join(parts, ", "))
+		}
+
+		var engines []string
+		for _, name := range pdftool.EngineNames() {
+			if engine, ok := pdftool.LookupEngine(name); ok && engine.Available() {
+				engines = append(engines, name)
+			}
+		}
+		fmt.Printf("✅ Compression engines: %s\n", strings.Join(engines, ", "))
+
+		for _, program := range doctorPrograms {
+			found := ""
+			for _, name := range program.names {
+				if pdftool.PureGo() {
+					break
+				}
+				if path, err := exec.LookPath(name); err == nil {
+					found = path
+					break
+				}
+			}
+			if found != "" {
+				fmt.Printf("✅ %s (%s): %s\n", program.names[0], found, program.purpose)
+			} else {
+				fmt.Printf("⚠️  %s not found: %s\n", strings.Join(program.names, " or "), program.purpose)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
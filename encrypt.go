@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+
+	"github.com/spf13/cobra"
+)
+
+var encryptOpts pdftool.EncryptOptions
+
+var encryptCmd = &cobra.Command{
+	Use:   "encrypt [input.pdf] [output.pdf]",
+	Short: "Password-protect a PDF with AES encryption",
+	Long: `Encrypt a PDF with AES-256 (or AES-128 with --key-length 128).
+
+The user password is needed to open the document. Without one, anyone can
+open it but only the owner password allows changes beyond printing. When no
+owner password is given, the user password is used for both.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputFile := args[0]
+		outputFile := args[1]
+
+		fmt.Printf("🔄 Encrypting PDF: %s -> %s\n", inputFile, outputFile)
+
+		if err := pdftool.EncryptPDF(cmd.Context(), inputFile, outputFile, encryptOpts); err != nil {
+			return fmt.Errorf("encryption failed: %w", err)
+		}
+
+		fmt.Println("✅ Encryption completed successfully!")
+		return nil
+	},
+}
+
+func init() {
+	encryptCmd.Flags().StringVar(&encryptOpts.UserPassword, "user-pass", "", "Password required to open the PDF")
+	encryptCmd.Flags().StringVar(&encryptOpts.OwnerPassword, "owner-pass", "", "Password required to change permissions")
+	encryptCmd.Flags().IntVar(&encryptOpts.KeyLength, "key-length", 256, "AES key length in bits: 128 or 256")
+
+	rootCmd.AddCommand(encryptCmd)
+}
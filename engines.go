@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+)
+
+// compressEngineCmds holds --engine-cmd definitions like
+// "fast=my-compressor {in} {out} --q {quality}"
+var compressEngineCmds []string
+
+// engineConfigFile returns the file defining command engines, a JSON object
+// mapping engine names to command templates
+func engineConfigFile() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "pdf-tool", "engines.json"), nil
+}
+
+// registerCommandEngines registers the command engines from the config file,
+// if it exists, and from --engine-cmd flags, which take precedence
+func registerCommandEngines(definitions []string) error {
+	templates := map[string]string{}
+
+	if file, err := engineConfigFile(); err == nil {
+		data, err := os.ReadFile(file)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+		case err != nil:
+			return fmt.Errorf("failed to read engine config: %w", err)
+		default:
+			if err := json.Unmarshal(data, &templates); err != nil {
+				return fmt.Errorf("invalid engine config %s: %w", file, err)
+			}
+		}
+	}
+
+	for _, definition := range definitions {
+		name, template, ok := strings.Cut(definition, "=")
+		if !ok {
+			return fmt.Errorf("invalid engine command: %s (expected name=command)", definition)
+		}
+		templates[strings.TrimSpace(name)] = template
+	}
+
+	for name, template := range templates {
+		engine, err := pdftool.NewCommandEngine(name, template)
+		if err != nil {
+			return err
+		}
+		pdftool.RegisterEngine(engine)
+	}
+	return nil
+}
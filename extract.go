@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	extractImagesOpts    pdftool.ExtractImagesOptions
+	extractImagesMinSize string
+	extractTextOpts      pdftool.ExtractTextOptions
+)
+
+var extractCmd = &cobra.Command{
+	Use:   "extract",
+	Short: "Extract images, text and attachments from a PDF",
+}
+
+var extractImagesCmd = &cobra.Command{
+	Use:   "images [input.pdf] [output-dir]",
+	Short: "Save the images embedded in a PDF",
+	Long: `Save the images embedded in a PDF, such as scans and photos, named after
+the input, page and image number.
+
+By default images keep their embedded encoding (usually JPEG or PNG); use
+--format png or jpg to convert them. --min-size skips small images such as
+logos and icons.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputFile := args[0]
+		outputDir := args[1]
+
+		if extractImagesMinSize != "" {
+			size, err := pdftool.ParseSize(extractImagesMinSize)
+			if err != nil {
+				return err
+			}
+			extractImagesOpts.MinSize = size
+		}
+
+		fmt.Printf("🔄 Extracting images: %s -> %s\n", inputFile, outputDir)
+
+		files, err := pdftool.ExtractImages(cmd.Context(), inputFile, outputDir, extractImagesOpts)
+		if err != nil {
+			return fmt.Errorf("image extraction failed: %w", err)
+		}
+
+		for _, file := range files {
+			fmt.Printf("   %s\n", file)
+		}
+
+		fmt.Printf("✅ Extracted %d images successfully!\n", len(files))
+		return nil
+	},
+}
+
+var extractTextCmd = &cobra.Command{
+	Use:   "text [input.pdf] [output.txt|output-dir]",
+	Short: "Save the text of a PDF as plain text",
+	Long: `Save the text of a PDF as plain UTF-8 text. Pages are separated by form
+feeds; with --per-page the output is a directory that receives one file per
+page.
+
+--layout keeps the horizontal and vertical placement of the text, which helps
+with tables and multi-column pages. Scanned pages have no text; run ocr on
+them first.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputFile := args[0]
+		output := args[1]
+
+		fmt.Printf("🔄 Extracting text: %s -> %s\n", inputFile, output)
+
+		files, err := pdftool.ExtractText(cmd.Context(), inputFile, output, extractTextOpts)
+		if err != nil {
+			return fmt.Errorf("text extraction failed: %w", err)
+		}
+
+		if extractTextOpts.PerPage {
+			for _, file := range files {
+				fmt.Printf("   %s\n", file)
+			}
+		}
+
+		fmt.Println("✅ Text extraction completed successfully!")
+		return nil
+	},
+}
+
+var extractAttachmentsCmd = &cobra.Command{
+	Use:   "attachments [input.pdf] [output-dir]",
+	Short: "Save the files embedded in a PDF",
+	Long: `Save the files embedded in a PDF, such as the XML of a ZUGFeRD/Factur-X
+invoice or the documents of a portfolio. Files attached to pages as
+annotations are included.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputFile := args[0]
+		outputDir := args[1]
+
+		fmt.Printf("🔄 Extracting attachments: %s -> %s\n", inputFile, outputDir)
+
+		files, err := pdftool.ExtractAttachments(cmd.Context(), inputFile, outputDir)
+		if err != nil {
+			return fmt.Errorf("attachment extraction failed: %w", err)
+		}
+
+		for _, file := range files {
+			fmt.Printf("   %s\n", file)
+		}
+
+		fmt.Printf("✅ Extracted %d attachments successfully!\n", len(files))
+		return nil
+	},
+}
+
+func init() {
+	extractImagesCmd.Flags().StringVar(&extractImagesOpts.Format, "format", "original", "Image format: original, png or jpg")
+	extractImagesCmd.Flags().StringVar(&extractImagesMinSize, "min-size", "", "Skip images smaller than this, e.g. 10KB")
+	extractImagesCmd.Flags().StringVar(&extractImagesOpts.Pages, "pages", "", "Pages to extract from, e.g. 1-5,8 (default: all)")
+	extractImagesCmd.Flags().IntVar(&extractImagesOpts.Quality, "quality", 90, "JPEG quality (1-100) when converting to jpg")
+
+	extractTextCmd.Flags().StringVar(&extractTextOpts.Pages, "pages", "", "Pages to extract from, e.g. 1-10 (default: all)")
+	extractTextCmd.Flags().BoolVar(&extractTextOpts.Layout, "layout", false, "Keep the physical layout of the text")
+	extractTextCmd.Flags().BoolVar(&extractTextOpts.PerPage, "per-page", false, "Write one text file per page into the output directory")
+
+	extractCmd.AddCommand(extractImagesCmd)
+	extractCmd.AddCommand(extractTextCmd)
+	extractCmd.AddCommand(extractAttachmentsCmd)
+	rootCmd.AddCommand(extractCmd)
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+
+	"github.com/spf13/cobra"
+)
+
+var flattenCmd = &cobra.Command{
+	Use:   "flatten [input.pdf] [output.pdf]",
+	Short: "Bake form fields and annotations into the page content",
+	Long: `Draw filled-in form fields, comments, stamps and other annotations into the
+page content and remove them, so the PDF looks the same in every viewer and
+can no longer be edited. This is a common step before archiving or
+compressing forms. Links stay clickable.
+
+Flattening a signed form keeps the look of the signature but invalidates it.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputFile := args[0]
+		outputFile := args[1]
+
+		fmt.Printf("🔄 Flattening PDF: %s -> %s\n", inputFile, outputFile)
+
+		if err := pdftool.FlattenPDF(cmd.Context(), inputFile, outputFile); err != nil {
+			return fmt.Errorf("flatten failed: %w", err)
+		}
+
+		fmt.Println("✅ Flatten completed successfully!")
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(flattenCmd)
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+
+	"github.com/spf13/cobra"
+)
+
+var fontsUnembeddedOnly bool
+
+var fontsCmd = &cobra.Command{
+	Use:   "fonts [input.pdf]",
+	Short: "List the fonts of a PDF",
+	Long: `List every font of a PDF with its type, encoding, whether it is embedded
+and subset, the size of its embedded data and the pages using it.
+
+Fonts that are not embedded are drawn with substitutes on other systems and
+fail PDF/A and most prepress checks; use --unembedded-only to list just
+those. Ghostscript-based compression and pdfa embed them where it can.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fonts, err := pdftool.FontReports(cmd.Context(), args[0])
+		if err != nil {
+			return fmt.Errorf("reading fonts failed: %w", err)
+		}
+
+		if fontsUnembeddedOnly {
+			fonts = pdftool.UnembeddedFonts(fonts)
+			if len(fonts) == 0 {
+				fmt.Printf("✅ All fonts of %s are embedded\n", args[0])
+				return nil
+			}
+		}
+
+		fmt.Printf("🔍 Fonts of %s:\n", args[0])
+		printFontReports(os.Stdout, fonts)
+		return nil
+	},
+}
+
+func init() {
+	fontsCmd.Flags().BoolVar(&fontsUnembeddedOnly, "unembedded-only", false, "Only list fonts that are not embedded")
+
+	rootCmd.AddCommand(fontsCmd)
+}
+
+// printFontReports writes a font list in human-readable form
+func printFontReports(w io.Writer, fonts []pdftool.FontReport) {
+	if len(fonts) == 0 {
+		fmt.Fprintln(w, "No fonts found")
+		return
+	}
+
+	fmt.Fprintf(w, "%-36s %-20s %-8s %-6s %-20s %10s  %s\n", "Name", "Type", "Embedded", "Subset", "Encoding", "Size", "Pages")
+	unembedded := 0
+	for _, font := range fonts {
+		embedded, subset, size := "no", "no", "-"
+		if font.Embedded {
+			embedded, size = "yes", pdftool.FormatSize(font.Bytes)
+		} else {
+			unembedded++
+		}
+		if font.Subset {
+			subset = "yes"
+		}
+		encoding := font.Encoding
+		if encoding == "" {
+			encoding = "built-in"
+		}
+		fmt.Fprintf(w, "%-36s %-20s %-8s %-6s %-20s %10s  %s\n", font.Name, font.Type, embedded, subset, encoding, size, formatPageList(font.Pages))
+	}
+
+	if unembedded > 0 {
+		fmt.Fprintf(w, "\n⚠️  %d of %d fonts are not embedded; they may look different on other systems and fail PDF/A or prepress checks\n", unembedded, len(fonts))
+	}
+}
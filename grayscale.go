@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+
+	"github.com/spf13/cobra"
+)
+
+var grayscaleCmd = &cobra.Command{
+	Use:   "grayscale [input.pdf] [output.pdf]",
+	Short: "Convert a PDF to grayscale",
+	Long: `Convert all colors of a PDF, in images as well as text and vector graphics, to
+grayscale, for example for black and white printing. Images keep their
+resolution; use compress to reduce the quality as well.
+
+Requires Ghostscript:
+  - Linux: sudo apt install ghostscript
+  - macOS: brew install ghostscript
+  - Windows: Download from ghostscript.com`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputFile := args[0]
+		outputFile := args[1]
+
+		if inputFile == outputFile {
+			return fmt.Errorf("input and output files cannot be the same")
+		}
+
+		fmt.Printf("🔄 Converting PDF to grayscale: %s -> %s\n", inputFile, outputFile)
+
+		if err := pdftool.GrayscalePDF(cmd.Context(), inputFile, outputFile); err != nil {
+			return fmt.Errorf("grayscale conversion failed: %w", err)
+		}
+
+		fmt.Println("✅ Grayscale conversion completed successfully!")
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(grayscaleCmd)
+}
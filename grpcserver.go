@@ -0,0 +1,365 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+	"github.com/ansrivas/pdftool/pkg/pdftoolpb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// grpcServer implements the gRPC API of the serve command, sharing the
+// limits of the HTTP API
+type grpcServer struct {
+	pdftoolpb.UnimplementedPDFToolServer
+	s *server
+}
+
+// newGRPCServer returns a gRPC server for the PDFTool service
+func (s *server) newGRPCServer() *grpc.Server {
+	srv := grpc.NewServer(grpc.StreamInterceptor(s.interceptStream))
+	pdftoolpb.RegisterPDFToolServer(srv, &grpcServer{s: s})
+	return srv
+}
+
+// grpcCodes maps the HTTP statuses of statusCode to gRPC codes
+var grpcCodes = map[int]codes.Code{
+	http.StatusBadRequest:            codes.InvalidArgument,
+	http.StatusRequestEntityTooLarge: codes.ResourceExhausted,
+	http.StatusUnprocessableEntity:   codes.InvalidArgument,
+	http.StatusNotImplemented:        codes.Unimplemented,
+	http.StatusGatewayTimeout:        codes.DeadlineExceeded,
+}
+
+// grpcError converts an error to a gRPC status error
+func grpcError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, context.Canceled) {
+		return status.Error(codes.Canceled, err.Error())
+	}
+	code, ok := grpcCodes[statusCode(err)]
+	if !ok {
+		code = codes.Internal
+	}
+	return status.Error(code, err.Error())
+}
+
+// interceptStream limits the calls processed at once, converts errors to
+// gRPC statuses and logs every call
+func (s *server) interceptStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := s.acquire(ss.Context())
+	if err == nil {
+		err = handler(srv, ss)
+		<-s.slots
+	}
+	if err != nil {
+		err = grpcError(err)
+	}
+
+	msg := fmt.Sprintf("gRPC %s: %s in %s", info.FullMethod, status.Code(err), time.Since(start).Round(time.Millisecond))
+	if err != nil {
+		rootLogger.Warn(msg + ": " + status.Convert(err).Message())
+	} else {
+		rootLogger.Info(msg)
+	}
+	return err
+}
+
+// uploadWriter writes received chunks to a file, failing once the uploads
+// of a call exceed the limit
+type uploadWriter struct {
+	f         *os.File
+	remaining *int64
+}
+
+func (u *uploadWriter) Write(chunk []byte) error {
+	if *u.remaining -= int64(len(chunk)); *u.remaining < 0 {
+		return status.Error(codes.ResourceExhausted, "uploads exceed the size limit")
+	}
+	_, err := u.f.Write(chunk)
+	return err
+}
+
+// sendFile streams a file in chunks of pdftoolpb.ChunkSize
+func sendFile(file string, send func(chunk []byte) error) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	buf := make([]byte, pdftoolpb.ChunkSize)
+	for {
+		n, err := f.Read(buf)
+		if n > 0 {
+			if err := send(buf[:n]); err != nil {
+				return err
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Compress implements pdftoolpb.PDFToolServer
+func (g *grpcServer) Compress(stream grpc.BidiStreamingServer[pdftoolpb.CompressRequest, pdftoolpb.CompressResponse]) (err error) {
+	var result *pdftool.Result
+	start := time.Now()
+	defer func() { g.s.metrics.observe(jobCompress, "grpc", start, result, err) }()
+
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	req := first.GetOptions()
+	if req == nil {
+		return status.Error(codes.InvalidArgument, "the first message must carry the options")
+	}
+
+	opts := pdftool.CompressOptions{
+		Quality:       int(req.GetQuality()),
+		Engine:        req.GetEngine(),
+		ImageDPI:      int(req.GetDpi()),
+		StripMetadata: req.GetStripMetadata(),
+	}
+	if opts.Quality == 0 {
+		opts.Quality = 50
+	}
+	if opts.Quality < 1 || opts.Quality > 100 {
+		return status.Errorf(codes.InvalidArgument, "quality must be between 1 and 100, got: %d", opts.Quality)
+	}
+
+	tmpDir, removeTmpDir, err := pdftool.TempDir("", "pdftool-serve-")
+	if err != nil {
+		return err
+	}
+	defer removeTmpDir()
+
+	inputFile := filepath.Join(tmpDir, "input.pdf")
+	f, err := os.Create(inputFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	remaining := g.s.maxUpload
+	upload := &uploadWriter{f: f, remaining: &remaining}
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if req.GetOptions() != nil {
+			return status.Error(codes.InvalidArgument, "options must only be sent first")
+		}
+		if err := upload.Write(req.GetChunk()); err != nil {
+			return err
+		}
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	outputFile := filepath.Join(tmpDir, "output.pdf")
+	result, err = pdftool.CompressPDF(stream.Context(), inputFile, outputFile, opts)
+	if err != nil {
+		return err
+	}
+
+	err = stream.Send(&pdftoolpb.CompressResponse{Payload: &pdftoolpb.CompressResponse_Result{Result: &pdftoolpb.CompressResult{
+		InputSize:  result.InputSize,
+		OutputSize: result.OutputSize,
+		Engine:     result.Engine,
+		Warnings:   result.Warnings,
+	}}})
+	if err != nil {
+		return err
+	}
+	return sendFile(outputFile, func(chunk []byte) error {
+		return stream.Send(&pdftoolpb.CompressResponse{Payload: &pdftoolpb.CompressResponse_Chunk{Chunk: chunk}})
+	})
+}
+
+// fileRequest is a request of a call receiving several files
+type fileRequest interface {
+	GetFile() *pdftoolpb.FileStart
+	GetChunk() []byte
+}
+
+// receiveFiles saves the files of a call to numbered directories in dir,
+// keeping their names, until the client finishes sending
+func receiveFiles[T fileRequest](recv func() (T, error), dir string, limit int64) ([]string, error) {
+	var files []string
+	var upload *uploadWriter
+	defer func() {
+		if upload != nil {
+			upload.f.Close()
+		}
+	}()
+
+	for {
+		req, err := recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if start := req.GetFile(); start != nil {
+			if upload != nil {
+				if err := upload.f.Close(); err != nil {
+					return nil, err
+				}
+			}
+			name := filepath.Base(start.GetName())
+			if name == "." || name == string(filepath.Separator) {
+				return nil, status.Errorf(codes.InvalidArgument, "file %d has no name", len(files)+1)
+			}
+
+			fileDir := filepath.Join(dir, strconv.Itoa(len(files)))
+			if err := os.Mkdir(fileDir, 0o755); err != nil {
+				return nil, err
+			}
+			file := filepath.Join(fileDir, name)
+			f, err := os.Create(file)
+			if err != nil {
+				return nil, err
+			}
+			upload = &uploadWriter{f: f, remaining: &limit}
+			files = append(files, file)
+			continue
+		}
+
+		if upload == nil {
+			return nil, status.Error(codes.InvalidArgument, "a file must be started before its chunks")
+		}
+		if err := upload.Write(req.GetChunk()); err != nil {
+			return nil, err
+		}
+	}
+
+	if upload != nil {
+		if err := upload.f.Close(); err != nil {
+			return nil, err
+		}
+		upload = nil
+	}
+	return files, nil
+}
+
+// Convert implements pdftoolpb.PDFToolServer
+func (g *grpcServer) Convert(stream grpc.BidiStreamingServer[pdftoolpb.ConvertRequest, pdftoolpb.Chunk]) (err error) {
+	start := time.Now()
+	defer func() { g.s.metrics.observe(jobConvert, "grpc", start, nil, err) }()
+
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	req := first.GetOptions()
+	if req == nil {
+		return status.Error(codes.InvalidArgument, "the first message must carry the options")
+	}
+
+	opts := pdftool.ConvertOptions{
+		PageSize:  req.GetPageSize(),
+		Landscape: req.GetLandscape(),
+		Fit:       req.GetFit(),
+		DPI:       int(req.GetDpi()),
+		Margin:    req.GetMargin(),
+		Grayscale: req.GetGrayscale(),
+		Caption:   req.GetCaption(),
+		PDFA:      req.GetPdfa(),
+		Title:     req.GetTitle(),
+		Author:    req.GetAuthor(),
+		Subject:   req.GetSubject(),
+		Keywords:  req.GetKeywords(),
+	}
+	if nup := req.GetNup(); nup != "" {
+		if opts.NupColumns, opts.NupRows, err = pdftool.ParseGrid(nup); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	tmpDir, removeTmpDir, err := pdftool.TempDir("", "pdftool-serve-")
+	if err != nil {
+		return err
+	}
+	defer removeTmpDir()
+
+	inputFiles, err := receiveFiles(stream.Recv, tmpDir, g.s.maxUpload)
+	if err != nil {
+		return err
+	}
+	if len(inputFiles) == 0 {
+		return status.Error(codes.InvalidArgument, "no images sent")
+	}
+
+	outputFile := filepath.Join(tmpDir, "output.pdf")
+	if err := pdftool.ConvertImagesToPDF(stream.Context(), inputFiles, outputFile, opts); err != nil {
+		return uploadError(err)
+	}
+	return sendFile(outputFile, func(chunk []byte) error {
+		return stream.Send(&pdftoolpb.Chunk{Data: chunk})
+	})
+}
+
+// Merge implements pdftoolpb.PDFToolServer
+func (g *grpcServer) Merge(stream grpc.BidiStreamingServer[pdftoolpb.MergeRequest, pdftoolpb.Chunk]) (err error) {
+	start := time.Now()
+	defer func() { g.s.metrics.observe(jobMerge, "grpc", start, nil, err) }()
+
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	req := first.GetOptions()
+	if req == nil {
+		return status.Error(codes.InvalidArgument, "the first message must carry the options")
+	}
+	opts := pdftool.MergeOptions{BookmarkPerFile: req.GetBookmarks(), TOC: req.GetToc()}
+
+	tmpDir, removeTmpDir, err := pdftool.TempDir("", "pdftool-serve-")
+	if err != nil {
+		return err
+	}
+	defer removeTmpDir()
+
+	inputFiles, err := receiveFiles(stream.Recv, tmpDir, g.s.maxUpload)
+	if err != nil {
+		return err
+	}
+	if len(inputFiles) < 2 {
+		return status.Error(codes.InvalidArgument, "at least 2 PDFs are required")
+	}
+
+	outputFile := filepath.Join(tmpDir, "merged.pdf")
+	if err := pdftool.MergePDFs(stream.Context(), inputFiles, outputFile, opts); err != nil {
+		return uploadError(err)
+	}
+	return sendFile(outputFile, func(chunk []byte) error {
+		return stream.Send(&pdftoolpb.Chunk{Data: chunk})
+	})
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+
+	"github.com/spf13/cobra"
+)
+
+var headerFooterOpts pdftool.HeaderFooterOptions
+
+var headerFooterCmd = &cobra.Command{
+	Use:   "headerfooter [input.pdf] [output.pdf]",
+	Short: "Stamp header and footer text onto PDF pages",
+	Long: `Stamp templated header and footer text onto the selected pages of a PDF.
+
+Templates support these placeholders:
+  {page}      page number
+  {total}     number of pages
+  {date}      today's date (YYYY-MM-DD)
+  {filename}  input file name
+  {name}      input file name without extension
+
+Example: --header "{filename}" --footer "Printed {date} - page {page} of {total}"`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputFile := args[0]
+		outputFile := args[1]
+
+		fmt.Printf("🔄 Adding header/footer: %s -> %s\n", inputFile, outputFile)
+
+		if err := pdftool.AddHeaderFooter(cmd.Context(), inputFile, outputFile, headerFooterOpts); err != nil {
+			return fmt.Errorf("header/footer failed: %w", err)
+		}
+
+		fmt.Println("✅ Header/footer completed successfully!")
+		return nil
+	},
+}
+
+func init() {
+	headerFooterCmd.Flags().StringVar(&headerFooterOpts.Header, "header", "", "Header text template")
+	headerFooterCmd.Flags().StringVar(&headerFooterOpts.Footer, "footer", "", "Footer text template")
+	headerFooterCmd.Flags().StringVar(&headerFooterOpts.HeaderAlign, "header-align", "center", "Header alignment: left, center or right")
+	headerFooterCmd.Flags().StringVar(&headerFooterOpts.FooterAlign, "footer-align", "center", "Footer alignment: left, center or right")
+	headerFooterCmd.Flags().StringVar(&headerFooterOpts.Pages, "pages", "all", "Pages to stamp, e.g. 2- or all")
+	headerFooterCmd.Flags().StringVar(&headerFooterOpts.Font, "font", "Helvetica", "Font name, e.g. Helvetica, Times-Roman, Courier")
+	headerFooterCmd.Flags().IntVar(&headerFooterOpts.FontSize, "size", 9, "Font size in points")
+	headerFooterCmd.Flags().StringVar(&headerFooterOpts.Color, "color", "#000000", "Text color (#rrggbb)")
+	headerFooterCmd.Flags().Float64Var(&headerFooterOpts.Margin, "margin", 20, "Distance from the page edges in points")
+
+	rootCmd.AddCommand(headerFooterCmd)
+}
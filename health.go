@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+)
+
+// healthCheck is one check of /readyz, returning a short description of the
+// state and whether the server can take requests with it
+type healthCheck struct {
+	name  string
+	check func() (string, bool)
+}
+
+// readyChecks returns the checks of /readyz
+func (s *server) readyChecks() []healthCheck {
+	checks := []healthCheck{
+		{"ghostscript", checkGhostscript},
+		{"temp_dir", checkTempDir},
+	}
+	if s.queue != nil {
+		checks = append(checks, healthCheck{"queue", s.checkQueue})
+	}
+	return checks
+}
+
+// checkGhostscript reports whether Ghostscript is installed. It is not
+// needed in pure-Go mode.
+func checkGhostscript() (string, bool) {
+	if pdftool.PureGo() {
+		return "not used in pure-Go mode", true
+	}
+	info, err := pdftool.DetectGhostscript()
+	if err != nil {
+		return err.Error(), false
+	}
+	return "ok, version " + info.Version, true
+}
+
+// checkTempDir reports whether temporary files can be created for uploads
+// and intermediate results
+func checkTempDir() (string, bool) {
+	dir, removeDir, err := pdftool.TempDir("", "pdftool-ready-")
+	if err != nil {
+		return err.Error(), false
+	}
+	defer removeDir()
+	if err := os.WriteFile(filepath.Join(dir, "probe"), nil, 0o644); err != nil {
+		return err.Error(), false
+	}
+	return "ok", true
+}
+
+// checkQueue reports whether the queue can be read and its backlog of
+// queued jobs is within --max-backlog
+func (s *server) checkQueue() (string, bool) {
+	counts, err := s.queue.counts()
+	if err != nil {
+		return err.Error(), false
+	}
+	backlog := counts[jobQueued]
+	if s.maxBacklog > 0 && backlog > s.maxBacklog {
+		return fmt.Sprintf("%d jobs queued, more than %d", backlog, s.maxBacklog), false
+	}
+	return fmt.Sprintf("ok, %d jobs queued, %d running", backlog, counts[jobRunning]), true
+}
+
+// healthz handles GET /healthz, which only tells that the server is
+// running. Probes are not logged.
+func (s *server) healthz(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// readyz handles GET /readyz: it answers 200 if all checks pass and 503
+// otherwise, with the result of every check
+func (s *server) readyz(w http.ResponseWriter, r *http.Request) {
+	status, ready := http.StatusOK, "ready"
+	results := map[string]string{}
+	for _, c := range s.readyChecks() {
+		result, ok := c.check()
+		results[c.name] = result
+		if !ok {
+			status, ready = http.StatusServiceUnavailable, "not ready"
+		}
+	}
+	writeJSON(w, status, map[string]any{"status": ready, "checks": results})
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+
+	"github.com/spf13/cobra"
+)
+
+// Commands run around each processed file, from --pre-hook and --post-hook
+var (
+	preHookCmd  string
+	postHookCmd string
+)
+
+// addHookFlags adds the --pre-hook and --post-hook flags to a command
+func addHookFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&preHookCmd, "pre-hook", "", "Command to run before each file, e.g. 'sha256sum {in}'; failing skips the file")
+	cmd.Flags().StringVar(&postHookCmd, "post-hook", "", "Command to run after each file, e.g. 'aws s3 cp {out} s3://bucket/'")
+}
+
+// setCommandHooks sets pre and post to the hooks given with --pre-hook and
+// --post-hook, if any
+func setCommandHooks(pre, post *pdftool.Hook) error {
+	if preHookCmd != "" {
+		hook, err := pdftool.CommandHook(preHookCmd)
+		if err != nil {
+			return err
+		}
+		*pre = hook
+	}
+	if postHookCmd != "" {
+		hook, err := pdftool.CommandHook(postHookCmd)
+		if err != nil {
+			return err
+		}
+		*post = hook
+	}
+	return nil
+}
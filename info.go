@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	infoJSON     bool
+	infoPassword string
+)
+
+var infoCmd = &cobra.Command{
+	Use:   "info [input.pdf]",
+	Short: "Show page, font and image details of a PDF",
+	Long: `Show a PDF's page count, page sizes, PDF version, encryption status,
+producer, fonts, and the number and total size of its images, which helps
+when choosing compression settings.
+
+Use --json for machine-readable output.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		info, err := pdftool.GetPDFInfo(cmd.Context(), args[0], infoPassword)
+		if err != nil {
+			return fmt.Errorf("reading PDF info failed: %w", err)
+		}
+
+		if infoJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(info)
+		}
+
+		printInfo(os.Stdout, info)
+		return nil
+	},
+}
+
+func init() {
+	infoCmd.Flags().BoolVar(&infoJSON, "json", false, "Print the information as JSON")
+	infoCmd.Flags().StringVar(&infoPassword, "password", "", "Password for encrypted PDFs")
+
+	rootCmd.AddCommand(infoCmd)
+}
+
+// printInfo writes a PDF summary in human-readable form
+func printInfo(w io.Writer, info *pdftool.PDFInfo) {
+	fmt.Fprintf(w, "File:        %s (%s)\n", filepath.Base(info.File), pdftool.FormatSize(info.FileSize))
+	fmt.Fprintf(w, "Version:     PDF %s\n", info.Version)
+	fmt.Fprintf(w, "Pages:       %d\n", info.PageCount)
+
+	for i, size := range info.PageSizes {
+		label := ""
+		if i == 0 {
+			label = "Page sizes:"
+		}
+		name := ""
+		if size.Name != "" {
+			name = " (" + size.Name + ")"
+		}
+		fmt.Fprintf(w, "%-12s %g x %g pt%s, %d pages\n", label, size.Width, size.Height, name, size.Pages)
+	}
+
+	encrypted := "no"
+	if info.Encrypted {
+		encrypted = "yes"
+	}
+	fmt.Fprintf(w, "Encrypted:   %s\n", encrypted)
+
+	for _, field := range []struct{ label, value string }{
+		{"Title:", info.Title},
+		{"Author:", info.Author},
+		{"Creator:", info.Creator},
+		{"Producer:", info.Producer},
+	} {
+		if field.value != "" {
+			fmt.Fprintf(w, "%-12s %s\n", field.label, field.value)
+		}
+	}
+
+	fmt.Fprintf(w, "Images:      %d (%s)\n", info.ImageCount, pdftool.FormatSize(info.ImageBytes))
+
+	fmt.Fprintf(w, "Fonts:       %d\n", len(info.Fonts))
+	for _, font := range info.Fonts {
+		embedded := "not embedded"
+		if font.Embedded {
+			embedded = "embedded"
+		}
+		fmt.Fprintf(w, "  %s (%s, %s)\n", font.Name, font.Type, embedded)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	jobsQueue string
+	jobsJSON  bool
+)
+
+var jobsCmd = &cobra.Command{
+	Use:   "jobs",
+	Short: "Inspect and manage the job queue of serve and watch",
+	Long: `Inspect and manage the job queue that serve and watch process with
+--queue. The queue can be changed while they are running.`,
+}
+
+var jobsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the jobs in the queue",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		queue, err := openJobsQueue()
+		if err != nil {
+			return err
+		}
+		jobs, err := queue.List()
+		if err != nil {
+			return fmt.Errorf("listing jobs failed: %w", err)
+		}
+
+		if jobsJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(jobs)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tSTATUS\tATTEMPTS\tKIND\tFILE\tUPDATED\tERROR")
+		for _, job := range jobs {
+			status := job.Status
+			if job.Status == jobQueued && job.NextAttempt.After(time.Now()) {
+				status = fmt.Sprintf("retry in %s", time.Until(job.NextAttempt).Round(time.Second))
+			}
+			fmt.Fprintf(w, "%d\t%s\t%d\t%s\t%s\t%s\t%s\n", job.ID, status, job.Attempts, job.Kind,
+				remoteBase(job.InputFile), job.Updated.Format(time.DateTime), job.Error)
+		}
+		return w.Flush()
+	},
+}
+
+var jobsRetryCmd = &cobra.Command{
+	Use:   "retry [id]...",
+	Short: "Queue failed or canceled jobs again",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return changeJobs(args, "retry", (*jobQueue).Retry, "🔁 Job %d queued again\n")
+	},
+}
+
+var jobsCancelCmd = &cobra.Command{
+	Use:   "cancel [id]...",
+	Short: "Cancel queued or running jobs",
+	Long: `Cancel queued or running jobs. A running job finishes its current attempt,
+but its result is discarded.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return changeJobs(args, "cancel", (*jobQueue).Cancel, "🚫 Job %d canceled\n")
+	},
+}
+
+func init() {
+	jobsCmd.PersistentFlags().StringVar(&jobsQueue, "queue", os.Getenv("PDF_TOOL_QUEUE"), "Folder of the job queue (env: PDF_TOOL_QUEUE)")
+	jobsListCmd.Flags().BoolVar(&jobsJSON, "json", false, "Print the jobs as JSON")
+
+	jobsCmd.AddCommand(jobsListCmd)
+	jobsCmd.AddCommand(jobsRetryCmd)
+	jobsCmd.AddCommand(jobsCancelCmd)
+	rootCmd.AddCommand(jobsCmd)
+}
+
+// openJobsQueue opens the queue of the jobs command
+func openJobsQueue() (*jobQueue, error) {
+	if jobsQueue == "" {
+		return nil, fmt.Errorf("--queue is required")
+	}
+	return openQueue(jobsQueue)
+}
+
+// changeJobs applies change to the jobs with the given IDs
+func changeJobs(args []string, verb string, change func(q *jobQueue, id uint64) error, done string) error {
+	ids := make([]uint64, len(args))
+	for i, arg := range args {
+		id, err := strconv.ParseUint(arg, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid job id: %s", arg)
+		}
+		ids[i] = id
+	}
+
+	queue, err := openJobsQueue()
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err := change(queue, id); err != nil {
+			return fmt.Errorf("%s failed: %w", verb, err)
+		}
+		fmt.Printf(done, id)
+	}
+	return nil
+}
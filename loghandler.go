@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// cliHandler is a slog.Handler that writes library messages the way the CLI
+// has always printed them: the plain message, warnings marked with ⚠️ and
+// errors with ❌, and attributes as indented "Label: value" lines
+type cliHandler struct {
+	mu    *sync.Mutex
+	w     io.Writer
+	level slog.Level
+	attrs []slog.Attr
+}
+
+// newCLIHandler returns a handler writing messages of at least level to w
+func newCLIHandler(w io.Writer, level slog.Level) *cliHandler {
+	return &cliHandler{mu: &sync.Mutex{}, w: w, level: level}
+}
+
+func (h *cliHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h *cliHandler) Handle(_ context.Context, r slog.Record) error {
+	var b strings.Builder
+	if len(h.attrs) > 0 || r.NumAttrs() > 0 {
+		b.WriteString("\n") // Set off messages with details
+	}
+	switch {
+	case r.Level >= slog.LevelError:
+		b.WriteString("❌ ")
+	case r.Level >= slog.LevelWarn:
+		b.WriteString("⚠️  ")
+	}
+	b.WriteString(r.Message)
+	b.WriteString("\n")
+
+	writeAttr := func(a slog.Attr) bool {
+		label, value := formatAttr(a)
+		fmt.Fprintf(&b, "   %s: %s\n", label, value)
+		return true
+	}
+	for _, a := range h.attrs {
+		writeAttr(a)
+	}
+	r.Attrs(writeAttr)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, b.String())
+	return err
+}
+
+func (h *cliHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = append(append([]slog.Attr{}, h.attrs...), attrs...)
+	return &clone
+}
+
+// WithGroup returns h unchanged; the output is flat
+func (h *cliHandler) WithGroup(string) slog.Handler {
+	return h
+}
+
+// formatAttr returns a label and value for an attribute, e.g. "Page count"
+// and "12" for page_count=12
+func formatAttr(a slog.Attr) (string, string) {
+	label := strings.ReplaceAll(a.Key, "_", " ")
+	if label != "" {
+		label = strings.ToUpper(label[:1]) + label[1:]
+	}
+	return label, a.Value.Resolve().String()
+}
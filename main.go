@@ -1,11 +1,17 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
+	"os/signal"
 	"strconv"
+	"syscall"
+	"time"
 
-	"github.com/ansrivas/pdftool/internal"
+	"github.com/ansrivas/pdftool/pkg/pdftool"
 
 	"github.com/spf13/cobra"
 )
@@ -19,18 +25,96 @@ For best compression results, install Ghostscript:
   - Linux: sudo apt install ghostscript  
   - macOS: brew install ghostscript
   - Windows: Download from ghostscript.com`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := setupLogger(); err != nil {
+			return err
+		}
+		if rootPureGo {
+			pdftool.SetPureGo(true)
+		}
+		if err := checkRemoteArgs(cmd, args); err != nil {
+			return err
+		}
+		if rootTimeout > 0 {
+			ctx, cancel := context.WithTimeout(cmd.Context(), rootTimeout)
+			cmd.SetContext(ctx)
+			cobra.OnFinalize(cancel)
+		}
+		return nil
+	},
 }
 
+// rootQuiet hides the library's informational messages, keeping warnings
+// and errors
+var rootQuiet bool
+
+// rootPureGo keeps operations from running external programs like
+// Ghostscript
+var rootPureGo bool
+
+// rootLogFormat selects how library messages are written: text for people,
+// json for log collectors
+var rootLogFormat string
+
+// rootLogger writes the library's messages and those of long-running
+// commands like serve; set up by setupLogger
+var rootLogger *slog.Logger
+
+// setupLogger routes the library's messages to stdout in the format chosen
+// with --log-format
+func setupLogger() error {
+	level := slog.LevelInfo
+	if rootQuiet {
+		level = slog.LevelWarn
+	}
+
+	switch rootLogFormat {
+	case "text":
+		rootLogger = slog.New(newCLIHandler(os.Stdout, level))
+	case "json":
+		rootLogger = slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: level}))
+	default:
+		return fmt.Errorf("unsupported log format: %s (supported: text, json)", rootLogFormat)
+	}
+	pdftool.SetLogger(rootLogger)
+	return nil
+}
+
+// rootTimeout limits how long a command may run, including external
+// programs like Ghostscript
+var rootTimeout time.Duration
+
+var compressOpts pdftool.CompressOptions
+
+var compressEncrypt pdftool.EncryptOptions
+
 var compressCmd = &cobra.Command{
-	Use:   "compress [input.pdf] [output.pdf] [quality%]",
-	Short: "Compress a PDF file",
+	Annotations: map[string]string{remoteAnnotation: "true"},
+	Use:         "compress [input.pdf|input-dir] [output.pdf|output-dir] [quality%]",
+	Short:       "Compress a PDF file",
 	Long: `Compress a PDF file with specified quality percentage.
 
 Quality levels:
   1-25:   Maximum compression, lowest quality (/screen preset)
   26-50:  High compression, medium-low quality (/ebook preset) 
   51-75:  Medium compression, good quality (/printer preset)
-  76-100: Light compression, highest quality (/prepress preset)`,
+  76-100: Light compression, highest quality (/prepress preset)
+
+Use --dpi to downsample images to a different resolution than the preset,
+--engine pdfcpu to only optimize the file structure (or qpdf, mutool, or a
+fallback chain like qpdf,pdfcpu), --strip-metadata to remove the document
+properties, and --user-pass or --owner-pass to encrypt the result with
+AES-256.
+
+Other optimizers can be used as engines with --engine-cmd, e.g.
+  --engine-cmd 'fast=my-compressor {in} {out} --q {quality}' --engine fast
+or permanently in engines.json in the user config directory
+(~/.config/pdf-tool on Linux), e.g. {"fast": "my-compressor {in} {out}"}.
+Templates can also use {dpi}.
+
+When the input is a directory, every PDF in it is compressed into the output
+directory by --workers files at a time. Failed files are retried --retries
+times and do not stop the others.`,
 	Args: cobra.ExactArgs(3),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		inputFile := args[0]
@@ -51,29 +135,147 @@ Quality levels:
 			return fmt.Errorf("input and output files cannot be the same")
 		}
 
-		fmt.Printf("🔄 Compressing PDF: %s -> %s (Quality: %d%%)\n", inputFile, outputFile, quality)
+		if err := registerCommandEngines(compressEngineCmds); err != nil {
+			return err
+		}
+		if err := setCommandHooks(&compressOpts.PreHook, &compressOpts.PostHook); err != nil {
+			return err
+		}
+
+		compressOpts.Quality = quality
+		if compressEncrypt.UserPassword != "" || compressEncrypt.OwnerPassword != "" {
+			compressEncrypt.KeyLength = 256
+			compressOpts.Encrypt = &compressEncrypt
+		}
+
+		if info, err := os.Stat(inputFile); (err == nil && info.IsDir()) || isRemotePrefix(inputFile) {
+			return compressDir(cmd.Context(), inputFile, outputFile)
+		}
+
+		fmt.Printf("🔄 Compressing PDF: %s -> %s (Quality: %d%%)\n", redactURI(inputFile), redactURI(outputFile), quality)
+
+		compressOpts.Progress = progressBar()
 
-		if err := internal.CompressPDF(inputFile, outputFile, quality); err != nil {
+		result, err := compressURI(cmd.Context(), inputFile, outputFile, func(ctx context.Context, inputFile, outputFile string) (*pdftool.Result, error) {
+			return pdftool.CompressPDF(ctx, inputFile, outputFile, compressOpts)
+		})
+		if err != nil {
 			return fmt.Errorf("compression failed: %w", err)
 		}
+		printCompressionResult(result)
 
 		fmt.Println("✅ PDF compression completed successfully!")
 		return nil
 	},
 }
 
+// printCompressionResult prints the sizes before and after compression and
+// any warnings; with --quiet only the warnings
+func printCompressionResult(result *pdftool.Result) {
+	if !rootQuiet {
+		fmt.Printf("\n📊 Compression Results:\n")
+		fmt.Printf("   Original size: %.2f KB (%.2f MB)\n",
+			float64(result.InputSize)/1024, float64(result.InputSize)/(1024*1024))
+		fmt.Printf("   Compressed size: %.2f KB (%.2f MB)\n",
+			float64(result.OutputSize)/1024, float64(result.OutputSize)/(1024*1024))
+		fmt.Printf("   Final size: %.1f%% of original\n", result.Ratio*100)
+		fmt.Printf("   Space saved: %.1f%%\n", (1-result.Ratio)*100)
+		fmt.Printf("   Engine: %s, %s\n", result.Engine, result.Duration.Round(time.Millisecond))
+	}
+
+	for _, warning := range result.Warnings {
+		fmt.Printf("   ⚠️  %s\n", warning)
+	}
+}
+
+var convertOpts pdftool.ConvertOptions
+
+var (
+	convertNup         string
+	convertNupCaptions bool
+	convertSort        string
+	convertBackground  string
+	convertRotate      string
+)
+
 var convertCmd = &cobra.Command{
-	Use:   "convert [input.png/jpg] [output.pdf]",
-	Short: "Convert PNG or JPEG to PDF",
-	Long:  `Convert PNG or JPEG image files to PDF format with automatic sizing`,
-	Args:  cobra.ExactArgs(2),
+	Annotations: map[string]string{remoteAnnotation: "true"},
+	Use:         "convert [input.png/jpg/dir/zip]... [output.pdf]",
+	Short:       "Convert PNG or JPEG to PDF",
+	Long: `Convert PNG or JPEG image files to PDF format with automatic sizing.
+
+Multiple images become one page each, or are laid out in a grid with --nup 2x2.
+Add a caption under each image with a template, e.g. --caption "{index}: {filename}".
+Directories and ZIP archives are expanded to the images they contain, ordered
+by --sort:
+  name:      plain file name order
+  natural:   numbers compared by value, so page2 comes before page10 (default)
+  mtime:     file modification time
+  exif-date: camera capture date, falling back to modification time
+  none:      directory listing or archive entry order
+
+JPEG variants from print workflows that Go cannot decode, such as arithmetic
+coding or 12-bit samples, are converted with ImageMagick when it is installed.
+
+Use --ocr to add an invisible, searchable text layer (requires Tesseract):
+  - Linux: sudo apt install tesseract-ocr
+  - macOS: brew install tesseract`,
+	Args: cobra.MinimumNArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		inputFile := args[0]
-		outputFile := args[1]
+		outputFile := args[len(args)-1]
 
-		fmt.Printf("🔄 Converting image: %s -> %s\n", inputFile, outputFile)
+		inputs, cleanupRemote, err := localInputs(cmd.Context(), args[:len(args)-1], ".png", ".jpg", ".jpeg", ".zip")
+		if err != nil {
+			return err
+		}
+		defer cleanupRemote()
+
+		inputFiles, cleanup, err := pdftool.CollectImages(inputs, convertSort)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
 
-		if err := internal.ConvertImageToPDF(inputFile, outputFile); err != nil {
+		localOutputFile, commit, cleanupOutput, err := localOutput(cmd.Context(), outputFile)
+		if err != nil {
+			return err
+		}
+		defer cleanupOutput()
+
+		background, err := pdftool.ParseHexColor(convertBackground)
+		if err != nil {
+			return err
+		}
+		convertOpts.Background = background
+
+		if convertRotate != "" {
+			if convertOpts.Rotate, err = pdftool.ParseRotation(convertRotate); err != nil {
+				return err
+			}
+		}
+
+		if convertNup != "" {
+			cols, rows, err := pdftool.ParseGrid(convertNup)
+			if err != nil {
+				return err
+			}
+			convertOpts.NupColumns, convertOpts.NupRows = cols, rows
+		}
+
+		if convertNupCaptions && convertOpts.Caption == "" {
+			convertOpts.Caption = "{filename}"
+		}
+
+		if len(inputFiles) == 1 {
+			fmt.Printf("🔄 Converting image: %s -> %s\n", inputFiles[0], redactURI(outputFile))
+		} else {
+			fmt.Printf("🔄 Converting %d images -> %s\n", len(inputFiles), redactURI(outputFile))
+		}
+
+		if err := pdftool.ConvertImagesToPDF(cmd.Context(), inputFiles, localOutputFile, convertOpts); err != nil {
+			return fmt.Errorf("conversion failed: %w", err)
+		}
+		if err := commit(); err != nil {
 			return fmt.Errorf("conversion failed: %w", err)
 		}
 
@@ -83,13 +285,105 @@ var convertCmd = &cobra.Command{
 }
 
 func init() {
+	convertCmd.Flags().BoolVar(&convertOpts.OCR, "ocr", false, "Add a searchable text layer using Tesseract OCR")
+	convertCmd.Flags().StringVar(&convertOpts.OCRLanguage, "lang", "eng", "OCR language(s), e.g. deu+eng")
+	convertCmd.Flags().BoolVar(&convertOpts.Grayscale, "grayscale", false, "Convert images to 8-bit grayscale")
+	convertCmd.Flags().BoolVar(&convertOpts.Bilevel, "bilevel", false, "Convert images to black and white")
+	convertCmd.Flags().Uint8Var(&convertOpts.Threshold, "threshold", 128, "Gray level (0-255) separating black from white with --bilevel")
+	convertCmd.Flags().BoolVar(&convertOpts.Dither, "dither", false, "Use dithering instead of a fixed threshold with --bilevel")
+	convertCmd.Flags().StringVar(&convertRotate, "rotate", "", "Rotate images clockwise: 90, 180, 270, or auto (EXIF or Tesseract detection)")
+	convertCmd.Flags().BoolVar(&convertOpts.Enhance, "enhance", false, "Improve document photos: despeckle, more contrast, sharpen")
+	convertCmd.Flags().Float64Var(&convertOpts.Brightness, "brightness", 0, "Brightness adjustment in percent (-100 to 100)")
+	convertCmd.Flags().Float64Var(&convertOpts.Contrast, "contrast", 0, "Contrast adjustment in percent (-100 to 100)")
+	convertCmd.Flags().Float64Var(&convertOpts.Sharpen, "sharpen", 0, "Sharpening strength as a Gaussian sigma, e.g. 1.0")
+	convertCmd.Flags().BoolVar(&convertOpts.Despeckle, "despeckle", false, "Remove isolated noise pixels")
+	convertCmd.Flags().BoolVar(&convertOpts.Deskew, "deskew", false, "Straighten slightly rotated scans")
+	convertCmd.Flags().BoolVar(&convertOpts.AutoCrop, "autocrop", false, "Trim uniform borders and margins from images")
+	convertCmd.Flags().Uint8Var(&convertOpts.AutoCropThreshold, "autocrop-threshold", 32, "Gray level difference (0-255) from the border that counts as content")
+	convertCmd.Flags().StringVar(&convertBackground, "background", "#ffffff", "Background color for transparent images")
+	convertCmd.Flags().StringVar(&convertNup, "nup", "", "Lay out multiple images per page in a grid, e.g. 2x2")
+	convertCmd.Flags().StringVar(&convertOpts.Caption, "caption", "", "Caption under each image: {filename}, {name}, {index}, {count}, {date}")
+	convertCmd.Flags().BoolVar(&convertNupCaptions, "nup-captions", false, "Print file names under images in --nup grids")
+	convertCmd.Flags().MarkDeprecated("nup-captions", "use --caption \"{filename}\" instead")
+	convertCmd.Flags().StringVar(&convertOpts.PageNumbers, "page-numbers", "", "Number pages at a position: {top,bottom}-{left,center,right}")
+	convertCmd.Flags().StringVar(&convertOpts.PageNumberFormat, "page-number-format", pdftool.DefaultPageNumberFormat, "Page number template with {n} and {total}")
+	convertCmd.Flags().StringVar(&convertSort, "sort", pdftool.SortNatural, "Order of images read from directories and archives: name, natural, mtime, exif-date, none")
+	convertCmd.Flags().StringVar(&convertOpts.PageSize, "page-size", "A4", "Page size, e.g. A4, A3, A5, Letter, Legal")
+	convertCmd.Flags().BoolVar(&convertOpts.Landscape, "landscape", false, "Use landscape pages")
+	convertCmd.Flags().Float64Var(&convertOpts.Margin, "margin", 0, "Page margin in points (default: none, 36 for --nup grids)")
+	convertCmd.Flags().StringVar(&convertOpts.Fit, "fit", pdftool.FitAuto, "Image sizing: auto, contain (fill the page or cell) or actual (natural size at --dpi)")
+	convertCmd.Flags().IntVar(&convertOpts.DPI, "dpi", 300, "Resolution images are assumed to have for their natural size")
+	convertCmd.Flags().BoolVar(&convertOpts.PDFA, "pdfa", false, "Produce PDF/A-2b output for archiving")
+	convertCmd.Flags().StringVar(&convertOpts.Title, "title", "", "Document title (default: input file name)")
+	convertCmd.Flags().StringVar(&convertOpts.Author, "author", "", "Document author")
+	convertCmd.Flags().StringVar(&convertOpts.Subject, "subject", "", "Document subject")
+	convertCmd.Flags().StringVar(&convertOpts.Keywords, "keywords", "", "Document keywords (comma separated)")
+
+	rootCmd.PersistentFlags().DurationVar(&rootTimeout, "timeout", 0, "Abort the command after this long, e.g. 5m (default: no limit)")
+	rootCmd.PersistentFlags().BoolVarP(&rootQuiet, "quiet", "q", false, "Only show warnings and errors from operations")
+	rootCmd.PersistentFlags().BoolVar(&rootPureGo, "pure-go", os.Getenv("PDF_TOOL_PURE_GO") != "", "Never run external programs; features that need them fail (env: PDF_TOOL_PURE_GO)")
+	rootCmd.PersistentFlags().StringVar(&rootLogFormat, "log-format", "text", "Format of operation messages: text or json")
+
+	compressCmd.Flags().StringVar(&compressOpts.Engine, "engine", pdftool.EngineAuto, "Compression engine: auto, ghostscript, mutool, qpdf, pdfcpu, or a fallback chain like qpdf,pdfcpu")
+	compressCmd.Flags().IntVar(&compressOpts.ImageDPI, "dpi", 0, "Downsample images to this resolution instead of the quality preset's")
+	compressCmd.Flags().StringArrayVar(&compressEngineCmds, "engine-cmd", nil, "Define an engine running a command, e.g. 'name=my-compressor {in} {out} --q {quality}' (repeatable)")
+	addHookFlags(compressCmd)
+	compressCmd.Flags().IntVar(&compressWorkers, "workers", 0, "Files compressed at once with a directory input (default: number of CPUs)")
+	compressCmd.Flags().IntVar(&compressRetries, "retries", 0, "Further attempts for files that fail with a directory input")
+	compressCmd.Flags().BoolVar(&compressOpts.StripMetadata, "strip-metadata", false, "Remove document properties and XMP metadata")
+	compressCmd.Flags().BoolVar(&compressOpts.RequireSmaller, "require-smaller", false, "Fail instead of writing an output that is not smaller than the input")
+	compressCmd.Flags().StringVar(&compressEncrypt.UserPassword, "user-pass", "", "Encrypt the result with a password required to open it")
+	compressCmd.Flags().StringVar(&compressEncrypt.OwnerPassword, "owner-pass", "", "Encrypt the result with a password required to change permissions")
+
 	rootCmd.AddCommand(compressCmd)
 	rootCmd.AddCommand(convertCmd)
 }
 
+// exitCodes maps failure causes to exit codes, so scripts can tell them
+// apart; other errors exit with 1
+var exitCodes = []struct {
+	err  error
+	code int
+}{
+	{pdftool.ErrGhostscriptNotFound, 3},
+	{pdftool.ErrEncrypted, 4},
+	{pdftool.ErrInvalidPDF, 5},
+	{pdftool.ErrOutputLarger, 6},
+	{pdftool.ErrTimeout, 7},
+	{pdftool.ErrPureGo, 8},
+	{context.Canceled, 130}, // Interrupted, as shells report Ctrl+C
+}
+
+// exitCode returns the exit code for an error
+func exitCode(err error) int {
+	for _, e := range exitCodes {
+		if errors.Is(err, e.err) {
+			return e.code
+		}
+	}
+	return 1
+}
+
 func main() {
-	if err := rootCmd.Execute(); err != nil {
+	// Cancel running operations on Ctrl+C, which also stops Ghostscript and
+	// other external programs
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// A second signal exits at once, e.g. while a step that cannot be
+	// canceled finishes; deferred cleanup won't run then, so remove the
+	// temporary files first
+	go func() {
+		<-ctx.Done()
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		<-signals
+		pdftool.RemoveTempFiles()
+		os.Exit(exitCode(context.Canceled))
+	}()
+
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
 		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitCode(err))
 	}
 }
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+
+	"github.com/spf13/cobra"
+)
+
+var mergeOpts pdftool.MergeOptions
+
+var mergeCmd = &cobra.Command{
+	Annotations: map[string]string{remoteAnnotation: "true"},
+	Use:         "merge [input.pdf]... [output.pdf]",
+	Short:       "Merge PDF files into one",
+	Long: `Merge two or more PDF files into a single document, in the given order.
+
+Use --bookmark-per-file to add an outline entry for each source file, and
+--toc to also prepend a table of contents page linking to each of them.
+--toc-from-filenames titles those entries with the cleaned-up file names,
+e.g. "Annual report" for 01_annual_report.pdf, which keeps bundles of many
+files navigable.`,
+	Args: cobra.MinimumNArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		outputFile := args[len(args)-1]
+
+		inputFiles, cleanup, err := localInputs(cmd.Context(), args[:len(args)-1], ".pdf")
+		if err != nil {
+			return fmt.Errorf("merge failed: %w", err)
+		}
+		defer cleanup()
+
+		localOutputFile, commit, cleanupOutput, err := localOutput(cmd.Context(), outputFile)
+		if err != nil {
+			return fmt.Errorf("merge failed: %w", err)
+		}
+		defer cleanupOutput()
+
+		fmt.Printf("🔄 Merging %d PDFs -> %s\n", len(inputFiles), redactURI(outputFile))
+
+		if err := pdftool.MergePDFs(cmd.Context(), inputFiles, localOutputFile, mergeOpts); err != nil {
+			return fmt.Errorf("merge failed: %w", err)
+		}
+		if err := commit(); err != nil {
+			return fmt.Errorf("merge failed: %w", err)
+		}
+
+		fmt.Println("✅ PDF merge completed successfully!")
+		return nil
+	},
+}
+
+func init() {
+	mergeCmd.Flags().BoolVar(&mergeOpts.BookmarkPerFile, "bookmark-per-file", false, "Add a bookmark for each input file")
+	mergeCmd.Flags().BoolVar(&mergeOpts.TitlesFromFilenames, "toc-from-filenames", false, "Add a bookmark for each input file titled with its cleaned-up name")
+	mergeCmd.Flags().BoolVar(&mergeOpts.TOC, "toc", false, "Prepend a table of contents listing the input files")
+
+	rootCmd.AddCommand(mergeCmd)
+}
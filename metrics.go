@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// metrics holds the Prometheus metrics of the serve command
+type metrics struct {
+	registry   *prometheus.Registry
+	jobs       *prometheus.CounterVec   // By operation, api and status
+	failures   *prometheus.CounterVec   // By operation and reason
+	duration   *prometheus.HistogramVec // By operation and engine
+	inputBytes *prometheus.CounterVec   // By operation
+	savedBytes *prometheus.CounterVec   // By engine
+}
+
+// newMetrics returns the metrics of a server, including its queue
+func newMetrics(s *server) *metrics {
+	m := &metrics{
+		registry: prometheus.NewRegistry(),
+		jobs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "pdftool_jobs_total",
+			Help: "Compressions, conversions and merges processed, by API and whether they succeeded",
+		}, []string{"operation", "api", "status"}),
+		failures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "pdftool_failures_total",
+			Help: "Failed jobs by the reason they failed",
+		}, []string{"operation", "reason"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "pdftool_job_duration_seconds",
+			Help:    "Time taken by successful jobs; for compressions by the engine used",
+			Buckets: prometheus.ExponentialBuckets(0.05, 2, 12), // 50ms to about 100s
+		}, []string{"operation", "engine"}),
+		inputBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "pdftool_input_bytes_total",
+			Help: "Size of the inputs of successful jobs",
+		}, []string{"operation"}),
+		savedBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "pdftool_saved_bytes_total",
+			Help: "Bytes saved by compressing, by the engine used",
+		}, []string{"engine"}),
+	}
+
+	m.registry.MustRegister(
+		m.jobs, m.failures, m.duration, m.inputBytes, m.savedBytes,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "pdftool_requests_in_progress",
+			Help: "HTTP and gRPC requests being processed",
+		}, func() float64 { return float64(len(s.slots)) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "pdftool_workers",
+			Help: "Requests that can be processed at once",
+		}, func() float64 { return float64(cap(s.slots)) }),
+	)
+	if s.queue != nil {
+		m.registry.MustRegister(&queueCollector{
+			queue: s.queue,
+			desc:  prometheus.NewDesc("pdftool_queue_jobs", "Jobs in the queue by status", []string{"status"}, nil),
+		})
+	}
+	return m
+}
+
+// handler returns the handler of the /metrics endpoint
+func (m *metrics) handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// observe records a job that started at start. result is nil for jobs
+// that failed, and carries the sizes of successful conversions and merges.
+func (m *metrics) observe(operation, api string, start time.Time, result *pdftool.Result, err error) {
+	if err != nil {
+		m.jobs.WithLabelValues(operation, api, "error").Inc()
+		m.failures.WithLabelValues(operation, failureReason(err)).Inc()
+		return
+	}
+	m.jobs.WithLabelValues(operation, api, "ok").Inc()
+
+	elapsed, engine := time.Since(start), ""
+	if result != nil {
+		if result.Duration > 0 {
+			elapsed = result.Duration
+		}
+		engine = result.Engine
+		m.inputBytes.WithLabelValues(operation).Add(float64(result.InputSize))
+		if operation == jobCompress {
+			m.savedBytes.WithLabelValues(engine).Add(float64(max(result.InputSize-result.OutputSize, 0)))
+		}
+	}
+	m.duration.WithLabelValues(operation, engine).Observe(elapsed.Seconds())
+}
+
+// failureReasons names the causes of failed jobs in pdftool_failures_total
+var failureReasons = []struct {
+	err    error
+	reason string
+}{
+	{pdftool.ErrInvalidPDF, "invalid_pdf"},
+	{pdftool.ErrEncrypted, "encrypted"},
+	{pdftool.ErrOutputLarger, "output_larger"},
+	{pdftool.ErrGhostscriptNotFound, "ghostscript_not_found"},
+	{pdftool.ErrPureGo, "pure_go"},
+	{pdftool.ErrTimeout, "timeout"},
+	{context.Canceled, "canceled"},
+}
+
+// failureReason returns the reason label for an error
+func failureReason(err error) string {
+	for _, e := range failureReasons {
+		if errors.Is(err, e.err) {
+			return e.reason
+		}
+	}
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return "too_large"
+	}
+	if st, ok := status.FromError(err); ok {
+		switch st.Code() {
+		case codes.ResourceExhausted:
+			return "too_large"
+		case codes.InvalidArgument:
+			return "bad_request"
+		case codes.Canceled:
+			return "canceled"
+		}
+	}
+	switch statusCode(err) {
+	case http.StatusBadRequest:
+		return "bad_request"
+	case http.StatusUnprocessableEntity:
+		return "invalid_input"
+	}
+	return "other"
+}
+
+// queueCollector reports the jobs in a queue by status
+type queueCollector struct {
+	queue *jobQueue
+	desc  *prometheus.Desc
+}
+
+func (c *queueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *queueCollector) Collect(ch chan<- prometheus.Metric) {
+	counts, err := c.queue.counts()
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.desc, err)
+		return
+	}
+	for _, st := range []string{jobQueued, jobRunning, jobDone, jobFailed, jobCanceled} {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(counts[st]), st)
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+
+	"github.com/spf13/cobra"
+)
+
+var numberOpts pdftool.NumberOptions
+
+var numberCmd = &cobra.Command{
+	Use:   "number [input.pdf] [output.pdf]",
+	Short: "Add page numbers to a PDF",
+	Long: `Stamp page numbers onto every page of a PDF, for example a merged bundle.
+
+The --format template supports {n} for the page number and {total} for the
+last page number. Use --skip-first to leave a cover page unnumbered; numbering
+then starts at --start on the second page.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputFile := args[0]
+		outputFile := args[1]
+
+		fmt.Printf("🔄 Numbering pages: %s -> %s\n", inputFile, outputFile)
+
+		if err := pdftool.NumberPages(cmd.Context(), inputFile, outputFile, numberOpts); err != nil {
+			return fmt.Errorf("page numbering failed: %w", err)
+		}
+
+		fmt.Println("✅ Page numbering completed successfully!")
+		return nil
+	},
+}
+
+func init() {
+	numberCmd.Flags().IntVar(&numberOpts.Start, "start", 1, "Number of the first numbered page")
+	numberCmd.Flags().StringVar(&numberOpts.Format, "format", "{n}", "Page number template with {n} and {total}")
+	numberCmd.Flags().StringVar(&numberOpts.Position, "position", "bottom-center", "Position on the page, e.g. bottom-right or top-center")
+	numberCmd.Flags().BoolVar(&numberOpts.SkipFirst, "skip-first", false, "Leave the first page unnumbered")
+	numberCmd.Flags().StringVar(&numberOpts.Font, "font", "Helvetica", "Font name, e.g. Helvetica, Times-Roman, Courier")
+	numberCmd.Flags().IntVar(&numberOpts.FontSize, "size", 10, "Font size in points")
+	numberCmd.Flags().StringVar(&numberOpts.Color, "color", "#000000", "Text color (#rrggbb)")
+	numberCmd.Flags().Float64Var(&numberOpts.Margin, "margin", 20, "Distance from the page edges in points")
+
+	rootCmd.AddCommand(numberCmd)
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+
+	"github.com/spf13/cobra"
+)
+
+var nupOpts pdftool.NupOptions
+
+var nupGrid string
+
+var nupCmd = &cobra.Command{
+	Use:   "nup [input.pdf] [output.pdf]",
+	Short: "Place multiple pages on each sheet",
+	Long: `Place several pages on each sheet in a grid, filled left to right, top to
+bottom, for handouts and paper-saving printing. --grid 2x1 puts two pages side
+by side, --grid 2x2 four pages per sheet.
+
+Sheets are landscape when the grid has more columns than rows. Pages are
+scaled to fit their cell.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputFile := args[0]
+		outputFile := args[1]
+
+		cols, rows, err := pdftool.ParseGrid(nupGrid)
+		if err != nil {
+			return err
+		}
+		nupOpts.Columns, nupOpts.Rows = cols, rows
+
+		fmt.Printf("🔄 Placing pages %s per sheet: %s -> %s\n", nupGrid, inputFile, outputFile)
+
+		if err := pdftool.NupPages(cmd.Context(), inputFile, outputFile, nupOpts); err != nil {
+			return fmt.Errorf("nup failed: %w", err)
+		}
+
+		fmt.Println("✅ N-up completed successfully!")
+		return nil
+	},
+}
+
+func init() {
+	nupCmd.Flags().StringVar(&nupGrid, "grid", "2x1", "Pages per sheet as COLSxROWS")
+	nupCmd.Flags().StringVar(&nupOpts.Paper, "paper", "A4", "Sheet paper size, e.g. A4, A3, Letter")
+	nupCmd.Flags().BoolVar(&nupOpts.Border, "border", false, "Draw a frame around each page")
+	nupCmd.Flags().Float64Var(&nupOpts.Margin, "margin", 3, "Space around each page in points")
+	nupCmd.Flags().StringVar(&nupOpts.Pages, "pages", "all", "Pages to place, e.g. 1-3,7 or all")
+
+	rootCmd.AddCommand(nupCmd)
+}
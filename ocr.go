@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+
+	"github.com/spf13/cobra"
+)
+
+var ocrOpts pdftool.OCROptions
+
+var ocrCmd = &cobra.Command{
+	Use:   "ocr [input.pdf] [output.pdf]",
+	Short: "Make scanned pages searchable with OCR",
+	Long: `Recognize the text of image-only pages, such as scans, with Tesseract and add
+it as an invisible text layer over the page images. The pages look the same,
+but their text can be searched, selected and copied.
+
+Pages that already have text are an error, so that documents are not OCRed
+twice; use --skip-text-pages to process only the pages without text. Use
+--lang to set the Tesseract language(s), e.g. deu or deu+eng.
+
+Requires Tesseract. Pages are rendered with Ghostscript; without it, only
+scanned pages consisting of a single image can be processed.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputFile := args[0]
+		outputFile := args[1]
+
+		fmt.Printf("🔄 Running OCR: %s -> %s\n", inputFile, outputFile)
+
+		if err := pdftool.OCRPDF(cmd.Context(), inputFile, outputFile, ocrOpts); err != nil {
+			return fmt.Errorf("OCR failed: %w", err)
+		}
+
+		fmt.Println("✅ OCR completed successfully!")
+		return nil
+	},
+}
+
+func init() {
+	ocrCmd.Flags().StringVar(&ocrOpts.Language, "lang", "eng", "Tesseract language(s), e.g. eng or deu+eng")
+	ocrCmd.Flags().BoolVar(&ocrOpts.SkipTextPages, "skip-text-pages", false, "Skip pages that already have text instead of failing")
+	ocrCmd.Flags().IntVar(&ocrOpts.DPI, "dpi", 300, "Rendering resolution for recognition")
+
+	rootCmd.AddCommand(ocrCmd)
+}
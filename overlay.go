@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+
+	"github.com/spf13/cobra"
+)
+
+var overlayOpts pdftool.OverlayOptions
+
+var overlayCmd = &cobra.Command{
+	Use:   "overlay [input.pdf] [overlay.pdf] [output.pdf]",
+	Short: "Place the pages of another PDF over or under PDF pages",
+	Long: `Stamp every page with a page of another PDF, such as a letterhead, a grid or
+a form background. The overlay is scaled to the page width and centered.
+
+Use --under to place it under the page content, as a letterhead should be.
+--overlay-page 0 uses overlay page N for page N and repeats the last overlay
+page, e.g. for a different first page letterhead.`,
+	Args: cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputFile := args[0]
+		overlayFile := args[1]
+		outputFile := args[2]
+
+		fmt.Printf("🔄 Overlaying PDF: %s + %s -> %s\n", inputFile, overlayFile, outputFile)
+
+		if err := pdftool.OverlayPDF(cmd.Context(), inputFile, overlayFile, outputFile, overlayOpts); err != nil {
+			return fmt.Errorf("overlay failed: %w", err)
+		}
+
+		fmt.Println("✅ Overlay completed successfully!")
+		return nil
+	},
+}
+
+func init() {
+	overlayCmd.Flags().BoolVar(&overlayOpts.Under, "under", false, "Place the overlay under the page content")
+	overlayCmd.Flags().IntVar(&overlayOpts.OverlayPage, "overlay-page", 1, "Page of the overlay PDF to use, 0 to match pages")
+	overlayCmd.Flags().StringVar(&overlayOpts.Pages, "pages", "all", "Pages to overlay, e.g. 1-3,7 or all")
+
+	rootCmd.AddCommand(overlayCmd)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+
+	"github.com/spf13/cobra"
+)
+
+var pagesCmd = &cobra.Command{
+	Use:   "pages",
+	Short: "Extract, delete or rearrange PDF pages",
+	Long: `Work with the pages of a PDF.
+
+Page selections are comma-separated pages and ranges, e.g. 1,3-5,10-
+("10-" runs to the last page).`,
+}
+
+var pagesExtractCmd = &cobra.Command{
+	Use:   "extract [input.pdf] [pages] [output.pdf]",
+	Short: "Copy selected pages into a new PDF",
+	Args:  cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputFile, selection, outputFile := args[0], args[1], args[2]
+
+		fmt.Printf("🔄 Extracting pages %s: %s -> %s\n", selection, inputFile, outputFile)
+
+		if err := pdftool.ExtractPages(cmd.Context(), inputFile, selection, outputFile); err != nil {
+			return fmt.Errorf("page extraction failed: %w", err)
+		}
+
+		fmt.Println("✅ Page extraction completed successfully!")
+		return nil
+	},
+}
+
+var pagesDeleteCmd = &cobra.Command{
+	Use:   "delete [input.pdf] [pages] [output.pdf]",
+	Short: "Remove selected pages, e.g. cover sheets or blank pages",
+	Args:  cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputFile, selection, outputFile := args[0], args[1], args[2]
+
+		fmt.Printf("🔄 Deleting pages %s: %s -> %s\n", selection, inputFile, outputFile)
+
+		if err := pdftool.DeletePages(cmd.Context(), inputFile, selection, outputFile); err != nil {
+			return fmt.Errorf("page deletion failed: %w", err)
+		}
+
+		fmt.Println("✅ Page deletion completed successfully!")
+		return nil
+	},
+}
+
+var pagesReverse bool
+
+var pagesReorderCmd = &cobra.Command{
+	Use:   "reorder [input.pdf] [order] [output.pdf]",
+	Short: "Rearrange pages, e.g. 3,1,2,4-",
+	Long: `Rearrange the pages of a PDF. The order must list every page exactly once,
+e.g. 3,1,2,4- moves page 3 to the front.
+
+Use --reverse without an order for documents scanned back-to-front:
+  pdf-tool pages reorder input.pdf output.pdf --reverse`,
+	Args: cobra.RangeArgs(2, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputFile, outputFile := args[0], args[len(args)-1]
+
+		order := ""
+		if len(args) == 3 {
+			order = args[1]
+		}
+		if pagesReverse == (order != "") {
+			return fmt.Errorf("give either a page order or --reverse")
+		}
+
+		fmt.Printf("🔄 Reordering pages: %s -> %s\n", inputFile, outputFile)
+
+		if err := pdftool.ReorderPages(cmd.Context(), inputFile, order, outputFile, pagesReverse); err != nil {
+			return fmt.Errorf("page reordering failed: %w", err)
+		}
+
+		fmt.Println("✅ Page reordering completed successfully!")
+		return nil
+	},
+}
+
+func init() {
+	pagesReorderCmd.Flags().BoolVar(&pagesReverse, "reverse", false, "Reverse the page order")
+
+	pagesCmd.AddCommand(pagesExtractCmd)
+	pagesCmd.AddCommand(pagesDeleteCmd)
+	pagesCmd.AddCommand(pagesReorderCmd)
+
+	rootCmd.AddCommand(pagesCmd)
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	pdfaLevel    string
+	pdfaValidate bool
+)
+
+var pdfaCmd = &cobra.Command{
+	Use:   "pdfa [input.pdf] [output.pdf]",
+	Short: "Convert a PDF to PDF/A for archiving",
+	Long: `Convert a PDF to PDF/A-1b, 2b or 3b for long-term archiving. Fonts are
+embedded, colors are converted to sRGB and the sRGB ICC profile is embedded as
+output intent, along with matching XMP metadata.
+
+With --validate, the result is checked against the main PDF/A requirements
+(metadata, output intent, embedded fonts, forbidden actions, transparency
+for PDF/A-1) and the command fails if any are not met. Use a validator such
+as veraPDF for a certified conformance check.
+
+Uses Ghostscript when available:
+  - Linux: sudo apt install ghostscript
+  - macOS: brew install ghostscript
+  - Windows: Download from ghostscript.com
+
+Without it, PDF/A-2b and 3b are written by adding the output intent and
+metadata only, which conforms when all fonts are already embedded.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputFile := args[0]
+		outputFile := args[1]
+
+		if inputFile == outputFile {
+			return fmt.Errorf("input and output files cannot be the same")
+		}
+
+		part, err := pdftool.ParsePDFALevel(pdfaLevel)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("🔄 Converting PDF to PDF/A-%dB: %s -> %s\n", part, inputFile, outputFile)
+
+		if err := pdftool.ConvertToPDFA(cmd.Context(), inputFile, outputFile, part); err != nil {
+			return fmt.Errorf("PDF/A conversion failed: %w", err)
+		}
+
+		if pdfaValidate {
+			report, err := pdftool.CheckPDFA(cmd.Context(), outputFile, part)
+			if err != nil {
+				return fmt.Errorf("PDF/A validation failed: %w", err)
+			}
+			printPDFAReport(os.Stdout, report)
+			if len(report.Issues) > 0 {
+				return fmt.Errorf("%s does not conform to PDF/A-%dB: %d issues", outputFile, part, len(report.Issues))
+			}
+		}
+
+		fmt.Println("✅ PDF/A conversion completed successfully!")
+		return nil
+	},
+}
+
+func init() {
+	pdfaCmd.Flags().StringVar(&pdfaLevel, "level", "2b", "PDF/A level (1b, 2b, 3b)")
+	pdfaCmd.Flags().BoolVar(&pdfaValidate, "validate", false, "Check the result against the PDF/A requirements")
+	rootCmd.AddCommand(pdfaCmd)
+}
+
+// printPDFAReport writes a conformance report in human-readable form
+func printPDFAReport(w io.Writer, report *pdftool.PDFAReport) {
+	if len(report.Issues) == 0 {
+		fmt.Fprintf(w, "%s meets the checked PDF/A-%dB requirements\n", report.File, report.Part)
+		fmt.Fprintln(w, "Use a validator such as veraPDF for a full conformance check")
+		return
+	}
+
+	fmt.Fprintf(w, "%s does not conform to PDF/A-%dB:\n", report.File, report.Part)
+	for _, issue := range report.Issues {
+		fmt.Fprintf(w, "  - %s\n", issue)
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	permListPassword string
+	permSetOpts      pdftool.PermissionOptions
+)
+
+var permCmd = &cobra.Command{
+	Use:   "perm",
+	Short: "List or change PDF permissions",
+	Long: `Inspect and change the permissions an encrypted PDF grants to readers
+without the owner password.
+
+Permissions: print, modify, copy, annotate, fill, assemble (or all).`,
+}
+
+var permListCmd = &cobra.Command{
+	Use:   "list [input.pdf]",
+	Short: "Show which permissions a PDF grants",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputFile := args[0]
+
+		fmt.Printf("🔍 Permissions of %s:\n", inputFile)
+
+		report, err := pdftool.ListPermissions(cmd.Context(), inputFile, permListPassword)
+		if err != nil {
+			return fmt.Errorf("listing permissions failed: %w", err)
+		}
+
+		printPermissions(os.Stdout, report)
+		return nil
+	},
+}
+
+// printPermissions writes which permissions a PDF grants, one per line
+func printPermissions(w io.Writer, report *pdftool.PermissionReport) {
+	if !report.Encrypted {
+		fmt.Fprintf(w, "%s is not encrypted: all permissions are granted\n", report.File)
+		return
+	}
+	for _, p := range report.Permissions {
+		state := "denied"
+		if p.Allowed {
+			state = "allowed"
+		}
+		fmt.Fprintf(w, "  %-9s %-8s %s\n", p.Name, state, p.Description)
+	}
+}
+
+var permSetCmd = &cobra.Command{
+	Use:   "set [input.pdf] [output.pdf]",
+	Short: "Allow or deny permissions without changing the content",
+	Long: `Allow or deny permissions, e.g. --allow print --deny copy,modify.
+
+Permissions only apply to encrypted PDFs. An unencrypted PDF is encrypted
+with AES-256 using --owner-pass (and --user-pass, if given). Changing an
+encrypted PDF needs both its user and owner passwords.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputFile := args[0]
+		outputFile := args[1]
+
+		fmt.Printf("🔄 Setting permissions: %s -> %s\n", inputFile, outputFile)
+
+		if err := pdftool.SetPermissions(cmd.Context(), inputFile, outputFile, permSetOpts); err != nil {
+			return fmt.Errorf("setting permissions failed: %w", err)
+		}
+
+		fmt.Println("✅ Permissions updated successfully!")
+		return nil
+	},
+}
+
+func init() {
+	permListCmd.Flags().StringVar(&permListPassword, "password", "", "User or owner password")
+
+	permSetCmd.Flags().StringSliceVar(&permSetOpts.Allow, "allow", nil, "Permissions to allow, e.g. print")
+	permSetCmd.Flags().StringSliceVar(&permSetOpts.Deny, "deny", nil, "Permissions to deny, e.g. copy,modify")
+	permSetCmd.Flags().StringVar(&permSetOpts.UserPassword, "user-pass", "", "User password")
+	permSetCmd.Flags().StringVar(&permSetOpts.OwnerPassword, "owner-pass", "", "Owner password (required)")
+
+	permCmd.AddCommand(permListCmd, permSetCmd)
+	rootCmd.AddCommand(permCmd)
+}
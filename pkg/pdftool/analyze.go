@@ -0,0 +1,452 @@
+package pdftool
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// Size categories of a PDF analysis
+const (
+	SizeImages      = "images"
+	SizeFonts       = "fonts"
+	SizeContent     = "content"
+	SizeAttachments = "attachments"
+	SizeMetadata    = "metadata"
+	SizeOther       = "other"     // Other streams, such as ICC profiles
+	SizeStructure   = "structure" // Objects outside of streams, cross-reference data and overhead
+)
+
+// SizeReport breaks the size of a PDF down by what the bytes are spent on
+type SizeReport struct {
+	File       string         `json:"file"`
+	FileSize   int64          `json:"fileSize"`
+	Categories []SizeCategory `json:"categories"`
+	Images     []ImageSize    `json:"images"`
+	Fonts      []FontSize     `json:"fonts"`
+}
+
+// SizeCategory is the number of objects and bytes of one kind of content
+type SizeCategory struct {
+	Name    string `json:"name"`
+	Objects int    `json:"objects"`
+	Bytes   int64  `json:"bytes"`
+}
+
+// ImageSize describes an image and how it is placed
+type ImageSize struct {
+	Object     int     `json:"object"`
+	Pages      []int   `json:"pages"`
+	Width      int     `json:"width"`
+	Height     int     `json:"height"`
+	DPI        float64 `json:"dpi"` // Lowest resolution the image is shown at; 0 if not shown on a page
+	ColorSpace string  `json:"colorSpace"`
+	Filter     string  `json:"filter"`
+	Bytes      int64   `json:"bytes"`
+}
+
+// FontSize describes a font and the size of its embedded font program
+type FontSize struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Embedded bool   `json:"embedded"`
+	Bytes    int64  `json:"bytes"`
+}
+
+// sizeCategories is the order categories are reported in
+var sizeCategories = []string{SizeImages, SizeFonts, SizeContent, SizeAttachments, SizeMetadata, SizeOther, SizeStructure}
+
+// AnalyzeSize reports what the bytes of a PDF are spent on: images, with
+// their resolution on the page, fonts, content streams, attachments and
+// metadata. Streams are counted at their stored, compressed size; shared
+// objects are counted once.
+func AnalyzeSize(ctx context.Context, inputFile string) (*SizeReport, error) {
+	doc, err := readContext(ctx, inputFile)
+	if err != nil {
+		return nil, err
+	}
+	stat, err := os.Stat(inputFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", inputFile, err)
+	}
+
+	a := &sizeAnalyzer{
+		ctx:      doc,
+		category: make(map[int]string),
+		images:   make(map[int]*ImageSize),
+	}
+	if err := a.classify(); err != nil {
+		return nil, err
+	}
+	for page := 1; page <= doc.PageCount; page++ {
+		if err := contextError(ctx); err != nil {
+			return nil, err
+		}
+		if err := a.placeImages(page); err != nil {
+			return nil, fmt.Errorf("failed to read page %d: %w", page, err)
+		}
+	}
+
+	report := &SizeReport{File: inputFile, FileSize: stat.Size(), Images: []ImageSize{}, Fonts: append([]FontSize{}, a.fonts...)}
+	totals := make(map[string]*SizeCategory)
+	for _, name := range sizeCategories {
+		totals[name] = &SizeCategory{Name: name}
+	}
+	var streamBytes int64
+	for objNr, name := range a.category {
+		size := streamSize(doc, objNr)
+		totals[name].Objects++
+		totals[name].Bytes += size
+		streamBytes += size
+	}
+	totals[SizeStructure].Bytes = max(report.FileSize-streamBytes, 0)
+	for _, name := range sizeCategories {
+		report.Categories = append(report.Categories, *totals[name])
+	}
+
+	for _, img := range a.images {
+		sort.Ints(img.Pages)
+		report.Images = append(report.Images, *img)
+	}
+	sort.Slice(report.Images, func(i, j int) bool {
+		if report.Images[i].Bytes != report.Images[j].Bytes {
+			return report.Images[i].Bytes > report.Images[j].Bytes
+		}
+		return report.Images[i].Object < report.Images[j].Object
+	})
+	sort.Slice(report.Fonts, func(i, j int) bool {
+		if report.Fonts[i].Bytes != report.Fonts[j].Bytes {
+			return report.Fonts[i].Bytes > report.Fonts[j].Bytes
+		}
+		return report.Fonts[i].Name < report.Fonts[j].Name
+	})
+
+	return report, nil
+}
+
+// sizeAnalyzer assigns the streams of a PDF to size categories
+type sizeAnalyzer struct {
+	ctx      *model.Context
+	category map[int]string // Category of each stream object
+	images   map[int]*ImageSize
+	fonts    []FontSize
+}
+
+// classify assigns every stream to a category and collects images and fonts
+func (a *sizeAnalyzer) classify() error {
+	ctx := a.ctx
+
+	// Fonts first, as their programs are only recognizable from the font
+	for _, entry := range ctx.Table {
+		if entry == nil || entry.Free {
+			continue
+		}
+		if d, ok := entry.Object.(types.Dict); ok {
+			// CID fonts are counted with the Type0 font they descend from
+			if typ, subtype := d.Type(), d.Subtype(); typ != nil && *typ == "Font" &&
+				(subtype == nil || !strings.HasPrefix(*subtype, "CIDFontType")) {
+				a.addFont(d)
+			}
+		}
+	}
+
+	for objNr, entry := range ctx.Table {
+		if entry == nil || entry.Free || a.category[objNr] != "" {
+			continue
+		}
+		sd, ok := entry.Object.(types.StreamDict)
+		if !ok {
+			continue
+		}
+
+		typ, subtype := "", ""
+		if t := sd.Type(); t != nil {
+			typ = *t
+		}
+		if t := sd.Subtype(); t != nil {
+			subtype = *t
+		}
+
+		switch {
+		case subtype == "Image":
+			a.category[objNr] = SizeImages
+			a.images[objNr] = &ImageSize{
+				Object:     objNr,
+				Pages:      []int{},
+				Width:      intEntry(sd.Dict, "Width"),
+				Height:     intEntry(sd.Dict, "Height"),
+				ColorSpace: a.colorSpaceName(sd.Dict["ColorSpace"]),
+				Filter:     filterNames(sd),
+				Bytes:      streamSize(ctx, objNr),
+			}
+		case typ == "EmbeddedFile":
+			a.category[objNr] = SizeAttachments
+		case typ == "Metadata":
+			a.category[objNr] = SizeMetadata
+		case typ == "ObjStm" || typ == "XRef":
+			a.category[objNr] = SizeStructure
+		case subtype == "Form" || typ == "Pattern" || sd.Dict["PatternType"] != nil:
+			a.category[objNr] = SizeContent
+		}
+	}
+
+	// Page content streams have no type of their own
+	for page := 1; page <= ctx.PageCount; page++ {
+		pageDict, _, _, err := ctx.PageDict(page, false)
+		if err != nil || pageDict == nil {
+			return fmt.Errorf("failed to read page %d: %w", page, err)
+		}
+		contents := types.Array{pageDict["Contents"]}
+		if arr, err := ctx.DereferenceArray(pageDict["Contents"]); err == nil && arr != nil {
+			contents = arr
+		}
+		for _, obj := range contents {
+			if ref, ok := obj.(types.IndirectRef); ok {
+				a.category[ref.ObjectNumber.Value()] = SizeContent
+			}
+		}
+	}
+
+	for objNr, entry := range ctx.Table {
+		if entry == nil || entry.Free || a.category[objNr] != "" {
+			continue
+		}
+		if _, ok := entry.Object.(types.StreamDict); ok {
+			a.category[objNr] = SizeOther
+		}
+	}
+	return nil
+}
+
+// addFont records a font and assigns its font program, glyph procedures and
+// ToUnicode map to the fonts category
+func (a *sizeAnalyzer) addFont(font types.Dict) {
+	f := FontSize{Name: "(unnamed)", Embedded: fontEmbedded(a.ctx, font)}
+	if name := font.NameEntry("BaseFont"); name != nil {
+		f.Name = *name
+	}
+	if subtype := font.Subtype(); subtype != nil {
+		f.Type = *subtype
+	}
+
+	for _, nr := range fontStreams(a.ctx, font) {
+		if a.category[nr] == "" { // Shared font programs are counted once
+			a.category[nr] = SizeFonts
+			f.Bytes += streamSize(a.ctx, nr)
+		}
+	}
+
+	// Fonts used under several resource names are listed once
+	for i := range a.fonts {
+		if a.fonts[i].Name == f.Name && a.fonts[i].Type == f.Type && a.fonts[i].Embedded == f.Embedded {
+			a.fonts[i].Bytes += f.Bytes
+			return
+		}
+	}
+	a.fonts = append(a.fonts, f)
+}
+
+// fontStreams returns the object numbers of the streams belonging to a font:
+// its font program, the glyph procedures of Type3 fonts and its ToUnicode map
+func fontStreams(ctx *model.Context, font types.Dict) []int {
+	streams := []types.Object{font["ToUnicode"]}
+
+	descendant := font
+	if subtype := font.Subtype(); subtype != nil && *subtype == "Type0" {
+		if descendants, err := ctx.DereferenceArray(font["DescendantFonts"]); err == nil && len(descendants) > 0 {
+			if d, err := ctx.DereferenceDict(descendants[0]); err == nil && d != nil {
+				descendant = d
+			}
+		}
+	}
+	if descriptor, err := ctx.DereferenceDict(descendant["FontDescriptor"]); err == nil && descriptor != nil {
+		for _, key := range []string{"FontFile", "FontFile2", "FontFile3"} {
+			streams = append(streams, descriptor[key])
+		}
+	}
+	if procs, err := ctx.DereferenceDict(font["CharProcs"]); err == nil {
+		for _, proc := range procs {
+			streams = append(streams, proc)
+		}
+	}
+
+	var objNrs []int
+	for _, obj := range streams {
+		if ref, ok := obj.(types.IndirectRef); ok {
+			objNrs = append(objNrs, ref.ObjectNumber.Value())
+		}
+	}
+	return objNrs
+}
+
+// streamSize returns the stored size of a stream object
+func streamSize(ctx *model.Context, objNr int) int64 {
+	entry, ok := ctx.Table[objNr]
+	if !ok || entry == nil {
+		return 0
+	}
+	sd, ok := entry.Object.(types.StreamDict)
+	if !ok {
+		return 0
+	}
+	if sd.StreamLength != nil {
+		return *sd.StreamLength
+	}
+	return int64(len(sd.Raw))
+}
+
+// colorSpaceName returns the name of a color space, or its family for
+// color spaces defined by an array such as [/ICCBased 12 0 R]
+func (a *sizeAnalyzer) colorSpaceName(obj types.Object) string {
+	obj, err := a.ctx.Dereference(obj)
+	if err != nil {
+		return ""
+	}
+	switch v := obj.(type) {
+	case types.Name:
+		return string(v)
+	case types.Array:
+		if len(v) > 0 {
+			if name, ok := v[0].(types.Name); ok {
+				return string(name)
+			}
+		}
+	}
+	return ""
+}
+
+// filterNames returns the filters of a stream joined by "+", or "none"
+func filterNames(sd types.StreamDict) string {
+	if len(sd.FilterPipeline) == 0 {
+		return "none"
+	}
+	names := make([]string, len(sd.FilterPipeline))
+	for i, f := range sd.FilterPipeline {
+		names[i] = f.Name
+	}
+	return strings.Join(names, "+")
+}
+
+// intEntry returns an integer entry of a dictionary, or 0
+func intEntry(d types.Dict, key string) int {
+	if v := d.IntEntry(key); v != nil {
+		return *v
+	}
+	return 0
+}
+
+// placeImages follows the content of a page to find the pages images are
+// shown on and the size they are shown at
+func (a *sizeAnalyzer) placeImages(page int) error {
+	pageDict, _, inherited, err := a.ctx.PageDict(page, false)
+	if err != nil || pageDict == nil {
+		return err
+	}
+	content, err := a.ctx.PageContent(pageDict, page)
+	if err != nil {
+		return nil // Pages without content show no images
+	}
+
+	var resources types.Dict
+	if inherited != nil {
+		resources = inherited.Resources
+	}
+	a.runImages(page, content, resources, identity, 0)
+	return nil
+}
+
+// runImages interprets a content stream for the CTM at which images are
+// drawn, following form XObjects
+func (a *sizeAnalyzer) runImages(page int, content []byte, resources types.Dict, ctm matrix, depth int) {
+	lexer := contentLexer{data: content}
+	var operands []any
+	var stack []matrix
+
+	for {
+		value, op, ok := lexer.next()
+		if !ok {
+			return
+		}
+		if op == "" {
+			operands = append(operands, value)
+			continue
+		}
+
+		switch op {
+		case "q":
+			stack = append(stack, ctm)
+		case "Q":
+			if n := len(stack); n > 0 {
+				ctm, stack = stack[n-1], stack[:n-1]
+			}
+		case "cm":
+			if nums := numberOperands(operands); len(nums) == 6 {
+				ctm = matrix(nums).multiply(ctm)
+			}
+		case "Do":
+			if len(operands) == 1 {
+				if name, ok := operands[0].(pdfName); ok {
+					a.drawXObject(page, string(name), resources, ctm, depth)
+				}
+			}
+		}
+		operands = operands[:0]
+	}
+}
+
+// drawXObject records an image drawn with the given CTM, or follows a form
+func (a *sizeAnalyzer) drawXObject(page int, name string, resources types.Dict, ctm matrix, depth int) {
+	xobjects, err := a.ctx.DereferenceDict(resources["XObject"])
+	if err != nil || xobjects == nil {
+		return
+	}
+	ref, ok := xobjects[name].(types.IndirectRef)
+	if !ok {
+		return
+	}
+
+	if img := a.images[ref.ObjectNumber.Value()]; img != nil {
+		if len(img.Pages) == 0 || img.Pages[len(img.Pages)-1] != page {
+			img.Pages = append(img.Pages, page)
+		}
+		// Images fill the unit square, so the CTM scales it to the size shown
+		width, height := math.Hypot(ctm[0], ctm[1]), math.Hypot(ctm[2], ctm[3])
+		if width > 0 && height > 0 {
+			dpi := math.Min(float64(img.Width)/(width/72), float64(img.Height)/(height/72))
+			if img.DPI == 0 || dpi < img.DPI {
+				img.DPI = math.Round(dpi)
+			}
+		}
+		return
+	}
+
+	if depth >= maxFormDepth {
+		return
+	}
+	sd, _, err := a.ctx.DereferenceStreamDict(ref)
+	if err != nil || sd == nil || sd.Subtype() == nil || *sd.Subtype() != "Form" {
+		return
+	}
+	if err := sd.Decode(); err != nil {
+		return
+	}
+
+	formResources := resources
+	if d, err := a.ctx.DereferenceDict(sd.Dict["Resources"]); err == nil && d != nil {
+		formResources = d
+	}
+	if m, err := a.ctx.DereferenceArray(sd.Dict["Matrix"]); err == nil && len(m) == 6 {
+		var fm matrix
+		for i, v := range m {
+			fm[i], _ = numberValue(v)
+		}
+		ctm = fm.multiply(ctm)
+	}
+	a.runImages(page, sd.Content, formResources, ctm, depth+1)
+}
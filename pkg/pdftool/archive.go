@@ -0,0 +1,135 @@
+package pdftool
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// extractImages extracts the supported images of a ZIP archive into dir, in
+// entry order, and returns their paths. Each image keeps its base name and
+// modification time so captions and sorting work as for directories.
+func extractImages(archivePath, dir string) ([]string, error) {
+	archive, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer archive.Close()
+
+	var files []string
+	for _, entry := range archive.File {
+		name := path.Base(entry.Name)
+		if entry.FileInfo().IsDir() || !isImageFile(name) || isHiddenEntry(entry.Name) {
+			continue
+		}
+
+		// Entries in different folders may share a name, so each gets its
+		// own directory; this also keeps entry paths out of the file system
+		target := filepath.Join(dir, fmt.Sprintf("%05d", len(files)), name)
+		if err := extractEntry(entry, target); err != nil {
+			return nil, fmt.Errorf("failed to extract %s: %w", entry.Name, err)
+		}
+		files = append(files, target)
+	}
+
+	return files, nil
+}
+
+// extractEntry writes a single archive entry to target
+func extractEntry(entry *zip.File, target string) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	src, err := entry.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+
+	return os.Chtimes(target, entry.Modified, entry.Modified)
+}
+
+// isHiddenEntry reports whether an archive entry is metadata added by the
+// archiver, such as macOS resource forks
+func isHiddenEntry(name string) bool {
+	for _, part := range strings.Split(name, "/") {
+		if strings.HasPrefix(part, ".") || part == "__MACOSX" {
+			return true
+		}
+	}
+	return false
+}
+
+// writeZip creates a ZIP archive and lets fill add its entries. The archive
+// is removed again if fill fails.
+func writeZip(zipFile string, fill func(zw *zip.Writer) error) error {
+	file, err := os.Create(zipFile)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", zipFile, err)
+	}
+
+	zw := zip.NewWriter(file)
+	err = fill(zw)
+	if closeErr := zw.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to write %s: %w", zipFile, closeErr)
+	}
+	if closeErr := file.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to write %s: %w", zipFile, closeErr)
+	}
+	if err != nil {
+		os.Remove(zipFile)
+		return err
+	}
+	return nil
+}
+
+// createZipEntry starts an archive entry dated now. Already compressed data
+// such as images is better stored than deflated again.
+func createZipEntry(zw *zip.Writer, name string, compress bool) (io.Writer, error) {
+	header := &zip.FileHeader{Name: name, Method: zip.Store, Modified: time.Now()}
+	if compress {
+		header.Method = zip.Deflate
+	}
+	w, err := zw.CreateHeader(header)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add %s to archive: %w", name, err)
+	}
+	return w, nil
+}
+
+// addFileToZip copies a file into an archive entry named after its base name
+func addFileToZip(zw *zip.Writer, file string, compress bool) error {
+	src, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	w, err := createZipEntry(zw, filepath.Base(file), compress)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, src); err != nil {
+		return fmt.Errorf("failed to add %s to archive: %w", filepath.Base(file), err)
+	}
+	return nil
+}
@@ -0,0 +1,347 @@
+package pdftool
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// attachment is a file embedded in a PDF
+type attachment struct {
+	name string
+	data []byte
+}
+
+// ExtractAttachments saves the files embedded in a PDF, such as the XML of an
+// electronic invoice or the documents of a portfolio, to outputDir and returns
+// their paths. Files attached to pages as annotations are included.
+func ExtractAttachments(ctx context.Context, inputFile, outputDir string) ([]string, error) {
+	if err := checkInputFile(inputFile); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(inputFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PDF: %w", err)
+	}
+
+	conf := newConfig()
+	conf.Cmd = model.EXTRACTATTACHMENTS
+
+	doc, err := await(ctx, func() (*model.Context, error) {
+		return api.ReadAndValidate(bytes.NewReader(data), conf)
+	})
+	if err != nil {
+		return nil, readError(err)
+	}
+
+	attachments, err := documentAttachments(doc)
+	if err != nil {
+		return nil, err
+	}
+	if len(attachments) == 0 {
+		logf("No attachments found in %s", inputFile)
+		return nil, nil
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	var outputFiles []string
+	used := make(map[string]bool)
+	for i, a := range attachments {
+		outputFile := filepath.Join(outputDir, attachmentFileName(a.name, i+1, used))
+		if err := os.WriteFile(outputFile, a.data, 0644); err != nil {
+			return outputFiles, fmt.Errorf("failed to write attachment: %w", err)
+		}
+		outputFiles = append(outputFiles, outputFile)
+	}
+
+	logf("Extracted %d attachments from %s", len(outputFiles), inputFile)
+	return outputFiles, nil
+}
+
+// documentAttachments returns the files of the EmbeddedFiles name tree
+// followed by those of file attachment annotations
+func documentAttachments(ctx *model.Context) ([]attachment, error) {
+	var attachments []attachment
+	seen := make(map[types.Object]bool)
+
+	if tree := ctx.Names["EmbeddedFiles"]; tree != nil {
+		err := tree.Process(ctx.XRefTable, func(_ *model.XRefTable, _ string, o *types.Object) error {
+			if ref, ok := (*o).(types.IndirectRef); ok {
+				seen[ref] = true
+			}
+			a, err := fileSpecAttachment(ctx, *o)
+			if err != nil {
+				return err
+			}
+			if a != nil {
+				attachments = append(attachments, *a)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read embedded files: %w", err)
+		}
+	}
+
+	for page := 1; page <= ctx.PageCount; page++ {
+		pageDict, _, _, err := ctx.PageDict(page, false)
+		if err != nil || pageDict == nil {
+			continue
+		}
+		annots, err := ctx.DereferenceArray(pageDict["Annots"])
+		if err != nil {
+			continue
+		}
+
+		for _, annot := range annots {
+			d, err := ctx.DereferenceDict(annot)
+			if err != nil || d == nil {
+				continue
+			}
+			if subtype := d.Subtype(); subtype == nil || *subtype != "FileAttachment" {
+				continue
+			}
+
+			// The same file spec may also be listed in the name tree
+			if ref, ok := d["FS"].(types.IndirectRef); ok {
+				if seen[ref] {
+					continue
+				}
+				seen[ref] = true
+			}
+
+			a, err := fileSpecAttachment(ctx, d["FS"])
+			if err != nil {
+				return nil, fmt.Errorf("failed to read attachment on page %d: %w", page, err)
+			}
+			if a != nil {
+				attachments = append(attachments, *a)
+			}
+		}
+	}
+
+	return attachments, nil
+}
+
+// fileSpecAttachment reads the name and contents of the file embedded in a
+// file specification; it returns nil for references to external files
+func fileSpecAttachment(ctx *model.Context, obj types.Object) (*attachment, error) {
+	d, err := ctx.DereferenceDict(obj)
+	if err != nil || d == nil {
+		return nil, err
+	}
+
+	ef, err := ctx.DereferenceDict(d["EF"])
+	if err != nil || ef == nil {
+		return nil, err
+	}
+
+	var name string
+	for _, key := range []string{"UF", "F"} {
+		if o, found := d.Find(key); found {
+			if name, err = ctx.DereferenceStringOrHexLiteral(o, model.V10, nil); err == nil && name != "" {
+				break
+			}
+		}
+	}
+
+	streamObj := ef["UF"]
+	if streamObj == nil {
+		streamObj = ef["F"]
+	}
+	sd, _, err := ctx.DereferenceStreamDict(streamObj)
+	if err != nil || sd == nil {
+		return nil, err
+	}
+	if sd.FilterPipeline == nil {
+		sd.Content = sd.Raw
+	} else if err := sd.Decode(); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", name, err)
+	}
+
+	return &attachment{name: name, data: sd.Content}, nil
+}
+
+// attachmentFileName returns a safe, unique file name for the n-th
+// attachment. Embedded names may contain directories or be missing.
+func attachmentFileName(name string, n int, used map[string]bool) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	if name == "." || name == "/" || name == ".." {
+		name = "attachment-" + strconv.Itoa(n)
+	}
+
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	for i := 2; used[strings.ToLower(name)]; i++ {
+		name = fmt.Sprintf("%s-%d%s", base, i, ext)
+	}
+	used[strings.ToLower(name)] = true
+	return name
+}
+
+// AttachOptions controls how files are embedded into a PDF. MimeTypes and
+// Descriptions hold either one value for all files or one value per file.
+type AttachOptions struct {
+	MimeTypes    []string // Detected from the file extension when empty
+	Descriptions []string
+	Relationship string // Associated file relationship, e.g. Alternative; empty for none
+}
+
+// afRelationships lists the associated file relationships of PDF/A-3
+var afRelationships = []string{"Source", "Data", "Alternative", "Supplement", "Unspecified"}
+
+// AttachFiles embeds files into a PDF, replacing attachments of the same name.
+// With a relationship the files are also listed as associated files of the
+// document, as ZUGFeRD/Factur-X invoices require for their XML.
+func AttachFiles(ctx context.Context, inputFile, outputFile string, files []string, opts AttachOptions) error {
+	if len(files) == 0 {
+		return fmt.Errorf("no files to attach")
+	}
+	for _, file := range files {
+		if err := checkInputFile(file); err != nil {
+			return err
+		}
+	}
+
+	mimeTypes, err := perFileValues("MIME type", opts.MimeTypes, len(files))
+	if err != nil {
+		return err
+	}
+	descriptions, err := perFileValues("description", opts.Descriptions, len(files))
+	if err != nil {
+		return err
+	}
+
+	relationship := ""
+	if opts.Relationship != "" {
+		for _, r := range afRelationships {
+			if strings.EqualFold(r, opts.Relationship) {
+				relationship = r
+			}
+		}
+		if relationship == "" {
+			return fmt.Errorf("invalid relationship: %s (expected %s)", opts.Relationship, strings.Join(afRelationships, ", "))
+		}
+	}
+
+	doc, err := readContext(ctx, inputFile)
+	if err != nil {
+		return err
+	}
+	if err := doc.LocateNameTree("EmbeddedFiles", true); err != nil {
+		return fmt.Errorf("failed to prepare attachments: %w", err)
+	}
+
+	for i, file := range files {
+		mimeType := mimeTypes[i]
+		if mimeType == "" {
+			mimeType = attachmentMimeType(file)
+		}
+
+		ref, err := embedFile(doc, file, mimeType, descriptions[i], relationship)
+		if err != nil {
+			return err
+		}
+
+		if relationship != "" {
+			rootDict, err := doc.Catalog()
+			if err != nil {
+				return fmt.Errorf("failed to read catalog: %w", err)
+			}
+			af, _ := doc.DereferenceArray(rootDict["AF"])
+			rootDict["AF"] = append(af, *ref)
+		}
+	}
+
+	if err := writeContextFile(ctx, doc, outputFile); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outputFile, err)
+	}
+
+	logf("Attached %d files, wrote %s", len(files), outputFile)
+	return nil
+}
+
+// embedFile adds a file to the EmbeddedFiles name tree and returns a
+// reference to its file specification
+func embedFile(ctx *model.Context, file, mimeType, description, relationship string) (*types.IndirectRef, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", file, err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+
+	streamRef, err := ctx.NewEmbeddedStreamDict(f, info.ModTime())
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed %s: %w", file, err)
+	}
+	sd, _, err := ctx.DereferenceStreamDict(*streamRef)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed %s: %w", file, err)
+	}
+	sd.InsertName("Subtype", mimeType)
+
+	name := filepath.Base(file)
+	fileSpec, err := ctx.NewFileSpecDict(name, name, description, *streamRef)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed %s: %w", file, err)
+	}
+	if relationship != "" {
+		fileSpec.InsertName("AFRelationship", relationship)
+	}
+
+	ref, err := ctx.IndRefForNewObject(fileSpec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed %s: %w", file, err)
+	}
+
+	m := model.NameMap{name: []types.Dict{fileSpec}}
+	if err := ctx.Names["EmbeddedFiles"].Add(ctx.XRefTable, name, *ref, m, []string{"F", "UF"}); err != nil {
+		return nil, fmt.Errorf("failed to embed %s: %w", file, err)
+	}
+	return ref, nil
+}
+
+// attachmentMimeType guesses the MIME type of a file from its extension
+func attachmentMimeType(file string) string {
+	mimeType, _, _ := strings.Cut(mime.TypeByExtension(filepath.Ext(file)), ";")
+	if mimeType == "" {
+		return "application/octet-stream"
+	}
+	return mimeType
+}
+
+// perFileValues spreads a single value over n files or checks that there is
+// one value per file
+func perFileValues(what string, values []string, n int) ([]string, error) {
+	switch len(values) {
+	case 0:
+		return make([]string, n), nil
+	case 1:
+		spread := make([]string, n)
+		for i := range spread {
+			spread[i] = values[0]
+		}
+		return spread, nil
+	case n:
+		return values, nil
+	}
+	return nil, fmt.Errorf("got %d values for %s, expected 1 or one per file (%d)", len(values), what, n)
+}
@@ -0,0 +1,102 @@
+package pdftool
+
+import (
+	"image"
+
+	"github.com/disintegration/imaging"
+)
+
+// autoCropPasses limits how many nested borders are removed, e.g. a dark
+// scanner bed edge followed by a white page margin
+const autoCropPasses = 3
+
+// autoCrop trims uniform borders from an image. A border is the color of the
+// image's corners; rows and columns count as content once more than 0.5% of
+// their pixels differ from it by more than threshold gray levels.
+func autoCrop(img image.Image, threshold uint8) image.Image {
+	for pass := 0; pass < autoCropPasses; pass++ {
+		bounds := contentBounds(toGrayscale(img), threshold)
+		if bounds.Empty() || bounds == img.Bounds() {
+			break
+		}
+		img = imaging.Crop(img, bounds)
+	}
+	return img
+}
+
+// contentBounds returns the area of a grayscale image that is not border
+func contentBounds(gray *image.Gray, threshold uint8) image.Rectangle {
+	b := gray.Bounds()
+	if b.Dx() < 3 || b.Dy() < 3 {
+		return b
+	}
+
+	background := borderLevel(gray)
+	differs := func(x, y int) bool {
+		v := int(gray.GrayAt(x, y).Y)
+		return v > background+int(threshold) || v < background-int(threshold)
+	}
+
+	rowHasContent := func(y int) bool {
+		limit, count := max(1, b.Dx()/200), 0
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if differs(x, y) {
+				if count++; count > limit {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	colHasContent := func(x int, minY, maxY int) bool {
+		limit, count := max(1, (maxY-minY)/200), 0
+		for y := minY; y < maxY; y++ {
+			if differs(x, y) {
+				if count++; count > limit {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	top, bottom := b.Min.Y, b.Max.Y
+	for top < bottom && !rowHasContent(top) {
+		top++
+	}
+	for bottom > top && !rowHasContent(bottom-1) {
+		bottom--
+	}
+	if top == bottom {
+		return b // Blank image; nothing to keep
+	}
+
+	left, right := b.Min.X, b.Max.X
+	for left < right && !colHasContent(left, top, bottom) {
+		left++
+	}
+	for right > left && !colHasContent(right-1, top, bottom) {
+		right--
+	}
+
+	return image.Rect(left, top, right, bottom)
+}
+
+// borderLevel estimates the border gray level as the median of the four corners
+func borderLevel(gray *image.Gray) int {
+	b := gray.Bounds()
+	corners := []int{
+		int(gray.GrayAt(b.Min.X, b.Min.Y).Y),
+		int(gray.GrayAt(b.Max.X-1, b.Min.Y).Y),
+		int(gray.GrayAt(b.Min.X, b.Max.Y-1).Y),
+		int(gray.GrayAt(b.Max.X-1, b.Max.Y-1).Y),
+	}
+
+	// Median of four: average the middle two
+	for i := 1; i < len(corners); i++ {
+		for j := i; j > 0 && corners[j] < corners[j-1]; j-- {
+			corners[j], corners[j-1] = corners[j-1], corners[j]
+		}
+	}
+	return (corners[1] + corners[2]) / 2
+}
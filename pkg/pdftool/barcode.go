@@ -0,0 +1,125 @@
+package pdftool
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"strings"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/code128"
+	"github.com/boombuler/barcode/qr"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// Barcode types
+const (
+	BarcodeQR      = "qr"
+	BarcodeCode128 = "code128"
+)
+
+// BarcodeOptions controls how a QR code or barcode is stamped onto pages
+type BarcodeOptions struct {
+	Data     string  // Text or URL to encode
+	Type     string  // BarcodeQR or BarcodeCode128
+	Size     float64 // Width in points, including the quiet zone
+	Position string  // See stampAnchors
+	Margin   float64 // Distance from the page edges in points
+	Pages    string  // Empty or "all" stamps every page
+}
+
+// Barcode rendering settings
+const (
+	barcodeModulePixels = 8    // Pixels per module, so codes stay sharp when scaled
+	qrQuietZone         = 4    // Modules of white space around a QR code
+	code128QuietZone    = 10   // Modules of white space left and right of a barcode
+	code128HeightRatio  = 0.25 // Bar height relative to the barcode width
+)
+
+// StampBarcode generates a QR code or Code 128 barcode for the data and
+// stamps it onto the selected pages of a PDF, e.g. to add a tracking ID
+func StampBarcode(ctx context.Context, inputFile, outputFile string, opts BarcodeOptions) error {
+	if opts.Data == "" {
+		return fmt.Errorf("no data to encode")
+	}
+	if opts.Size <= 0 {
+		return fmt.Errorf("size must be positive, got: %g", opts.Size)
+	}
+
+	anchor, err := stampAnchor(opts.Position)
+	if err != nil {
+		return err
+	}
+
+	img, err := barcodeImage(opts.Data, opts.Type)
+	if err != nil {
+		return err
+	}
+
+	var encoded bytes.Buffer
+	if err := png.Encode(&encoded, img); err != nil {
+		return fmt.Errorf("failed to encode %s image: %w", opts.Type, err)
+	}
+
+	// Absolute scale factors apply to the image size in pixels, which
+	// pdfcpu takes as points
+	desc := strings.Join([]string{
+		fmt.Sprintf("scalefactor:%g abs", opts.Size/float64(img.Bounds().Dx())),
+		"rotation:0",
+		"position:" + anchor,
+		"offset:" + stampOffset(anchor, opts.Margin),
+	}, ", ")
+
+	wm, err := api.ImageWatermarkForReader(&encoded, desc, true, false, types.POINTS)
+	if err != nil {
+		return fmt.Errorf("invalid stamp: %s", strings.TrimSpace(err.Error()))
+	}
+
+	stamped, total, err := stampPages(ctx, inputFile, outputFile, opts.Pages, wm)
+	if err != nil {
+		return err
+	}
+
+	logf("Stamped %s code onto %d of %d pages, wrote %s", opts.Type, stamped, total, outputFile)
+	return nil
+}
+
+// barcodeImage renders data as a black and white code with its quiet zone
+func barcodeImage(data, typ string) (image.Image, error) {
+	var code barcode.Barcode
+	var err error
+	var quietX, quietY int
+	switch strings.ToLower(typ) {
+	case BarcodeQR:
+		code, err = qr.Encode(data, qr.M, qr.Auto)
+		quietX, quietY = qrQuietZone, qrQuietZone
+	case BarcodeCode128:
+		code, err = code128.Encode(data)
+		quietX = code128QuietZone
+	default:
+		return nil, fmt.Errorf("unsupported barcode type: %s (supported: qr, code128)", typ)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %q as %s: %w", data, typ, err)
+	}
+
+	modules := code.Bounds().Size()
+	width := modules.X * barcodeModulePixels
+	height := modules.Y * barcodeModulePixels
+	if modules.Y == 1 { // 1D codes have no height of their own
+		height = int(float64(width) * code128HeightRatio)
+	}
+	if code, err = barcode.Scale(code, width, height); err != nil {
+		return nil, fmt.Errorf("failed to scale %s: %w", typ, err)
+	}
+
+	canvas := image.NewGray(image.Rect(0, 0, width+2*quietX*barcodeModulePixels, height+2*quietY*barcodeModulePixels))
+	draw.Draw(canvas, canvas.Bounds(), image.White, image.Point{}, draw.Src)
+	at := image.Pt(quietX*barcodeModulePixels, quietY*barcodeModulePixels)
+	draw.Draw(canvas, code.Bounds().Add(at), code, code.Bounds().Min, draw.Src)
+	return canvas, nil
+}
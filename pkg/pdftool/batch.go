@@ -0,0 +1,196 @@
+package pdftool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Job is a file to compress in a Batch
+type Job struct {
+	InputFile  string
+	OutputFile string
+}
+
+// BatchOptions holds settings for Batch
+type BatchOptions struct {
+	Compress CompressOptions // Settings for every job; Progress is not used
+	Workers  int             // Jobs compressed at once; 0 for the number of CPUs
+	Retries  int             // Further attempts for failed jobs, unless retrying cannot help
+	OnResult func(JobResult) // Called as each job finishes, from one goroutine at a time; may be nil
+
+	// Run compresses a job in place of the batch, e.g. to read or write
+	// other storage than local files; may be nil. It passes local files to
+	// compress, which compresses them with the engine the batch selected and
+	// runs the hooks.
+	Run func(ctx context.Context, job Job, compress func(ctx context.Context, inputFile, outputFile string) (*Result, error)) (*Result, error)
+}
+
+// JobResult is the outcome of a Job
+type JobResult struct {
+	Job
+	Result   *Result // nil if the job failed
+	Err      error
+	Attempts int
+}
+
+// BatchResult summarizes a Batch
+type BatchResult struct {
+	Jobs       []JobResult   // In the order of the jobs
+	Succeeded  int           // Jobs compressed
+	Failed     int           // Jobs that failed after all attempts
+	InputSize  int64         // Total input size of the succeeded jobs
+	OutputSize int64         // Total output size of the succeeded jobs
+	Duration   time.Duration // Time taken for the whole batch
+}
+
+// permanentErrors are failures that retrying a job cannot fix
+var permanentErrors = []error{
+	ErrGhostscriptNotFound,
+	ErrPureGo,
+	ErrEncrypted,
+	ErrInvalidPDF,
+	ErrOutputLarger,
+	ErrInvalidOption,
+	context.Canceled,
+}
+
+// Batch compresses many files with a pool of workers, e.g. a directory of
+// scans. Failed jobs are retried up to opts.Retries times and do not stop
+// the others; the result has the outcome of every job and the totals. The
+// error reports how many jobs failed, or that ctx was canceled.
+func Batch(ctx context.Context, jobs []Job, opts BatchOptions) (*BatchResult, error) {
+	start := time.Now()
+	engine, err := compressionEngine(opts.Compress)
+	if err != nil {
+		return nil, err
+	}
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	compressOpts := opts.Compress
+	compressOpts.Progress = nil // Events of concurrent jobs cannot be told apart
+
+	batch := &BatchResult{Jobs: make([]JobResult, len(jobs))}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	queue := make(chan int)
+
+	for range min(workers, len(jobs)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				jobResult := runJob(ctx, jobs[i], engine, compressOpts, opts)
+
+				mu.Lock()
+				batch.Jobs[i] = jobResult
+				if jobResult.Err != nil {
+					batch.Failed++
+				} else {
+					batch.Succeeded++
+					batch.InputSize += jobResult.Result.InputSize
+					batch.OutputSize += jobResult.Result.OutputSize
+				}
+				if opts.OnResult != nil {
+					opts.OnResult(jobResult)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i := range jobs {
+		if ctx.Err() != nil {
+			break
+		}
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+	batch.Duration = time.Since(start)
+
+	if err := ctx.Err(); err != nil {
+		return batch, commandError(ctx, err)
+	}
+	if batch.Failed > 0 {
+		return batch, fmt.Errorf("%d of %d PDFs could not be compressed", batch.Failed, len(jobs))
+	}
+	return batch, nil
+}
+
+// runJob compresses the file of a job, retrying failures that may be
+// transient
+func runJob(ctx context.Context, job Job, engine Engine, compressOpts CompressOptions, opts BatchOptions) JobResult {
+	compress := func(ctx context.Context, inputFile, outputFile string) (*Result, error) {
+		return compressWithHooks(ctx, inputFile, outputFile, engine, compressOpts)
+	}
+
+	jobResult := JobResult{Job: job}
+	for {
+		jobResult.Attempts++
+		if opts.Run != nil {
+			jobResult.Result, jobResult.Err = opts.Run(ctx, job, compress)
+		} else {
+			jobResult.Result, jobResult.Err = compress(ctx, job.InputFile, job.OutputFile)
+		}
+		if jobResult.Err == nil || jobResult.Attempts > opts.Retries || !retryable(ctx, jobResult.Err) {
+			return jobResult
+		}
+
+		// Back off a little, in case the failure was due to load
+		select {
+		case <-ctx.Done():
+			return jobResult
+		case <-time.After(time.Duration(jobResult.Attempts) * time.Second):
+		}
+	}
+}
+
+// retryable reports whether another attempt might fix a failed job
+func retryable(ctx context.Context, err error) bool {
+	return ctx.Err() == nil && !IsPermanent(err)
+}
+
+// IsPermanent reports whether an error is a failure that retrying cannot
+// fix, such as ErrInvalidPDF or ErrGhostscriptNotFound
+func IsPermanent(err error) bool {
+	for _, permanent := range permanentErrors {
+		if errors.Is(err, permanent) {
+			return true
+		}
+	}
+	return false
+}
+
+// DirJobs returns a Job for every PDF directly inside inputDir, writing to
+// a file of the same name in outputDir, which is created if needed
+func DirJobs(inputDir, outputDir string) ([]Job, error) {
+	if filepath.Clean(inputDir) == filepath.Clean(outputDir) {
+		return nil, fmt.Errorf("input and output directories cannot be the same")
+	}
+	files, err := listPDFs(inputDir)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no PDF files found in directory: %s", inputDir)
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	jobs := make([]Job, len(files))
+	for i, file := range files {
+		jobs[i] = Job{InputFile: file, OutputFile: filepath.Join(outputDir, filepath.Base(file))}
+	}
+	return jobs, nil
+}
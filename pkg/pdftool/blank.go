@@ -0,0 +1,104 @@
+package pdftool
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// BlankOptions controls blank page detection
+type BlankOptions struct {
+	Threshold float64 // Fraction of white pixels from which a page counts as blank
+	DPI       int     // Resolution pages are rendered at for the check
+}
+
+// Blank page detection settings
+const (
+	blankWhiteLevel = 220  // Gray level (0-255) from which a pixel counts as white, allowing for paper tone
+	blankEdge       = 0.05 // Fraction of each side ignored, where scans often show dark borders
+)
+
+// RemoveBlankPages renders the pages of a PDF at a low resolution and drops
+// those that are (nearly) blank, such as the empty backs of duplex scans
+func RemoveBlankPages(ctx context.Context, inputFile, outputFile string, opts BlankOptions) error {
+	if opts.Threshold <= 0 || opts.Threshold > 1 {
+		return fmt.Errorf("threshold must be between 0 and 1, got: %g", opts.Threshold)
+	}
+	if opts.DPI <= 0 {
+		return fmt.Errorf("DPI must be positive, got: %d", opts.DPI)
+	}
+	if inputFile == outputFile {
+		return fmt.Errorf("input and output files cannot be the same")
+	}
+
+	doc, err := readContext(ctx, inputFile)
+	if err != nil {
+		return err
+	}
+
+	tmpDir, removeTmpDir, err := TempDir("", "pdf-tool-blank-")
+	if err != nil {
+		return fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer removeTmpDir()
+
+	rendered, err := RasterizePDF(ctx, inputFile, tmpDir, RasterizeOptions{Format: "png", DPI: opts.DPI})
+	if err != nil {
+		return err
+	}
+
+	var kept []int
+	var blank []string
+	for i, file := range rendered {
+		img, err := decodeImage(ctx, diskFS{}, file)
+		if err != nil {
+			return err
+		}
+		if whiteFraction(img) >= opts.Threshold {
+			blank = append(blank, strconv.Itoa(i+1))
+		} else {
+			kept = append(kept, i+1)
+		}
+	}
+
+	if len(blank) == 0 {
+		logf("No blank pages found")
+	}
+	if len(kept) == 0 {
+		return fmt.Errorf("all %d pages are blank", doc.PageCount)
+	}
+
+	if err := writePages(ctx, doc, kept, outputFile); err != nil {
+		return err
+	}
+
+	if len(blank) > 0 {
+		logf("Removed %d blank pages (%s), wrote %s", len(blank), strings.Join(blank, ", "), outputFile)
+	}
+	return nil
+}
+
+// whiteFraction returns the fraction of white pixels of an image, ignoring
+// its edges
+func whiteFraction(img image.Image) float64 {
+	bounds := img.Bounds()
+	dx := int(float64(bounds.Dx()) * blankEdge)
+	dy := int(float64(bounds.Dy()) * blankEdge)
+	inner := image.Rect(bounds.Min.X+dx, bounds.Min.Y+dy, bounds.Max.X-dx, bounds.Max.Y-dy)
+	if inner.Empty() {
+		inner = bounds
+	}
+
+	white := 0
+	for y := inner.Min.Y; y < inner.Max.Y; y++ {
+		for x := inner.Min.X; x < inner.Max.X; x++ {
+			if color.GrayModel.Convert(img.At(x, y)).(color.Gray).Y >= blankWhiteLevel {
+				white++
+			}
+		}
+	}
+	return float64(white) / float64(inner.Dx()*inner.Dy())
+}
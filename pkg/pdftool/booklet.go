@@ -0,0 +1,96 @@
+package pdftool
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// BookletOptions controls booklet imposition
+type BookletOptions struct {
+	Sheet  string  // Paper size of the printed sheets, used in landscape
+	Margin float64 // Space around each page in points
+}
+
+// MakeBooklet imposes a PDF for saddle-stitch printing: pages are placed two
+// per side on landscape sheets so that the sheets, printed double-sided
+// (flipped on the short edge), stacked and folded in the middle, read in
+// order. Blank pages are added at the end to fill the last sheet.
+func MakeBooklet(ctx context.Context, inputFile, outputFile string, opts BookletOptions) error {
+	if opts.Margin < 0 {
+		return fmt.Errorf("margin must not be negative")
+	}
+
+	name, dim, ok := paperSize(opts.Sheet)
+	if !ok {
+		return fmt.Errorf("unknown paper size: %s (e.g. A4, A3, Letter, Legal)", opts.Sheet)
+	}
+	if dim.Width < dim.Height {
+		dim.Width, dim.Height = dim.Height, dim.Width
+	}
+
+	doc, err := readContext(ctx, inputFile)
+	if err != nil {
+		return err
+	}
+
+	// Each sheet holds four pages, two on each side
+	pageCount := doc.PageCount
+	padded := (pageCount + 3) / 4 * 4
+	for page := pageCount; page < padded; page++ {
+		if err := doc.InsertBlankPages(types.IntSet{page: true}, nil, false); err != nil {
+			return fmt.Errorf("failed to add blank pages: %w", err)
+		}
+	}
+	doc.PageCount = padded
+
+	booklet, err := pdfcpu.ExtractPages(doc, bookletOrder(padded), false)
+	if err != nil {
+		return fmt.Errorf("failed to reorder pages: %w", err)
+	}
+	if err := booklet.EnsurePageCount(); err != nil {
+		return err
+	}
+
+	nup := model.DefaultNUpConfig()
+	nup.InpUnit = types.POINTS
+	nup.PageSize = name
+	nup.PageDim = dim
+	nup.UserDim = true
+	nup.Grid = &types.Dim{Width: 2, Height: 1}
+	nup.Border = false
+	nup.Margin = opts.Margin
+
+	pages := make([]int, padded)
+	for i := range pages {
+		pages[i] = i + 1
+	}
+	if err := pdfcpu.NUpFromPDF(booklet, pageSet(pages), nup); err != nil {
+		return fmt.Errorf("failed to place pages: %w", err)
+	}
+
+	if err := writeContextFile(ctx, booklet, outputFile); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outputFile, err)
+	}
+
+	logf("Imposed %d pages (%d blank) on %d sheets, wrote %s", pageCount, padded-pageCount, padded/4, outputFile)
+	return nil
+}
+
+// bookletOrder returns the page order for saddle-stitch imposition of a page
+// count that is a multiple of four, left and right page of each sheet side in
+// turn, working from the outermost sheet inwards: 8,1,2,7,6,3,4,5 for eight
+// pages
+func bookletOrder(pageCount int) []int {
+	order := make([]int, 0, pageCount)
+	for sheet := 0; sheet < pageCount/4; sheet++ {
+		order = append(order,
+			pageCount-2*sheet, 2*sheet+1, // Front: left, right
+			2*sheet+2, pageCount-2*sheet-1, // Back: left, right
+		)
+	}
+	return order
+}
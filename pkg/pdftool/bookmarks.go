@@ -0,0 +1,104 @@
+package pdftool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+)
+
+// ExportBookmarks writes the outline of a PDF to a JSON file. The format is
+// the one of pdfcpu's bookmarks export: a header and a "bookmarks" list of
+// entries with "title", "page" and optional "kids", "bold", "italic" and
+// "color".
+func ExportBookmarks(ctx context.Context, inputFile, jsonFile string) error {
+	doc, err := readContext(ctx, inputFile)
+	if err != nil {
+		return err
+	}
+
+	tree, err := pdfcpu.ExportBookmarks(doc, filepath.Base(inputFile))
+	if err != nil {
+		return fmt.Errorf("failed to read bookmarks: %w", err)
+	}
+	if tree == nil {
+		return fmt.Errorf("no bookmarks found in %s", inputFile)
+	}
+
+	data, err := json.MarshalIndent(tree, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode bookmarks: %w", err)
+	}
+	if err := os.WriteFile(jsonFile, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", jsonFile, err)
+	}
+
+	logf("Exported %d bookmarks to %s", countBookmarks(tree.Bookmarks), jsonFile)
+	return nil
+}
+
+// ImportBookmarks replaces the outline of a PDF with the bookmarks of a JSON
+// file in the format written by ExportBookmarks
+func ImportBookmarks(ctx context.Context, inputFile, jsonFile, outputFile string) error {
+	data, err := os.ReadFile(jsonFile)
+	if err != nil {
+		return fmt.Errorf("failed to read bookmark file: %w", err)
+	}
+
+	var tree pdfcpu.BookmarkTree
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return fmt.Errorf("invalid bookmark file %s: %w", jsonFile, err)
+	}
+	if len(tree.Bookmarks) == 0 {
+		return fmt.Errorf("no bookmarks found in %s", jsonFile)
+	}
+
+	doc, err := readContext(ctx, inputFile)
+	if err != nil {
+		return err
+	}
+
+	if err := checkBookmarkPages(tree.Bookmarks, doc.PageCount); err != nil {
+		return err
+	}
+
+	if err := pdfcpu.AddBookmarks(doc, tree.Bookmarks, true); err != nil {
+		return fmt.Errorf("failed to add bookmarks: %w", err)
+	}
+
+	if err := writeContextFile(ctx, doc, outputFile); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outputFile, err)
+	}
+
+	logf("Imported %d bookmarks, wrote %s", countBookmarks(tree.Bookmarks), outputFile)
+	return nil
+}
+
+// checkBookmarkPages verifies that every bookmark has a title and points to
+// an existing page
+func checkBookmarkPages(bookmarks []pdfcpu.Bookmark, pageCount int) error {
+	for _, bm := range bookmarks {
+		if bm.Title == "" {
+			return fmt.Errorf("bookmark on page %d has no title", bm.PageFrom)
+		}
+		if bm.PageFrom < 1 || bm.PageFrom > pageCount {
+			return fmt.Errorf("bookmark %q points to page %d, but the document has %d pages", bm.Title, bm.PageFrom, pageCount)
+		}
+		if err := checkBookmarkPages(bm.Kids, pageCount); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// countBookmarks returns the number of bookmarks including nested ones
+func countBookmarks(bookmarks []pdfcpu.Bookmark) int {
+	n := len(bookmarks)
+	for _, bm := range bookmarks {
+		n += countBookmarks(bm.Kids)
+	}
+	return n
+}
@@ -0,0 +1,34 @@
+package pdftool
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// captionText expands a caption template for an image. Supported placeholders:
+//
+//	{filename}  file name with extension
+//	{name}      file name without extension
+//	{index}     position of the image in the document, starting at 1
+//	{count}     number of images in the document
+//	{date}      EXIF capture date or file modification date (YYYY-MM-DD)
+func captionText(fsys fs.FS, template, file string, index, count int) string {
+	base := filepath.Base(file)
+
+	date := ""
+	if strings.Contains(template, "{date}") {
+		if t := fileTime(fsys, file, true); !t.IsZero() {
+			date = t.Format("2006-01-02")
+		}
+	}
+
+	return strings.NewReplacer(
+		"{filename}", base,
+		"{name}", strings.TrimSuffix(base, filepath.Ext(base)),
+		"{index}", strconv.Itoa(index),
+		"{count}", strconv.Itoa(count),
+		"{date}", date,
+	).Replace(template)
+}
@@ -0,0 +1,125 @@
+package pdftool
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// commandEngine runs an external program built from a command template
+type commandEngine struct {
+	name string
+	args []string // Program and arguments, with placeholders
+}
+
+// NewCommandEngine returns an engine that runs a command template like
+// "my-compressor {in} {out} --q {quality}", for optimizers the library has
+// no built-in support for. The placeholders {in} and {out} are required;
+// {quality} is replaced with the quality percentage and {dpi} with the
+// image resolution. Only templates with {dpi} accept a DPI setting.
+// Arguments containing spaces can be quoted with ' or ".
+func NewCommandEngine(name, template string) (Engine, error) {
+	if name == "" || name == EngineAuto || strings.Contains(name, ",") {
+		return nil, fmt.Errorf("invalid engine name: %q", name)
+	}
+	args, err := splitCommand(template)
+	if err != nil {
+		return nil, fmt.Errorf("invalid command for engine %s: %w", name, err)
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("invalid command for engine %s: empty", name)
+	}
+	for _, placeholder := range []string{"{in}", "{out}"} {
+		if !strings.Contains(template, placeholder) {
+			return nil, fmt.Errorf("invalid command for engine %s: missing %s", name, placeholder)
+		}
+	}
+	return commandEngine{name: name, args: args}, nil
+}
+
+func (e commandEngine) Name() string { return e.name }
+
+func (e commandEngine) Available() bool {
+	_, err := lookPath(e.args[0])
+	return err == nil
+}
+
+func (e commandEngine) DownsamplesImages() bool {
+	for _, arg := range e.args {
+		if strings.Contains(arg, "{dpi}") {
+			return true
+		}
+	}
+	return false
+}
+
+func (e commandEngine) Compress(ctx context.Context, job CompressJob) error {
+	_, dpi := getGhostscriptSettings(job.Quality)
+	if job.ImageDPI > 0 {
+		dpi = job.ImageDPI
+	}
+	replacer := strings.NewReplacer(
+		"{in}", job.InputFile,
+		"{out}", job.OutputFile,
+		"{quality}", strconv.Itoa(job.Quality),
+		"{dpi}", strconv.Itoa(dpi),
+	)
+
+	args := make([]string, len(e.args))
+	for i, arg := range e.args {
+		args[i] = replacer.Replace(arg)
+	}
+
+	cmd := command(ctx, args[0], args[1:]...)
+	cmd.Stdout = os.Stderr // Keep stdout for the caller's own output
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s compression failed: %w", e.name, commandError(ctx, err))
+	}
+	if _, err := os.Stat(job.OutputFile); err != nil {
+		return fmt.Errorf("%s compression failed: no output written", e.name)
+	}
+	return nil
+}
+
+// splitCommand splits a command line into arguments at spaces outside of
+// single or double quotes
+func splitCommand(command string) ([]string, error) {
+	var args []string
+	var arg strings.Builder
+	inArg := false
+	var quote rune
+
+	for _, r := range command {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				arg.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args, nil
+}
@@ -0,0 +1,481 @@
+package pdftool
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+// Compression engines
+const (
+	EngineAuto        = "auto"        // The first installed of Ghostscript, mutool and qpdf, pdfcpu otherwise
+	EngineGhostscript = "ghostscript" // Re-distills pages and downsamples images
+	EnginePdfcpu      = "pdfcpu"      // Optimizes the file structure only
+	EngineQpdf        = "qpdf"        // Recompresses streams and packs objects into object streams
+	EngineMutool      = "mutool"      // Removes unused and duplicate objects and compresses streams
+)
+
+// CompressOptions holds settings for compressing a PDF
+type CompressOptions struct {
+	Quality        int             // Quality percentage from 1 (smallest) to 100 (best)
+	Engine         string          // A registered engine, EngineAuto (default), or a fallback chain like "qpdf,pdfcpu"
+	ImageDPI       int             // Resolution images are downsampled to, overriding the quality preset; Ghostscript only
+	StripMetadata  bool            // Remove the document information and XMP metadata
+	Encrypt        *EncryptOptions // Encrypt the result, if set
+	Timeout        time.Duration   // Abort after this long; 0 for no limit
+	RequireSmaller bool            // Fail with ErrOutputLarger instead of writing an output that is not smaller
+	Progress       ProgressFunc    // Called as compression advances; may be nil
+	Logger         *slog.Logger    // Receives the engine choice; nil for the logger set with SetLogger
+	PreHook        Hook            // Runs before CompressPDF compresses; an error aborts it
+	PostHook       Hook            // Runs after CompressPDF wrote the output, with the Result
+}
+
+// Result describes a finished compression
+type Result struct {
+	InputSize  int64         // Size of the input in bytes
+	OutputSize int64         // Size of the output in bytes, after stripping metadata and encrypting
+	Ratio      float64       // OutputSize divided by InputSize, e.g. 0.4 when 60% were saved
+	Engine     string        // Name of the engine that compressed
+	Duration   time.Duration // Time taken, including verification
+	Warnings   []string      // Problems that did not stop the compression
+}
+
+// newResult returns the result of compressing inputSize to outputSize bytes
+// with an engine, noting settings it ignored
+func newResult(engine Engine, opts CompressOptions, inputSize, outputSize int64, start time.Time) *Result {
+	result := &Result{
+		InputSize:  inputSize,
+		OutputSize: outputSize,
+		Engine:     engine.Name(),
+		Duration:   time.Since(start),
+	}
+	if inputSize > 0 {
+		result.Ratio = float64(outputSize) / float64(inputSize)
+	}
+
+	if opts.ImageDPI > 0 && !downsamplesImages(engine) {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("%s does not downsample images; the DPI setting was ignored", engine.Name()))
+	}
+	if outputSize >= inputSize {
+		result.Warnings = append(result.Warnings, "Output file is not smaller than input")
+	}
+	return result
+}
+
+// logger returns the logger for messages about a compression
+func (opts CompressOptions) logger() *slog.Logger {
+	if opts.Logger != nil {
+		return opts.Logger
+	}
+	return defaultLogger.Load()
+}
+
+// CompressPDF compresses a PDF file with the settings in opts and returns
+// the sizes and engine used
+func CompressPDF(ctx context.Context, inputFile, outputFile string, opts CompressOptions) (*Result, error) {
+	// Check if input file exists
+	if _, err := os.Stat(inputFile); os.IsNotExist(err) {
+		return nil, fmt.Errorf("input file does not exist: %s", inputFile)
+	}
+	engine, err := compressionEngine(opts)
+	if err != nil {
+		return nil, err
+	}
+	return compressWithHooks(ctx, inputFile, outputFile, engine, opts)
+}
+
+// compressWithHooks compresses a PDF file with an engine chosen by
+// compressionEngine, running the hooks in opts around it
+func compressWithHooks(ctx context.Context, inputFile, outputFile string, engine Engine, opts CompressOptions) (*Result, error) {
+	event := HookEvent{InputFile: inputFile, OutputFile: outputFile}
+	if err := opts.PreHook.run(ctx, "pre-hook", event); err != nil {
+		return nil, err
+	}
+	result, err := compressFile(ctx, inputFile, outputFile, engine, opts)
+	if err != nil {
+		return nil, err
+	}
+	event.Result = result
+	if err := opts.PostHook.run(ctx, "post-hook", event); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// compressFile compresses a PDF file with an engine chosen by
+// compressionEngine
+func compressFile(ctx context.Context, inputFile, outputFile string, engine Engine, opts CompressOptions) (*Result, error) {
+	start := time.Now()
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	inputInfo, err := os.Stat(inputFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get input file info: %w", err)
+	}
+
+	// The page count is only needed for progress; Ghostscript may still
+	// handle files pdfcpu cannot read
+	pageCount, _ := api.PageCountFile(inputFile)
+	opts.Progress.report(ProgressEvent{Stage: StageAnalyze, Pages: pageCount})
+
+	opts.Progress.report(ProgressEvent{Stage: StageCompress, Pages: pageCount})
+	err = engine.Compress(ctx, CompressJob{
+		InputFile:  inputFile,
+		OutputFile: outputFile,
+		Quality:    opts.Quality,
+		ImageDPI:   opts.ImageDPI,
+		Pages:      pageCount,
+		Progress:   opts.Progress,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := os.ReadFile(outputFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read compressed PDF: %w", err)
+	}
+	finished, err := finishCompressed(output, pageCount, opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts.RequireSmaller && int64(len(finished)) >= inputInfo.Size() {
+		os.Remove(outputFile)
+		return nil, outputLargerError(inputInfo.Size(), int64(len(finished)))
+	}
+	if !bytes.Equal(finished, output) {
+		if err := os.WriteFile(outputFile, finished, 0644); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", outputFile, err)
+		}
+	}
+
+	return newResult(engine, opts, inputInfo.Size(), int64(len(finished)), start), nil
+}
+
+// Compress compresses a PDF read from r and writes the result to w, e.g. to
+// process an upload without choosing file paths. Engines work on files, so
+// the input and output are spooled to a temporary directory; only pdfcpu
+// optimizes in memory.
+func Compress(ctx context.Context, r io.Reader, w io.Writer, opts CompressOptions) (*Result, error) {
+	start := time.Now()
+	engine, err := compressionEngine(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := engine.(pdfcpuEngine); !ok {
+		var result *Result
+		err := withSpooledFiles(r, w, "pdf-tool-compress-", func(inputFile, outputFile string) error {
+			var err error
+			result, err = compressFile(ctx, inputFile, outputFile, engine, opts)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read input: %w", err)
+	}
+	pageCount, _ := api.PageCount(bytes.NewReader(data), newConfig())
+	opts.Progress.report(ProgressEvent{Stage: StageAnalyze, Pages: pageCount})
+
+	opts.Progress.report(ProgressEvent{Stage: StageCompress, Pages: pageCount})
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+	output, err := await(ctx, func() ([]byte, error) {
+		var out bytes.Buffer
+		err := api.Optimize(bytes.NewReader(data), &out, pdfcpuCompressionConfig(opts.Quality))
+		return out.Bytes(), err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("pdfcpu optimization failed: %w", readError(err))
+	}
+	finished, err := finishCompressed(output, pageCount, opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts.RequireSmaller && len(finished) >= len(data) {
+		return nil, outputLargerError(int64(len(data)), int64(len(finished)))
+	}
+
+	if _, err := w.Write(finished); err != nil {
+		return nil, fmt.Errorf("failed to write output: %w", err)
+	}
+	return newResult(engine, opts, int64(len(data)), int64(len(finished)), start), nil
+}
+
+// CompressFS compresses the PDF at name in fsys, e.g. an embed.FS or a
+// *zip.Reader, and writes the result to w
+func CompressFS(ctx context.Context, fsys fs.FS, name string, w io.Writer, opts CompressOptions) (*Result, error) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	defer file.Close()
+
+	return Compress(ctx, file, w, opts)
+}
+
+// compressionEngine checks the options and returns the engine to compress
+// with, announcing the choice
+func compressionEngine(opts CompressOptions) (Engine, error) {
+	if opts.Quality < 1 || opts.Quality > 100 {
+		return nil, fmt.Errorf("%w: quality must be between 1 and 100, got: %d", ErrInvalidOption, opts.Quality)
+	}
+	if opts.ImageDPI < 0 {
+		return nil, fmt.Errorf("%w: DPI must be positive, got: %d", ErrInvalidOption, opts.ImageDPI)
+	}
+	return selectEngine(opts)
+}
+
+// finishCompressed applies the metadata and encryption settings to a
+// compressed PDF and verifies it before it is encrypted
+func finishCompressed(output []byte, pageCount int, opts CompressOptions) ([]byte, error) {
+	if opts.StripMetadata {
+		ctx, err := api.ReadValidateAndOptimize(bytes.NewReader(output), newConfig())
+		if err != nil {
+			return nil, fmt.Errorf("compressed PDF is unreadable: %w", err)
+		}
+		if err := stripMetadata(ctx); err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		if err := api.WriteContext(ctx, &buf); err != nil {
+			return nil, fmt.Errorf("failed to write PDF: %w", err)
+		}
+		output = buf.Bytes()
+	}
+
+	if err := verifyCompressed(bytes.NewReader(output), pageCount, opts.Progress); err != nil {
+		return nil, err
+	}
+
+	if opts.Encrypt != nil {
+		conf, err := encryptConfig(*opts.Encrypt)
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		if err := api.Encrypt(bytes.NewReader(output), &buf, conf); err != nil {
+			return nil, fmt.Errorf("failed to encrypt PDF: %w", err)
+		}
+		output = buf.Bytes()
+	}
+	return output, nil
+}
+
+// outputLargerError returns ErrOutputLarger with both sizes
+func outputLargerError(inputSize, outputSize int64) error {
+	return fmt.Errorf("%w (%s, input %s)", ErrOutputLarger, FormatSize(outputSize), FormatSize(inputSize))
+}
+
+// verifyCompressed checks that a compressed PDF can be read and, if the page
+// count of the input is known, still has all pages
+func verifyCompressed(output io.ReadSeeker, pageCount int, progress ProgressFunc) error {
+	n, err := api.PageCount(output, newConfig())
+	if err != nil {
+		return fmt.Errorf("compressed PDF is unreadable: %w", err)
+	}
+	if pageCount > 0 && n != pageCount {
+		return fmt.Errorf("compressed PDF has %d pages instead of %d", n, pageCount)
+	}
+
+	size, err := output.Seek(0, io.SeekEnd)
+	if err != nil {
+		return fmt.Errorf("failed to read compressed PDF: %w", err)
+	}
+
+	progress.report(ProgressEvent{Stage: StageVerify, Page: n, Pages: n, BytesWritten: size})
+	return nil
+}
+
+// isGhostscriptAvailable checks if Ghostscript is installed
+func isGhostscriptAvailable() bool {
+	_, err := lookPath(ghostscriptCommand())
+	return err == nil
+}
+
+// ghostscriptCommand returns the name of the Ghostscript executable
+func ghostscriptCommand() string {
+	if runtime.GOOS != "windows" {
+		return "gs"
+	}
+
+	// Try 64-bit version first, then 32-bit
+	if _, err := lookPath("gswin64c"); err == nil {
+		return "gswin64c"
+	}
+	return "gswin32c"
+}
+
+// ghostscriptEngine compresses with Ghostscript, re-distilling the pages
+type ghostscriptEngine struct{}
+
+func (ghostscriptEngine) Name() string            { return EngineGhostscript }
+func (ghostscriptEngine) Available() bool         { return isGhostscriptAvailable() }
+func (ghostscriptEngine) DownsamplesImages() bool { return true }
+
+func (ghostscriptEngine) Compress(ctx context.Context, job CompressJob) error {
+	return compressWithGhostscript(ctx, job)
+}
+
+// compressWithGhostscript uses Ghostscript for effective PDF compression
+// and reports the pages it processes to job.Progress, if set
+func compressWithGhostscript(ctx context.Context, job CompressJob) error {
+	if err := checkGhostscriptDevice("pdfwrite"); err != nil {
+		return err
+	}
+	cmd := ghostscriptCommand()
+	inputFile, outputFile := job.InputFile, job.OutputFile
+
+	// Get quality settings based on percentage
+	pdfSettings, imageRes := getGhostscriptSettings(job.Quality)
+	if job.ImageDPI > 0 {
+		imageRes = job.ImageDPI
+	}
+
+	// Build Ghostscript command
+	args := []string{
+		"-dNOPAUSE",                           // Don't pause between pages
+		"-dBATCH",                             // Exit after processing
+		"-dSAFER",                             // Restrict file operations
+		"-sDEVICE=pdfwrite",                   // Output device
+		"-dCompatibilityLevel=1.4",            // PDF version
+		"-dPDFSETTINGS=" + pdfSettings,        // Compression preset
+		"-dEmbedAllFonts=true",                // Embed fonts
+		"-dSubsetFonts=true",                  // Subset fonts
+		"-dColorImageDownsampleType=/Bicubic", // Color image resampling
+		"-dColorImageResolution=" + fmt.Sprintf("%d", imageRes),
+		"-dGrayImageDownsampleType=/Bicubic", // Grayscale image resampling
+		"-dGrayImageResolution=" + fmt.Sprintf("%d", imageRes),
+		"-dMonoImageDownsampleType=/Bicubic", // Monochrome image resampling
+		"-dMonoImageResolution=" + fmt.Sprintf("%d", imageRes),
+		"-sOutputFile=" + outputFile, // Output file
+		inputFile,                    // Input file
+	}
+
+	// Ghostscript announces each page on stdout unless in quiet mode
+	progress := job.Progress
+	if progress == nil {
+		args = append([]string{"-q"}, args...)
+	}
+
+	// Execute Ghostscript
+	gsCmd := command(ctx, cmd, args...)
+	gsCmd.Stderr = os.Stderr
+
+	var stdout io.Reader
+	if progress != nil {
+		pipe, err := gsCmd.StdoutPipe()
+		if err != nil {
+			return fmt.Errorf("failed to start ghostscript: %w", err)
+		}
+		stdout = pipe
+	}
+	if err := gsCmd.Start(); err != nil {
+		return fmt.Errorf("failed to start ghostscript: %w", err)
+	}
+	if stdout != nil {
+		reportGhostscriptPages(stdout, outputFile, job.Pages, progress)
+	}
+
+	if err := gsCmd.Wait(); err != nil {
+		return fmt.Errorf("ghostscript compression failed: %w", commandError(ctx, err))
+	}
+	return nil
+}
+
+// reportGhostscriptPages reads Ghostscript's "Page N" messages until it exits
+// and reports each page along with the size of the output so far
+func reportGhostscriptPages(stdout io.Reader, outputFile string, pageCount int, progress ProgressFunc) {
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		page, err := strconv.Atoi(strings.TrimPrefix(scanner.Text(), "Page "))
+		if err != nil {
+			continue // Banner and other messages
+		}
+
+		event := ProgressEvent{Stage: StageCompress, Page: page, Pages: pageCount}
+		if info, err := os.Stat(outputFile); err == nil {
+			event.BytesWritten = info.Size()
+		}
+		progress.report(event)
+	}
+	io.Copy(io.Discard, stdout) // Don't block Ghostscript if scanning stopped early
+}
+
+// getGhostscriptSettings returns appropriate settings based on quality percentage
+func getGhostscriptSettings(quality int) (string, int) {
+	switch {
+	case quality <= 25:
+		return "/screen", 72 // Maximum compression, lowest quality
+	case quality <= 50:
+		return "/ebook", 150 // High compression, medium-low quality
+	case quality <= 75:
+		return "/printer", 300 // Medium compression, good quality
+	default:
+		return "/prepress", 300 // Light compression, highest quality
+	}
+}
+
+// pdfcpuEngine optimizes the file structure with pdfcpu, which needs no
+// external programs
+type pdfcpuEngine struct{}
+
+func (pdfcpuEngine) Name() string    { return EnginePdfcpu }
+func (pdfcpuEngine) Available() bool { return true }
+
+func (pdfcpuEngine) Compress(ctx context.Context, job CompressJob) error {
+	return compressWithPdfcpu(ctx, job.InputFile, job.OutputFile, job.Quality)
+}
+
+// compressWithPdfcpu provides basic PDF optimization using pdfcpu
+func compressWithPdfcpu(ctx context.Context, inputFile, outputFile string, quality int) error {
+	err := transformFile(ctx, inputFile, outputFile, func(rs io.ReadSeeker, w io.Writer) error {
+		return api.Optimize(rs, w, pdfcpuCompressionConfig(quality))
+	})
+	if err != nil {
+		return fmt.Errorf("pdfcpu optimization failed: %w", readError(err))
+	}
+	return nil
+}
+
+// pdfcpuCompressionConfig returns the pdfcpu configuration for a quality
+// percentage
+func pdfcpuCompressionConfig(quality int) *model.Configuration {
+	config := model.NewDefaultConfiguration()
+	config.ValidationMode = model.ValidationRelaxed
+
+	// Enable compression features based on quality
+	if quality < 50 {
+		config.WriteObjectStream = true
+		config.WriteXRefStream = true
+	} else if quality < 80 {
+		config.WriteObjectStream = true
+	}
+
+	return config
+}
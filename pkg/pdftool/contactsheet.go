@@ -0,0 +1,105 @@
+package pdftool
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+)
+
+// ContactSheetOptions holds settings for contact sheet generation
+type ContactSheetOptions struct {
+	Columns int
+	Rows    int
+
+	// Caption template under each thumbnail (see captionText); defaults to
+	// "{filename}" for images and "Page {index}" for PDF pages
+	Caption string
+
+	Sort          string // Order of images read from directories and archives
+	ThumbnailSize int    // Longer thumbnail side in pixels
+	DPI           int    // Resolution for rendering PDF pages
+}
+
+// CreateContactSheet lays out thumbnails of images, image directories or the
+// pages of a single PDF in a captioned grid for quick review
+func CreateContactSheet(ctx context.Context, inputs []string, outputFile string, opts ContactSheetOptions) error {
+	if len(inputs) == 1 && strings.EqualFold(filepath.Ext(inputs[0]), ".pdf") {
+		return contactSheetFromPDF(ctx, inputs[0], outputFile, opts)
+	}
+
+	images, cleanup, err := CollectImages(inputs, opts.Sort)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	caption := opts.Caption
+	if caption == "" {
+		caption = "{filename}"
+	}
+
+	return ConvertImagesToPDF(ctx, images, outputFile, contactSheetConvertOptions(opts, caption))
+}
+
+// contactSheetFromPDF renders the pages of a PDF as thumbnails. Without
+// Ghostscript, pdfcpu places the pages in a grid as vector graphics instead,
+// without captions.
+func contactSheetFromPDF(ctx context.Context, inputFile, outputFile string, opts ContactSheetOptions) error {
+	if err := checkInputFile(inputFile); err != nil {
+		return err
+	}
+
+	if !isGhostscriptAvailable() {
+		logf("Ghostscript not found, using pdfcpu to lay out pages without captions...")
+
+		conf := newConfig()
+
+		nup, err := api.PDFGridConfig(opts.Rows, opts.Columns, "border:on, margin:6", conf)
+		if err != nil {
+			return fmt.Errorf("invalid grid: %w", err)
+		}
+		err = transformFile(ctx, inputFile, outputFile, func(rs io.ReadSeeker, w io.Writer) error {
+			return api.NUp(rs, w, nil, nil, nup, conf)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create contact sheet: %w", err)
+		}
+		return nil
+	}
+
+	dir, removeDir, err := TempDir("", "pdf-tool-pages-")
+	if err != nil {
+		return fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer removeDir()
+
+	logf("Rendering pages with Ghostscript...")
+	pages, err := renderPages(ctx, inputFile, dir, opts.DPI)
+	if err != nil {
+		return err
+	}
+
+	caption := opts.Caption
+	if caption == "" {
+		caption = "Page {index}"
+	}
+
+	convertOpts := contactSheetConvertOptions(opts, caption)
+	convertOpts.Title = strings.TrimSuffix(filepath.Base(inputFile), filepath.Ext(inputFile))
+	return ConvertImagesToPDF(ctx, pages, outputFile, convertOpts)
+}
+
+// contactSheetConvertOptions returns the conversion settings for a contact sheet grid
+func contactSheetConvertOptions(opts ContactSheetOptions, caption string) ConvertOptions {
+	return ConvertOptions{
+		NupColumns:  opts.Columns,
+		NupRows:     opts.Rows,
+		Caption:     caption,
+		MaxPixels:   opts.ThumbnailSize,
+		PageNumbers: "bottom-center",
+	}
+}
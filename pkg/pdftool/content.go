@@ -0,0 +1,248 @@
+package pdftool
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strconv"
+)
+
+// pdfName is a name object such as /F1 in a content stream, without the slash
+type pdfName string
+
+// pdfDict stands in for a dictionary operand, whose contents are not needed
+type pdfDict struct{}
+
+// contentLexer splits PDF content streams and CMaps into operands and operators
+type contentLexer struct {
+	data []byte
+	pos  int
+}
+
+// isDelimiter reports whether c ends a name, number or operator
+func isDelimiter(c byte) bool {
+	switch c {
+	case ' ', '\t', '\r', '\n', '\f', 0, '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
+
+// isSpace reports whether c is PDF whitespace
+func isSpace(c byte) bool {
+	switch c {
+	case ' ', '\t', '\r', '\n', '\f', 0:
+		return true
+	}
+	return false
+}
+
+// next returns the next operand, or the next operator with op set. Operands
+// are float64, string (raw string bytes), pdfName, pdfDict or []any. Inline
+// images are skipped up to EI and returned as the operator BI.
+// It returns ok false at the end of the data.
+func (l *contentLexer) next() (value any, op string, ok bool) {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		switch {
+		case isSpace(c):
+			l.pos++
+		case c == '%':
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+		case c == '/':
+			l.pos++
+			return pdfName(l.word()), "", true
+		case c == '(':
+			l.pos++
+			return l.literalString(), "", true
+		case c == '<' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '<':
+			l.skipDict()
+			return pdfDict{}, "", true
+		case c == '<':
+			l.pos++
+			return l.hexString(), "", true
+		case c == '[':
+			l.pos++
+			return l.array(), "", true
+		case c == ']' || c == '>' || c == ')' || c == '{' || c == '}':
+			l.pos++ // Unbalanced or PostScript syntax; ignore
+		case c == '+' || c == '-' || c == '.' || (c >= '0' && c <= '9'):
+			word := l.word()
+			if n, err := strconv.ParseFloat(word, 64); err == nil {
+				return n, "", true
+			}
+			return nil, word, true
+		default:
+			word := l.word()
+			if word == "BI" {
+				l.skipInlineImage()
+			}
+			return nil, word, true
+		}
+	}
+	return nil, "", false
+}
+
+// word reads regular characters up to the next delimiter
+func (l *contentLexer) word() string {
+	start := l.pos
+	for l.pos < len(l.data) && !isDelimiter(l.data[l.pos]) {
+		l.pos++
+	}
+	if l.pos == start && l.pos < len(l.data) {
+		l.pos++ // A stray delimiter; consume it to make progress
+	}
+	return string(l.data[start:l.pos])
+}
+
+// literalString reads a (string) after the opening parenthesis
+func (l *contentLexer) literalString() string {
+	var b []byte
+	depth := 1
+
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return string(b)
+			}
+		case '\\':
+			if l.pos >= len(l.data) {
+				return string(b)
+			}
+			e := l.data[l.pos]
+			l.pos++
+			switch e {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r':
+				if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+				continue
+			case '\n':
+				continue
+			default:
+				if e >= '0' && e <= '7' {
+					n := int(e - '0')
+					for i := 0; i < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+						n = n*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					c = byte(n)
+				} else {
+					c = e
+				}
+			}
+		}
+		b = append(b, c)
+	}
+	return string(b)
+}
+
+// hexString reads a <hex string> after the opening bracket
+func (l *contentLexer) hexString() string {
+	end := bytes.IndexByte(l.data[l.pos:], '>')
+	if end < 0 {
+		end = len(l.data) - l.pos
+	}
+
+	digits := make([]byte, 0, end)
+	for _, c := range l.data[l.pos : l.pos+end] {
+		if !isSpace(c) {
+			digits = append(digits, c)
+		}
+	}
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	l.pos += end + 1
+
+	decoded, _ := hex.DecodeString(string(digits))
+	return string(decoded)
+}
+
+// array reads an [array] after the opening bracket
+func (l *contentLexer) array() []any {
+	var values []any
+	for l.pos < len(l.data) {
+		for l.pos < len(l.data) && isSpace(l.data[l.pos]) {
+			l.pos++
+		}
+		if l.pos < len(l.data) && l.data[l.pos] == ']' {
+			l.pos++
+			return values
+		}
+
+		value, op, ok := l.next()
+		if !ok {
+			break
+		}
+		if op == "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// skipDict skips a <<dictionary>>, including nested ones
+func (l *contentLexer) skipDict() {
+	depth := 0
+	for l.pos < len(l.data) {
+		switch {
+		case bytes.HasPrefix(l.data[l.pos:], []byte("<<")):
+			depth++
+			l.pos += 2
+		case bytes.HasPrefix(l.data[l.pos:], []byte(">>")):
+			depth--
+			l.pos += 2
+			if depth == 0 {
+				return
+			}
+		case l.data[l.pos] == '(':
+			l.pos++
+			l.literalString()
+		default:
+			l.pos++
+		}
+	}
+}
+
+// skipInlineImage skips an inline image from its dictionary to EI
+func (l *contentLexer) skipInlineImage() {
+	id := bytes.Index(l.data[l.pos:], []byte("ID"))
+	if id < 0 {
+		l.pos = len(l.data)
+		return
+	}
+	l.pos += id + 2
+
+	for l.pos < len(l.data) {
+		ei := bytes.Index(l.data[l.pos:], []byte("EI"))
+		if ei < 0 {
+			l.pos = len(l.data)
+			return
+		}
+		l.pos += ei + 2
+
+		// EI must stand alone; binary image data may contain the bytes too
+		if l.pos >= 3 && isSpace(l.data[l.pos-3]) && (l.pos == len(l.data) || isSpace(l.data[l.pos])) {
+			return
+		}
+	}
+}
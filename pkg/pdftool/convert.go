@@ -0,0 +1,539 @@
+package pdftool
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/disintegration/imaging"
+	"github.com/go-pdf/fpdf"
+)
+
+// ConvertOptions holds optional settings for image to PDF conversion
+type ConvertOptions struct {
+	OCR         bool   // Add an invisible text layer using Tesseract
+	OCRLanguage string // Tesseract language(s), e.g. "deu+eng"
+
+	// Color reduction; Bilevel takes precedence over Grayscale
+	Grayscale bool  // Convert to 8-bit grayscale
+	Bilevel   bool  // Convert to black and white
+	Threshold uint8 // Gray level at or above which a pixel becomes white
+	Dither    bool  // Use Floyd-Steinberg dithering instead of a fixed threshold
+
+	// Clockwise rotation in degrees (0, 90, 180, 270) or RotateAuto
+	Rotate int
+
+	// Image adjustments; Enhance applies a preset for document photos that
+	// the other settings override
+	Enhance    bool
+	Brightness float64 // Percent, -100 to 100
+	Contrast   float64 // Percent, -100 to 100
+	Sharpen    float64 // Gaussian sigma; 0 disables
+	Despeckle  bool    // Remove isolated noise pixels
+
+	// Straighten slightly rotated scans
+	Deskew bool
+
+	// Trim uniform borders such as scanner bed edges and white margins;
+	// pixels within AutoCropThreshold gray levels of the border count as border
+	AutoCrop          bool
+	AutoCropThreshold uint8
+
+	// Background for transparent images (default white)
+	Background color.Color
+
+	// N-up grid layout; 0 or 1 in both means one image per page
+	NupColumns int
+	NupRows    int
+
+	// Caption template printed under each image, e.g. "{filename}"; see
+	// captionText for the supported placeholders
+	Caption string
+
+	// Page number position such as "bottom-center" (empty for none) and its
+	// template with {n} and {total} placeholders
+	PageNumbers      string
+	PageNumberFormat string
+
+	// Downscale images so their longer side is at most this many pixels
+	// (0 keeps full resolution), e.g. for thumbnails
+	MaxPixels int
+
+	// Produce PDF/A-2b (sRGB output intent, XMP metadata, no transparency)
+	PDFA bool
+
+	// Document metadata; Title defaults to the input file name
+	Title    string
+	Author   string
+	Subject  string
+	Keywords string
+
+	// File system to read the images from instead of the disk, e.g. an
+	// embed.FS or a *zip.Reader; paths then use slashes as in fs.FS
+	FS fs.FS
+
+	// Page size such as A4 (default), A3, A5, Letter or Legal
+	PageSize  string
+	Landscape bool
+
+	// Space between the page edge and the images in points; 0 means none
+	// for single images and 36 for N-up grids
+	Margin float64
+
+	// How images are sized in their page or grid cell: FitAuto (default),
+	// FitContain or FitActual
+	Fit string
+
+	// Resolution images are assumed to have for their natural size
+	// (default 300)
+	DPI int
+}
+
+// Image fit modes for ConvertOptions.Fit
+const (
+	FitAuto    = "auto"    // Natural size capped at 500 points, or fill N-up cells
+	FitContain = "contain" // Scale up or down to fill the area, keeping the aspect ratio
+	FitActual  = "actual"  // Natural size, only scaled down if it does not fit
+)
+
+// ImageSource is an image given to ConvertImages, e.g. an upload. The
+// extension of Name selects the format; the name is also used in captions
+// and as the default title.
+type ImageSource struct {
+	Name    string
+	Reader  io.Reader
+	ModTime time.Time // For the {date} caption of images without EXIF date; optional
+}
+
+// ConvertImageToPDF converts PNG or JPEG image to PDF
+func ConvertImageToPDF(ctx context.Context, inputFile, outputFile string, opts ConvertOptions) error {
+	return ConvertImagesToPDF(ctx, []string{inputFile}, outputFile, opts)
+}
+
+// ConvertImagesToPDF converts PNG or JPEG images to a single PDF, one image per
+// page or several per page when an N-up grid is set
+func ConvertImagesToPDF(ctx context.Context, inputFiles []string, outputFile string, opts ConvertOptions) error {
+	pdf, err := buildImagePDF(ctx, inputFS(opts.FS), inputFiles, opts)
+	if err != nil {
+		return err
+	}
+
+	// Save PDF
+	if opts.PDFA {
+		var buf bytes.Buffer
+		if err := pdf.Output(&buf); err != nil {
+			return fmt.Errorf("failed to generate PDF: %w", err)
+		}
+		if err := writePDFA(ctx, buf.Bytes(), outputFile, 2); err != nil {
+			return err
+		}
+	} else if err := pdf.OutputFileAndClose(outputFile); err != nil {
+		return fmt.Errorf("failed to save PDF: %w", err)
+	}
+
+	if len(inputFiles) == 1 {
+		logf("Successfully converted %s to %s", inputFiles[0], outputFile)
+	} else {
+		logf("Successfully converted %d images to %s", len(inputFiles), outputFile)
+	}
+	return nil
+}
+
+// ConvertImages converts PNG or JPEG images read from memory or streams to a
+// PDF written to w, e.g. to turn uploaded photos into a PDF response without
+// touching the disk. Options work as for ConvertImagesToPDF; opts.FS is
+// ignored.
+func ConvertImages(ctx context.Context, inputs []ImageSource, w io.Writer, opts ConvertOptions) error {
+	fsys := memFS{}
+	names := make([]string, len(inputs))
+	for i, input := range inputs {
+		name := path.Base(filepath.ToSlash(input.Name))
+		if input.Name == "" || name == "." || name == "/" {
+			return fmt.Errorf("image %d has no name", i+1)
+		}
+		if _, ok := fsys[name]; ok {
+			return fmt.Errorf("duplicate image name: %s", name)
+		}
+		data, err := io.ReadAll(input.Reader)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		fsys[name] = &memFile{name: name, data: data, modTime: input.ModTime}
+		names[i] = name
+	}
+	opts.FS = fsys
+
+	pdf, err := buildImagePDF(ctx, fsys, names, opts)
+	if err != nil {
+		return err
+	}
+
+	if !opts.PDFA {
+		if err := pdf.Output(w); err != nil {
+			return fmt.Errorf("failed to generate PDF: %w", err)
+		}
+		return nil
+	}
+
+	// PDF/A metadata is added as an incremental update, which needs a file
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return fmt.Errorf("failed to generate PDF: %w", err)
+	}
+	tmpDir, removeTmpDir, err := TempDir("", "pdftool-convert-")
+	if err != nil {
+		return fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer removeTmpDir()
+
+	outputFile := filepath.Join(tmpDir, "output.pdf")
+	if err := writePDFA(ctx, buf.Bytes(), outputFile, 2); err != nil {
+		return err
+	}
+
+	out, err := os.Open(outputFile)
+	if err != nil {
+		return fmt.Errorf("failed to read output: %w", err)
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(w, out); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	return nil
+}
+
+// buildImagePDF lays out images read from fsys on PDF pages
+func buildImagePDF(ctx context.Context, fsys fs.FS, inputFiles []string, opts ConvertOptions) (*fpdf.Fpdf, error) {
+	if len(inputFiles) == 0 {
+		return nil, fmt.Errorf("no input images given")
+	}
+
+	// Validate all inputs and options before doing any work
+	for _, inputFile := range inputFiles {
+		if err := checkImageFile(fsys, inputFile); err != nil {
+			return nil, err
+		}
+	}
+	switch opts.Fit {
+	case "", FitAuto, FitContain, FitActual:
+	default:
+		return nil, fmt.Errorf("invalid fit: %s (expected auto, contain or actual)", opts.Fit)
+	}
+	if opts.DPI < 0 {
+		return nil, fmt.Errorf("DPI must not be negative")
+	}
+	if opts.Margin < 0 {
+		return nil, fmt.Errorf("margin must not be negative")
+	}
+
+	pageSize := opts.PageSize
+	if pageSize == "" {
+		pageSize = "A4"
+	}
+	_, dim, ok := paperSize(pageSize)
+	if !ok {
+		return nil, fmt.Errorf("unknown paper size: %s (e.g. A4, A3, Letter, Legal)", opts.PageSize)
+	}
+	orientation := "P"
+	if opts.Landscape {
+		orientation = "L"
+	}
+
+	cols, rows := opts.NupColumns, opts.NupRows
+	if cols < 1 || rows < 1 {
+		cols, rows = 1, 1
+	}
+	perPage := cols * rows
+	totalPages := (len(inputFiles) + perPage - 1) / perPage
+
+	margin := opts.Margin
+	if margin == 0 && perPage > 1 {
+		margin = pageMargin
+	}
+
+	numberFormat := opts.PageNumberFormat
+	if numberFormat == "" {
+		numberFormat = DefaultPageNumberFormat
+	}
+
+	// Create PDF
+	pdf := fpdf.NewCustom(&fpdf.InitType{
+		OrientationStr: orientation,
+		UnitStr:        "pt",
+		Size:           fpdf.SizeType{Wd: dim.Width, Ht: dim.Height},
+	})
+	pdf.SetAutoPageBreak(false, 0) // Pages are laid out explicitly
+	setDocumentInfo(pdf, inputFiles[0], opts)
+	pageWidth, pageHeight := pdf.GetPageSize()
+
+	var numberArea rect
+	var numberAlign string
+	if opts.PageNumbers != "" {
+		var err error
+		if numberArea, numberAlign, err = pageNumberArea(opts.PageNumbers, pageWidth, pageHeight); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, inputFile := range inputFiles {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if i%perPage == 0 {
+			pdf.AddPage()
+			if opts.PageNumbers != "" {
+				text := pageNumberText(numberFormat, i/perPage+1, totalPages)
+				drawText(pdf, text, numberArea, numberAlign)
+			}
+		}
+
+		area := gridCell(pageWidth, pageHeight, margin, cols, rows, i%perPage)
+		if opts.Caption != "" {
+			area.H -= captionHeight
+		}
+
+		placed, err := addImage(ctx, pdf, fsys, inputFile, i, area, perPage > 1, opts)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", inputFile, err)
+		}
+
+		// Keep the caption directly below the image, within the area's width
+		if opts.Caption != "" {
+			text := captionText(fsys, opts.Caption, inputFile, i+1, len(inputFiles))
+			drawCaption(pdf, text, rect{X: area.X, Y: placed.Y + placed.H, W: area.W, H: captionHeight})
+		}
+	}
+
+	return pdf, nil
+}
+
+// checkImageFile verifies that an input image exists in fsys and has a
+// supported extension
+func checkImageFile(fsys fs.FS, inputFile string) error {
+	// Check if input file exists
+	if _, err := fs.Stat(fsys, inputFile); errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("input file does not exist: %s", inputFile)
+	}
+
+	// Get file extension
+	ext := strings.ToLower(filepath.Ext(inputFile))
+	if ext != ".png" && ext != ".jpg" && ext != ".jpeg" {
+		return fmt.Errorf("unsupported file format: %s (supported: .png, .jpg, .jpeg)", ext)
+	}
+
+	return nil
+}
+
+// decodeImage opens and decodes a PNG or JPEG file from fsys
+func decodeImage(ctx context.Context, fsys fs.FS, inputFile string) (image.Image, error) {
+	file, err := fsys.Open(inputFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open image file: %w", err)
+	}
+	defer file.Close()
+
+	var img image.Image
+	switch strings.ToLower(filepath.Ext(inputFile)) {
+	case ".png":
+		img, err = png.Decode(file)
+	case ".jpg", ".jpeg":
+		if img, err = jpeg.Decode(file); err != nil {
+			return decodeJPEGFallback(ctx, fsys, inputFile, err)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	return img, nil
+}
+
+// decodeJPEGFallback decodes JPEG variants the standard decoder rejects, such
+// as arithmetic coding, 12-bit samples or truncated progressive scans, with
+// ImageMagick
+func decodeJPEGFallback(ctx context.Context, fsys fs.FS, inputFile string, decodeErr error) (image.Image, error) {
+	kind := "JPEG"
+	if frame, ok := readJPEGFrame(fsys, inputFile); ok {
+		kind = frame.String() + " JPEG"
+	}
+
+	if _, ok := imageMagickCommand(); !ok {
+		if PureGo() {
+			return nil, fmt.Errorf("failed to decode image (%s): %w", kind, decodeErr)
+		}
+		return nil, fmt.Errorf("failed to decode image (%s): %w; install ImageMagick to convert it", kind, decodeErr)
+	}
+
+	logf("Converting %s with ImageMagick...", kind)
+	img, err := decodeWithImageMagick(ctx, fsys, inputFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image (%s): %w", kind, err)
+	}
+
+	return img, nil
+}
+
+// addImage draws an image centered in the given page area and returns where it
+// was placed, sized according to opts.Fit; with FitAuto, inGrid makes the
+// image fill its cell.
+func addImage(ctx context.Context, pdf *fpdf.Fpdf, fsys fs.FS, inputFile string, index int, area rect, inGrid bool, opts ConvertOptions) (rect, error) {
+	img, err := decodeImage(ctx, fsys, inputFile)
+	if err != nil {
+		return rect{}, err
+	}
+
+	if opts.Rotate == RotateAuto {
+		img = autoOrient(ctx, img, fsys, inputFile)
+	} else {
+		img = rotateClockwise(img, opts.Rotate)
+	}
+
+	// Transparent areas render unpredictably across viewers, so they are
+	// composited over a solid background, which also fills deskewed corners
+	background := opts.Background
+	if background == nil {
+		background = color.White
+	}
+
+	if opts.Deskew {
+		img = deskew(img, background)
+	}
+
+	if opts.AutoCrop {
+		img = autoCrop(img, opts.AutoCropThreshold)
+	}
+
+	img = enhanceImage(img, opts)
+
+	// Get image dimensions
+	bounds := img.Bounds()
+	width := float64(bounds.Dx())
+	height := float64(bounds.Dy())
+
+	// Convert pixels to points at the image resolution
+	dpi := float64(opts.DPI)
+	if dpi == 0 {
+		dpi = 300
+	}
+	pdfWidth := width * 72 / dpi
+	pdfHeight := height * 72 / dpi
+
+	switch {
+	case opts.Fit == FitContain || (opts.Fit == "" || opts.Fit == FitAuto) && inGrid:
+		pdfWidth, pdfHeight = fitSize(pdfWidth, pdfHeight, area.W, area.H)
+	case opts.Fit == FitActual:
+		if pdfWidth > area.W || pdfHeight > area.H {
+			pdfWidth, pdfHeight = fitSize(pdfWidth, pdfHeight, area.W, area.H)
+		}
+	default:
+		// Handle large images by scaling down if necessary
+		const maxSize = 500 // Maximum dimension in points
+		if pdfWidth > maxSize || pdfHeight > maxSize {
+			pdfWidth, pdfHeight = fitSize(pdfWidth, pdfHeight, maxSize, maxSize)
+		}
+	}
+
+	// Black-and-white output is always embedded losslessly as PNG
+	ext := strings.ToLower(filepath.Ext(inputFile))
+	if opts.Bilevel {
+		ext = ".png"
+	}
+
+	// Normalize to 8-bit NRGBA before re-encoding; the PDF writer cannot embed
+	// 16-bit PNGs, and CMYK JPEGs would otherwise keep their inverted Adobe
+	// channel layout
+	var embedImg image.Image = imaging.Clone(img)
+	if opts.MaxPixels > 0 && (bounds.Dx() > opts.MaxPixels || bounds.Dy() > opts.MaxPixels) {
+		embedImg = imaging.Fit(embedImg, opts.MaxPixels, opts.MaxPixels, imaging.Lanczos)
+	}
+
+	embedImg = flattenAlpha(embedImg, background)
+
+	switch {
+	case opts.Bilevel:
+		embedImg = toBilevel(embedImg, opts.Threshold, opts.Dither)
+	case opts.Grayscale:
+		embedImg = toGrayscale(embedImg)
+	}
+
+	// Encode the image in memory for embedding
+	var encoded bytes.Buffer
+	if err := encodeImage(&encoded, embedImg, ext); err != nil {
+		return rect{}, fmt.Errorf("failed to encode image: %w", err)
+	}
+
+	// Add image to PDF; fpdf caches images by name, so every image needs its own
+	imageType := "JPG"
+	if ext == ".png" {
+		imageType = "PNG"
+	}
+
+	imageName := fmt.Sprintf("image_%d%s", index, ext)
+	imageOpts := fpdf.ImageOptions{ImageType: imageType, ReadDpi: true}
+	pdf.RegisterImageOptionsReader(imageName, imageOpts, bytes.NewReader(encoded.Bytes()))
+
+	// Center the image in its area
+	x := area.X + (area.W-pdfWidth)/2
+	y := area.Y + (area.H-pdfHeight)/2
+
+	pdf.ImageOptions(imageName, x, y, pdfWidth, pdfHeight, false, imageOpts, 0, "")
+
+	// Overlay recognized text so the scan becomes searchable
+	if opts.OCR {
+		logf("Running Tesseract OCR...")
+		words, err := recognizeText(ctx, encoded.Bytes(), opts.OCRLanguage)
+		if err != nil {
+			return rect{}, err
+		}
+		embedded := embedImg.Bounds()
+		addTextLayer(pdf, words, x, y, pdfWidth, pdfHeight, float64(embedded.Dx()), float64(embedded.Dy()))
+	}
+
+	return rect{X: x, Y: y, W: pdfWidth, H: pdfHeight}, pdf.Error()
+}
+
+// setDocumentInfo fills in the PDF document information dictionary
+func setDocumentInfo(pdf *fpdf.Fpdf, inputFile string, opts ConvertOptions) {
+	title := opts.Title
+	if title == "" {
+		title = strings.TrimSuffix(filepath.Base(inputFile), filepath.Ext(inputFile))
+	}
+
+	pdf.SetTitle(title, true)
+	pdf.SetAuthor(opts.Author, true)
+	pdf.SetSubject(opts.Subject, true)
+	pdf.SetKeywords(opts.Keywords, true)
+	pdf.SetCreator("pdf-tool", true)
+	pdf.SetProducer("pdf-tool", true)
+}
+
+// encodeImage writes an image to w in the specified format
+func encodeImage(w io.Writer, img image.Image, format string) error {
+	return encodeImageQuality(w, img, format, 90)
+}
+
+// encodeImageQuality writes an image to w in the specified format, using the
+// given quality for JPEG
+func encodeImageQuality(w io.Writer, img image.Image, format string, quality int) error {
+	switch format {
+	case ".png":
+		return png.Encode(w, img)
+	case ".jpg", ".jpeg":
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: quality})
+	default:
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+}
@@ -0,0 +1,153 @@
+package pdftool
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// CropOptions selects the visible area of pages. Box and Trim are mutually
+// exclusive; lengths take a unit of pt (default), mm, cm or in.
+type CropOptions struct {
+	Box   string // "x0,y0,x1,y1" from the bottom-left corner of the page
+	Trim  string // Margin to cut off: "all", "vertical,horizontal" or "top,right,bottom,left"
+	Pages string // Empty or "all" crops every page
+}
+
+// lengthUnits maps length units to points
+var lengthUnits = map[string]float64{
+	"pt": 1,
+	"mm": 72 / 25.4,
+	"cm": 72 / 2.54,
+	"in": 72,
+}
+
+// parseLength converts a length such as "5mm", "0.5in" or "12" (points) to
+// points
+func parseLength(s string) (float64, error) {
+	number := strings.ToLower(strings.TrimSpace(s))
+	factor := 1.0
+	for unit, f := range lengthUnits {
+		if strings.HasSuffix(number, unit) {
+			number = strings.TrimSpace(strings.TrimSuffix(number, unit))
+			factor = f
+			break
+		}
+	}
+
+	v, err := strconv.ParseFloat(number, 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, fmt.Errorf("invalid length: %s (expected a number with an optional unit pt, mm, cm or in)", s)
+	}
+	return v * factor, nil
+}
+
+// parseLengths converts a comma separated list of lengths to points
+func parseLengths(s string) ([]float64, error) {
+	var lengths []float64
+	for _, part := range strings.Split(s, ",") {
+		v, err := parseLength(part)
+		if err != nil {
+			return nil, err
+		}
+		lengths = append(lengths, v)
+	}
+	return lengths, nil
+}
+
+// CropPages sets the CropBox of the selected pages, either to a fixed box or
+// by trimming margins off the current visible area. Content outside the box is
+// hidden, not removed.
+func CropPages(ctx context.Context, inputFile, outputFile string, opts CropOptions) error {
+	if (opts.Box == "") == (opts.Trim == "") {
+		return fmt.Errorf("specify either a crop box or a trim margin")
+	}
+
+	var box []float64
+	var trim [4]float64 // top, right, bottom, left
+	if opts.Box != "" {
+		var err error
+		if box, err = parseLengths(opts.Box); err != nil {
+			return err
+		}
+		if len(box) != 4 {
+			return fmt.Errorf("invalid crop box: %s (expected x0,y0,x1,y1)", opts.Box)
+		}
+	} else {
+		margins, err := parseLengths(opts.Trim)
+		if err != nil {
+			return err
+		}
+		switch len(margins) {
+		case 1:
+			trim = [4]float64{margins[0], margins[0], margins[0], margins[0]}
+		case 2:
+			trim = [4]float64{margins[0], margins[1], margins[0], margins[1]}
+		case 4:
+			trim = [4]float64{margins[0], margins[1], margins[2], margins[3]}
+		default:
+			return fmt.Errorf("invalid trim: %s (expected 1, 2 or 4 lengths)", opts.Trim)
+		}
+		for _, m := range trim {
+			if m < 0 {
+				return fmt.Errorf("invalid trim: %s (margins cannot be negative)", opts.Trim)
+			}
+		}
+	}
+
+	doc, err := readContext(ctx, inputFile)
+	if err != nil {
+		return err
+	}
+
+	pages, err := stampSelection(doc.PageCount, opts.Pages)
+	if err != nil {
+		return err
+	}
+
+	for _, page := range pages {
+		if err := contextError(ctx); err != nil {
+			return err
+		}
+		pageDict, _, inherited, err := doc.PageDict(page, false)
+		if err != nil {
+			return fmt.Errorf("failed to read page %d: %w", page, err)
+		}
+		mediaBox := inherited.MediaBox
+		if mediaBox == nil {
+			return fmt.Errorf("page %d has no media box", page)
+		}
+
+		var crop *types.Rectangle
+		if box != nil {
+			crop = types.NewRectangle(math.Min(box[0], box[2]), math.Min(box[1], box[3]), math.Max(box[0], box[2]), math.Max(box[1], box[3]))
+		} else {
+			visible := mediaBox
+			if inherited.CropBox != nil {
+				visible = inherited.CropBox
+			}
+			crop = types.NewRectangle(visible.LL.X+trim[3], visible.LL.Y+trim[2], visible.UR.X-trim[1], visible.UR.Y-trim[0])
+		}
+
+		// The crop box cannot extend beyond the media box
+		crop = types.NewRectangle(
+			math.Max(crop.LL.X, mediaBox.LL.X), math.Max(crop.LL.Y, mediaBox.LL.Y),
+			math.Min(crop.UR.X, mediaBox.UR.X), math.Min(crop.UR.Y, mediaBox.UR.Y))
+		if crop.Width() < 1 || crop.Height() < 1 {
+			return fmt.Errorf("crop area of page %d is empty (page is %g x %g pt)", page, mediaBox.Width(), mediaBox.Height())
+		}
+
+		pageDict["CropBox"] = crop.Array()
+	}
+
+	if err := writeContextFile(ctx, doc, outputFile); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outputFile, err)
+	}
+
+	logf("Cropped %d of %d pages, wrote %s", len(pages), doc.PageCount, outputFile)
+	return nil
+}
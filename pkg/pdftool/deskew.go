@@ -0,0 +1,85 @@
+package pdftool
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/disintegration/imaging"
+)
+
+const (
+	deskewMaxAngle   = 15.0 // Largest skew in degrees that is corrected
+	deskewStep       = 0.1  // Angle resolution in degrees
+	deskewMinAngle   = 0.2  // Smaller skews are left alone
+	deskewMinGain    = 1.2  // Required improvement over the unrotated image
+	deskewAnalysisPx = 1200 // Longer side of the image used for detection
+)
+
+// deskew detects small rotations of scanned text and rotates the image back,
+// filling exposed corners with the background color
+func deskew(img image.Image, background color.Color) image.Image {
+	angle := detectSkew(img)
+	if math.Abs(angle) < deskewMinAngle {
+		return img
+	}
+	return imaging.Rotate(img, -angle, background)
+}
+
+// detectSkew estimates the skew of text lines in degrees, counter-clockwise,
+// using a Hough transform restricted to near-horizontal lines. Each angle
+// accumulates the bottom edges of dark shapes (text baselines) into distance
+// bins; the angle whose bins are most sharply peaked aligns with the lines.
+func detectSkew(img image.Image) float64 {
+	small := imaging.Fit(img, deskewAnalysisPx, deskewAnalysisPx, imaging.Box)
+	gray := toGrayscale(small)
+	b := gray.Bounds()
+
+	// Collect dark pixels whose lower neighbor is light
+	const dark = 128
+	var points []image.Point
+	for y := b.Min.Y; y < b.Max.Y-1; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if gray.GrayAt(x, y).Y < dark && gray.GrayAt(x, y+1).Y >= dark {
+				points = append(points, image.Pt(x-b.Min.X, y-b.Min.Y))
+			}
+		}
+	}
+	if len(points) < 100 {
+		return 0 // Too little content to measure
+	}
+
+	diagonal := int(math.Hypot(float64(b.Dx()), float64(b.Dy()))) + 1
+	bins := make([]int, 2*diagonal+1)
+
+	bestStep, bestScore, levelScore := 0, -1.0, 0.0
+	steps := int(math.Round(deskewMaxAngle / deskewStep))
+	for i := -steps; i <= steps; i++ {
+		angle := float64(i) * deskewStep
+		sin, cos := math.Sincos(angle * math.Pi / 180)
+
+		clear(bins)
+		for _, p := range points {
+			rho := float64(p.Y)*cos + float64(p.X)*sin
+			bins[int(math.Round(rho))+diagonal]++
+		}
+
+		var score float64
+		for _, n := range bins {
+			score += float64(n) * float64(n)
+		}
+		if score > bestScore {
+			bestStep, bestScore = i, score
+		}
+		if i == 0 {
+			levelScore = score
+		}
+	}
+
+	// Without text lines, e.g. photos, the peak is weak or runs into the
+	// search limit
+	if bestStep == -steps || bestStep == steps || bestScore < deskewMinGain*levelScore {
+		return 0
+	}
+	return float64(bestStep) * deskewStep
+}
@@ -0,0 +1,296 @@
+package pdftool
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Comparison modes for DiffPDF
+const (
+	DiffText   = "text"
+	DiffVisual = "visual"
+)
+
+// pixelTolerance is the largest difference of a color channel (0-255) that
+// still counts as equal, so anti-aliasing and JPEG noise are ignored
+const pixelTolerance = 32
+
+// DiffOptions holds settings for comparing two PDFs
+type DiffOptions struct {
+	Mode      string  // DiffText or DiffVisual
+	OutputDir string  // Report directory for diff files; empty writes none
+	DPI       int     // Rendering resolution for visual comparison
+	Threshold float64 // Percentage of pixels that may differ on an unchanged page
+}
+
+// PageDiff describes a page that differs between two PDFs
+type PageDiff struct {
+	Page    int
+	Summary string // What changed, e.g. "2 lines added, 1 removed"
+	File    string // Diff file in the report directory, if written
+}
+
+// DiffResult is the outcome of comparing two PDFs
+type DiffResult struct {
+	PageCountA int
+	PageCountB int
+	Changed    []PageDiff
+}
+
+// DiffPDF compares two PDFs page by page, either by their extracted text or
+// by rendering them and comparing pixels, and returns the pages that differ.
+// With an output directory, a text diff or an image highlighting the changed
+// pixels in red is written for each changed page.
+func DiffPDF(ctx context.Context, fileA, fileB string, opts DiffOptions) (*DiffResult, error) {
+	if opts.Mode != DiffText && opts.Mode != DiffVisual {
+		return nil, fmt.Errorf("invalid diff mode: %s (supported: text, visual)", opts.Mode)
+	}
+	if opts.Threshold < 0 || opts.Threshold > 100 {
+		return nil, fmt.Errorf("threshold must be between 0 and 100, got: %g", opts.Threshold)
+	}
+
+	for _, file := range []string{fileA, fileB} {
+		if err := checkInputFile(file); err != nil {
+			return nil, err
+		}
+	}
+
+	ctxA, err := readContext(ctx, fileA)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fileA, err)
+	}
+	ctxB, err := readContext(ctx, fileB)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fileB, err)
+	}
+
+	if opts.OutputDir != "" {
+		if err := os.MkdirAll(opts.OutputDir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create output directory: %w", err)
+		}
+	}
+
+	result := &DiffResult{PageCountA: ctxA.PageCount, PageCountB: ctxB.PageCount}
+
+	var compare func(page int) (*PageDiff, error)
+	if opts.Mode == DiffText {
+		compare = func(page int) (*PageDiff, error) {
+			textA, err := pageText(ctxA, page, false)
+			if err != nil {
+				return nil, fmt.Errorf("failed to extract text from page %d of %s: %w", page, fileA, err)
+			}
+			textB, err := pageText(ctxB, page, false)
+			if err != nil {
+				return nil, fmt.Errorf("failed to extract text from page %d of %s: %w", page, fileB, err)
+			}
+			return diffPageText(page, textA, textB, opts.OutputDir)
+		}
+	} else {
+		tmpDir, removeTmpDir, err := TempDir("", "pdf-tool-diff-")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create temp directory: %w", err)
+		}
+		defer removeTmpDir()
+
+		renderedA, err := renderAllPages(ctx, fileA, filepath.Join(tmpDir, "a"), opts.DPI, ctxA.PageCount)
+		if err != nil {
+			return nil, err
+		}
+		renderedB, err := renderAllPages(ctx, fileB, filepath.Join(tmpDir, "b"), opts.DPI, ctxB.PageCount)
+		if err != nil {
+			return nil, err
+		}
+		compare = func(page int) (*PageDiff, error) {
+			return diffPageImages(ctx, page, renderedA[page-1], renderedB[page-1], opts)
+		}
+	}
+
+	for page := 1; page <= max(ctxA.PageCount, ctxB.PageCount); page++ {
+		if err := contextError(ctx); err != nil {
+			return nil, err
+		}
+		switch {
+		case page > ctxA.PageCount:
+			result.Changed = append(result.Changed, PageDiff{Page: page, Summary: "only in " + filepath.Base(fileB)})
+		case page > ctxB.PageCount:
+			result.Changed = append(result.Changed, PageDiff{Page: page, Summary: "only in " + filepath.Base(fileA)})
+		default:
+			pageDiff, err := compare(page)
+			if err != nil {
+				return nil, err
+			}
+			if pageDiff != nil {
+				result.Changed = append(result.Changed, *pageDiff)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// renderAllPages renders every page of a PDF into a new directory for
+// visual comparison
+func renderAllPages(ctx context.Context, inputFile, dir string, dpi, pageCount int) ([]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+
+	pages, err := renderPages(ctx, inputFile, dir, dpi)
+	if err != nil {
+		return nil, err
+	}
+	if len(pages) != pageCount {
+		return nil, fmt.Errorf("rendered %d of %d pages of %s", len(pages), pageCount, inputFile)
+	}
+	return pages, nil
+}
+
+// diffPageText compares the text lines of a page and writes a diff file
+// when they differ
+func diffPageText(page int, textA, textB, outputDir string) (*PageDiff, error) {
+	if textA == textB {
+		return nil, nil
+	}
+
+	lines := diffLines(strings.Split(textA, "\n"), strings.Split(textB, "\n"))
+	added, removed := 0, 0
+	for _, line := range lines {
+		switch line[0] {
+		case '+':
+			added++
+		case '-':
+			removed++
+		}
+	}
+
+	pageDiff := &PageDiff{Page: page, Summary: fmt.Sprintf("%d lines added, %d removed", added, removed)}
+	if added == 0 && removed == 0 {
+		pageDiff.Summary = "whitespace changed"
+	}
+	if outputDir != "" {
+		pageDiff.File = filepath.Join(outputDir, fmt.Sprintf("page-%04d.diff", page))
+		if err := os.WriteFile(pageDiff.File, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+			return nil, fmt.Errorf("failed to write diff file: %w", err)
+		}
+	}
+	return pageDiff, nil
+}
+
+// diffLines returns the lines of both texts prefixed with ' ' for common
+// lines, '-' for lines only in a and '+' for lines only in b, based on their
+// longest common subsequence
+func diffLines(a, b []string) []string {
+	// common[i][j] is the length of the common subsequence of a[i:] and b[j:]
+	common := make([][]int, len(a)+1)
+	for i := range common {
+		common[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	var lines []string
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, " "+a[i])
+			i++
+			j++
+		case j == len(b) || i < len(a) && common[i+1][j] >= common[i][j+1]:
+			lines = append(lines, "-"+a[i])
+			i++
+		default:
+			lines = append(lines, "+"+b[j])
+			j++
+		}
+	}
+	return lines
+}
+
+// diffPageImages compares two rendered pages pixel by pixel and writes an
+// image of page a with the changed pixels in red when more than the threshold
+// differ
+func diffPageImages(ctx context.Context, page int, fileA, fileB string, opts DiffOptions) (*PageDiff, error) {
+	imgA, err := decodeImage(ctx, diskFS{}, fileA)
+	if err != nil {
+		return nil, err
+	}
+	imgB, err := decodeImage(ctx, diskFS{}, fileB)
+	if err != nil {
+		return nil, err
+	}
+
+	boundsA, boundsB := imgA.Bounds(), imgB.Bounds()
+	width := max(boundsA.Dx(), boundsB.Dx())
+	height := max(boundsA.Dy(), boundsB.Dy())
+	diff := image.NewRGBA(image.Rect(0, 0, width, height))
+	highlight := color.RGBA{R: 255, A: 255}
+
+	changed := 0
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			pointA := image.Pt(boundsA.Min.X+x, boundsA.Min.Y+y)
+			pointB := image.Pt(boundsB.Min.X+x, boundsB.Min.Y+y)
+			if !pointA.In(boundsA) || !pointB.In(boundsB) {
+				// Outside one of the pages: the page sizes differ
+				diff.SetRGBA(x, y, highlight)
+				changed++
+				continue
+			}
+
+			cA, cB := imgA.At(pointA.X, pointA.Y), imgB.At(pointB.X, pointB.Y)
+			if !similarColors(cA, cB) {
+				diff.SetRGBA(x, y, highlight)
+				changed++
+				continue
+			}
+
+			// Fade unchanged content so the changes stand out
+			gray := color.GrayModel.Convert(cA).(color.Gray).Y
+			faded := 128 + gray/2
+			diff.SetRGBA(x, y, color.RGBA{R: faded, G: faded, B: faded, A: 255})
+		}
+	}
+
+	percent := float64(changed) / float64(width*height) * 100
+	if changed == 0 || percent <= opts.Threshold {
+		return nil, nil
+	}
+
+	pageDiff := &PageDiff{Page: page, Summary: fmt.Sprintf("%.2f%% of pixels changed", percent)}
+	if boundsA.Size() != boundsB.Size() {
+		pageDiff.Summary = fmt.Sprintf("page size changed, %.2f%% of pixels changed", percent)
+	}
+	if opts.OutputDir != "" {
+		pageDiff.File = filepath.Join(opts.OutputDir, fmt.Sprintf("page-%04d.png", page))
+		if err := writeImageFile(pageDiff.File, diff, ".png", 0); err != nil {
+			return nil, err
+		}
+	}
+	return pageDiff, nil
+}
+
+// similarColors reports whether no channel of two colors differs by more
+// than pixelTolerance
+func similarColors(a, b color.Color) bool {
+	r1, g1, b1, _ := a.RGBA()
+	r2, g2, b2, _ := b.RGBA()
+	for _, d := range []int{int(r1>>8) - int(r2>>8), int(g1>>8) - int(g2>>8), int(b1>>8) - int(b2>>8)} {
+		if d > pixelTolerance || d < -pixelTolerance {
+			return false
+		}
+	}
+	return true
+}
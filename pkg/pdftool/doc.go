@@ -0,0 +1,64 @@
+// Package pdftool compresses, converts and edits PDF files. It is the
+// library behind the pdf-tool command line and can be used by other Go
+// programs without shelling out to the CLI.
+//
+// The main entry points are CompressPDF, ConvertImagesToPDF and MergePDFs;
+// most other commands have a matching function taking input and output
+// paths and an options struct, e.g. SplitPDF, RasterizePDF or StampBarcode.
+// Compression uses Ghostscript when it is installed and falls back to
+// pdfcpu otherwise.
+//
+// CompressOptions.Engine picks the backend by name. Besides the built-in
+// Ghostscript, pdfcpu, qpdf and mutool engines, programs can add their own
+// Engine with RegisterEngine, e.g. one running an external optimizer made
+// with NewCommandEngine.
+//
+// In pure-Go mode, set with SetPureGo or the purego build tag, no external
+// programs are run and features that need them fail with ErrPureGo.
+// SetRunner replaces how programs are run, e.g. to check their arguments in
+// tests.
+//
+// Operations take a context.Context. Canceling it, or reaching its deadline,
+// kills a running program like Ghostscript, Tesseract or ImageMagick, stops
+// work between pages and returns the context's error, with deadlines
+// reported as ErrTimeout. pdfcpu calls cannot be interrupted: canceling only
+// returns early, while the call keeps running in the background, using CPU
+// and memory until it finishes, and its output is dropped.
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+//	defer cancel()
+//	result, err := pdftool.CompressPDF(ctx, "large.pdf", "small.pdf", pdftool.CompressOptions{Quality: 40})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Printf("saved %.0f%% with %s\n", (1-result.Ratio)*100, result.Engine)
+//
+//	err := pdftool.ConvertImagesToPDF(ctx, []string{"scan1.jpg", "scan2.jpg"}, "scans.pdf", pdftool.ConvertOptions{})
+//
+// Compress works on an io.Reader and io.Writer instead of paths, e.g. to
+// compress an upload straight into an HTTP response:
+//
+//	result, err := pdftool.Compress(ctx, upload, w, pdftool.CompressOptions{Quality: 40})
+//
+// CompressFS and ConvertOptions.FS read inputs from an fs.FS instead, e.g.
+// an embed.FS or a *zip.Reader. ConvertImages converts images from readers,
+// such as uploads, straight to a writer.
+//
+// Batch compresses many files with a pool of workers, retrying failures and
+// returning the outcome of every file along with the totals.
+//
+// Set CompressOptions.Progress to follow long compressions; it receives the
+// stage, the pages processed so far and the bytes written.
+//
+// The functions print nothing. Compression returns a Result with the sizes,
+// engine, time taken and warnings. Messages like "Using ghostscript for
+// compression..." go to a log/slog logger, which discards them until
+// SetLogger is called; CompressOptions.Logger overrides it for a single
+// compression.
+//
+//	pdftool.SetLogger(slog.New(slog.NewJSONHandler(os.Stderr, nil)))
+//
+// Failures with a known cause wrap one of the Err values, such as
+// ErrGhostscriptNotFound or ErrEncrypted, so callers can handle them with
+// errors.Is.
+package pdftool
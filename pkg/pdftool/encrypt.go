@@ -0,0 +1,147 @@
+package pdftool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+// errNotEncrypted is returned when decrypting a PDF without encryption
+var errNotEncrypted = errors.New("PDF is not encrypted")
+
+// EncryptOptions holds the passwords and AES key length for encryption
+type EncryptOptions struct {
+	UserPassword  string // Needed to open the document; may be empty
+	OwnerPassword string // Needed to change permissions; defaults to the user password
+	KeyLength     int    // 128 or 256 bits
+}
+
+// EncryptPDF encrypts a PDF with AES. Printing stays allowed; other
+// permissions are denied to anyone without the owner password.
+func EncryptPDF(ctx context.Context, inputFile, outputFile string, opts EncryptOptions) error {
+	if err := checkInputFile(inputFile); err != nil {
+		return err
+	}
+	conf, err := encryptConfig(opts)
+	if err != nil {
+		return err
+	}
+
+	err = transformFile(ctx, inputFile, outputFile, func(rs io.ReadSeeker, w io.Writer) error {
+		return api.Encrypt(rs, w, conf)
+	})
+	if err != nil {
+		if errors.Is(err, pdfcpu.ErrWrongPassword) {
+			return fmt.Errorf("%s is already encrypted", inputFile)
+		}
+		return fmt.Errorf("failed to encrypt PDF: %w", err)
+	}
+
+	logf("Successfully encrypted %s with AES-%d", outputFile, opts.KeyLength)
+	return nil
+}
+
+// encryptConfig returns the pdfcpu configuration for encrypting with opts
+func encryptConfig(opts EncryptOptions) (*model.Configuration, error) {
+	if opts.KeyLength != 128 && opts.KeyLength != 256 {
+		return nil, fmt.Errorf("invalid key length: %d (must be 128 or 256)", opts.KeyLength)
+	}
+
+	owner := opts.OwnerPassword
+	if owner == "" {
+		owner = opts.UserPassword
+	}
+	if owner == "" {
+		return nil, fmt.Errorf("an owner or user password is required")
+	}
+
+	conf := newConfig()
+	conf.UserPW = opts.UserPassword
+	conf.OwnerPW = owner
+	conf.EncryptUsingAES = true
+	conf.EncryptKeyLength = opts.KeyLength
+	conf.Permissions = model.PermissionsPrint
+	return conf, nil
+}
+
+// DecryptPDF removes the encryption from a PDF given its user or owner password
+func DecryptPDF(ctx context.Context, inputFile, outputFile, password string) error {
+	if err := decryptFile(ctx, inputFile, outputFile, password); err != nil {
+		return err
+	}
+
+	logf("Successfully decrypted %s", outputFile)
+	return nil
+}
+
+// DecryptDir decrypts every PDF directly inside inputDir into outputDir using
+// the same password. PDFs that are not encrypted are skipped; other failures
+// are reported and the remaining files are still processed.
+func DecryptDir(ctx context.Context, inputDir, outputDir, password string) error {
+	files, err := listPDFs(inputDir)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no PDF files found in directory: %s", inputDir)
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	decrypted, skipped, failed := 0, 0, 0
+	for _, file := range files {
+		if err := contextError(ctx); err != nil {
+			return err
+		}
+		outputFile := filepath.Join(outputDir, filepath.Base(file))
+
+		switch err := decryptFile(ctx, file, outputFile, password); {
+		case errors.Is(err, errNotEncrypted):
+			logf("Skipped %s: not encrypted", file)
+			skipped++
+		case err != nil:
+			defaultLogger.Load().Error(err.Error())
+			failed++
+		default:
+			decrypted++
+		}
+	}
+
+	logf("Decrypted %d of %d PDFs into %s (%d skipped)", decrypted, len(files), outputDir, skipped)
+	if failed > 0 {
+		return fmt.Errorf("%d PDFs could not be decrypted", failed)
+	}
+	return nil
+}
+
+// decryptFile writes a decrypted copy of a single PDF
+func decryptFile(ctx context.Context, inputFile, outputFile, password string) error {
+	doc, err := readEncryptedContext(ctx, inputFile, password, password)
+	if err != nil {
+		return err
+	}
+	if doc.E == nil {
+		return fmt.Errorf("%s: %w", inputFile, errNotEncrypted)
+	}
+
+	conf := newConfig()
+	conf.UserPW = password
+	conf.OwnerPW = password
+
+	err = transformFile(ctx, inputFile, outputFile, func(rs io.ReadSeeker, w io.Writer) error {
+		return api.Decrypt(rs, w, conf)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to decrypt %s: %w", inputFile, err)
+	}
+	return nil
+}
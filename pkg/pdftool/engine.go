@@ -0,0 +1,148 @@
+package pdftool
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Engine is a compression backend. The built-in engines wrap Ghostscript,
+// pdfcpu, qpdf and MuPDF's mutool; others can be added with RegisterEngine.
+type Engine interface {
+	// Name identifies the engine in CompressOptions.Engine, e.g. "qpdf"
+	Name() string
+	// Available reports whether the engine can run, e.g. whether its
+	// program is installed
+	Available() bool
+	// Compress writes a compressed copy of job.InputFile to job.OutputFile
+	Compress(ctx context.Context, job CompressJob) error
+}
+
+// ImageDownsampler is implemented by engines that can reduce images to
+// CompressJob.ImageDPI. Setting a DPI for other engines is an error when
+// they are chosen explicitly; when the auto chain picks one, it is used and
+// the result warns that the DPI was ignored.
+type ImageDownsampler interface {
+	DownsamplesImages() bool
+}
+
+// CompressJob describes a single compression run by an Engine
+type CompressJob struct {
+	InputFile  string
+	OutputFile string
+	Quality    int          // Quality percentage from 1 (smallest) to 100 (best)
+	ImageDPI   int          // Resolution images are downsampled to; 0 for the quality preset's
+	Pages      int          // Page count of the input, 0 if unknown
+	Progress   ProgressFunc // Receives StageCompress events; may be nil
+}
+
+// autoEngines is the fallback chain EngineAuto selects from, most effective
+// first
+var autoEngines = []string{EngineGhostscript, EngineMutool, EngineQpdf, EnginePdfcpu}
+
+var engineRegistry = struct {
+	sync.RWMutex
+	names   []string
+	engines map[string]Engine
+}{engines: map[string]Engine{}}
+
+func init() {
+	RegisterEngine(ghostscriptEngine{})
+	RegisterEngine(pdfcpuEngine{})
+	RegisterEngine(qpdfEngine{})
+	RegisterEngine(mutoolEngine{})
+}
+
+// RegisterEngine makes an engine available to CompressOptions.Engine under
+// its name, replacing any engine registered with the same name
+func RegisterEngine(e Engine) {
+	engineRegistry.Lock()
+	defer engineRegistry.Unlock()
+
+	if _, ok := engineRegistry.engines[e.Name()]; !ok {
+		engineRegistry.names = append(engineRegistry.names, e.Name())
+	}
+	engineRegistry.engines[e.Name()] = e
+}
+
+// LookupEngine returns the engine registered under a name
+func LookupEngine(name string) (Engine, bool) {
+	engineRegistry.RLock()
+	defer engineRegistry.RUnlock()
+
+	e, ok := engineRegistry.engines[name]
+	return e, ok
+}
+
+// EngineNames returns the names of the registered engines in the order they
+// were registered
+func EngineNames() []string {
+	engineRegistry.RLock()
+	defer engineRegistry.RUnlock()
+
+	return append([]string(nil), engineRegistry.names...)
+}
+
+// selectEngine returns the first available engine of a chain like
+// "qpdf,pdfcpu", where "auto" stands for the default chain, and logs the
+// choice. A DPI can only be set for engines that downsample images; in the
+// default chain other engines are still used, and the result warns that the
+// DPI was ignored.
+func selectEngine(opts CompressOptions) (Engine, error) {
+	spec := opts.Engine
+	if spec == "" {
+		spec = EngineAuto
+	}
+
+	var chain []string
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.TrimSpace(name)
+		if name == EngineAuto {
+			chain = append(chain, autoEngines...)
+		} else {
+			chain = append(chain, name)
+		}
+	}
+
+	var missing []string
+	for _, name := range chain {
+		e, ok := LookupEngine(name)
+		if !ok {
+			return nil, fmt.Errorf("%w: unsupported engine: %s (supported: auto, %s)", ErrInvalidOption, name, strings.Join(EngineNames(), ", "))
+		}
+		if !e.Available() {
+			missing = append(missing, name)
+			continue
+		}
+
+		if opts.ImageDPI > 0 && !downsamplesImages(e) && spec != EngineAuto {
+			return nil, fmt.Errorf("%w: the %s engine does not downsample images; use Ghostscript to set a DPI", ErrInvalidOption, name)
+		}
+
+		switch {
+		case len(missing) > 0 && PureGo():
+			opts.logger().Info(fmt.Sprintf("%s disabled in pure-Go mode, using %s for compression...", strings.Join(missing, ", "), name))
+		case len(missing) > 0:
+			opts.logger().Info(fmt.Sprintf("%s not found, using %s for compression...", strings.Join(missing, ", "), name))
+		default:
+			opts.logger().Info(fmt.Sprintf("Using %s for compression...", name))
+		}
+		return e, nil
+	}
+
+	if len(chain) == 1 && chain[0] == EngineGhostscript {
+		return nil, programMissing("ghostscript", "install it or use the pdfcpu engine")
+	}
+	if PureGo() {
+		return nil, fmt.Errorf("the %s engine: %w", strings.Join(missing, ", "), ErrPureGo)
+	}
+	return nil, fmt.Errorf("no compression engine available (tried %s)", strings.Join(missing, ", "))
+}
+
+// downsamplesImages reports whether an engine implements ImageDownsampler
+// and can downsample images
+func downsamplesImages(e Engine) bool {
+	d, ok := e.(ImageDownsampler)
+	return ok && d.DownsamplesImages()
+}
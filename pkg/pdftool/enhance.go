@@ -0,0 +1,75 @@
+package pdftool
+
+import (
+	"image"
+	"sort"
+
+	"github.com/disintegration/imaging"
+)
+
+// Settings used by the Enhance preset for phone photos of documents, unless
+// set explicitly
+const (
+	enhanceContrast = 25  // Percent
+	enhanceSharpen  = 1.0 // Gaussian sigma
+)
+
+// enhanceImage applies despeckling, brightness, contrast and sharpening
+func enhanceImage(img image.Image, opts ConvertOptions) image.Image {
+	contrast, sharpen, despeckle := opts.Contrast, opts.Sharpen, opts.Despeckle
+	if opts.Enhance {
+		if contrast == 0 {
+			contrast = enhanceContrast
+		}
+		if sharpen == 0 {
+			sharpen = enhanceSharpen
+		}
+		despeckle = true
+	}
+
+	// Remove noise first so it isn't amplified by the other adjustments
+	if despeckle {
+		img = medianFilter(img)
+	}
+	if opts.Brightness != 0 {
+		img = imaging.AdjustBrightness(img, opts.Brightness)
+	}
+	if contrast != 0 {
+		img = imaging.AdjustContrast(img, contrast)
+	}
+	if sharpen > 0 {
+		img = imaging.Sharpen(img, sharpen)
+	}
+
+	return img
+}
+
+// medianFilter replaces each pixel with the per-channel median of its 3x3
+// neighborhood, removing isolated specks while keeping edges
+func medianFilter(img image.Image) *image.NRGBA {
+	src := imaging.Clone(img)
+	w, h := src.Rect.Dx(), src.Rect.Dy()
+	dst := image.NewNRGBA(src.Rect)
+
+	var window [9]int
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			i := y*dst.Stride + x*4
+			for c := 0; c < 4; c++ {
+				n := 0
+				for dy := -1; dy <= 1; dy++ {
+					for dx := -1; dx <= 1; dx++ {
+						nx := min(max(x+dx, 0), w-1)
+						ny := min(max(y+dy, 0), h-1)
+						window[n] = int(src.Pix[ny*src.Stride+nx*4+c])
+						n++
+					}
+				}
+				sort.Ints(window[:])
+				dst.Pix[i+c] = uint8(window[4])
+			}
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,58 @@
+package pdftool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+)
+
+// Errors returned by operations, possibly wrapped with more detail; test
+// for them with errors.Is
+var (
+	ErrGhostscriptNotFound = errors.New("ghostscript not found")
+	ErrEncrypted           = errors.New("PDF is encrypted")
+	ErrInvalidPDF          = errors.New("invalid PDF")
+	ErrOutputLarger        = errors.New("output is not smaller than input")
+	ErrTimeout             = errors.New("timed out")
+	ErrPureGo              = errors.New("unsupported in pure-Go mode")
+	ErrInvalidOption       = errors.New("invalid option")
+)
+
+// readError classifies an error from reading a PDF with pdfcpu as
+// ErrEncrypted or ErrInvalidPDF. Files that cannot be opened and canceled
+// or timed out contexts say nothing about the PDF, so those errors are
+// returned unchanged.
+func readError(err error) error {
+	var pathErr *fs.PathError
+	switch {
+	case errors.Is(err, pdfcpu.ErrWrongPassword):
+		return fmt.Errorf("%w; decrypt it first", ErrEncrypted)
+	case errors.Is(err, fs.ErrNotExist), errors.Is(err, fs.ErrPermission), errors.As(err, &pathErr):
+		return err
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded), errors.Is(err, ErrTimeout):
+		return err
+	}
+	return fmt.Errorf("%w: %w", ErrInvalidPDF, err)
+}
+
+// contextError returns the context's error once it is canceled or timed
+// out, with deadlines reported as ErrTimeout, and nil before
+func contextError(ctx context.Context) error {
+	return commandError(ctx, nil)
+}
+
+// commandError returns the context's error if a command was killed because
+// the context was canceled or timed out, and err otherwise. Deadlines are
+// reported as ErrTimeout.
+func commandError(ctx context.Context, err error) error {
+	switch ctxErr := ctx.Err(); {
+	case errors.Is(ctxErr, context.DeadlineExceeded):
+		return fmt.Errorf("%w: %w", ErrTimeout, ctxErr)
+	case ctxErr != nil:
+		return ctxErr
+	}
+	return err
+}
@@ -0,0 +1,225 @@
+package pdftool
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"io/fs"
+	"strings"
+	"time"
+)
+
+// EXIF tags holding capture dates and orientation
+const (
+	tagOrientation      = 0x0112
+	tagDateTime         = 0x0132
+	tagExifIFDPointer   = 0x8769
+	tagDateTimeOriginal = 0x9003
+)
+
+// exifDateTime returns the capture date recorded in a JPEG's EXIF data or a
+// PNG's eXIf chunk, preferring DateTimeOriginal over DateTime
+func exifDateTime(fsys fs.FS, path string) (time.Time, bool) {
+	tiff := readExif(fsys, path)
+	if tiff == nil {
+		return time.Time{}, false
+	}
+
+	return parseExifDate(tiff)
+}
+
+// exifOrientation returns the EXIF orientation of an image (1-8), or 1 when
+// none is recorded
+func exifOrientation(fsys fs.FS, path string) int {
+	tiff := readExif(fsys, path)
+	order := tiffByteOrder(tiff)
+	if order == nil {
+		return 1
+	}
+
+	const typeShort = 3
+	entry, ok := readIFD(tiff, order, order.Uint32(tiff[4:]))[tagOrientation]
+	if !ok || order.Uint16(entry) != typeShort {
+		return 1
+	}
+
+	orientation := int(order.Uint16(entry[6:]))
+	if orientation < 1 || orientation > 8 {
+		return 1
+	}
+	return orientation
+}
+
+// readExif returns the TIFF-structured EXIF block of a JPEG or PNG file in
+// fsys
+func readExif(fsys fs.FS, path string) []byte {
+	file, err := fsys.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	r := bufio.NewReader(file)
+	magic, err := r.Peek(8)
+	if err != nil {
+		return nil
+	}
+
+	switch {
+	case magic[0] == 0xFF && magic[1] == 0xD8:
+		return jpegExifSegment(r)
+	case bytes.Equal(magic, []byte("\x89PNG\r\n\x1a\n")):
+		return pngExifChunk(r)
+	}
+	return nil
+}
+
+// tiffByteOrder returns the byte order of a TIFF block, or nil if it is invalid
+func tiffByteOrder(tiff []byte) binary.ByteOrder {
+	if len(tiff) < 8 {
+		return nil
+	}
+
+	switch string(tiff[:2]) {
+	case "II":
+		return binary.LittleEndian
+	case "MM":
+		return binary.BigEndian
+	}
+	return nil
+}
+
+// jpegExifSegment returns the TIFF payload of a JPEG's APP1 Exif segment
+func jpegExifSegment(r *bufio.Reader) []byte {
+	if _, err := r.Discard(2); err != nil { // SOI
+		return nil
+	}
+
+	for {
+		var marker [4]byte
+		if _, err := io.ReadFull(r, marker[:]); err != nil || marker[0] != 0xFF {
+			return nil
+		}
+
+		// Metadata segments come before the image data starts
+		if marker[1] == 0xDA || marker[1] == 0xD9 {
+			return nil
+		}
+
+		length := int(binary.BigEndian.Uint16(marker[2:])) - 2
+		if length < 0 {
+			return nil
+		}
+
+		segment := make([]byte, length)
+		if _, err := io.ReadFull(r, segment); err != nil {
+			return nil
+		}
+
+		if marker[1] == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return segment[6:]
+		}
+	}
+}
+
+// pngExifChunk returns the payload of a PNG's eXIf chunk
+func pngExifChunk(r *bufio.Reader) []byte {
+	if _, err := r.Discard(8); err != nil { // Signature
+		return nil
+	}
+
+	for {
+		var header [8]byte
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			return nil
+		}
+
+		length := int(binary.BigEndian.Uint32(header[:4]))
+		chunkType := string(header[4:])
+
+		if chunkType == "IDAT" || chunkType == "IEND" {
+			return nil // eXIf must precede the image data
+		}
+
+		if chunkType == "eXIf" {
+			data := make([]byte, length)
+			if _, err := io.ReadFull(r, data); err != nil {
+				return nil
+			}
+			return data
+		}
+
+		if _, err := r.Discard(length + 4); err != nil { // Data and CRC
+			return nil
+		}
+	}
+}
+
+// parseExifDate reads the capture date from a TIFF-structured EXIF block
+func parseExifDate(tiff []byte) (time.Time, bool) {
+	order := tiffByteOrder(tiff)
+	if order == nil {
+		return time.Time{}, false
+	}
+
+	ifd0 := readIFD(tiff, order, order.Uint32(tiff[4:]))
+
+	if ptr, ok := ifd0[tagExifIFDPointer]; ok {
+		exifIFD := readIFD(tiff, order, order.Uint32(ptr[6:]))
+		if t, ok := parseExifTime(tiff, order, exifIFD[tagDateTimeOriginal]); ok {
+			return t, true
+		}
+	}
+
+	return parseExifTime(tiff, order, ifd0[tagDateTime])
+}
+
+// readIFD returns the type, count and value fields of each entry in an image
+// file directory, keyed by tag
+func readIFD(tiff []byte, order binary.ByteOrder, offset uint32) map[uint16][]byte {
+	entries := make(map[uint16][]byte)
+	if int(offset)+2 > len(tiff) {
+		return entries
+	}
+
+	count := int(order.Uint16(tiff[offset:]))
+	for i := 0; i < count; i++ {
+		start := int(offset) + 2 + i*12
+		if start+12 > len(tiff) {
+			break
+		}
+		entry := tiff[start : start+12]
+		entries[order.Uint16(entry)] = entry[2:12] // Type, count, value/offset
+	}
+
+	return entries
+}
+
+// parseExifTime decodes an ASCII date entry in EXIF "2006:01:02 15:04:05" format
+func parseExifTime(tiff []byte, order binary.ByteOrder, entry []byte) (time.Time, bool) {
+	const typeASCII = 2
+	if len(entry) != 10 || order.Uint16(entry) != typeASCII {
+		return time.Time{}, false
+	}
+
+	count := int(order.Uint32(entry[2:]))
+	var value []byte
+	if count <= 4 {
+		value = entry[6 : 6+count]
+	} else {
+		offset := int(order.Uint32(entry[6:]))
+		if offset+count > len(tiff) {
+			return time.Time{}, false
+		}
+		value = tiff[offset : offset+count]
+	}
+
+	text := strings.TrimRight(string(value), "\x00 ")
+	t, err := time.ParseInLocation("2006:01:02 15:04:05", text, time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return t, true
+}
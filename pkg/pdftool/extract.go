@@ -0,0 +1,152 @@
+package pdftool
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+
+	_ "golang.org/x/image/tiff" // pdfcpu exports CMYK and some other images as TIFF
+)
+
+// ExtractImagesOptions holds settings for exporting the images of a PDF
+type ExtractImagesOptions struct {
+	Format  string // "original" keeps the embedded encoding; "png" or "jpg" converts
+	MinSize int64  // Skip images whose embedded data is smaller, e.g. icons
+	Pages   string // Page selection like "1-5,8"; empty for all pages
+	Quality int    // JPEG quality (1-100)
+}
+
+// ExtractImages saves the images embedded in a PDF to outputDir, named after
+// the input, page and image number, and returns their paths. Images shared by
+// several pages are saved once.
+func ExtractImages(ctx context.Context, inputFile, outputDir string, opts ExtractImagesOptions) ([]string, error) {
+	format := strings.ToLower(opts.Format)
+	switch format {
+	case "original", "png":
+	case "jpg", "jpeg":
+		format = "jpg"
+	default:
+		return nil, fmt.Errorf("unsupported image format: %s (supported: original, png, jpg)", opts.Format)
+	}
+
+	if err := checkInputFile(inputFile); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(inputFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PDF: %w", err)
+	}
+
+	conf := newConfig()
+	conf.Cmd = model.EXTRACTIMAGES
+
+	doc, err := await(ctx, func() (*model.Context, error) {
+		return api.ReadValidateAndOptimize(bytes.NewReader(data), conf)
+	})
+	if err != nil {
+		return nil, readError(err)
+	}
+
+	pages, err := pagesForSelection(doc.PageCount, opts.Pages)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	base := strings.TrimSuffix(filepath.Base(inputFile), filepath.Ext(inputFile))
+	digits := len(strconv.Itoa(doc.PageCount))
+
+	var outputFiles []string
+	seen := make(map[int]bool)
+	skipped := 0
+
+	for _, page := range pages {
+		if err := contextError(ctx); err != nil {
+			return outputFiles, err
+		}
+		index := 0
+		for _, objNr := range pdfcpu.ImageObjNrs(doc, page) {
+			if seen[objNr] {
+				continue
+			}
+			seen[objNr] = true
+
+			imageObj := doc.Optimize.ImageObjects[objNr]
+			if embeddedSize(imageObj) < opts.MinSize {
+				skipped++
+				continue
+			}
+
+			img, err := pdfcpu.ExtractImage(doc, imageObj.ImageDict, false, imageObj.ResourceNames[page-1], objNr, false)
+			if err != nil {
+				return outputFiles, fmt.Errorf("failed to extract image %d on page %d: %w", objNr, page, err)
+			}
+			if img == nil {
+				warnf("Skipping an image on page %d: unsupported encoding", page)
+				continue
+			}
+
+			index++
+			name := fmt.Sprintf("%s-p%0*d-%d", base, digits, page, index)
+			outputFile, err := saveExtractedImage(img, filepath.Join(outputDir, name), format, opts.Quality)
+			if err != nil {
+				return outputFiles, err
+			}
+			outputFiles = append(outputFiles, outputFile)
+		}
+	}
+
+	if skipped > 0 {
+		logf("Skipped %d images smaller than %s", skipped, FormatSize(opts.MinSize))
+	}
+	logf("Extracted %d images from %s", len(outputFiles), inputFile)
+	return outputFiles, nil
+}
+
+// embeddedSize returns the size of an image's encoded stream data
+func embeddedSize(imageObj *model.ImageObject) int64 {
+	sd := imageObj.ImageDict
+	if sd.StreamLength != nil {
+		return *sd.StreamLength
+	}
+	return int64(len(sd.Raw))
+}
+
+// saveExtractedImage writes an extracted image to path plus the extension of
+// the chosen format and returns the file name. Images that cannot be decoded
+// for conversion, such as JPEG 2000, are kept in their original encoding.
+func saveExtractedImage(img *model.Image, path, format string, quality int) (string, error) {
+	data, err := io.ReadAll(img)
+	if err != nil {
+		return "", fmt.Errorf("failed to read image data: %w", err)
+	}
+
+	if format != "original" && format != img.FileType {
+		decoded, _, err := image.Decode(bytes.NewReader(data))
+		if err == nil {
+			outputFile := path + "." + format
+			return outputFile, writeImageFile(outputFile, decoded, "."+format, quality)
+		}
+		warnf("Cannot convert %s image to %s, keeping the original: %v", img.FileType, format, err)
+	}
+
+	outputFile := path + "." + img.FileType
+	if err := os.WriteFile(outputFile, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write image file: %w", err)
+	}
+	return outputFile, nil
+}
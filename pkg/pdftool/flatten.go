@@ -0,0 +1,280 @@
+package pdftool
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// Annotation flags that keep an annotation from being shown
+const (
+	annotHidden = 1 << 1
+	annotNoView = 1 << 5
+)
+
+// FlattenPDF draws the appearance of form fields and annotations into the page
+// content and removes them, so the result looks the same in every viewer and
+// can no longer be edited. Links stay clickable. Signatures are flattened
+// too, which invalidates them.
+func FlattenPDF(ctx context.Context, inputFile, outputFile string) error {
+	doc, err := readContext(ctx, inputFile)
+	if err != nil {
+		return err
+	}
+
+	flattened, dropped := 0, 0
+	for page := 1; page <= doc.PageCount; page++ {
+		if err := contextError(ctx); err != nil {
+			return err
+		}
+		f, d, err := flattenPage(doc, page)
+		if err != nil {
+			return fmt.Errorf("failed to flatten page %d: %w", page, err)
+		}
+		flattened += f
+		dropped += d
+	}
+
+	rootDict, err := doc.Catalog()
+	if err != nil {
+		return fmt.Errorf("failed to read catalog: %w", err)
+	}
+	rootDict.Delete("AcroForm")
+
+	if err := writeContextFile(ctx, doc, outputFile); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outputFile, err)
+	}
+
+	if dropped > 0 {
+		warnf("Removed %d hidden annotations or fields without an appearance", dropped)
+	}
+	logf("Flattened %d fields and annotations, wrote %s", flattened, outputFile)
+	return nil
+}
+
+// flattenPage draws the visible annotations of a page into its content and
+// returns the number of annotations drawn and dropped
+func flattenPage(ctx *model.Context, page int) (int, int, error) {
+	pageDict, _, inherited, err := ctx.PageDict(page, false)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	annots, err := ctx.DereferenceArray(pageDict["Annots"])
+	if err != nil || len(annots) == 0 {
+		return 0, 0, err
+	}
+
+	var kept types.Array
+	var draw strings.Builder
+	xobjects := types.Dict{}
+	flattened, dropped := 0, 0
+
+	for _, obj := range annots {
+		annot, err := ctx.DereferenceDict(obj)
+		if err != nil || annot == nil {
+			continue
+		}
+
+		subtype := ""
+		if s := annot.Subtype(); s != nil {
+			subtype = *s
+		}
+		switch subtype {
+		case "Link":
+			kept = append(kept, obj)
+			continue
+		case "Popup":
+			continue // Belongs to a markup annotation that is flattened
+		}
+
+		flags := 0
+		if f, ok := numberValue(annot["F"]); ok {
+			flags = int(f)
+		}
+		ref, appearance := annotAppearance(ctx, annot)
+		if flags&(annotHidden|annotNoView) != 0 || appearance == nil {
+			dropped++
+			continue
+		}
+
+		rect, err := ctx.DereferenceArray(annot["Rect"])
+		if err != nil || len(rect) != 4 {
+			dropped++
+			continue
+		}
+
+		name := fmt.Sprintf("Flat%d", len(xobjects)+1)
+		xobjects[name] = *ref
+		draw.WriteString(fmt.Sprintf("q %s cm /%s Do Q\n", appearanceMatrix(ctx, appearance, rect), name))
+		flattened++
+	}
+
+	if flattened > 0 {
+		if err := addPageXObjects(ctx, pageDict, inherited, xobjects); err != nil {
+			return 0, 0, err
+		}
+		if err := wrapPageContent(ctx, pageDict, draw.String()); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	if len(kept) > 0 {
+		pageDict["Annots"] = kept
+	} else {
+		pageDict.Delete("Annots")
+	}
+	return flattened, dropped, nil
+}
+
+// annotAppearance returns the normal appearance stream of an annotation,
+// picking the current state of check boxes and radio buttons
+func annotAppearance(ctx *model.Context, annot types.Dict) (*types.IndirectRef, *types.StreamDict) {
+	ap, err := ctx.DereferenceDict(annot["AP"])
+	if err != nil || ap == nil {
+		return nil, nil
+	}
+
+	normal := ap["N"]
+	if states, err := ctx.DereferenceDict(normal); err == nil && states != nil {
+		state := annot.NameEntry("AS")
+		if state == nil {
+			return nil, nil
+		}
+		normal = states[*state]
+	}
+
+	ref, ok := normal.(types.IndirectRef)
+	if !ok {
+		return nil, nil
+	}
+	sd, _, err := ctx.DereferenceStreamDict(ref)
+	if err != nil || sd == nil {
+		return nil, nil
+	}
+	sd.InsertName("Type", "XObject")
+	sd.InsertName("Subtype", "Form")
+	return &ref, sd
+}
+
+// appearanceMatrix returns the "a b c d e f" matrix that maps an appearance
+// stream, after its own Matrix, onto the annotation rectangle
+func appearanceMatrix(ctx *model.Context, appearance *types.StreamDict, rect types.Array) string {
+	r := make([]float64, 4)
+	for i, v := range rect {
+		v, _ = ctx.Dereference(v)
+		r[i], _ = numberValue(v)
+	}
+	rx0, rx1 := math.Min(r[0], r[2]), math.Max(r[0], r[2])
+	ry0, ry1 := math.Min(r[1], r[3]), math.Max(r[1], r[3])
+
+	b := []float64{0, 0, rx1 - rx0, ry1 - ry0}
+	if bbox, err := ctx.DereferenceArray(appearance.Dict["BBox"]); err == nil && len(bbox) == 4 {
+		for i, v := range bbox {
+			b[i], _ = numberValue(v)
+		}
+	}
+
+	m := identity
+	if mat, err := ctx.DereferenceArray(appearance.Dict["Matrix"]); err == nil && len(mat) == 6 {
+		for i, v := range mat {
+			m[i], _ = numberValue(v)
+		}
+	}
+
+	// Bounding box of the transformed BBox
+	bx0, by0 := math.Inf(1), math.Inf(1)
+	bx1, by1 := math.Inf(-1), math.Inf(-1)
+	for _, corner := range [][2]float64{{b[0], b[1]}, {b[2], b[1]}, {b[0], b[3]}, {b[2], b[3]}} {
+		x := m[0]*corner[0] + m[2]*corner[1] + m[4]
+		y := m[1]*corner[0] + m[3]*corner[1] + m[5]
+		bx0, bx1 = math.Min(bx0, x), math.Max(bx1, x)
+		by0, by1 = math.Min(by0, y), math.Max(by1, y)
+	}
+
+	sx, sy := 1.0, 1.0
+	if bx1 > bx0 {
+		sx = (rx1 - rx0) / (bx1 - bx0)
+	}
+	if by1 > by0 {
+		sy = (ry1 - ry0) / (by1 - by0)
+	}
+	return fmt.Sprintf("%.4f 0 0 %.4f %.4f %.4f", sx, sy, rx0-bx0*sx, ry0-by0*sy)
+}
+
+// addPageXObjects adds XObjects to the resources of a page, giving the page
+// its own resource dictionaries so that other pages are not affected
+func addPageXObjects(ctx *model.Context, pageDict types.Dict, inherited *model.InheritedPageAttrs, xobjects types.Dict) error {
+	resources, err := ctx.DereferenceDict(pageDict["Resources"])
+	if err != nil {
+		return err
+	}
+	if resources == nil && inherited != nil && inherited.Resources != nil {
+		resources = inherited.Resources
+	}
+	if resources == nil {
+		resources = types.Dict{}
+	}
+	resources = resources.Clone().(types.Dict)
+
+	existing, err := ctx.DereferenceDict(resources["XObject"])
+	if err != nil {
+		return err
+	}
+	merged := types.Dict{}
+	for k, v := range existing {
+		merged[k] = v
+	}
+	for k, v := range xobjects {
+		for merged[k] != nil { // Keep the names used by the page content
+			k += "x"
+		}
+		merged[k] = v
+	}
+
+	resources["XObject"] = merged
+	pageDict["Resources"] = resources
+	return nil
+}
+
+// wrapPageContent saves the graphics state around the existing page content
+// and appends content drawn in the default coordinate system
+func wrapPageContent(ctx *model.Context, pageDict types.Dict, appended string) error {
+	newStream := func(content string) (*types.IndirectRef, error) {
+		sd, err := ctx.NewStreamDictForBuf([]byte(content))
+		if err != nil {
+			return nil, err
+		}
+		if err := sd.Encode(); err != nil {
+			return nil, err
+		}
+		return ctx.IndRefForNewObject(*sd)
+	}
+
+	before, err := newStream("q\n")
+	if err != nil {
+		return err
+	}
+	after, err := newStream("\nQ\n" + appended)
+	if err != nil {
+		return err
+	}
+
+	contents := types.Array{*before}
+	obj, err := ctx.Dereference(pageDict["Contents"])
+	if err != nil {
+		return err
+	}
+	switch v := obj.(type) {
+	case types.Array:
+		contents = append(contents, v...)
+	case types.StreamDict:
+		contents = append(contents, pageDict["Contents"])
+	}
+	pageDict["Contents"] = append(contents, *after)
+	return nil
+}
@@ -0,0 +1,124 @@
+package pdftool
+
+import (
+	"context"
+	"regexp"
+	"sort"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// FontReport describes a font of a PDF for prepress checks
+type FontReport struct {
+	Object   int    `json:"object"`
+	Name     string `json:"name"` // Font name without the subset tag
+	Type     string `json:"type"`
+	Encoding string `json:"encoding,omitempty"`
+	Embedded bool   `json:"embedded"`
+	Subset   bool   `json:"subset"`
+	Bytes    int64  `json:"bytes"` // Size of the embedded font program and glyph data
+	Pages    []int  `json:"pages"`
+}
+
+// subsetTag matches the six capital letters and plus sign in front of the
+// name of a subset font, as in ABCDEF+Helvetica
+var subsetTag = regexp.MustCompile(`^[A-Z]{6}\+`)
+
+// FontReports lists the fonts of a PDF with their type, encoding, embedding
+// and subsetting, the size of their embedded data and the pages using them
+func FontReports(ctx context.Context, inputFile string) ([]FontReport, error) {
+	doc, err := readContext(ctx, inputFile)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := make(map[int][]int)
+	for i, fonts := range doc.Optimize.PageFonts {
+		for objNr := range fonts {
+			pages[objNr] = append(pages[objNr], i+1)
+		}
+	}
+
+	reports := []FontReport{}
+	for objNr, font := range doc.Optimize.FontObjects {
+		r := FontReport{
+			Object:   objNr,
+			Name:     subsetTag.ReplaceAllString(font.FontName, ""),
+			Subset:   subsetTag.MatchString(font.FontName),
+			Encoding: fontEncoding(doc, font.FontDict),
+			Embedded: fontEmbedded(doc, font.FontDict),
+			Pages:    pages[objNr],
+		}
+		if subtype := font.FontDict.Subtype(); subtype != nil {
+			r.Type = *subtype
+		}
+		if r.Type == "Type0" {
+			if cidType := descendantFontType(doc, font.FontDict); cidType != "" {
+				r.Type += "/" + cidType
+			}
+		}
+		for _, nr := range fontStreams(doc, font.FontDict) {
+			r.Bytes += streamSize(doc, nr)
+		}
+		if r.Pages == nil {
+			r.Pages = []int{}
+		}
+		sort.Ints(r.Pages)
+		reports = append(reports, r)
+	}
+
+	sort.Slice(reports, func(i, j int) bool {
+		if reports[i].Name != reports[j].Name {
+			return reports[i].Name < reports[j].Name
+		}
+		return reports[i].Object < reports[j].Object
+	})
+	return reports, nil
+}
+
+// fontEncoding returns the name of a font's encoding, "custom" for encoding
+// dictionaries and "" if the font uses its built-in encoding
+func fontEncoding(ctx *model.Context, font types.Dict) string {
+	obj, err := ctx.Dereference(font["Encoding"])
+	if err != nil {
+		return ""
+	}
+	switch v := obj.(type) {
+	case types.Name:
+		return string(v)
+	case types.Dict:
+		if base := v.NameEntry("BaseEncoding"); base != nil {
+			return *base + " (modified)"
+		}
+		return "custom"
+	case types.StreamDict:
+		return "embedded CMap"
+	}
+	return ""
+}
+
+// descendantFontType returns the subtype of the CID font of a Type0 font,
+// which tells TrueType (CIDFontType2) and CFF (CIDFontType0) outlines apart
+func descendantFontType(ctx *model.Context, font types.Dict) string {
+	descendants, err := ctx.DereferenceArray(font["DescendantFonts"])
+	if err != nil || len(descendants) == 0 {
+		return ""
+	}
+	d, err := ctx.DereferenceDict(descendants[0])
+	if err != nil || d == nil || d.Subtype() == nil {
+		return ""
+	}
+	return *d.Subtype()
+}
+
+// UnembeddedFonts returns the fonts of a report that are not embedded
+func UnembeddedFonts(fonts []FontReport) []FontReport {
+	unembedded := []FontReport{}
+	for _, font := range fonts {
+		if !font.Embedded {
+			unembedded = append(unembedded, font)
+		}
+	}
+	return unembedded
+}
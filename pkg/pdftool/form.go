@@ -0,0 +1,277 @@
+package pdftool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/form"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+// FormField describes a field of an interactive PDF form
+type FormField struct {
+	Name    string   `json:"name"`
+	ID      string   `json:"id"`
+	Type    string   `json:"type"` // text, date, checkbox, radio, combobox or listbox
+	Pages   []int    `json:"pages"`
+	Value   any      `json:"value"` // string, bool for check boxes, []string for list boxes
+	Options []string `json:"options,omitempty"`
+	Locked  bool     `json:"locked,omitempty"`
+}
+
+// ListFormFields returns the fields of the form in a PDF in page order
+func ListFormFields(ctx context.Context, inputFile string) ([]FormField, error) {
+	group, err := exportForm(ctx, inputFile)
+	if err != nil {
+		return nil, err
+	}
+	f := group.Forms[0]
+
+	var fields []FormField
+	for _, tf := range f.TextFields {
+		fields = append(fields, FormField{Name: tf.Name, ID: tf.ID, Type: "text", Pages: tf.Pages, Value: tf.Value, Locked: tf.Locked})
+	}
+	for _, df := range f.DateFields {
+		fields = append(fields, FormField{Name: df.Name, ID: df.ID, Type: "date", Pages: df.Pages, Value: df.Value, Locked: df.Locked})
+	}
+	for _, cb := range f.CheckBoxes {
+		fields = append(fields, FormField{Name: cb.Name, ID: cb.ID, Type: "checkbox", Pages: cb.Pages, Value: cb.Value, Locked: cb.Locked})
+	}
+	for _, rb := range f.RadioButtonGroups {
+		fields = append(fields, FormField{Name: rb.Name, ID: rb.ID, Type: "radio", Pages: rb.Pages, Value: rb.Value, Options: rb.Options, Locked: rb.Locked})
+	}
+	for _, cb := range f.ComboBoxes {
+		fields = append(fields, FormField{Name: cb.Name, ID: cb.ID, Type: "combobox", Pages: cb.Pages, Value: cb.Value, Options: cb.Options, Locked: cb.Locked})
+	}
+	for _, lb := range f.ListBoxes {
+		values := lb.Values
+		if values == nil {
+			values = []string{}
+		}
+		fields = append(fields, FormField{Name: lb.Name, ID: lb.ID, Type: "listbox", Pages: lb.Pages, Value: values, Options: lb.Options, Locked: lb.Locked})
+	}
+
+	sort.SliceStable(fields, func(i, j int) bool { return firstPage(fields[i].Pages) < firstPage(fields[j].Pages) })
+	return fields, nil
+}
+
+// firstPage returns the first page a field appears on
+func firstPage(pages []int) int {
+	if len(pages) == 0 {
+		return 0
+	}
+	return pages[0]
+}
+
+// FormData returns the field values of a form keyed by field name, in the
+// format FillForm reads
+func FormData(fields []FormField) map[string]any {
+	data := make(map[string]any, len(fields))
+	for _, field := range fields {
+		data[field.Key()] = field.Value
+	}
+	return data
+}
+
+// Key returns the name of a field, or its ID for unnamed fields, as used in
+// form data
+func (field FormField) Key() string {
+	if field.Name != "" {
+		return field.Name
+	}
+	return field.ID
+}
+
+// FillForm fills the form of a template PDF with the values of a JSON file
+// and writes the result. The JSON object maps field names (or IDs) to values:
+// strings for text, date, radio and combo box fields, true or false for check
+// boxes and string arrays for list boxes. Files exported by pdfcpu's form
+// export, which have a "forms" list, are accepted as well.
+func FillForm(ctx context.Context, inputFile, dataFile, outputFile string) error {
+	data, err := os.ReadFile(dataFile)
+	if err != nil {
+		return fmt.Errorf("failed to read form data: %w", err)
+	}
+
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(data, &values); err != nil {
+		return fmt.Errorf("invalid form data %s: %w", dataFile, err)
+	}
+
+	filled := len(values)
+	if raw, ok := values["forms"]; !ok || !bytes.HasPrefix(bytes.TrimSpace(raw), []byte("[")) {
+		group, err := exportForm(ctx, inputFile)
+		if err != nil {
+			return err
+		}
+		if err := setFormValues(&group.Forms[0], values); err != nil {
+			return err
+		}
+		if data, err = json.Marshal(group); err != nil {
+			return fmt.Errorf("failed to encode form data: %w", err)
+		}
+	} else {
+		filled = 0
+	}
+
+	template, err := os.ReadFile(inputFile)
+	if err != nil {
+		return fmt.Errorf("failed to open PDF: %w", err)
+	}
+
+	var out bytes.Buffer
+	err = awaitErr(ctx, func() error {
+		return api.FillForm(bytes.NewReader(template), bytes.NewReader(data), &out, newConfig())
+	})
+	if contextError(ctx) != nil {
+		return err
+	}
+	switch {
+	case errors.Is(err, api.ErrNoFormFieldsAffected):
+		// The data matches the current values; pass the template through
+		warnf("The form data does not change any field")
+		out.Reset()
+		out.Write(template)
+	case err != nil:
+		return fmt.Errorf("failed to fill form: %s", strings.TrimSpace(err.Error()))
+	}
+	if err := os.WriteFile(outputFile, out.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outputFile, err)
+	}
+
+	if filled > 0 {
+		logf("Filled %d fields, wrote %s", filled, outputFile)
+	} else {
+		logf("Filled form, wrote %s", outputFile)
+	}
+	return nil
+}
+
+// exportForm reads the form of a PDF in pdfcpu's form export format
+func exportForm(ctx context.Context, inputFile string) (*form.FormGroup, error) {
+	if err := checkInputFile(inputFile); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(inputFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PDF: %w", err)
+	}
+
+	conf := newConfig()
+	conf.Cmd = model.EXPORTFORMFIELDS
+
+	doc, err := await(ctx, func() (*model.Context, error) {
+		return api.ReadValidateAndOptimize(bytes.NewReader(data), conf)
+	})
+	if err != nil {
+		return nil, readError(err)
+	}
+
+	rootDict, err := doc.Catalog()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read catalog: %w", err)
+	}
+	if _, ok := rootDict.Find("AcroForm"); !ok {
+		return nil, fmt.Errorf("no form fields found in %s", inputFile)
+	}
+
+	group, ok, err := form.ExportForm(doc.XRefTable, filepath.Base(inputFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read form: %s", strings.TrimSpace(err.Error()))
+	}
+	if !ok || len(group.Forms) == 0 {
+		return nil, fmt.Errorf("no form fields found in %s", inputFile)
+	}
+	return group, nil
+}
+
+// setFormValues applies values keyed by field name or ID to a form
+func setFormValues(f *form.Form, values map[string]json.RawMessage) error {
+	for key, raw := range values {
+		if err := setFormValue(f, key, raw); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setFormValue sets the value of the field named or identified by key
+func setFormValue(f *form.Form, key string, raw json.RawMessage) error {
+	match := func(id, name string) bool { return name == key || id == key }
+	decode := func(v any, kind string) error {
+		if err := json.Unmarshal(raw, v); err != nil {
+			return fmt.Errorf("field %s expects %s, got %s", key, kind, raw)
+		}
+		return nil
+	}
+	checkOption := func(value string, options []string) error {
+		if value == "" {
+			return nil
+		}
+		for _, option := range options {
+			if option == value {
+				return nil
+			}
+		}
+		return fmt.Errorf("invalid value %q for field %s (options: %s)", value, key, strings.Join(options, ", "))
+	}
+
+	for _, tf := range f.TextFields {
+		if match(tf.ID, tf.Name) {
+			return decode(&tf.Value, "a string")
+		}
+	}
+	for _, df := range f.DateFields {
+		if match(df.ID, df.Name) {
+			return decode(&df.Value, "a string")
+		}
+	}
+	for _, cb := range f.CheckBoxes {
+		if match(cb.ID, cb.Name) {
+			return decode(&cb.Value, "true or false")
+		}
+	}
+	for _, rb := range f.RadioButtonGroups {
+		if match(rb.ID, rb.Name) {
+			if err := decode(&rb.Value, "a string"); err != nil {
+				return err
+			}
+			return checkOption(rb.Value, rb.Options)
+		}
+	}
+	for _, cb := range f.ComboBoxes {
+		if match(cb.ID, cb.Name) {
+			if err := decode(&cb.Value, "a string"); err != nil {
+				return err
+			}
+			if cb.Editable {
+				return nil
+			}
+			return checkOption(cb.Value, cb.Options)
+		}
+	}
+	for _, lb := range f.ListBoxes {
+		if match(lb.ID, lb.Name) {
+			if err := decode(&lb.Values, "a list of strings"); err != nil {
+				return err
+			}
+			for _, value := range lb.Values {
+				if err := checkOption(value, lb.Options); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown form field: %s", key)
+}
@@ -0,0 +1,64 @@
+package pdftool
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"time"
+)
+
+// diskFS is an fs.FS over the operating system's files that, unlike
+// os.DirFS, accepts any path os.Open does, including absolute ones. It is
+// used when callers give no file system.
+type diskFS struct{}
+
+func (diskFS) Open(name string) (fs.File, error) {
+	return os.Open(name)
+}
+
+func (diskFS) Stat(name string) (fs.FileInfo, error) {
+	return os.Stat(name)
+}
+
+// inputFS returns fsys, or the disk if fsys is nil
+func inputFS(fsys fs.FS) fs.FS {
+	if fsys == nil {
+		return diskFS{}
+	}
+	return fsys
+}
+
+// memFS is a read-only fs.FS of in-memory files in a single directory,
+// keyed by name
+type memFS map[string]*memFile
+
+func (m memFS) Open(name string) (fs.File, error) {
+	f, ok := m[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return &openMemFile{info: f, Reader: bytes.NewReader(f.data)}, nil
+}
+
+// memFile is a file of a memFS and its fs.FileInfo
+type memFile struct {
+	name    string
+	data    []byte
+	modTime time.Time
+}
+
+func (f *memFile) Name() string       { return f.name }
+func (f *memFile) Size() int64        { return int64(len(f.data)) }
+func (f *memFile) Mode() fs.FileMode  { return 0o444 }
+func (f *memFile) ModTime() time.Time { return f.modTime }
+func (f *memFile) IsDir() bool        { return false }
+func (f *memFile) Sys() any           { return nil }
+
+// openMemFile is an open memFile
+type openMemFile struct {
+	*bytes.Reader
+	info *memFile
+}
+
+func (f *openMemFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *openMemFile) Close() error               { return nil }
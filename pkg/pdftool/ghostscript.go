@@ -0,0 +1,156 @@
+package pdftool
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ghostscriptProbeTimeout limits each Ghostscript run of DetectGhostscript
+const ghostscriptProbeTimeout = 10 * time.Second
+
+// ghostscriptFilters are the optional decode filters DetectGhostscript
+// checks for
+var ghostscriptFilters = []string{"JPXDecode", "JBIG2Decode", "DCTDecode"}
+
+// GhostscriptInfo describes the installed Ghostscript
+type GhostscriptInfo struct {
+	Path    string   // Absolute path of the executable
+	Version string   // e.g. "10.02.1"
+	Devices []string // Output devices, e.g. "pdfwrite" or "png16m"; empty if unknown
+	Filters []string // Supported decode filters of JPXDecode, JBIG2Decode and DCTDecode
+}
+
+// HasDevice reports whether Ghostscript has an output device
+func (info *GhostscriptInfo) HasDevice(device string) bool {
+	return slices.Contains(info.Devices, device)
+}
+
+// HasFilter reports whether Ghostscript supports a decode filter, e.g.
+// JPXDecode for JPEG 2000 images
+func (info *GhostscriptInfo) HasFilter(filter string) bool {
+	return slices.Contains(info.Filters, filter)
+}
+
+// SupportsPDFA reports whether Ghostscript can write PDF/A of a part, 1, 2
+// or 3; parts 2 and 3 need version 9.10 or later
+func (info *GhostscriptInfo) SupportsPDFA(part int) bool {
+	if !info.HasDevice("pdfwrite") && len(info.Devices) > 0 {
+		return false
+	}
+	switch part {
+	case 1:
+		return true
+	case 2, 3:
+		return info.atLeast(9, 10)
+	}
+	return false
+}
+
+// atLeast reports whether the Ghostscript version is major.minor or later
+func (info *GhostscriptInfo) atLeast(major, minor int) bool {
+	parts := strings.SplitN(info.Version, ".", 3)
+	gotMajor, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return true // Unknown version format; let Ghostscript decide
+	}
+	gotMinor := 0
+	if len(parts) > 1 {
+		gotMinor, _ = strconv.Atoi(parts[1])
+	}
+	return gotMajor > major || gotMajor == major && gotMinor >= minor
+}
+
+// ghostscriptDetection runs probeGhostscript once and returns its result on
+// later calls; SetRunner replaces it
+var ghostscriptDetection atomic.Pointer[func() (*GhostscriptInfo, error)]
+
+// resetGhostscriptDetection makes the next DetectGhostscript probe again
+func resetGhostscriptDetection() {
+	detect := sync.OnceValues(probeGhostscript)
+	ghostscriptDetection.Store(&detect)
+}
+
+// probeGhostscript runs Ghostscript to find its version, devices and filters
+func probeGhostscript() (*GhostscriptInfo, error) {
+	path, err := lookPath(ghostscriptCommand())
+	if err != nil {
+		return nil, ErrGhostscriptNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ghostscriptProbeTimeout)
+	defer cancel()
+
+	out, err := command(ctx, path, "--version").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run %s: %w", path, commandError(ctx, err))
+	}
+	info := &GhostscriptInfo{Path: path, Version: strings.TrimSpace(string(out))}
+
+	// The help lists the devices; older versions exit with an error after it
+	help, _ := command(ctx, path, "-h").Output()
+	info.Devices = parseGhostscriptDevices(help)
+
+	probe := "[/" + strings.Join(ghostscriptFilters, " /") + "] " +
+		"{ dup /Filter resourcestatus { pop pop = } { pop } ifelse } forall"
+	filters, _ := command(ctx, path, "-q", "-dNODISPLAY", "-dNOPAUSE", "-dBATCH", "-dSAFER", "-c", probe).Output()
+	for _, line := range strings.Fields(string(filters)) {
+		if slices.Contains(ghostscriptFilters, line) {
+			info.Filters = append(info.Filters, line)
+		}
+	}
+	return info, nil
+}
+
+// DetectGhostscript returns the path, version, devices and filters of the
+// installed Ghostscript, ErrGhostscriptNotFound, or ErrPureGo in pure-Go
+// mode. Ghostscript is only run the first time; later calls return the same
+// result until SetRunner is called.
+func DetectGhostscript() (*GhostscriptInfo, error) {
+	if PureGo() {
+		return nil, fmt.Errorf("ghostscript: %w", ErrPureGo)
+	}
+	return (*ghostscriptDetection.Load())()
+}
+
+// parseGhostscriptDevices returns the devices listed under "Available
+// devices:" in Ghostscript's help
+func parseGhostscriptDevices(help []byte) []string {
+	var devices []string
+	inDevices := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(help))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "Available devices:"):
+			inDevices = true
+		case inDevices && strings.HasPrefix(line, " "):
+			devices = append(devices, strings.Fields(line)...)
+		default:
+			inDevices = false
+		}
+	}
+	return devices
+}
+
+// checkGhostscriptDevice returns an error if the installed Ghostscript is
+// known to lack an output device. If it cannot be detected, Ghostscript
+// itself reports the problem when it runs.
+func checkGhostscriptDevice(device string) error {
+	info, err := DetectGhostscript()
+	if err != nil || len(info.Devices) == 0 {
+		return nil
+	}
+	if !info.HasDevice(device) {
+		return fmt.Errorf("installed Ghostscript %s has no %s device", info.Version, device)
+	}
+	return nil
+}
@@ -0,0 +1,421 @@
+package pdftool
+
+import (
+	"strings"
+	"unicode/utf16"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// glyphDecoder maps the character codes of a PDF font to text and glyph widths
+type glyphDecoder struct {
+	codeBytes    int               // 1 for simple fonts, usually 2 for Type0 fonts
+	toUnicode    map[uint32]string // From the ToUnicode CMap
+	encoding     [256]string       // Simple fonts without a ToUnicode entry
+	widths       map[uint32]float64
+	defaultWidth float64 // In thousandths of the font size
+}
+
+// glyph is one decoded character code
+type glyph struct {
+	code  uint32
+	text  string
+	width float64 // In thousandths of the font size
+}
+
+// decode splits a shown string into glyphs
+func (f *glyphDecoder) decode(s string) []glyph {
+	glyphs := make([]glyph, 0, len(s)/f.codeBytes)
+	for i := 0; i+f.codeBytes <= len(s); i += f.codeBytes {
+		var code uint32
+		for j := 0; j < f.codeBytes; j++ {
+			code = code<<8 | uint32(s[i+j])
+		}
+
+		text, ok := f.toUnicode[code]
+		if !ok && f.codeBytes == 1 {
+			text = f.encoding[code]
+		}
+
+		width, ok := f.widths[code]
+		if !ok {
+			width = f.defaultWidth
+		}
+
+		glyphs = append(glyphs, glyph{code: code, text: text, width: width})
+	}
+	return glyphs
+}
+
+// loadGlyphDecoder reads the encoding, ToUnicode map and widths of a font dictionary
+func loadGlyphDecoder(ctx *model.Context, d types.Dict) *glyphDecoder {
+	f := &glyphDecoder{codeBytes: 1, widths: make(map[uint32]float64), defaultWidth: 500}
+	f.encoding = winAnsiEncoding
+
+	subtype := ""
+	if s := d.Subtype(); s != nil {
+		subtype = *s
+	}
+
+	if subtype == "Type0" {
+		f.codeBytes = 2
+		f.defaultWidth = 1000
+		if descendants, err := ctx.DereferenceArray(d["DescendantFonts"]); err == nil && len(descendants) > 0 {
+			if cid, err := ctx.DereferenceDict(descendants[0]); err == nil && cid != nil {
+				f.loadCIDWidths(ctx, cid)
+			}
+		}
+	} else {
+		f.loadSimpleEncoding(ctx, d)
+		f.loadSimpleWidths(ctx, d, subtype)
+	}
+
+	if sd, _, err := ctx.DereferenceStreamDict(d["ToUnicode"]); err == nil && sd != nil {
+		if err := sd.Decode(); err == nil {
+			f.toUnicode, f.codeBytes = parseToUnicode(sd.Content, f.codeBytes)
+		}
+	}
+
+	return f
+}
+
+// loadSimpleEncoding applies a simple font's base encoding and differences
+func (f *glyphDecoder) loadSimpleEncoding(ctx *model.Context, d types.Dict) {
+	obj, err := ctx.Dereference(d["Encoding"])
+	if err != nil || obj == nil {
+		return
+	}
+
+	switch enc := obj.(type) {
+	case types.Name:
+		f.encoding = baseEncoding(string(enc))
+	case types.Dict:
+		if base := enc.NameEntry("BaseEncoding"); base != nil {
+			f.encoding = baseEncoding(*base)
+		}
+
+		differences, err := ctx.DereferenceArray(enc["Differences"])
+		if err != nil {
+			return
+		}
+		code := 0
+		for _, entry := range differences {
+			switch v := entry.(type) {
+			case types.Integer:
+				code = v.Value()
+			case types.Name:
+				if code >= 0 && code < 256 {
+					f.encoding[code] = glyphText(string(v))
+				}
+				code++
+			}
+		}
+	}
+}
+
+// loadSimpleWidths reads the Widths array of a simple font
+func (f *glyphDecoder) loadSimpleWidths(ctx *model.Context, d types.Dict, subtype string) {
+	scale := 1.0
+	if subtype == "Type3" {
+		// Type3 widths are in glyph space, usually 1/1000 of text space
+		if matrix, err := ctx.DereferenceArray(d["FontMatrix"]); err == nil && len(matrix) > 0 {
+			if v, ok := numberValue(matrix[0]); ok {
+				scale = v * 1000
+			}
+		}
+	}
+
+	if descriptor, err := ctx.DereferenceDict(d["FontDescriptor"]); err == nil && descriptor != nil {
+		if v, ok := numberValue(descriptor["MissingWidth"]); ok && v > 0 {
+			f.defaultWidth = v * scale
+		}
+	}
+
+	first := 0
+	if v, ok := numberValue(d["FirstChar"]); ok {
+		first = int(v)
+	}
+
+	widths, err := ctx.DereferenceArray(d["Widths"])
+	if err != nil {
+		return
+	}
+	for i, w := range widths {
+		if w, err := ctx.Dereference(w); err == nil {
+			if v, ok := numberValue(w); ok {
+				f.widths[uint32(first+i)] = v * scale
+			}
+		}
+	}
+}
+
+// loadCIDWidths reads the DW and W entries of a CID font
+func (f *glyphDecoder) loadCIDWidths(ctx *model.Context, cid types.Dict) {
+	if v, ok := numberValue(cid["DW"]); ok {
+		f.defaultWidth = v
+	}
+
+	w, err := ctx.DereferenceArray(cid["W"])
+	if err != nil {
+		return
+	}
+
+	// Entries are "c [w1 w2 ...]" or "cFirst cLast w"
+	for i := 0; i+1 < len(w); {
+		first, ok := numberValue(w[i])
+		if !ok {
+			return
+		}
+
+		if list, err := ctx.DereferenceArray(w[i+1]); err == nil && list != nil {
+			for j, width := range list {
+				if v, ok := numberValue(width); ok {
+					f.widths[uint32(int(first)+j)] = v
+				}
+			}
+			i += 2
+			continue
+		}
+
+		if i+2 >= len(w) {
+			return
+		}
+		last, ok1 := numberValue(w[i+1])
+		width, ok2 := numberValue(w[i+2])
+		if !ok1 || !ok2 {
+			return
+		}
+		for code := int(first); code <= int(last) && code-int(first) < 65536; code++ {
+			f.widths[uint32(code)] = width
+		}
+		i += 3
+	}
+}
+
+// numberValue returns the value of an integer or real object
+func numberValue(obj types.Object) (float64, bool) {
+	switch v := obj.(type) {
+	case types.Integer:
+		return float64(v.Value()), true
+	case types.Float:
+		return v.Value(), true
+	}
+	return 0, false
+}
+
+// parseToUnicode reads the code-to-text mappings of a ToUnicode CMap and the
+// code length in bytes given by its codespace ranges
+func parseToUnicode(data []byte, codeBytes int) (map[uint32]string, int) {
+	mapping := make(map[uint32]string)
+	lexer := contentLexer{data: data}
+	var operands []any
+
+	for {
+		value, op, ok := lexer.next()
+		if !ok {
+			break
+		}
+		if op == "" {
+			operands = append(operands, value)
+			continue
+		}
+
+		switch op {
+		case "endcodespacerange":
+			if len(operands) > 0 {
+				if lo, ok := operands[0].(string); ok && len(lo) > 0 {
+					codeBytes = len(lo)
+				}
+			}
+		case "endbfchar":
+			for i := 0; i+1 < len(operands); i += 2 {
+				src, ok1 := operands[i].(string)
+				dst, ok2 := operands[i+1].(string)
+				if ok1 && ok2 {
+					mapping[codeValue(src)] = utf16Text(dst)
+				}
+			}
+		case "endbfrange":
+			for i := 0; i+2 < len(operands); i += 3 {
+				lo, ok1 := operands[i].(string)
+				hi, ok2 := operands[i+1].(string)
+				if !ok1 || !ok2 {
+					continue
+				}
+				start, end := codeValue(lo), codeValue(hi)
+				if end < start || end-start > 65535 {
+					continue
+				}
+
+				switch dst := operands[i+2].(type) {
+				case string:
+					units := utf16.Decode(utf16Units(dst))
+					for code := start; code <= end; code++ {
+						if len(units) > 0 {
+							mapping[code] = string(units)
+							units[len(units)-1]++
+						}
+					}
+				case []any:
+					for j, item := range dst {
+						if s, ok := item.(string); ok && start+uint32(j) <= end {
+							mapping[start+uint32(j)] = utf16Text(s)
+						}
+					}
+				}
+			}
+		}
+		operands = operands[:0]
+	}
+
+	return mapping, codeBytes
+}
+
+// codeValue converts big-endian code bytes to a number
+func codeValue(s string) uint32 {
+	var code uint32
+	for i := 0; i < len(s); i++ {
+		code = code<<8 | uint32(s[i])
+	}
+	return code
+}
+
+// utf16Units splits UTF-16BE bytes into code units
+func utf16Units(s string) []uint16 {
+	units := make([]uint16, 0, len(s)/2)
+	for i := 0; i+1 < len(s); i += 2 {
+		units = append(units, uint16(s[i])<<8|uint16(s[i+1]))
+	}
+	return units
+}
+
+// utf16Text decodes UTF-16BE bytes
+func utf16Text(s string) string {
+	return string(utf16.Decode(utf16Units(s)))
+}
+
+// Glyph names of the printable ASCII characters from 0x20 and of the
+// Windows-1252 characters from 0x80, in code order; "-" marks unused codes
+const (
+	asciiGlyphNames = "space exclam quotedbl numbersign dollar percent ampersand quotesingle " +
+		"parenleft parenright asterisk plus comma hyphen period slash " +
+		"zero one two three four five six seven eight nine colon semicolon less equal greater question at"
+	asciiGlyphNames2 = "bracketleft backslash bracketright asciicircum underscore grave"
+	asciiGlyphNames3 = "braceleft bar braceright asciitilde"
+	highGlyphNames   = "Euro - quotesinglbase florin quotedblbase ellipsis dagger daggerdbl " +
+		"circumflex perthousand Scaron guilsinglleft OE - Zcaron - " +
+		"- quoteleft quoteright quotedblleft quotedblright bullet endash emdash " +
+		"tilde trademark scaron guilsinglright oe - zcaron Ydieresis " +
+		"space exclamdown cent sterling currency yen brokenbar section " +
+		"dieresis copyright ordfeminine guillemotleft logicalnot hyphen registered macron " +
+		"degree plusminus twosuperior threesuperior acute mu paragraph periodcentered " +
+		"cedilla onesuperior ordmasculine guillemotright onequarter onehalf threequarters questiondown " +
+		"Agrave Aacute Acircumflex Atilde Adieresis Aring AE Ccedilla " +
+		"Egrave Eacute Ecircumflex Edieresis Igrave Iacute Icircumflex Idieresis " +
+		"Eth Ntilde Ograve Oacute Ocircumflex Otilde Odieresis multiply " +
+		"Oslash Ugrave Uacute Ucircumflex Udieresis Yacute Thorn germandbls " +
+		"agrave aacute acircumflex atilde adieresis aring ae ccedilla " +
+		"egrave eacute ecircumflex edieresis igrave iacute icircumflex idieresis " +
+		"eth ntilde ograve oacute ocircumflex otilde odieresis divide " +
+		"oslash ugrave uacute ucircumflex udieresis yacute thorn ydieresis"
+)
+
+// cp1252High holds the characters for codes 0x80-0x9F of Windows-1252
+const cp1252High = "€�‚ƒ„…†‡ˆ‰Š‹Œ�Ž��‘’“”•–—˜™š›œ�žŸ"
+
+var (
+	// winAnsiEncoding maps codes to text for WinAnsiEncoding, also used
+	// when a simple font specifies no encoding
+	winAnsiEncoding [256]string
+
+	// glyphNames maps glyph names used in encoding differences to text
+	glyphNames = map[string]string{
+		"fi": "fi", "fl": "fl", "ff": "ff", "ffi": "ffi", "ffl": "ffl",
+		"minus": "−", "fraction": "⁄", "dotlessi": "ı", "Lslash": "Ł", "lslash": "ł",
+		"quotedbl": "\"", "nbspace": " ", "sfthyphen": "­",
+	}
+)
+
+func init() {
+	for c := 0x20; c < 0x7F; c++ {
+		winAnsiEncoding[c] = string(rune(c))
+	}
+	for i, r := range []rune(cp1252High) {
+		if r != '�' {
+			winAnsiEncoding[0x80+i] = string(r)
+		}
+	}
+	for c := 0xA0; c <= 0xFF; c++ {
+		winAnsiEncoding[c] = string(rune(c))
+	}
+
+	name := func(names string, first int) {
+		for i, n := range strings.Fields(names) {
+			if n != "-" {
+				if _, ok := glyphNames[n]; !ok {
+					glyphNames[n] = winAnsiEncoding[first+i]
+				}
+			}
+		}
+	}
+	name(asciiGlyphNames, 0x20)
+	name(asciiGlyphNames2, 0x5B)
+	name(asciiGlyphNames3, 0x7B)
+	name(highGlyphNames, 0x80)
+	for c := 'A'; c <= 'Z'; c++ {
+		glyphNames[string(c)] = string(c)
+		glyphNames[string(c+'a'-'A')] = string(c + 'a' - 'A')
+	}
+}
+
+// baseEncoding returns the code-to-text table of a named base encoding.
+// MacRoman and Standard are approximated by their common ASCII subset.
+func baseEncoding(name string) [256]string {
+	if name == "WinAnsiEncoding" {
+		return winAnsiEncoding
+	}
+
+	var enc [256]string
+	for c := 0x20; c < 0x7F; c++ {
+		enc[c] = string(rune(c))
+	}
+	if name == "StandardEncoding" {
+		enc['\''] = "’"
+		enc['`'] = "‘"
+	}
+	return enc
+}
+
+// glyphText returns the text for a glyph name such as "eacute" or "uni20AC"
+func glyphText(name string) string {
+	if text, ok := glyphNames[name]; ok {
+		return text
+	}
+
+	base, _, _ := strings.Cut(name, ".") // Variants like "a.sc"
+	if text, ok := glyphNames[base]; ok {
+		return text
+	}
+
+	for _, prefix := range []string{"uni", "u"} {
+		if hexCode, ok := strings.CutPrefix(base, prefix); ok && len(hexCode) >= 4 && len(hexCode) <= 6 {
+			var r rune
+			valid := true
+			for _, c := range hexCode[:4] {
+				switch {
+				case c >= '0' && c <= '9':
+					r = r*16 + c - '0'
+				case c >= 'A' && c <= 'F':
+					r = r*16 + c - 'A' + 10
+				default:
+					valid = false
+				}
+			}
+			if valid {
+				return string(r)
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,37 @@
+package pdftool
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+)
+
+// GrayscalePDF converts all colors of a PDF, in images as well as text and
+// vector graphics, to DeviceGray using Ghostscript. Images keep their
+// resolution, so only the color changes.
+func GrayscalePDF(ctx context.Context, inputFile, outputFile string) error {
+	if err := checkInputFile(inputFile); err != nil {
+		return err
+	}
+	if !isGhostscriptAvailable() {
+		return programMissing("ghostscript", "install it to convert PDFs to grayscale")
+	}
+
+	err := ghostscriptDistill(ctx, inputFile, outputFile,
+		"-sColorConversionStrategy=Gray",
+		"-dProcessColorModel=/DeviceGray",
+		"-dOverrideICC=true", // Convert colors with embedded ICC profiles too
+	)
+	if err != nil {
+		return fmt.Errorf("ghostscript grayscale conversion failed: %w", err)
+	}
+
+	pageCount, err := api.PageCountFile(outputFile)
+	if err != nil {
+		return fmt.Errorf("failed to read converted PDF: %w", err)
+	}
+
+	logf("Converted %d pages to grayscale, wrote %s", pageCount, outputFile)
+	return nil
+}
@@ -0,0 +1,106 @@
+package pdftool
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+// HeaderFooterOptions controls the header and footer text stamped onto pages
+type HeaderFooterOptions struct {
+	Header      string // Template; see headerFooterText
+	Footer      string
+	HeaderAlign string // left, center or right
+	FooterAlign string
+	Pages       string // Empty or "all" stamps every page
+	Font        string
+	FontSize    int
+	Color       string  // #rrggbb
+	Margin      float64 // Distance from the page edges in points
+}
+
+// AddHeaderFooter stamps header and footer text onto the selected pages of a PDF
+func AddHeaderFooter(ctx context.Context, inputFile, outputFile string, opts HeaderFooterOptions) error {
+	if opts.Header == "" && opts.Footer == "" {
+		return fmt.Errorf("no header or footer text given")
+	}
+	if opts.FontSize <= 0 {
+		return fmt.Errorf("invalid font size: %d", opts.FontSize)
+	}
+	for _, align := range []string{opts.HeaderAlign, opts.FooterAlign} {
+		if align != "left" && align != "center" && align != "right" {
+			return fmt.Errorf("invalid alignment: %s (expected left, center or right)", align)
+		}
+	}
+
+	doc, err := readContext(ctx, inputFile)
+	if err != nil {
+		return err
+	}
+
+	pages, err := stampSelection(doc.PageCount, opts.Pages)
+	if err != nil {
+		return err
+	}
+
+	date := time.Now().Format("2006-01-02")
+	bands := []struct {
+		template, vertical, align string
+	}{
+		{opts.Header, "top", opts.HeaderAlign},
+		{opts.Footer, "bottom", opts.FooterAlign},
+	}
+
+	stamps := make(map[int][]*model.Watermark)
+	for _, page := range pages {
+		for _, band := range bands {
+			if band.template == "" {
+				continue
+			}
+
+			text := headerFooterText(band.template, inputFile, page, doc.PageCount, date)
+			wm, err := textStamp(text, textStyle{
+				Font:     opts.Font,
+				FontSize: opts.FontSize,
+				Color:    opts.Color,
+				Position: band.vertical + "-" + band.align,
+				Margin:   opts.Margin,
+			})
+			if err != nil {
+				return err
+			}
+			stamps[page] = append(stamps[page], wm)
+		}
+	}
+
+	if err := addStamps(ctx, doc, stamps, outputFile); err != nil {
+		return err
+	}
+
+	logf("Added header/footer to %d of %d pages, wrote %s", len(pages), doc.PageCount, outputFile)
+	return nil
+}
+
+// headerFooterText expands a header or footer template. Supported placeholders:
+//
+//	{page}      page number
+//	{total}     number of pages in the document
+//	{date}      today's date (YYYY-MM-DD)
+//	{filename}  input file name with extension
+//	{name}      input file name without extension
+func headerFooterText(template, file string, page, total int, date string) string {
+	base := filepath.Base(file)
+
+	return strings.NewReplacer(
+		"{page}", strconv.Itoa(page),
+		"{total}", strconv.Itoa(total),
+		"{date}", date,
+		"{filename}", base,
+		"{name}", strings.TrimSuffix(base, filepath.Ext(base)),
+	).Replace(template)
+}
@@ -0,0 +1,75 @@
+package pdftool
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Hook runs before or after a file is processed, e.g. to checksum the
+// original or upload the result. An error from a pre-hook skips the file.
+type Hook func(ctx context.Context, event HookEvent) error
+
+// HookEvent describes the file a Hook runs for
+type HookEvent struct {
+	InputFile  string
+	OutputFile string
+	Result     *Result // Set for post-hooks of compressions
+}
+
+// run calls h, if set, wrapping its error with the hook's role
+func (h Hook) run(ctx context.Context, role string, event HookEvent) error {
+	if h == nil {
+		return nil
+	}
+	if err := h(ctx, event); err != nil {
+		return fmt.Errorf("%s failed: %w", role, err)
+	}
+	return nil
+}
+
+// CommandHook returns a hook that runs a command template like
+// "sha256sum {in}" or "aws s3 cp {out} s3://bucket/". The placeholders {in}
+// and {out} are replaced with the file paths, and the command also gets them
+// in PDF_TOOL_INPUT and PDF_TOOL_OUTPUT. After a compression PDF_TOOL_ENGINE,
+// PDF_TOOL_INPUT_SIZE and PDF_TOOL_OUTPUT_SIZE are set as well. Commands run
+// without a shell; use e.g. sh -c '...' for pipes.
+func CommandHook(template string) (Hook, error) {
+	args, err := splitCommand(template)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hook command: %w", err)
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("invalid hook command: empty")
+	}
+
+	return func(ctx context.Context, event HookEvent) error {
+		replacer := strings.NewReplacer("{in}", event.InputFile, "{out}", event.OutputFile)
+		expanded := make([]string, len(args))
+		for i, arg := range args {
+			expanded[i] = replacer.Replace(arg)
+		}
+
+		cmd := command(ctx, expanded[0], expanded[1:]...)
+		cmd.Env = append(os.Environ(),
+			"PDF_TOOL_INPUT="+event.InputFile,
+			"PDF_TOOL_OUTPUT="+event.OutputFile,
+		)
+		if event.Result != nil {
+			cmd.Env = append(cmd.Env,
+				"PDF_TOOL_ENGINE="+event.Result.Engine,
+				"PDF_TOOL_INPUT_SIZE="+strconv.FormatInt(event.Result.InputSize, 10),
+				"PDF_TOOL_OUTPUT_SIZE="+strconv.FormatInt(event.Result.OutputSize, 10),
+			)
+		}
+		cmd.Stdout = os.Stderr // Keep stdout for the caller's own output
+		cmd.Stderr = os.Stderr
+
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s: %w", expanded[0], commandError(ctx, err))
+		}
+		return nil
+	}, nil
+}
@@ -0,0 +1,124 @@
+package pdftool
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+)
+
+// sRGBDescription names the sRGB color space in ICC profiles and output intents
+const sRGBDescription = "sRGB IEC61966-2.1"
+
+// iccTag is a tag signature and its encoded data
+type iccTag struct {
+	sig  string
+	data []byte
+}
+
+// sRGBICCProfile builds a compact ICC v2 display profile for sRGB, with the
+// primaries adapted to the D50 profile connection space
+func sRGBICCProfile() []byte {
+	trc := iccCurve(1024, func(x float64) float64 {
+		if x <= 0.04045 {
+			return x / 12.92
+		}
+		return math.Pow((x+0.055)/1.055, 2.4)
+	})
+
+	tags := []iccTag{
+		{"desc", iccTextDescription(sRGBDescription)},
+		{"cprt", iccText("No copyright, use freely")},
+		{"wtpt", iccXYZ(0.9642, 1.0, 0.8249)},
+		{"rXYZ", iccXYZ(0.4361, 0.2225, 0.0139)},
+		{"gXYZ", iccXYZ(0.3851, 0.7169, 0.0971)},
+		{"bXYZ", iccXYZ(0.1431, 0.0606, 0.7141)},
+		{"rTRC", trc},
+		{"gTRC", trc},
+		{"bTRC", trc},
+	}
+
+	// Lay out tag data after the header and tag table, 4-byte aligned;
+	// identical data (the shared TRC) is stored once
+	offset := 128 + 4 + 12*len(tags)
+	offsets := make(map[string]int)
+	var body bytes.Buffer
+	table := make([]byte, 0, 12*len(tags))
+
+	for _, tag := range tags {
+		pos, ok := offsets[string(tag.data)]
+		if !ok {
+			pos = offset + body.Len()
+			offsets[string(tag.data)] = pos
+			body.Write(tag.data)
+			for body.Len()%4 != 0 {
+				body.WriteByte(0)
+			}
+		}
+
+		table = append(table, tag.sig...)
+		table = binary.BigEndian.AppendUint32(table, uint32(pos))
+		table = binary.BigEndian.AppendUint32(table, uint32(len(tag.data)))
+	}
+
+	size := offset + body.Len()
+	header := make([]byte, 128)
+	binary.BigEndian.PutUint32(header[0:], uint32(size))
+	binary.BigEndian.PutUint32(header[8:], 0x02100000) // Version 2.1
+	copy(header[12:], "mntr")                          // Display device class
+	copy(header[16:], "RGB ")
+	copy(header[20:], "XYZ ")
+	for i, v := range []uint16{2024, 1, 1, 0, 0, 0} { // Creation date
+		binary.BigEndian.PutUint16(header[24+2*i:], v)
+	}
+	copy(header[36:], "acsp")
+	copy(header[68:], iccXYZ(0.9642, 1.0, 0.8249)[8:]) // D50 illuminant
+
+	profile := make([]byte, 0, size)
+	profile = append(profile, header...)
+	profile = binary.BigEndian.AppendUint32(profile, uint32(len(tags)))
+	profile = append(profile, table...)
+	return append(profile, body.Bytes()...)
+}
+
+// iccS15Fixed16 encodes a signed 15.16 fixed-point number
+func iccS15Fixed16(v float64) uint32 {
+	return uint32(int32(math.Round(v * 65536)))
+}
+
+// iccXYZ encodes an XYZType tag
+func iccXYZ(x, y, z float64) []byte {
+	b := []byte("XYZ \x00\x00\x00\x00")
+	for _, v := range []float64{x, y, z} {
+		b = binary.BigEndian.AppendUint32(b, iccS15Fixed16(v))
+	}
+	return b
+}
+
+// iccCurve encodes a curveType tag sampling f over [0, 1]
+func iccCurve(n int, f func(float64) float64) []byte {
+	b := []byte("curv\x00\x00\x00\x00")
+	b = binary.BigEndian.AppendUint32(b, uint32(n))
+	for i := 0; i < n; i++ {
+		v := f(float64(i) / float64(n-1))
+		b = binary.BigEndian.AppendUint16(b, uint16(math.Round(v*65535)))
+	}
+	return b
+}
+
+// iccText encodes a textType tag
+func iccText(s string) []byte {
+	b := []byte("text\x00\x00\x00\x00")
+	b = append(b, s...)
+	return append(b, 0)
+}
+
+// iccTextDescription encodes a textDescriptionType tag with ASCII text only
+func iccTextDescription(s string) []byte {
+	b := []byte("desc\x00\x00\x00\x00")
+	b = binary.BigEndian.AppendUint32(b, uint32(len(s)+1))
+	b = append(b, s...)
+	b = append(b, 0)
+	b = append(b, make([]byte, 4+4)...) // Unicode language code and count
+	b = append(b, make([]byte, 2+1)...) // ScriptCode code and count
+	return append(b, make([]byte, 67)...)
+}
@@ -0,0 +1,72 @@
+package pdftool
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"strconv"
+	"strings"
+)
+
+// bilevelPalette is the two-color palette used for black-and-white output
+var bilevelPalette = color.Palette{color.Black, color.White}
+
+// toGrayscale converts an image to 8-bit grayscale
+func toGrayscale(img image.Image) *image.Gray {
+	bounds := img.Bounds()
+	gray := image.NewGray(bounds)
+	draw.Draw(gray, bounds, img, bounds.Min, draw.Src)
+	return gray
+}
+
+// toBilevel converts an image to pure black and white, either by thresholding
+// each pixel or by Floyd-Steinberg dithering
+func toBilevel(img image.Image, threshold uint8, dither bool) *image.Paletted {
+	gray := toGrayscale(img)
+	bounds := gray.Bounds()
+	bw := image.NewPaletted(bounds, bilevelPalette)
+
+	if dither {
+		draw.FloydSteinberg.Draw(bw, bounds, gray, bounds.Min)
+		return bw
+	}
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if gray.GrayAt(x, y).Y >= threshold {
+				bw.SetColorIndex(x, y, 1)
+			}
+		}
+	}
+
+	return bw
+}
+
+// ParseHexColor parses a color in #rrggbb or #rgb notation
+func ParseHexColor(s string) (color.Color, error) {
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil || len(hex) != 6 {
+		return nil, fmt.Errorf("invalid color: %s (expected #rrggbb)", s)
+	}
+
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}, nil
+}
+
+// flattenAlpha composites an image with transparency over a solid background
+func flattenAlpha(img image.Image, background color.Color) image.Image {
+	if o, ok := img.(interface{ Opaque() bool }); ok && o.Opaque() {
+		return img
+	}
+
+	bounds := img.Bounds()
+	flat := image.NewRGBA(bounds)
+	draw.Draw(flat, bounds, image.NewUniform(background), image.Point{}, draw.Src)
+	draw.Draw(flat, bounds, img, bounds.Min, draw.Over)
+	return flat
+}
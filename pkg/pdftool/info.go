@@ -0,0 +1,195 @@
+package pdftool
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// PDFInfo summarizes a PDF's structure and contents
+type PDFInfo struct {
+	File       string     `json:"file"`
+	FileSize   int64      `json:"fileSize"`
+	Version    string     `json:"version"`
+	PageCount  int        `json:"pageCount"`
+	PageSizes  []PageSize `json:"pageSizes"`
+	Encrypted  bool       `json:"encrypted"`
+	Title      string     `json:"title,omitempty"`
+	Author     string     `json:"author,omitempty"`
+	Creator    string     `json:"creator,omitempty"`
+	Producer   string     `json:"producer,omitempty"`
+	Fonts      []FontInfo `json:"fonts"`
+	ImageCount int        `json:"imageCount"`
+	ImageBytes int64      `json:"imageBytes"`
+}
+
+// PageSize is a page size in points and the number of pages using it
+type PageSize struct {
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+	Name   string  `json:"name,omitempty"` // Paper size such as A4, if it matches one
+	Pages  int     `json:"pages"`
+}
+
+// FontInfo describes a font used by a PDF
+type FontInfo struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Embedded bool   `json:"embedded"`
+}
+
+// paperSizes are common paper sizes in portrait orientation, in points
+var paperSizes = []struct {
+	name          string
+	width, height float64
+}{
+	{"A3", 842, 1191},
+	{"A4", 595, 842},
+	{"A5", 420, 595},
+	{"Letter", 612, 792},
+	{"Legal", 612, 1008},
+	{"Tabloid", 792, 1224},
+}
+
+// GetPDFInfo reads a PDF and summarizes its page sizes, fonts and images.
+// The password is only needed for encrypted PDFs.
+func GetPDFInfo(ctx context.Context, inputFile, password string) (*PDFInfo, error) {
+	doc, err := readEncryptedContext(ctx, inputFile, password, password)
+	if err != nil {
+		return nil, err
+	}
+	if err := awaitErr(ctx, func() error { return api.ValidateContext(doc) }); err != nil {
+		return nil, fmt.Errorf("failed to validate PDF: %w", err)
+	}
+	if err := awaitErr(ctx, func() error { return api.OptimizeContext(doc) }); err != nil {
+		return nil, fmt.Errorf("failed to read PDF resources: %w", err)
+	}
+
+	pages, err := pagesForSelection(doc.PageCount, "")
+	if err != nil {
+		return nil, err
+	}
+
+	details, err := pdfcpu.Info(doc, inputFile, pageSet(pages), false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read PDF info: %w", err)
+	}
+
+	info := &PDFInfo{
+		File:      inputFile,
+		Version:   details.Version,
+		PageCount: doc.PageCount,
+		Encrypted: details.Encrypted,
+		Title:     details.Title,
+		Author:    details.Author,
+		Creator:   details.Creator,
+		Producer:  details.Producer,
+		Fonts:     []FontInfo{},
+	}
+
+	if stat, err := os.Stat(inputFile); err == nil {
+		info.FileSize = stat.Size()
+	}
+
+	dims, err := doc.PageDims()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read page sizes: %w", err)
+	}
+	for _, dim := range dims {
+		width, height := math.Round(dim.Width*10)/10, math.Round(dim.Height*10)/10
+		found := false
+		for i := range info.PageSizes {
+			if info.PageSizes[i].Width == width && info.PageSizes[i].Height == height {
+				info.PageSizes[i].Pages++
+				found = true
+				break
+			}
+		}
+		if !found {
+			info.PageSizes = append(info.PageSizes, PageSize{Width: width, Height: height, Name: paperSizeName(width, height), Pages: 1})
+		}
+	}
+
+	seen := make(map[FontInfo]bool)
+	for _, font := range doc.Optimize.FontObjects {
+		f := FontInfo{Name: font.FontName, Type: font.SubType(), Embedded: fontEmbedded(doc, font.FontDict)}
+		if !seen[f] {
+			seen[f] = true
+			info.Fonts = append(info.Fonts, f)
+		}
+	}
+	sort.Slice(info.Fonts, func(i, j int) bool { return info.Fonts[i].Name < info.Fonts[j].Name })
+
+	// Images shared between pages are counted once
+	images, _, err := pdfcpu.Images(doc, pageSet(pages))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list images: %w", err)
+	}
+	counted := make(map[int]bool)
+	for _, pageImages := range images {
+		for objNr, img := range pageImages {
+			if !counted[objNr] && !img.Thumb {
+				counted[objNr] = true
+				info.ImageCount++
+				info.ImageBytes += img.Size
+			}
+		}
+	}
+
+	return info, nil
+}
+
+// fontEmbedded reports whether a font program is embedded for a font; Type0
+// fonts carry it in their descendant font and Type3 glyphs are always inline
+func fontEmbedded(ctx *model.Context, font types.Dict) bool {
+	subtype := font.Subtype()
+	if subtype == nil {
+		return false
+	}
+
+	switch *subtype {
+	case "Type3":
+		return true
+	case "Type0":
+		descendants, err := ctx.DereferenceArray(font["DescendantFonts"])
+		if err != nil || len(descendants) == 0 {
+			return false
+		}
+		if font, err = ctx.DereferenceDict(descendants[0]); err != nil || font == nil {
+			return false
+		}
+	}
+
+	descriptor, err := ctx.DereferenceDict(font["FontDescriptor"])
+	if err != nil || descriptor == nil {
+		return false
+	}
+
+	for _, key := range []string{"FontFile", "FontFile2", "FontFile3"} {
+		if _, ok := descriptor.Find(key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// paperSizeName returns the name of a paper size matching the page size in
+// either orientation, or "" if there is none
+func paperSizeName(width, height float64) string {
+	for _, paper := range paperSizes {
+		if math.Abs(width-paper.width) <= 1 && math.Abs(height-paper.height) <= 1 {
+			return paper.name
+		}
+		if math.Abs(width-paper.height) <= 1 && math.Abs(height-paper.width) <= 1 {
+			return paper.name + " landscape"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,209 @@
+package pdftool
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Sort modes for images collected from directories
+const (
+	SortName     = "name"      // Byte-wise file name order
+	SortNatural  = "natural"   // Numbers compared by value, so page2 < page10
+	SortMtime    = "mtime"     // File modification time
+	SortExifDate = "exif-date" // EXIF capture date, falling back to mtime
+	SortNone     = "none"      // Directory listing or archive entry order
+)
+
+// CollectImages expands directories and ZIP archives into the supported images
+// they contain, ordered by sortMode. Files given explicitly keep their
+// command-line order. Images from archives are extracted to a temporary
+// directory, which the returned cleanup function removes.
+func CollectImages(paths []string, sortMode string) ([]string, func(), error) {
+	var images []string
+	var removeDirs []func()
+
+	cleanup := func() {
+		for _, remove := range removeDirs {
+			remove()
+		}
+	}
+
+	for _, path := range paths {
+		var found []string
+		info, err := os.Stat(path)
+
+		switch {
+		case err == nil && info.IsDir():
+			if found, err = listImages(path); err != nil {
+				cleanup()
+				return nil, nil, err
+			}
+			if len(found) == 0 {
+				cleanup()
+				return nil, nil, fmt.Errorf("no PNG or JPEG images found in directory: %s", path)
+			}
+		case err == nil && strings.EqualFold(filepath.Ext(path), ".zip"):
+			dir, removeDir, err := TempDir("", "pdf-tool-zip-")
+			if err != nil {
+				cleanup()
+				return nil, nil, fmt.Errorf("failed to create temp directory: %w", err)
+			}
+			removeDirs = append(removeDirs, removeDir)
+
+			if found, err = extractImages(path, dir); err != nil {
+				cleanup()
+				return nil, nil, err
+			}
+			if len(found) == 0 {
+				cleanup()
+				return nil, nil, fmt.Errorf("no PNG or JPEG images found in archive: %s", path)
+			}
+		default:
+			images = append(images, path) // Validated later by the converter
+			continue
+		}
+
+		if err := sortFiles(found, sortMode); err != nil {
+			cleanup()
+			return nil, nil, err
+		}
+		images = append(images, found...)
+	}
+
+	return images, cleanup, nil
+}
+
+// isImageFile reports whether a file name has a supported image extension
+func isImageFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".png", ".jpg", ".jpeg":
+		return true
+	}
+	return false
+}
+
+// listImages returns the supported image files directly inside a directory
+func listImages(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory: %w", err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if !entry.IsDir() && isImageFile(entry.Name()) {
+			files = append(files, filepath.Join(dir, entry.Name()))
+		}
+	}
+
+	return files, nil
+}
+
+// listPDFs returns the PDF files directly inside a directory in name order
+func listPDFs(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory: %w", err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.EqualFold(filepath.Ext(entry.Name()), ".pdf") {
+			files = append(files, filepath.Join(dir, entry.Name()))
+		}
+	}
+
+	return files, nil
+}
+
+// sortFiles orders files in place according to the sort mode
+func sortFiles(files []string, sortMode string) error {
+	switch sortMode {
+	case SortName:
+		sort.Strings(files)
+	case SortNone:
+	case SortNatural, "":
+		sort.SliceStable(files, func(i, j int) bool {
+			return naturalLess(filepath.Base(files[i]), filepath.Base(files[j]))
+		})
+	case SortMtime, SortExifDate:
+		times := make(map[string]time.Time, len(files))
+		for _, file := range files {
+			times[file] = fileTime(diskFS{}, file, sortMode == SortExifDate)
+		}
+
+		sort.SliceStable(files, func(i, j int) bool {
+			ti, tj := times[files[i]], times[files[j]]
+			if ti.Equal(tj) {
+				return naturalLess(filepath.Base(files[i]), filepath.Base(files[j]))
+			}
+			return ti.Before(tj)
+		})
+	default:
+		return fmt.Errorf("invalid sort mode: %s (supported: name, natural, mtime, exif-date, none)", sortMode)
+	}
+
+	return nil
+}
+
+// fileTime returns the EXIF capture date when requested and available,
+// otherwise the file modification time
+func fileTime(fsys fs.FS, file string, useExif bool) time.Time {
+	if useExif {
+		if t, ok := exifDateTime(fsys, file); ok {
+			return t
+		}
+	}
+
+	info, err := fs.Stat(fsys, file)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// naturalLess compares strings case-insensitively, treating runs of digits as numbers
+func naturalLess(a, b string) bool {
+	ra, rb := []rune(strings.ToLower(a)), []rune(strings.ToLower(b))
+	i, j := 0, 0
+
+	for i < len(ra) && j < len(rb) {
+		if unicode.IsDigit(ra[i]) && unicode.IsDigit(rb[j]) {
+			// Compare whole numbers, ignoring leading zeros
+			si, sj := i, j
+			for i < len(ra) && unicode.IsDigit(ra[i]) {
+				i++
+			}
+			for j < len(rb) && unicode.IsDigit(rb[j]) {
+				j++
+			}
+
+			na := strings.TrimLeft(string(ra[si:i]), "0")
+			nb := strings.TrimLeft(string(rb[sj:j]), "0")
+			if len(na) != len(nb) {
+				return len(na) < len(nb)
+			}
+			if na != nb {
+				return na < nb
+			}
+			continue
+		}
+
+		if ra[i] != rb[j] {
+			return ra[i] < rb[j]
+		}
+		i++
+		j++
+	}
+
+	if len(ra)-i != len(rb)-j {
+		return len(ra)-i < len(rb)-j
+	}
+	return a < b
+}
@@ -0,0 +1,147 @@
+package pdftool
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"io/fs"
+	"runtime"
+	"strings"
+)
+
+// jpegFrame describes how a JPEG is coded, from its start-of-frame header
+type jpegFrame struct {
+	Marker     byte // SOF marker, 0xC0-0xCF
+	Precision  int  // Bits per sample
+	Components int  // 1 gray, 3 YCbCr/RGB, 4 CMYK/YCCK
+}
+
+// String describes the frame, e.g. "progressive 8-bit CMYK"
+func (f jpegFrame) String() string {
+	var parts []string
+
+	switch f.Marker {
+	case 0xC0, 0xC1:
+		parts = append(parts, "baseline")
+	case 0xC2:
+		parts = append(parts, "progressive")
+	case 0xC3:
+		parts = append(parts, "lossless")
+	case 0xC9, 0xCA, 0xCB:
+		parts = append(parts, "arithmetic-coded")
+	default:
+		parts = append(parts, fmt.Sprintf("SOF%d", f.Marker-0xC0))
+	}
+
+	parts = append(parts, fmt.Sprintf("%d-bit", f.Precision))
+
+	switch f.Components {
+	case 1:
+		parts = append(parts, "grayscale")
+	case 3:
+		parts = append(parts, "color")
+	case 4:
+		parts = append(parts, "CMYK")
+	default:
+		parts = append(parts, fmt.Sprintf("%d-channel", f.Components))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// readJPEGFrame returns the start-of-frame header of a JPEG file in fsys
+func readJPEGFrame(fsys fs.FS, path string) (jpegFrame, bool) {
+	file, err := fsys.Open(path)
+	if err != nil {
+		return jpegFrame{}, false
+	}
+	defer file.Close()
+
+	r := bufio.NewReader(file)
+	var soi [2]byte
+	if _, err := io.ReadFull(r, soi[:]); err != nil || soi != [2]byte{0xFF, 0xD8} {
+		return jpegFrame{}, false
+	}
+
+	for {
+		var marker [4]byte
+		if _, err := io.ReadFull(r, marker[:]); err != nil || marker[0] != 0xFF {
+			return jpegFrame{}, false
+		}
+
+		length := int(binary.BigEndian.Uint16(marker[2:])) - 2
+		if length < 0 {
+			return jpegFrame{}, false
+		}
+
+		// SOF0-SOF15, except DHT (C4), JPG (C8) and DAC (CC)
+		m := marker[1]
+		if m >= 0xC0 && m <= 0xCF && m != 0xC4 && m != 0xC8 && m != 0xCC {
+			header := make([]byte, 6)
+			if length < 6 {
+				return jpegFrame{}, false
+			}
+			if _, err := io.ReadFull(r, header); err != nil {
+				return jpegFrame{}, false
+			}
+			return jpegFrame{Marker: m, Precision: int(header[0]), Components: int(header[5])}, true
+		}
+
+		if m == 0xDA { // Image data without a frame header
+			return jpegFrame{}, false
+		}
+
+		if _, err := r.Discard(length); err != nil {
+			return jpegFrame{}, false
+		}
+	}
+}
+
+// imageMagickCommand returns the ImageMagick executable, if installed
+func imageMagickCommand() (string, bool) {
+	if _, err := lookPath("magick"); err == nil {
+		return "magick", true // ImageMagick 7
+	}
+
+	// ImageMagick 6; on Windows "convert" is an unrelated system tool
+	if runtime.GOOS != "windows" {
+		if _, err := lookPath("convert"); err == nil {
+			return "convert", true
+		}
+	}
+
+	return "", false
+}
+
+// decodeWithImageMagick converts an image in fsys that ImageMagick
+// understands to sRGB and decodes the result. The image is piped to
+// ImageMagick, so it need not be on disk.
+func decodeWithImageMagick(ctx context.Context, fsys fs.FS, inputFile string) (image.Image, error) {
+	cmd, ok := imageMagickCommand()
+	if !ok {
+		return nil, programMissing("imagemagick", "install it to convert unusual image formats")
+	}
+
+	file, err := fsys.Open(inputFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open image file: %w", err)
+	}
+	defer file.Close()
+
+	var stdout, stderr bytes.Buffer
+	magick := command(ctx, cmd, "-", "-colorspace", "sRGB", "png:-")
+	magick.Stdin = file
+	magick.Stdout = &stdout
+	magick.Stderr = &stderr
+
+	if err := magick.Run(); err != nil {
+		return nil, fmt.Errorf("imagemagick conversion failed: %w: %s", commandError(ctx, err), strings.TrimSpace(stderr.String()))
+	}
+
+	return png.Decode(&stdout)
+}
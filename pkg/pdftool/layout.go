@@ -0,0 +1,124 @@
+package pdftool
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+const (
+	pageMargin    = 36 // Default page margin in points for grid layouts
+	cellGap       = 12 // Space between grid cells in points
+	captionHeight = 14 // Height reserved for a caption line in points
+	captionSize   = 9  // Caption font size in points
+
+	textFont = "goregular" // Font family registered for visible text
+
+	// DefaultPageNumberFormat is the page number template used when none is given
+	DefaultPageNumberFormat = "Page {n} of {total}"
+)
+
+// rect is an area on a PDF page in points
+type rect struct {
+	X, Y, W, H float64
+}
+
+// ParseGrid parses a grid specification like "2x3" into columns and rows
+func ParseGrid(spec string) (int, int, error) {
+	parts := strings.Split(strings.ToLower(spec), "x")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid grid: %s (expected COLSxROWS, e.g. 2x2)", spec)
+	}
+
+	cols, err := strconv.Atoi(parts[0])
+	if err != nil || cols < 1 {
+		return 0, 0, fmt.Errorf("invalid grid columns: %s", parts[0])
+	}
+
+	rows, err := strconv.Atoi(parts[1])
+	if err != nil || rows < 1 {
+		return 0, 0, fmt.Errorf("invalid grid rows: %s", parts[1])
+	}
+
+	return cols, rows, nil
+}
+
+// gridCell returns the area of a cell in a cols x rows grid within the page
+// margin, counting positions left to right, top to bottom
+func gridCell(pageWidth, pageHeight, margin float64, cols, rows, pos int) rect {
+	cellWidth := (pageWidth - 2*margin - float64(cols-1)*cellGap) / float64(cols)
+	cellHeight := (pageHeight - 2*margin - float64(rows-1)*cellGap) / float64(rows)
+
+	col := pos % cols
+	row := pos / cols
+
+	return rect{
+		X: margin + float64(col)*(cellWidth+cellGap),
+		Y: margin + float64(row)*(cellHeight+cellGap),
+		W: cellWidth,
+		H: cellHeight,
+	}
+}
+
+// fitSize scales width and height proportionally to fit within maxWidth x maxHeight
+func fitSize(width, height, maxWidth, maxHeight float64) (float64, float64) {
+	scale := maxWidth / width
+	if s := maxHeight / height; s < scale {
+		scale = s
+	}
+	return width * scale, height * scale
+}
+
+// drawCaption writes a single centered line of text in the given area
+func drawCaption(pdf *fpdf.Fpdf, text string, area rect) {
+	drawText(pdf, text, area, "C")
+}
+
+// drawText writes a single line of text in the given area, aligned "L", "C" or "R"
+func drawText(pdf *fpdf.Fpdf, text string, area rect, align string) {
+	useTextFont(pdf, captionSize)
+	pdf.SetXY(area.X, area.Y)
+	pdf.CellFormat(area.W, area.H, text, "", 0, align, false, 0, "")
+}
+
+// pageNumberArea returns the page margin area and alignment for a page number
+// position like "bottom-center"
+func pageNumberArea(position string, pageWidth, pageHeight float64) (rect, string, error) {
+	vertical, horizontal, _ := strings.Cut(strings.ToLower(position), "-")
+
+	area := rect{X: pageMargin, W: pageWidth - 2*pageMargin, H: pageMargin}
+	switch vertical {
+	case "top":
+		area.Y = 0
+	case "bottom":
+		area.Y = pageHeight - pageMargin
+	default:
+		return rect{}, "", fmt.Errorf("invalid page number position: %s (expected e.g. bottom-center)", position)
+	}
+
+	switch horizontal {
+	case "left":
+		return area, "L", nil
+	case "center":
+		return area, "C", nil
+	case "right":
+		return area, "R", nil
+	default:
+		return rect{}, "", fmt.Errorf("invalid page number position: %s (expected e.g. bottom-center)", position)
+	}
+}
+
+// pageNumberText expands the {n} and {total} placeholders of a page number template
+func pageNumberText(format string, n, total int) string {
+	return strings.NewReplacer("{n}", strconv.Itoa(n), "{total}", strconv.Itoa(total)).Replace(format)
+}
+
+// useTextFont selects the embedded font for visible text. Unlike the core
+// fonts it supports Unicode and is allowed in PDF/A.
+func useTextFont(pdf *fpdf.Fpdf, size float64) {
+	pdf.AddUTF8FontFromBytes(textFont, "", goregular.TTF) // No-op once registered
+	pdf.SetFont(textFont, "", size)
+}
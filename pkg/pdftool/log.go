@@ -0,0 +1,36 @@
+package pdftool
+
+import (
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+)
+
+// defaultLogger receives the messages of operations without a logger in
+// their options. It discards them until SetLogger is called.
+var defaultLogger atomic.Pointer[slog.Logger]
+
+func init() {
+	defaultLogger.Store(slog.New(slog.DiscardHandler))
+}
+
+// SetLogger sets the logger for progress and result messages like "Using
+// Ghostscript for compression..." or "Extracted 3 of 10 pages to out.pdf".
+// Warnings are logged at level Warn and failures of single files in batch
+// operations at level Error. A nil logger discards all messages.
+func SetLogger(l *slog.Logger) {
+	if l == nil {
+		l = slog.New(slog.DiscardHandler)
+	}
+	defaultLogger.Store(l)
+}
+
+// logf logs a formatted message at level Info with the default logger
+func logf(format string, args ...any) {
+	defaultLogger.Load().Info(fmt.Sprintf(format, args...))
+}
+
+// warnf logs a formatted warning with the default logger
+func warnf(format string, args ...any) {
+	defaultLogger.Load().Warn(fmt.Sprintf(format, args...))
+}
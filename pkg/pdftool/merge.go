@@ -0,0 +1,238 @@
+package pdftool
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+// MergeOptions holds optional settings for merging PDFs
+type MergeOptions struct {
+	BookmarkPerFile     bool // Add an outline entry named after each input file
+	TitlesFromFilenames bool // Title the per-file outline entries with cleaned-up file names; implies BookmarkPerFile
+	TOC                 bool // Prepend a table of contents of the outline; implies BookmarkPerFile
+}
+
+// MergePDFs concatenates PDF files, in order, into a single document
+func MergePDFs(ctx context.Context, inputFiles []string, outputFile string, opts MergeOptions) error {
+	if len(inputFiles) < 2 {
+		return fmt.Errorf("at least two input files are required")
+	}
+
+	for _, inputFile := range inputFiles {
+		if err := checkInputFile(inputFile); err != nil {
+			return err
+		}
+		if inputFile == outputFile {
+			return fmt.Errorf("output file cannot be one of the input files: %s", outputFile)
+		}
+	}
+
+	conf := newConfig()
+	conf.Cmd = model.MERGECREATE
+	conf.CreateBookmarks = opts.BookmarkPerFile || opts.TitlesFromFilenames || opts.TOC
+
+	merged, err := mergeFiles(ctx, inputFiles, conf)
+	if err != nil {
+		return fmt.Errorf("failed to merge PDFs: %w", err)
+	}
+	if opts.TitlesFromFilenames {
+		if err := retitleFileBookmarks(merged, inputFiles); err != nil {
+			return err
+		}
+	}
+	if err := writeContextFile(ctx, merged, outputFile); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outputFile, err)
+	}
+
+	logf("Successfully merged %d files into %s", len(inputFiles), outputFile)
+
+	if opts.TOC {
+		return AddTableOfContents(ctx, outputFile, outputFile, TOCOptions{})
+	}
+	return nil
+}
+
+// mergeFiles reads the PDFs in turn and appends their pages to the first
+// one's, with an outline entry per file when conf asks for bookmarks
+func mergeFiles(ctx context.Context, inputFiles []string, conf *model.Configuration) (*model.Context, error) {
+	var merged *model.Context
+	for _, inputFile := range inputFiles {
+		doc, err := readMergeInput(ctx, inputFile, conf)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", inputFile, err)
+		}
+
+		if merged == nil {
+			if conf.CreateBookmarks {
+				if err := pdfcpu.EnsureOutlines(doc, filepath.Base(inputFile), false); err != nil {
+					return nil, err
+				}
+			}
+			if doc.XRefTable.Version() < model.V20 {
+				doc.EnsureVersionForWriting()
+			}
+			merged = doc
+			continue
+		}
+
+		if merged.XRefTable.Version() < model.V20 && doc.XRefTable.Version() == model.V20 {
+			return nil, fmt.Errorf("%s: %w", inputFile, pdfcpu.ErrUnsupportedVersion)
+		}
+		err = awaitErr(ctx, func() error {
+			return pdfcpu.MergeXRefTables(filepath.Base(inputFile), doc, merged, false, false)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if conf.OptimizeBeforeWriting {
+		if err := awaitErr(ctx, func() error { return api.OptimizeContext(merged) }); err != nil {
+			return nil, err
+		}
+	}
+	return merged, nil
+}
+
+// readMergeInput reads one of the PDFs to merge
+func readMergeInput(ctx context.Context, inputFile string, conf *model.Configuration) (*model.Context, error) {
+	data, err := os.ReadFile(inputFile)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := await(ctx, func() (*model.Context, error) {
+		return api.ReadAndValidate(bytes.NewReader(data), conf)
+	})
+	if err != nil {
+		return nil, readError(err)
+	}
+	return doc, nil
+}
+
+// retitleFileBookmarks names the top-level outline entries of a merged PDF,
+// one per input file, after the cleaned-up file names
+func retitleFileBookmarks(merged *model.Context, inputFiles []string) error {
+	catalog, err := merged.Catalog()
+	if err != nil {
+		return fmt.Errorf("failed to read catalog: %w", err)
+	}
+	outlines, err := merged.DereferenceDict(catalog["Outlines"])
+	if err != nil || outlines == nil {
+		return fmt.Errorf("merged file has no bookmarks: %v", err)
+	}
+
+	next := outlines["First"]
+	for _, inputFile := range inputFiles {
+		item, err := merged.DereferenceDict(next)
+		if err != nil || item == nil {
+			return fmt.Errorf("merged file has fewer bookmarks than input files")
+		}
+		title, err := pdfTextString(fileTitle(inputFile))
+		if err != nil {
+			return err
+		}
+		item["Title"] = title
+		next = item["Next"]
+	}
+	return nil
+}
+
+// fileOrderPrefix matches a numbering prefix used to sort files, as in
+// "01_intro.pdf" or "2 - Appendix.pdf"
+var fileOrderPrefix = regexp.MustCompile(`^\d{1,3}(?:\s*[-_.)]\s*|\s+)`)
+
+// fileTitle turns a file name into a bookmark title: without directory,
+// extension and numbering prefix, with underscores, and hyphens in names
+// without spaces, as spaces and a capitalized first letter. For example,
+// "scans/01_annual_report.pdf" becomes "Annual report".
+func fileTitle(file string) string {
+	name := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	if trimmed := fileOrderPrefix.ReplaceAllString(name, ""); trimmed != "" {
+		name = trimmed
+	}
+
+	name = strings.ReplaceAll(name, "_", " ")
+	if !strings.Contains(name, " ") {
+		name = strings.ReplaceAll(name, "-", " ")
+	}
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" {
+		return filepath.Base(file)
+	}
+
+	first, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToUpper(first)) + name[size:]
+}
+
+// ZipMergePDFs interleaves the pages of two PDFs, taking pages from each in
+// turn, e.g. the fronts and backs of double-sided pages scanned separately
+// with a single-sided scanner. With reverseSecond, the second file's pages
+// are taken last page first, as scanners produce them when the stack is
+// turned over. The second file may have one page less than the first.
+func ZipMergePDFs(ctx context.Context, fronts, backs, outputFile string, reverseSecond bool) error {
+	for _, inputFile := range []string{fronts, backs} {
+		if err := checkInputFile(inputFile); err != nil {
+			return err
+		}
+		if inputFile == outputFile {
+			return fmt.Errorf("output file cannot be one of the input files: %s", outputFile)
+		}
+	}
+
+	doc, err := readContext(ctx, fronts)
+	if err != nil {
+		return fmt.Errorf("%s: %w", fronts, err)
+	}
+	backDoc, err := readContext(ctx, backs)
+	if err != nil {
+		return fmt.Errorf("%s: %w", backs, err)
+	}
+
+	frontCount, backCount := doc.PageCount, backDoc.PageCount
+	if backCount != frontCount && backCount != frontCount-1 {
+		return fmt.Errorf("%s has %d pages and %s has %d; the second file must have as many pages or one less", fronts, frontCount, backs, backCount)
+	}
+
+	if reverseSecond {
+		order := make([]int, backCount)
+		for i := range order {
+			order[i] = backCount - i
+		}
+		reversed, err := pdfcpu.ExtractPages(backDoc, order, false)
+		if err != nil {
+			return fmt.Errorf("failed to reverse pages of %s: %w", backs, err)
+		}
+
+		// Merging needs a context as read from a file
+		var buf bytes.Buffer
+		if err := api.WriteContext(reversed, &buf); err != nil {
+			return fmt.Errorf("failed to reverse pages of %s: %w", backs, err)
+		}
+		if backDoc, err = api.ReadValidateAndOptimize(bytes.NewReader(buf.Bytes()), newConfig()); err != nil {
+			return fmt.Errorf("failed to reverse pages of %s: %w", backs, err)
+		}
+	}
+
+	if err := pdfcpu.MergeXRefTables("", backDoc, doc, true, false); err != nil {
+		return fmt.Errorf("failed to interleave pages: %w", err)
+	}
+
+	if err := writeContextFile(ctx, doc, outputFile); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outputFile, err)
+	}
+
+	logf("Interleaved %d pages of %s with %d pages of %s, wrote %s", frontCount, fronts, backCount, backs, outputFile)
+	return nil
+}
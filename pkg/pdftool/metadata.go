@@ -0,0 +1,406 @@
+package pdftool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// Metadata holds the document properties of a PDF
+type Metadata struct {
+	Title    string `json:"title,omitempty"`
+	Author   string `json:"author,omitempty"`
+	Subject  string `json:"subject,omitempty"`
+	Keywords string `json:"keywords,omitempty"`
+	Creator  string `json:"creator,omitempty"`
+	Producer string `json:"producer,omitempty"`
+	Created  string `json:"created,omitempty"`  // RFC 3339
+	Modified string `json:"modified,omitempty"` // RFC 3339
+	XMP      bool   `json:"xmp"`                // An XMP metadata stream is present
+	PDFA     string `json:"pdfa,omitempty"`     // PDF/A part and conformance, e.g. 2B
+}
+
+// MetadataUpdate lists document properties to change. Nil fields are left
+// alone and empty strings remove a property. Dates accept YYYY-MM-DD or RFC 3339.
+type MetadataUpdate struct {
+	Title    *string `json:"title"`
+	Author   *string `json:"author"`
+	Subject  *string `json:"subject"`
+	Keywords *string `json:"keywords"`
+	Creator  *string `json:"creator"`
+	Producer *string `json:"producer"`
+	Created  *string `json:"created"`
+	Modified *string `json:"modified"`
+}
+
+// infoKeys maps document information dictionary keys to the text fields of
+// a metadata update
+func (u *MetadataUpdate) infoKeys() map[string]*string {
+	return map[string]*string{
+		"Title":    u.Title,
+		"Author":   u.Author,
+		"Subject":  u.Subject,
+		"Keywords": u.Keywords,
+		"Creator":  u.Creator,
+		"Producer": u.Producer,
+	}
+}
+
+// LoadMetadataUpdate reads a metadata update from a JSON file with the same
+// keys as the output of meta get --json
+func LoadMetadataUpdate(path string) (MetadataUpdate, error) {
+	var update MetadataUpdate
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return update, fmt.Errorf("failed to read metadata file: %w", err)
+	}
+	if err := json.Unmarshal(data, &update); err != nil {
+		return update, fmt.Errorf("invalid metadata file %s: %w", path, err)
+	}
+	return update, nil
+}
+
+// ReadMetadata returns the document properties of a PDF
+func ReadMetadata(ctx context.Context, inputFile string) (*Metadata, error) {
+	doc, err := readContext(ctx, inputFile)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := &Metadata{}
+	if doc.Info != nil {
+		d, err := doc.DereferenceDict(*doc.Info)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read document info: %w", err)
+		}
+
+		for key, field := range map[string]*string{
+			"Title":    &meta.Title,
+			"Author":   &meta.Author,
+			"Subject":  &meta.Subject,
+			"Keywords": &meta.Keywords,
+			"Creator":  &meta.Creator,
+			"Producer": &meta.Producer,
+		} {
+			if obj, ok := d.Find(key); ok {
+				*field, _ = doc.DereferenceText(obj)
+			}
+		}
+
+		if t, ok := infoDate(doc, d, "CreationDate"); ok {
+			meta.Created = t.Format(time.RFC3339)
+		}
+		if t, ok := infoDate(doc, d, "ModDate"); ok {
+			meta.Modified = t.Format(time.RFC3339)
+		}
+	}
+
+	if xmp := readXMP(doc); xmp != nil {
+		meta.XMP = true
+		if part, conformance := xmpPDFAID(xmp); part > 0 {
+			meta.PDFA = fmt.Sprintf("%d%s", part, strings.ToUpper(conformance))
+		}
+	}
+
+	return meta, nil
+}
+
+// UpdateMetadata changes document properties in both the document
+// information dictionary and the XMP metadata. The changes are appended as an
+// incremental update, so the rest of the file is copied unchanged.
+func UpdateMetadata(ctx context.Context, inputFile, outputFile string, update MetadataUpdate) error {
+	if err := checkInputFile(inputFile); err != nil {
+		return err
+	}
+
+	dates := make(map[string]string)
+	for key, value := range map[string]*string{"CreationDate": update.Created, "ModDate": update.Modified} {
+		if value == nil || *value == "" {
+			continue
+		}
+		t, err := parseMetadataDate(*value)
+		if err != nil {
+			return err
+		}
+		dates[key] = types.DateString(t)
+	}
+
+	data, err := os.ReadFile(inputFile)
+	if err != nil {
+		return fmt.Errorf("failed to read input file: %w", err)
+	}
+
+	// Validation rewrites parts of the catalog in memory, such as the name
+	// trees, which would then be lost when the catalog is appended
+	conf := newConfig()
+	doc, err := await(ctx, func() (*model.Context, error) {
+		return api.ReadContext(bytes.NewReader(data), conf)
+	})
+	if err != nil {
+		return readError(err)
+	}
+
+	info, err := ensureInfo(doc)
+	if err != nil {
+		return err
+	}
+
+	for key, value := range update.infoKeys() {
+		if value == nil {
+			continue
+		}
+		if *value == "" {
+			info.Delete(key)
+			continue
+		}
+		text, err := pdfTextString(*value)
+		if err != nil {
+			return err
+		}
+		info.Update(key, text)
+	}
+	for key, value := range map[string]*string{"CreationDate": update.Created, "ModDate": update.Modified} {
+		switch {
+		case value == nil:
+		case *value == "":
+			info.Delete(key)
+		default:
+			info.Update(key, types.StringLiteral(dates[key]))
+		}
+	}
+
+	// Keep an existing PDF/A identification in the regenerated XMP packet
+	part, conformance := 0, ""
+	if xmp := readXMP(doc); xmp != nil {
+		part, conformance = xmpPDFAID(xmp)
+	}
+
+	if err := addXMPMetadata(doc, part, conformance); err != nil {
+		return fmt.Errorf("failed to update XMP metadata: %w", err)
+	}
+	doc.Write.IncrementWithObjNr(doc.Info.ObjectNumber.Value())
+
+	if err := writeIncrement(doc, data, outputFile, conf); err != nil {
+		return err
+	}
+
+	logf("Successfully updated metadata in %s", outputFile)
+	return nil
+}
+
+// SetXMPMetadata replaces the XMP metadata of a PDF with the packet in
+// xmpFile, e.g. one with Dublin Core properties or a PDF/A identification.
+// Document information entries the packet also describes are updated to
+// match, as PDF/A requires. The changes are appended as an incremental
+// update.
+func SetXMPMetadata(ctx context.Context, inputFile, xmpFile, outputFile string) error {
+	if err := checkInputFile(inputFile); err != nil {
+		return err
+	}
+
+	xmp, err := os.ReadFile(xmpFile)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", xmpFile, err)
+	}
+	props, err := parseXMP(xmp)
+	if err != nil {
+		return fmt.Errorf("%s: %w", xmpFile, err)
+	}
+	if !bytes.Contains(xmp, []byte("<?xpacket")) {
+		xmp = []byte(xmpPacketHeader + strings.TrimSpace(string(xmp)) + "\n" + xmpPacketTrailer)
+	}
+
+	data, err := os.ReadFile(inputFile)
+	if err != nil {
+		return fmt.Errorf("failed to read input file: %w", err)
+	}
+
+	conf := newConfig()
+	doc, err := await(ctx, func() (*model.Context, error) {
+		return api.ReadContext(bytes.NewReader(data), conf)
+	})
+	if err != nil {
+		return readError(err)
+	}
+
+	info, err := ensureInfo(doc)
+	if err != nil {
+		return err
+	}
+	synced := 0
+	for key, value := range map[string]string{
+		"Title":    props.Title,
+		"Author":   props.Author,
+		"Subject":  props.Subject,
+		"Keywords": props.Keywords,
+		"Creator":  props.Creator,
+		"Producer": props.Producer,
+	} {
+		if value == "" {
+			continue
+		}
+		text, err := pdfTextString(value)
+		if err != nil {
+			return err
+		}
+		info.Update(key, text)
+		synced++
+	}
+	for key, t := range map[string]time.Time{"CreationDate": props.CreateDate, "ModDate": props.ModifyDate} {
+		if !t.IsZero() {
+			info.Update(key, types.StringLiteral(types.DateString(t)))
+			synced++
+		}
+	}
+
+	if err := setXMPStream(doc, xmp); err != nil {
+		return fmt.Errorf("failed to set XMP metadata: %w", err)
+	}
+	doc.Write.IncrementWithObjNr(doc.Info.ObjectNumber.Value())
+
+	if err := writeIncrement(doc, data, outputFile, conf); err != nil {
+		return err
+	}
+
+	if part, conformance := xmpPDFAID(xmp); part > 0 {
+		warnf("XMP declares PDF/A-%d%s; the declaration alone does not make the file conform", part, strings.ToUpper(conformance))
+	}
+	logf("Set %d-byte XMP packet from %s and synced %d document properties, wrote %s", len(xmp), xmpFile, synced, outputFile)
+	return nil
+}
+
+// writeIncrement writes the original PDF to outputFile followed by the
+// incremental update of doc
+func writeIncrement(doc *model.Context, original []byte, outputFile string, conf *model.Configuration) error {
+	if err := os.WriteFile(outputFile, original, 0644); err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+
+	file, err := os.OpenFile(outputFile, os.O_RDWR, 0)
+	if err != nil {
+		return fmt.Errorf("failed to open output file: %w", err)
+	}
+	defer file.Close()
+
+	if err := api.WriteIncr(doc, file, conf); err != nil {
+		return fmt.Errorf("failed to write metadata: %w", err)
+	}
+	return file.Close()
+}
+
+// stripMetadata removes the document information dictionary and the XMP
+// metadata of the catalog. pdfcpu writes a new information dictionary with
+// only the producer and dates.
+func stripMetadata(ctx *model.Context) error {
+	catalog, err := ctx.Catalog()
+	if err != nil {
+		return fmt.Errorf("failed to read catalog: %w", err)
+	}
+	catalog.Delete("Metadata")
+	ctx.Info = nil
+	return nil
+}
+
+// ensureInfo returns the document information dictionary, creating one if
+// the PDF has none
+func ensureInfo(ctx *model.Context) (types.Dict, error) {
+	if ctx.Info == nil {
+		d := types.NewDict()
+		ir, err := ctx.IndRefForNewObject(d)
+		if err != nil {
+			return nil, err
+		}
+		ctx.Info = ir
+		return d, nil
+	}
+
+	d, err := ctx.DereferenceDict(*ctx.Info)
+	if err != nil || d == nil {
+		return nil, fmt.Errorf("failed to read document info: %v", err)
+	}
+	return d, nil
+}
+
+// pdfTextString encodes text for a PDF string object, using UTF-16 when it
+// is not plain ASCII
+func pdfTextString(s string) (types.StringLiteral, error) {
+	for _, r := range s {
+		if r > unicode.MaxASCII {
+			escaped, err := types.EscapedUTF16String(s)
+			if err != nil {
+				return "", err
+			}
+			return types.StringLiteral(*escaped), nil
+		}
+	}
+
+	escaped, err := types.Escape(s)
+	if err != nil {
+		return "", err
+	}
+	return types.StringLiteral(*escaped), nil
+}
+
+// parseMetadataDate parses a date in YYYY-MM-DD or RFC 3339 format
+func parseMetadataDate(s string) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date: %s (expected YYYY-MM-DD or RFC 3339)", s)
+}
+
+// readXMP returns the decoded XMP metadata stream of the catalog, or nil
+func readXMP(ctx *model.Context) []byte {
+	catalog, err := ctx.Catalog()
+	if err != nil {
+		return nil
+	}
+
+	obj, ok := catalog.Find("Metadata")
+	if !ok {
+		return nil
+	}
+
+	sd, _, err := ctx.DereferenceStreamDict(obj)
+	if err != nil || sd == nil {
+		return nil
+	}
+	if err := sd.Decode(); err != nil {
+		return nil
+	}
+	return sd.Content
+}
+
+var (
+	pdfaPartPattern        = regexp.MustCompile(`pdfaid:part(?:>|=["'])\s*(\d)`)
+	pdfaConformancePattern = regexp.MustCompile(`pdfaid:conformance(?:>|=["'])\s*([A-Za-z])`)
+)
+
+// xmpPDFAID returns the PDF/A part and conformance level declared in an XMP
+// packet, or 0 if there is none
+func xmpPDFAID(xmp []byte) (int, string) {
+	part := pdfaPartPattern.FindSubmatch(xmp)
+	if part == nil {
+		return 0, ""
+	}
+
+	conformance := "B"
+	if m := pdfaConformancePattern.FindSubmatch(xmp); m != nil {
+		conformance = string(m[1])
+	}
+	return int(part[1][0] - '0'), conformance
+}
@@ -0,0 +1,35 @@
+package pdftool
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// mutoolEngine compresses with MuPDF's mutool clean, which removes unused
+// and duplicate objects and compresses all streams; for lower qualities it
+// also rewrites the page content streams more compactly
+type mutoolEngine struct{}
+
+func (mutoolEngine) Name() string { return EngineMutool }
+
+func (mutoolEngine) Available() bool {
+	_, err := lookPath("mutool")
+	return err == nil
+}
+
+func (mutoolEngine) Compress(ctx context.Context, job CompressJob) error {
+	args := []string{"clean", "-gggz"}
+	if job.Quality <= 50 {
+		args = append(args, "-s") // Sanitize, which also cleans content streams
+	}
+	args = append(args, job.InputFile, job.OutputFile)
+
+	cmd := command(ctx, "mutool", args...)
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("mutool compression failed: %w", commandError(ctx, err))
+	}
+	return nil
+}
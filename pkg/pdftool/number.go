@@ -0,0 +1,111 @@
+package pdftool
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// NumberOptions controls how page numbers are stamped onto an existing PDF
+type NumberOptions struct {
+	Start     int    // Number of the first numbered page
+	Format    string // Template with {n} and {total}
+	Position  string // See stampAnchors
+	SkipFirst bool   // Leave a cover page unnumbered
+	Font      string
+	FontSize  int
+	Color     string  // #rrggbb
+	Margin    float64 // Distance from the page edges in points
+}
+
+// NumberPages stamps page numbers onto a PDF. {total} is the number of the
+// last numbered page, so "Page {n} of {total}" stays consistent with
+// --start and --skip-first.
+func NumberPages(ctx context.Context, inputFile, outputFile string, opts NumberOptions) error {
+	if opts.FontSize <= 0 {
+		return fmt.Errorf("invalid font size: %d", opts.FontSize)
+	}
+
+	doc, err := readContext(ctx, inputFile)
+	if err != nil {
+		return err
+	}
+
+	first := 1
+	if opts.SkipFirst {
+		first = 2
+	}
+	if first > doc.PageCount {
+		return fmt.Errorf("no pages to number")
+	}
+
+	total := opts.Start + doc.PageCount - first
+	style := textStyle{
+		Font:     opts.Font,
+		FontSize: opts.FontSize,
+		Color:    opts.Color,
+		Position: opts.Position,
+		Margin:   opts.Margin,
+	}
+
+	stamps := make(map[int][]*model.Watermark)
+	for page := first; page <= doc.PageCount; page++ {
+		text := pageNumberText(opts.Format, opts.Start+page-first, total)
+		wm, err := textStamp(text, style)
+		if err != nil {
+			return err
+		}
+		stamps[page] = []*model.Watermark{wm}
+	}
+
+	if err := addStamps(ctx, doc, stamps, outputFile); err != nil {
+		return err
+	}
+
+	logf("Numbered %d of %d pages, wrote %s", len(stamps), doc.PageCount, outputFile)
+	return nil
+}
+
+// textStyle describes a small line of text such as a page number, header or
+// footer stamped at a fixed size near the page edge
+type textStyle struct {
+	Font     string
+	FontSize int
+	Color    string // #rrggbb
+	Position string // See stampAnchors
+	Margin   float64
+}
+
+// textStamp builds a pdfcpu stamp drawing text in the given style
+func textStamp(text string, style textStyle) (*model.Watermark, error) {
+	anchor, err := stampAnchor(style.Position)
+	if err != nil {
+		return nil, err
+	}
+
+	fill, err := pdfcpuColor(style.Color)
+	if err != nil {
+		return nil, err
+	}
+
+	desc := strings.Join([]string{
+		"fontname:" + style.Font,
+		fmt.Sprintf("points:%d", style.FontSize),
+		"scalefactor:1 abs",
+		"fillcolor:" + fill,
+		"rotation:0",
+		"opacity:1",
+		"position:" + anchor,
+		"offset:" + stampOffset(anchor, style.Margin),
+	}, ", ")
+
+	wm, err := api.TextWatermark(text, desc, true, false, types.POINTS)
+	if err != nil {
+		return nil, fmt.Errorf("invalid text stamp: %s", strings.TrimSpace(err.Error()))
+	}
+	return wm, nil
+}
@@ -0,0 +1,73 @@
+package pdftool
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// NupOptions controls how pages are placed on sheets
+type NupOptions struct {
+	Columns int
+	Rows    int
+	Paper   string  // Sheet paper size; landscape when there are more columns than rows
+	Border  bool    // Draw a frame around each page
+	Margin  float64 // Space around each page in points
+	Pages   string  // Empty or "all" places every page
+}
+
+// NupPages places several pages of a PDF on each sheet in a grid, filled left
+// to right, top to bottom, for handouts and paper-saving printing. Pages are
+// scaled to fit their cell and rotated when that makes them larger.
+func NupPages(ctx context.Context, inputFile, outputFile string, opts NupOptions) error {
+	if opts.Columns < 1 || opts.Rows < 1 {
+		return fmt.Errorf("invalid grid: %dx%d", opts.Columns, opts.Rows)
+	}
+	if opts.Margin < 0 {
+		return fmt.Errorf("margin must not be negative")
+	}
+
+	name, dim, ok := paperSize(opts.Paper)
+	if !ok {
+		return fmt.Errorf("unknown paper size: %s (e.g. A4, A3, Letter, Legal)", opts.Paper)
+	}
+	landscape := opts.Columns > opts.Rows
+	if landscape != (dim.Width > dim.Height) {
+		dim.Width, dim.Height = dim.Height, dim.Width
+	}
+
+	nup := model.DefaultNUpConfig()
+	nup.InpUnit = types.POINTS
+	nup.PageSize = name
+	nup.PageDim = dim
+	nup.UserDim = true
+	nup.Grid = &types.Dim{Width: float64(opts.Columns), Height: float64(opts.Rows)}
+	nup.Border = opts.Border
+	nup.Margin = opts.Margin
+
+	doc, err := readContext(ctx, inputFile)
+	if err != nil {
+		return err
+	}
+
+	pages, err := stampSelection(doc.PageCount, opts.Pages)
+	if err != nil {
+		return err
+	}
+
+	if err := pdfcpu.NUpFromPDF(doc, pageSet(pages), nup); err != nil {
+		return fmt.Errorf("failed to place pages: %w", err)
+	}
+
+	if err := writeContextFile(ctx, doc, outputFile); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outputFile, err)
+	}
+
+	perSheet := opts.Columns * opts.Rows
+	sheets := (len(pages) + perSheet - 1) / perSheet
+	logf("Placed %d pages on %d sheets, wrote %s", len(pages), sheets, outputFile)
+	return nil
+}
@@ -0,0 +1,168 @@
+package pdftool
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// ocrWord is a single word recognized by Tesseract, in image pixel coordinates
+type ocrWord struct {
+	Text   string
+	Left   int
+	Top    int
+	Width  int
+	Height int
+	Line   [3]int // Block, paragraph and line number
+}
+
+// sameLine reports whether two words are on the same line of text
+func (w ocrWord) sameLine(o ocrWord) bool {
+	return w.Line == o.Line
+}
+
+// helveticaText encodes a word in Windows-1252 for the standard Helvetica
+// font, which has no other characters; ok is false if the word cannot be
+// encoded
+func helveticaText(text string) (encoded string, ok bool) {
+	encoded, err := charmap.Windows1252.NewEncoder().String(text)
+	return encoded, err == nil
+}
+
+// isTesseractAvailable checks if Tesseract is installed
+func isTesseractAvailable() bool {
+	_, err := lookPath("tesseract")
+	return err == nil
+}
+
+// recognizeText runs Tesseract on an encoded PNG or JPEG image and returns the recognized words
+func recognizeText(ctx context.Context, imageData []byte, lang string) ([]ocrWord, error) {
+	if !isTesseractAvailable() {
+		return nil, programMissing("tesseract", "install it to use OCR")
+	}
+
+	if lang == "" {
+		lang = "eng"
+	}
+
+	// Read the image from stdin and write TSV output (one row per recognized
+	// element) to stdout
+	var stdout bytes.Buffer
+	cmd := command(ctx, "tesseract", "stdin", "stdout", "-l", lang, "tsv")
+	cmd.Stdin = bytes.NewReader(imageData)
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("tesseract OCR failed: %w", commandError(ctx, err))
+	}
+
+	return parseTesseractTSV(&stdout)
+}
+
+// parseTesseractTSV extracts word-level entries from Tesseract's TSV output
+func parseTesseractTSV(r io.Reader) ([]ocrWord, error) {
+	var words []ocrWord
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		// level page_num block_num par_num line_num word_num left top width height conf text
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) < 12 || fields[0] != "5" {
+			continue // Header or non-word row
+		}
+
+		text := strings.TrimSpace(fields[11])
+		if text == "" {
+			continue
+		}
+
+		// block_num par_num line_num word_num left top width height
+		var nums [8]int
+		valid := true
+		for i := range nums {
+			n, err := strconv.Atoi(fields[2+i])
+			if err != nil {
+				valid = false
+				break
+			}
+			nums[i] = n
+		}
+		if !valid || nums[6] <= 0 || nums[7] <= 0 {
+			continue
+		}
+
+		words = append(words, ocrWord{
+			Text:   text,
+			Left:   nums[4],
+			Top:    nums[5],
+			Width:  nums[6],
+			Height: nums[7],
+			Line:   [3]int(nums[:3]),
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read tesseract output: %w", err)
+	}
+
+	return words, nil
+}
+
+// addTextLayer draws recognized words as invisible text over an image placed at
+// (x, y) with size (w, h) on the current page, scaled from imgWidth x imgHeight pixels.
+// Words on the same line are separated by spaces for text extraction; words
+// with characters Helvetica lacks are left out.
+func addTextLayer(pdf *fpdf.Fpdf, words []ocrWord, x, y, w, h, imgWidth, imgHeight float64) {
+	if len(words) == 0 {
+		return
+	}
+
+	scaleX := w / imgWidth
+	scaleY := h / imgHeight
+
+	const baseFontSize = 10
+	pdf.SetFont("Helvetica", "", baseFontSize)
+	pdf.SetTextRenderingMode(3) // Neither fill nor stroke (invisible)
+
+	skipped := 0
+	for i, word := range words {
+		text, ok := helveticaText(word.Text)
+		if !ok {
+			skipped++
+			continue
+		}
+		boxWidth := float64(word.Width) * scaleX
+		boxHeight := float64(word.Height) * scaleY
+
+		// Size the font so the word spans its bounding box, which keeps text
+		// selection aligned with the underlying image
+		fontSize := boxHeight
+		pdf.SetFontSize(baseFontSize)
+		if baseWidth := pdf.GetStringWidth(text); baseWidth > 0 {
+			fontSize = baseFontSize * boxWidth / baseWidth
+		}
+		pdf.SetFontSize(fontSize)
+
+		// The space after the word falls into the gap before the next one
+		if i+1 < len(words) && word.sameLine(words[i+1]) {
+			text += " "
+		}
+
+		baseline := y + float64(word.Top+word.Height)*scaleY
+		pdf.Text(x+float64(word.Left)*scaleX, baseline, text)
+	}
+
+	pdf.SetTextRenderingMode(0)
+	if skipped > 0 {
+		warnf("Left out %d recognized words with characters Helvetica cannot show", skipped)
+	}
+}
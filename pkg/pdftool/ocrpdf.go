@@ -0,0 +1,247 @@
+package pdftool
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/font"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// OCROptions holds settings for adding a text layer to a PDF
+type OCROptions struct {
+	Language      string // Tesseract language, e.g. "eng" or "deu+eng"
+	SkipTextPages bool   // Leave pages that already have text alone instead of failing
+	DPI           int    // Resolution pages are rendered at for recognition
+}
+
+// OCRPDF renders the pages of a PDF that have no text, recognizes their text
+// with Tesseract and adds it as an invisible layer, making scanned documents
+// searchable and selectable. Pages that already have text are an error
+// unless SkipTextPages is set.
+func OCRPDF(ctx context.Context, inputFile, outputFile string, opts OCROptions) error {
+	if opts.DPI <= 0 {
+		return fmt.Errorf("DPI must be positive, got: %d", opts.DPI)
+	}
+	if !isTesseractAvailable() {
+		return programMissing("tesseract", "install it to use OCR")
+	}
+
+	doc, err := readContext(ctx, inputFile)
+	if err != nil {
+		return err
+	}
+
+	var pages []int
+	var pageList []string
+	for page := 1; page <= doc.PageCount; page++ {
+		if err := contextError(ctx); err != nil {
+			return err
+		}
+		text, err := pageText(doc, page, false)
+		if err != nil {
+			return fmt.Errorf("failed to read text of page %d: %w", page, err)
+		}
+		if strings.TrimSpace(text) != "" {
+			if !opts.SkipTextPages {
+				return fmt.Errorf("page %d already has text (use --skip-text-pages to only process pages without text)", page)
+			}
+			continue
+		}
+		pages = append(pages, page)
+		pageList = append(pageList, strconv.Itoa(page))
+	}
+
+	words := 0
+	if len(pages) > 0 {
+		tmpDir, removeTmpDir, err := TempDir("", "pdf-tool-ocr-")
+		if err != nil {
+			return fmt.Errorf("failed to create temp directory: %w", err)
+		}
+		defer removeTmpDir()
+
+		rendered, err := RasterizePDF(ctx, inputFile, tmpDir, RasterizeOptions{Format: "png", DPI: opts.DPI, Pages: strings.Join(pageList, ",")})
+		if err != nil {
+			return err
+		}
+
+		// Ghostscript renders pages as displayed; without it, the page image
+		// is exported as stored, before page rotation
+		rotated := isGhostscriptAvailable()
+
+		for i, file := range rendered {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			logf("Recognizing page %d...", pages[i])
+			n, err := ocrPage(ctx, doc, pages[i], file, opts.Language, rotated)
+			if err != nil {
+				return fmt.Errorf("failed to OCR page %d: %w", pages[i], err)
+			}
+			words += n
+		}
+	}
+
+	if err := writeContextFile(ctx, doc, outputFile); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outputFile, err)
+	}
+
+	if skipped := doc.PageCount - len(pages); skipped > 0 {
+		logf("Recognized %d words on %d pages, skipped %d pages with text, wrote %s", words, len(pages), skipped, outputFile)
+		return nil
+	}
+	logf("Recognized %d words on %d pages, wrote %s", words, len(pages), outputFile)
+	return nil
+}
+
+// ocrPage recognizes the text of a rendered page and adds it to the page as
+// invisible text, returning the number of words added
+func ocrPage(ctx context.Context, doc *model.Context, page int, imageFile, lang string, rotated bool) (int, error) {
+	data, err := os.ReadFile(imageFile)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read rendered page: %w", err)
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return 0, fmt.Errorf("failed to decode rendered page: %w", err)
+	}
+
+	words, err := recognizeText(ctx, data, lang)
+	if err != nil || len(words) == 0 {
+		return 0, err
+	}
+
+	pageDict, _, inherited, err := doc.PageDict(page, false)
+	if err != nil || pageDict == nil {
+		return 0, fmt.Errorf("failed to read page: %w", err)
+	}
+	mediaBox := inherited.MediaBox
+	if mediaBox == nil {
+		return 0, fmt.Errorf("page has no media box")
+	}
+	rotate := 0
+	if rotated {
+		rotate = ((inherited.Rotate % 360) + 360) % 360
+	}
+
+	layer, added, err := ocrTextLayer(doc, words, mediaBox, rotate, float64(cfg.Width), float64(cfg.Height))
+	if err != nil {
+		return 0, err
+	}
+	if skipped := len(words) - added; skipped > 0 {
+		warnf("Left out %d recognized words on page %d with characters Helvetica cannot show", skipped, page)
+	}
+	if err := addPageXObjects(doc, pageDict, inherited, types.Dict{"OCRText": *layer}); err != nil {
+		return 0, err
+	}
+
+	// addPageXObjects may rename the layer if the page uses the name already
+	resources, err := doc.DereferenceDict(pageDict["Resources"])
+	if err != nil {
+		return 0, err
+	}
+	xobjects, err := doc.DereferenceDict(resources["XObject"])
+	if err != nil {
+		return 0, err
+	}
+	name := "OCRText"
+	for k, v := range xobjects {
+		if ref, ok := v.(types.IndirectRef); ok && ref.ObjectNumber == layer.ObjectNumber {
+			name = k
+		}
+	}
+
+	if err := wrapPageContent(doc, pageDict, fmt.Sprintf("q /%s Do Q\n", name)); err != nil {
+		return 0, err
+	}
+	return added, nil
+}
+
+// ocrTextLayer creates a form XObject with recognized words as invisible
+// Helvetica text, each sized to cover its box on the page image, and returns
+// it with the number of words in it. Words with characters Helvetica lacks
+// are left out. The form maps the image, which shows the page rotated by
+// rotate degrees, onto the media box.
+func ocrTextLayer(ctx *model.Context, words []ocrWord, mediaBox *types.Rectangle, rotate int, imgWidth, imgHeight float64) (*types.IndirectRef, int, error) {
+	x0, y0, w, h := mediaBox.LL.X, mediaBox.LL.Y, mediaBox.Width(), mediaBox.Height()
+
+	// Size of the page as displayed, and the matrix from displayed page
+	// coordinates to default user space
+	width, height := w, h
+	matrix := types.NewNumberArray(1, 0, 0, 1, x0, y0)
+	switch rotate {
+	case 90:
+		width, height = h, w
+		matrix = types.NewNumberArray(0, 1, -1, 0, x0+w, y0)
+	case 180:
+		matrix = types.NewNumberArray(-1, 0, 0, -1, x0+w, y0+h)
+	case 270:
+		width, height = h, w
+		matrix = types.NewNumberArray(0, -1, 1, 0, x0, y0+h)
+	}
+	scaleX, scaleY := width/imgWidth, height/imgHeight
+
+	var content strings.Builder
+	content.WriteString("BT 3 Tr\n") // Neither fill nor stroke (invisible)
+	added := 0
+	for i, word := range words {
+		text, ok := helveticaText(word.Text)
+		if !ok {
+			continue
+		}
+		shown := text
+		if i+1 < len(words) && word.sameLine(words[i+1]) {
+			shown += " " // Separates words in extracted text
+		}
+		escaped, err := types.Escape(shown)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		// Size the font to the box height and stretch the word to its width,
+		// which keeps text selection aligned with the page image
+		boxWidth := float64(word.Width) * scaleX
+		size := float64(word.Height) * scaleY
+		scaling := 100.0
+		if textWidth := font.TextWidth(text, "Helvetica", 1000) / 1000 * size; textWidth > 0 {
+			scaling = 100 * boxWidth / textWidth
+		}
+
+		x := float64(word.Left) * scaleX
+		y := height - float64(word.Top+word.Height)*scaleY // PDF coordinates start at the bottom left
+		fmt.Fprintf(&content, "/Helv %.2f Tf %.2f Tz 1 0 0 1 %.2f %.2f Tm (%s) Tj\n", size, scaling, x, y, *escaped)
+		added++
+	}
+	content.WriteString("ET\n")
+
+	fontRef, err := ctx.IndRefForNewObject(types.Dict{
+		"Type":     types.Name("Font"),
+		"Subtype":  types.Name("Type1"),
+		"BaseFont": types.Name("Helvetica"),
+		"Encoding": types.Name("WinAnsiEncoding"),
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	sd, err := ctx.NewStreamDictForBuf([]byte(content.String()))
+	if err != nil {
+		return nil, 0, err
+	}
+	sd.InsertName("Type", "XObject")
+	sd.InsertName("Subtype", "Form")
+	sd.Insert("BBox", types.NewNumberArray(0, 0, width, height))
+	sd.Insert("Matrix", matrix)
+	sd.Insert("Resources", types.Dict{"Font": types.Dict{"Helv": *fontRef}})
+	if err := sd.Encode(); err != nil {
+		return nil, 0, err
+	}
+	ref, err := ctx.IndRefForNewObject(*sd)
+	return ref, added, err
+}
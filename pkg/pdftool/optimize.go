@@ -0,0 +1,128 @@
+package pdftool
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/pdfcpu/pdfcpu/pkg/filter"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// OptimizeOptions controls the lossless optimizations
+type OptimizeOptions struct {
+	ObjectStreams bool // Pack objects into compressed object streams (PDF 1.5)
+	Recompress    bool // Flate-compress uncompressed and weakly compressed streams
+}
+
+// OptimizePDF shrinks a PDF without changing its content: duplicate fonts
+// and images are merged, unreferenced objects dropped, objects packed into
+// object streams and uncompressed streams compressed. Unlike CompressPDF,
+// images are never resampled or re-encoded lossily.
+func OptimizePDF(ctx context.Context, inputFile, outputFile string, opts OptimizeOptions) error {
+	doc, err := readContext(ctx, inputFile)
+	if err != nil {
+		return err
+	}
+
+	recompressed := 0
+	if opts.Recompress {
+		if recompressed, err = recompressStreams(ctx, doc); err != nil {
+			return err
+		}
+	}
+
+	doc.WriteObjectStream = opts.ObjectStreams
+	doc.WriteXRefStream = opts.ObjectStreams
+
+	if err := writeContextFile(ctx, doc, outputFile); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outputFile, err)
+	}
+
+	inputInfo, err := os.Stat(inputFile)
+	if err != nil {
+		return fmt.Errorf("failed to get input file info: %w", err)
+	}
+	outputInfo, err := os.Stat(outputFile)
+	if err != nil {
+		return fmt.Errorf("failed to get output file info: %w", err)
+	}
+
+	summary := fmt.Sprintf("Optimized %s: %s -> %s", inputFile, FormatSize(inputInfo.Size()), FormatSize(outputInfo.Size()))
+	if outputInfo.Size() < inputInfo.Size() {
+		summary += fmt.Sprintf(" (%.1f%% smaller)", float64(inputInfo.Size()-outputInfo.Size())/float64(inputInfo.Size())*100)
+	}
+	logf("%s", summary)
+	if recompressed > 0 {
+		logf("Recompressed %d streams", recompressed)
+	}
+	if outputInfo.Size() >= inputInfo.Size() {
+		warnf("Note: Output file is not smaller than input")
+	}
+	return nil
+}
+
+// recompressibleFilters are the lossless filters whose streams are re-encoded
+// with Flate
+var recompressibleFilters = map[string]bool{
+	filter.ASCII85:   true,
+	filter.ASCIIHex:  true,
+	filter.RunLength: true,
+	filter.LZW:       true,
+}
+
+// recompressStreams Flate-encodes streams that are stored uncompressed or
+// only with ASCII, run-length or LZW filters, keeping the new encoding when
+// it is smaller. Image codecs such as DCT, JPX, CCITT and JBIG2 are left as
+// they are.
+func recompressStreams(ctx context.Context, doc *model.Context) (int, error) {
+	count := 0
+	for objNr, entry := range doc.Table {
+		if err := contextError(ctx); err != nil {
+			return 0, err
+		}
+		if entry == nil || entry.Free || entry.Compressed {
+			continue
+		}
+		sd, ok := entry.Object.(types.StreamDict)
+		if !ok || !recompressible(sd) {
+			continue
+		}
+
+		if err := sd.Decode(); err != nil {
+			// Keep streams pdfcpu cannot decode untouched
+			continue
+		}
+		rawLength := len(sd.Raw)
+
+		sd.FilterPipeline = []types.PDFFilter{{Name: filter.Flate}}
+		sd.InsertName("Filter", filter.Flate)
+		sd.Delete("DecodeParms")
+		if err := sd.Encode(); err != nil {
+			return count, fmt.Errorf("failed to compress object %d: %w", objNr, err)
+		}
+		if len(sd.Raw) >= rawLength {
+			continue
+		}
+
+		entry.Object = sd
+		count++
+	}
+	return count, nil
+}
+
+// recompressible reports whether a stream is uncompressed or only uses
+// filters that Flate beats, without parameters such as LZW predictors
+func recompressible(sd types.StreamDict) bool {
+	if typ := sd.Type(); typ != nil && (*typ == "XRef" || *typ == "ObjStm" || *typ == "Metadata") {
+		// XMP metadata stays readable by tools that scan for it
+		return false
+	}
+	for _, f := range sd.FilterPipeline {
+		if !recompressibleFilters[f.Name] || f.DecodeParms != nil {
+			return false
+		}
+	}
+	return true
+}
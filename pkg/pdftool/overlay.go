@@ -0,0 +1,50 @@
+package pdftool
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// OverlayOptions controls how the pages of another PDF are placed onto pages
+type OverlayOptions struct {
+	OverlayPage int    // Page of the overlay PDF to use; 0 uses overlay page N for page N, repeating the last one
+	Under       bool   // Place the overlay under the page content, e.g. for letterheads
+	Pages       string // Empty or "all" overlays every page
+}
+
+// OverlayPDF places a page of another PDF, such as a letterhead, grid or form
+// background, over or under the selected pages. The overlay is scaled to the
+// page width and centered.
+func OverlayPDF(ctx context.Context, inputFile, overlayFile, outputFile string, opts OverlayOptions) error {
+	if err := checkInputFile(overlayFile); err != nil {
+		return err
+	}
+	if opts.OverlayPage < 0 {
+		return fmt.Errorf("invalid overlay page: %d", opts.OverlayPage)
+	}
+
+	overlay, err := os.Open(overlayFile)
+	if err != nil {
+		return fmt.Errorf("failed to open overlay: %w", err)
+	}
+	defer overlay.Close()
+
+	desc := "scalefactor:1 rel, rotation:0, opacity:1, position:c"
+	wm, err := api.PDFWatermarkForReadSeeker(overlay, opts.OverlayPage, desc, !opts.Under, false, types.POINTS)
+	if err != nil {
+		return fmt.Errorf("invalid overlay: %s", strings.TrimSpace(err.Error()))
+	}
+
+	stamped, total, err := stampPages(ctx, inputFile, outputFile, opts.Pages, wm)
+	if err != nil {
+		return err
+	}
+
+	logf("Overlaid %s onto %d of %d pages, wrote %s", overlayFile, stamped, total, outputFile)
+	return nil
+}
@@ -0,0 +1,304 @@
+package pdftool
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// pageLabelStyles maps label style names to the numbering styles of PDF
+// page labels; "none" labels pages with the prefix only
+var pageLabelStyles = map[string]string{
+	"arabic": "D",
+	"roman":  "r",
+	"Roman":  "R",
+	"alpha":  "a",
+	"Alpha":  "A",
+	"none":   "",
+}
+
+// PageLabelRange is a run of pages numbered in one style
+type PageLabelRange struct {
+	FirstPage int    // Physical page the range starts at, starting at 1
+	LastPage  int    // Last page of the range; 0 continues up to the next range
+	Style     string // Numbering style: arabic, roman, Roman, alpha, Alpha or none
+	Start     int    // Number of the first page in the range
+	Prefix    string // Text in front of every number, e.g. "A-"
+}
+
+// ParsePageLabelRanges parses page label ranges like
+// "1-4:roman,5-:arabic@1,20-:arabic@1:A-". Each range is pages:style,
+// optionally followed by @start and :prefix.
+func ParsePageLabelRanges(spec string) ([]PageLabelRange, error) {
+	var ranges []PageLabelRange
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		fields := strings.SplitN(part, ":", 3)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("invalid page label range: %q (expected e.g. 1-4:roman)", part)
+		}
+
+		pages, style := fields[0], fields[1]
+		r := PageLabelRange{Start: 1}
+		if len(fields) == 3 {
+			r.Prefix = fields[2]
+		}
+		if at := strings.Index(style, "@"); at >= 0 {
+			start, err := strconv.Atoi(style[at+1:])
+			if err != nil || start < 1 {
+				return nil, fmt.Errorf("invalid start number in page label range: %q", part)
+			}
+			r.Start = start
+			style = style[:at]
+		}
+		if _, ok := pageLabelStyles[style]; !ok {
+			return nil, fmt.Errorf("invalid page label style: %s (supported: arabic, roman, Roman, alpha, Alpha, none)", style)
+		}
+		r.Style = style
+
+		first, last, found := strings.Cut(pages, "-")
+		var err error
+		if r.FirstPage, err = strconv.Atoi(first); err != nil || r.FirstPage < 1 {
+			return nil, fmt.Errorf("invalid pages in page label range: %q", part)
+		}
+		switch {
+		case !found:
+			r.LastPage = r.FirstPage
+		case last != "":
+			if r.LastPage, err = strconv.Atoi(last); err != nil || r.LastPage < r.FirstPage {
+				return nil, fmt.Errorf("invalid pages in page label range: %q", part)
+			}
+		}
+		ranges = append(ranges, r)
+	}
+
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].FirstPage < ranges[j].FirstPage })
+	for i := 1; i < len(ranges); i++ {
+		prev := ranges[i-1]
+		if ranges[i].FirstPage == prev.FirstPage || prev.LastPage != 0 && ranges[i].FirstPage <= prev.LastPage {
+			return nil, fmt.Errorf("page label ranges overlap at page %d", ranges[i].FirstPage)
+		}
+	}
+	return ranges, nil
+}
+
+// SetPageLabels replaces the page labels of a PDF, the logical page numbers
+// viewers display, with the given ranges. Pages not covered by a range are
+// labeled with their page number.
+func SetPageLabels(ctx context.Context, inputFile, outputFile string, ranges []PageLabelRange) error {
+	doc, err := readContext(ctx, inputFile)
+	if err != nil {
+		return err
+	}
+
+	var nums types.Array
+	addRange := func(r PageLabelRange) error {
+		label := types.Dict{"Type": types.Name("PageLabel")}
+		if style := pageLabelStyles[r.Style]; style != "" {
+			label["S"] = types.Name(style)
+		}
+		if r.Prefix != "" {
+			prefix, err := pdfTextString(r.Prefix)
+			if err != nil {
+				return fmt.Errorf("invalid page label prefix %q: %w", r.Prefix, err)
+			}
+			label["P"] = prefix
+		}
+		if r.Start != 1 {
+			label["St"] = types.Integer(r.Start)
+		}
+		nums = append(nums, types.Integer(r.FirstPage-1), label)
+		return nil
+	}
+
+	// A range continues until the next one, so gaps get ranges numbering
+	// pages by their physical page number
+	next := 1
+	for _, r := range ranges {
+		if r.FirstPage > doc.PageCount {
+			return fmt.Errorf("page %d out of range (document has %d pages)", r.FirstPage, doc.PageCount)
+		}
+		if next > 0 && r.FirstPage > next {
+			if err := addRange(PageLabelRange{FirstPage: next, Style: "arabic", Start: next}); err != nil {
+				return err
+			}
+		}
+		if err := addRange(r); err != nil {
+			return err
+		}
+
+		next = 0
+		if r.LastPage > 0 {
+			next = r.LastPage + 1
+		}
+	}
+	if next > 0 && next <= doc.PageCount {
+		if err := addRange(PageLabelRange{FirstPage: next, Style: "arabic", Start: next}); err != nil {
+			return err
+		}
+	}
+
+	catalog, err := doc.Catalog()
+	if err != nil {
+		return fmt.Errorf("failed to read catalog: %w", err)
+	}
+	catalog["PageLabels"] = types.Dict{"Nums": nums}
+
+	if err := writeContextFile(ctx, doc, outputFile); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outputFile, err)
+	}
+
+	labels, err := pageLabels(doc)
+	if err != nil {
+		return err
+	}
+	logf("Labeled %d pages %s to %s, wrote %s", len(labels), labels[0], labels[len(labels)-1], outputFile)
+	return nil
+}
+
+// PageLabels returns the label of every page of a PDF. Pages of documents
+// without page labels are labeled with their page number.
+func PageLabels(ctx context.Context, inputFile string) ([]string, error) {
+	doc, err := readContext(ctx, inputFile)
+	if err != nil {
+		return nil, err
+	}
+	return pageLabels(doc)
+}
+
+// pageLabels computes the label of every page from the catalog's page label
+// number tree
+func pageLabels(ctx *model.Context) ([]string, error) {
+	labels := make([]string, ctx.PageCount)
+	for i := range labels {
+		labels[i] = strconv.Itoa(i + 1)
+	}
+
+	catalog, err := ctx.Catalog()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read catalog: %w", err)
+	}
+	root, err := ctx.DereferenceDict(catalog["PageLabels"])
+	if err != nil || root == nil {
+		return labels, err
+	}
+
+	ranges := make(map[int]types.Dict)
+	if err := collectNumberTree(ctx, root, ranges, 0); err != nil {
+		return nil, fmt.Errorf("invalid page labels: %w", err)
+	}
+
+	var label types.Dict
+	first := 0
+	for page := range labels {
+		if d, ok := ranges[page]; ok {
+			label, first = d, page
+		}
+		if label == nil {
+			continue
+		}
+
+		prefix := ""
+		if obj, ok := label.Find("P"); ok {
+			prefix, _ = ctx.DereferenceText(obj)
+		}
+		start := 1
+		if st := label.IntEntry("St"); st != nil {
+			start = *st
+		}
+		style := ""
+		if s := label.NameEntry("S"); s != nil {
+			style = *s
+		}
+		labels[page] = prefix + formatPageNumber(start+page-first, style)
+	}
+	return labels, nil
+}
+
+// collectNumberTree adds the entries of a number tree node and its kids to
+// values
+func collectNumberTree(ctx *model.Context, node types.Dict, values map[int]types.Dict, depth int) error {
+	if depth > 32 {
+		return fmt.Errorf("number tree too deep")
+	}
+
+	nums, err := ctx.DereferenceArray(node["Nums"])
+	if err != nil {
+		return err
+	}
+	for i := 0; i+1 < len(nums); i += 2 {
+		key, ok := nums[i].(types.Integer)
+		if !ok {
+			return fmt.Errorf("invalid number tree key: %v", nums[i])
+		}
+		value, err := ctx.DereferenceDict(nums[i+1])
+		if err != nil {
+			return err
+		}
+		values[key.Value()] = value
+	}
+
+	kids, err := ctx.DereferenceArray(node["Kids"])
+	if err != nil {
+		return err
+	}
+	for _, kid := range kids {
+		d, err := ctx.DereferenceDict(kid)
+		if err != nil {
+			return err
+		}
+		if d != nil {
+			if err := collectNumberTree(ctx, d, values, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// formatPageNumber formats a page number in a page label style: D decimal,
+// R and r roman, A and a letters (A to Z, then AA to ZZ and so on); any
+// other style shows no number
+func formatPageNumber(n int, style string) string {
+	switch style {
+	case "D":
+		return strconv.Itoa(n)
+	case "R":
+		return romanNumeral(n)
+	case "r":
+		return strings.ToLower(romanNumeral(n))
+	case "A":
+		return strings.Repeat(string(rune('A'+(n-1)%26)), (n-1)/26+1)
+	case "a":
+		return strings.Repeat(string(rune('a'+(n-1)%26)), (n-1)/26+1)
+	}
+	return ""
+}
+
+// romanNumeral formats a positive number as an upper case roman numeral
+func romanNumeral(n int) string {
+	if n <= 0 {
+		return strconv.Itoa(n)
+	}
+
+	var b strings.Builder
+	for _, numeral := range []struct {
+		value  int
+		symbol string
+	}{
+		{1000, "M"}, {900, "CM"}, {500, "D"}, {400, "CD"},
+		{100, "C"}, {90, "XC"}, {50, "L"}, {40, "XL"},
+		{10, "X"}, {9, "IX"}, {5, "V"}, {4, "IV"}, {1, "I"},
+	} {
+		for n >= numeral.value {
+			b.WriteString(numeral.symbol)
+			n -= numeral.value
+		}
+	}
+	return b.String()
+}
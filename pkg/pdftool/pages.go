@@ -0,0 +1,168 @@
+package pdftool
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+)
+
+// ExtractPages copies the selected pages, such as "2-5,9", into a new PDF,
+// keeping their links, annotations and form fields
+func ExtractPages(ctx context.Context, inputFile, selection, outputFile string) error {
+	if inputFile == outputFile {
+		return fmt.Errorf("input and output files cannot be the same")
+	}
+
+	doc, err := readContext(ctx, inputFile)
+	if err != nil {
+		return err
+	}
+
+	pages, err := pagesForSelection(doc.PageCount, selection)
+	if err != nil {
+		return err
+	}
+
+	if err := writePages(ctx, doc, pages, outputFile); err != nil {
+		return err
+	}
+
+	logf("Extracted %d of %d pages to %s", len(pages), doc.PageCount, outputFile)
+	return nil
+}
+
+// DeletePages writes a copy of a PDF without the selected pages, such as "1,3-4"
+func DeletePages(ctx context.Context, inputFile, selection, outputFile string) error {
+	if inputFile == outputFile {
+		return fmt.Errorf("input and output files cannot be the same")
+	}
+
+	doc, err := readContext(ctx, inputFile)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := pagesForSelection(doc.PageCount, selection)
+	if err != nil {
+		return err
+	}
+
+	remove := make(map[int]bool, len(deleted))
+	for _, page := range deleted {
+		remove[page] = true
+	}
+
+	var pages []int
+	for page := 1; page <= doc.PageCount; page++ {
+		if !remove[page] {
+			pages = append(pages, page)
+		}
+	}
+	if len(pages) == 0 {
+		return fmt.Errorf("cannot delete all %d pages", doc.PageCount)
+	}
+
+	if err := writePages(ctx, doc, pages, outputFile); err != nil {
+		return err
+	}
+
+	logf("Deleted %d of %d pages, wrote %s", len(deleted), doc.PageCount, outputFile)
+	return nil
+}
+
+// RotatePages rotates the selected pages clockwise by a multiple of 90
+// degrees; an empty selection rotates all pages
+func RotatePages(ctx context.Context, inputFile, outputFile string, angle int, selection string) error {
+	if angle%90 != 0 {
+		return fmt.Errorf("invalid angle: %d (must be a multiple of 90)", angle)
+	}
+
+	doc, err := readContext(ctx, inputFile)
+	if err != nil {
+		return err
+	}
+
+	pages, err := pagesForSelection(doc.PageCount, selection)
+	if err != nil {
+		return err
+	}
+
+	if err := pdfcpu.RotatePages(doc, pageSet(pages), angle); err != nil {
+		return fmt.Errorf("failed to rotate pages: %w", err)
+	}
+
+	if err := writeContextFile(ctx, doc, outputFile); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outputFile, err)
+	}
+
+	logf("Rotated %d of %d pages by %d°, wrote %s", len(pages), doc.PageCount, angle, outputFile)
+	return nil
+}
+
+// ReorderPages writes the pages of a PDF in a new order, such as "3,1,2,4-",
+// which must list every page exactly once. With reverse, the order is last
+// page first instead.
+func ReorderPages(ctx context.Context, inputFile, order, outputFile string, reverse bool) error {
+	if inputFile == outputFile {
+		return fmt.Errorf("input and output files cannot be the same")
+	}
+
+	doc, err := readContext(ctx, inputFile)
+	if err != nil {
+		return err
+	}
+
+	var pages []int
+	if reverse {
+		for page := doc.PageCount; page >= 1; page-- {
+			pages = append(pages, page)
+		}
+	} else if pages, err = pagesInOrder(doc.PageCount, order); err != nil {
+		return err
+	}
+
+	if err := writePages(ctx, doc, pages, outputFile); err != nil {
+		return err
+	}
+
+	logf("Reordered %d pages, wrote %s", len(pages), outputFile)
+	return nil
+}
+
+// pagesInOrder resolves a page order like "3,1,2,4-10", checking that every
+// page appears exactly once
+func pagesInOrder(pageCount int, order string) ([]int, error) {
+	selection, err := api.ParsePageSelection(order)
+	if err != nil {
+		return nil, fmt.Errorf("invalid page order: %w", err)
+	}
+
+	pages, err := api.PagesForPageCollection(pageCount, selection)
+	if err != nil {
+		return nil, fmt.Errorf("invalid page order: %w", err)
+	}
+
+	seen := make(map[int]bool, len(pages))
+	for _, page := range pages {
+		if seen[page] {
+			return nil, fmt.Errorf("page %d appears more than once", page)
+		}
+		seen[page] = true
+	}
+
+	var missing []string
+	for page := 1; page <= pageCount; page++ {
+		if !seen[page] {
+			missing = append(missing, strconv.Itoa(page))
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("page order must list all %d pages; missing: %s", pageCount, strings.Join(missing, ","))
+	}
+
+	return pages, nil
+}
@@ -0,0 +1,159 @@
+package pdftool
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// checkInputFile verifies that an input file exists
+func checkInputFile(inputFile string) error {
+	if _, err := os.Stat(inputFile); os.IsNotExist(err) {
+		return fmt.Errorf("input file does not exist: %s", inputFile)
+	}
+	return nil
+}
+
+// newConfig returns a pdfcpu configuration that tolerates the minor spec
+// violations common in real-world PDFs
+func newConfig() *model.Configuration {
+	conf := model.NewDefaultConfiguration()
+	conf.ValidationMode = model.ValidationRelaxed
+	return conf
+}
+
+// readContext reads, validates and optimizes a PDF file for processing
+func readContext(ctx context.Context, inputFile string) (*model.Context, error) {
+	if err := checkInputFile(inputFile); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(inputFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PDF: %w", err)
+	}
+
+	doc, err := await(ctx, func() (*model.Context, error) {
+		return api.ReadValidateAndOptimize(bytes.NewReader(data), newConfig())
+	})
+	if err != nil {
+		return nil, readError(err)
+	}
+
+	return doc, nil
+}
+
+// writeContextFile writes a PDF to outputFile, only once it is complete so
+// that canceled writes leave no partial file
+func writeContextFile(ctx context.Context, doc *model.Context, outputFile string) error {
+	data, err := await(ctx, func() ([]byte, error) {
+		var buf bytes.Buffer
+		err := api.WriteContext(doc, &buf)
+		return buf.Bytes(), err
+	})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(outputFile, data, 0644)
+}
+
+// transformFile runs a pdfcpu operation that reads a PDF and writes another
+// from inputFile to outputFile, writing nothing when ctx is done first
+func transformFile(ctx context.Context, inputFile, outputFile string, transform func(rs io.ReadSeeker, w io.Writer) error) error {
+	input, err := os.ReadFile(inputFile)
+	if err != nil {
+		return err
+	}
+	data, err := await(ctx, func() ([]byte, error) {
+		var buf bytes.Buffer
+		err := transform(bytes.NewReader(input), &buf)
+		return buf.Bytes(), err
+	})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(outputFile, data, 0644)
+}
+
+// await runs a pdfcpu call, which cannot be canceled, and returns the
+// context's error as soon as ctx is done, with deadlines reported as
+// ErrTimeout. This only returns early: the call keeps running in the
+// background until it finishes and its result is dropped, so it must not use
+// anything the caller releases on return, such as an open file. Read inputs
+// into memory first.
+func await[T any](ctx context.Context, call func() (T, error)) (T, error) {
+	var zero T
+	if err := contextError(ctx); err != nil {
+		return zero, err
+	}
+
+	type result struct {
+		value T
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		value, err := call()
+		done <- result{value, err}
+	}()
+	select {
+	case r := <-done:
+		return r.value, r.err
+	case <-ctx.Done():
+		return zero, contextError(ctx)
+	}
+}
+
+// awaitErr is await for calls that only return an error
+func awaitErr(ctx context.Context, call func() error) error {
+	_, err := await(ctx, func() (struct{}, error) {
+		return struct{}{}, call()
+	})
+	return err
+}
+
+// pagesForSelection resolves a page selection like "1-3,7,10-" to page
+// numbers in ascending order; an empty selection means all pages
+func pagesForSelection(pageCount int, selection string) ([]int, error) {
+	var selected []string
+	if selection != "" {
+		var err error
+		if selected, err = api.ParsePageSelection(selection); err != nil {
+			return nil, fmt.Errorf("invalid page selection: %w", err)
+		}
+	}
+
+	set, err := api.PagesForPageSelection(pageCount, selected, true, false)
+	if err != nil {
+		return nil, fmt.Errorf("invalid page selection: %w", err)
+	}
+
+	var pages []int
+	for page, ok := range set {
+		if ok {
+			pages = append(pages, page)
+		}
+	}
+	if len(pages) == 0 {
+		return nil, fmt.Errorf("no pages selected: %s", selection)
+	}
+	sort.Ints(pages)
+
+	return pages, nil
+}
+
+// pageSet converts page numbers to the set type used by pdfcpu
+func pageSet(pages []int) types.IntSet {
+	set := make(types.IntSet, len(pages))
+	for _, page := range pages {
+		set[page] = true
+	}
+	return set
+}
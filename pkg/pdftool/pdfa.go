@@ -0,0 +1,280 @@
+package pdftool
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// ParsePDFALevel parses a PDF/A level such as "2b" and returns its part.
+// Only conformance level B (visual appearance) is supported.
+func ParsePDFALevel(level string) (int, error) {
+	switch strings.ToLower(level) {
+	case "1b":
+		return 1, nil
+	case "2b":
+		return 2, nil
+	case "3b":
+		return 3, nil
+	}
+	return 0, fmt.Errorf("invalid PDF/A level: %s (supported: 1b, 2b, 3b)", level)
+}
+
+// ConvertToPDFA converts a PDF to PDF/A-1b, 2b or 3b. Ghostscript embeds
+// all fonts, converts colors to sRGB and embeds the sRGB ICC profile as
+// output intent. Without Ghostscript, PDF/A-2b and 3b are written by adding
+// the output intent and XMP metadata, which only conforms if the fonts are
+// already embedded.
+func ConvertToPDFA(ctx context.Context, inputFile, outputFile string, part int) error {
+	if err := checkInputFile(inputFile); err != nil {
+		return err
+	}
+
+	if isGhostscriptAvailable() {
+		if err := ghostscriptPDFA(ctx, inputFile, outputFile, part); err != nil {
+			return err
+		}
+	} else {
+		if part == 1 {
+			// pdfcpu always writes PDF 1.7, which PDF/A-1 does not allow
+			return programMissing("ghostscript", "install it to convert to PDF/A-1b")
+		}
+		warnf("Ghostscript not found: fonts are not embedded and colors are not converted")
+
+		data, err := os.ReadFile(inputFile)
+		if err != nil {
+			return fmt.Errorf("failed to read input file: %w", err)
+		}
+		if err := writePDFA(ctx, data, outputFile, part); err != nil {
+			return err
+		}
+	}
+
+	pageCount, err := api.PageCountFile(outputFile)
+	if err != nil {
+		return fmt.Errorf("failed to read converted PDF: %w", err)
+	}
+
+	logf("Converted %d pages to PDF/A-%dB, wrote %s", pageCount, part, outputFile)
+	return nil
+}
+
+// ghostscriptPDFA converts a PDF to PDF/A with Ghostscript. The output
+// intent is set by a PostScript prologue that embeds the sRGB ICC profile.
+func ghostscriptPDFA(ctx context.Context, inputFile, outputFile string, part int) error {
+	if err := checkGhostscriptDevice("pdfwrite"); err != nil {
+		return err
+	}
+	if info, err := DetectGhostscript(); err == nil && !info.SupportsPDFA(part) {
+		return fmt.Errorf("installed Ghostscript %s cannot write PDF/A-%d (version 9.10 or later is needed)", info.Version, part)
+	}
+
+	tmpDir, removeTmpDir, err := TempDir("", "pdf-tool-pdfa-")
+	if err != nil {
+		return fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer removeTmpDir()
+
+	iccFile := filepath.Join(tmpDir, "srgb.icc")
+	if err := os.WriteFile(iccFile, sRGBICCProfile(), 0644); err != nil {
+		return fmt.Errorf("failed to write ICC profile: %w", err)
+	}
+	defFile := filepath.Join(tmpDir, "PDFA_def.ps")
+	if err := os.WriteFile(defFile, pdfaDefinition(iccFile), 0644); err != nil {
+		return fmt.Errorf("failed to write PDF/A definition: %w", err)
+	}
+
+	err = ghostscriptDistill(ctx, inputFile, outputFile,
+		fmt.Sprintf("-dPDFA=%d", part),
+		"-dPDFACompatibilityPolicy=1", // Drop features PDF/A forbids instead of failing
+		"-sColorConversionStrategy=RGB",
+		"-sProcessColorModel=DeviceRGB",
+		"--permit-file-read="+tmpDir+string(filepath.Separator),
+		defFile,
+	)
+	if err != nil {
+		return fmt.Errorf("ghostscript PDF/A conversion failed: %w", err)
+	}
+	return nil
+}
+
+// pdfaDefinition returns the PostScript prologue that makes Ghostscript add
+// an sRGB output intent with the ICC profile read from iccFile
+func pdfaDefinition(iccFile string) []byte {
+	escaper := strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`)
+
+	var b strings.Builder
+	b.WriteString("%!\n")
+	fmt.Fprintf(&b, "/ICCProfile (%s) def\n", escaper.Replace(filepath.ToSlash(iccFile)))
+	b.WriteString("[/_objdef {icc_PDFA} /type /stream /OBJ pdfmark\n")
+	b.WriteString("[{icc_PDFA} << /N 3 >> /PUT pdfmark\n")
+	b.WriteString("[{icc_PDFA} ICCProfile (r) file /PUT pdfmark\n")
+	b.WriteString("[/_objdef {OutputIntent_PDFA} /type /dict /OBJ pdfmark\n")
+	b.WriteString("[{OutputIntent_PDFA} <<\n")
+	b.WriteString("  /Type /OutputIntent\n")
+	b.WriteString("  /S /GTS_PDFA1\n")
+	b.WriteString("  /DestOutputProfile {icc_PDFA}\n")
+	fmt.Fprintf(&b, "  /OutputConditionIdentifier (%s)\n", sRGBDescription)
+	fmt.Fprintf(&b, "  /Info (%s)\n", sRGBDescription)
+	b.WriteString("  /RegistryName (http://www.color.org)\n")
+	b.WriteString(">> /PUT pdfmark\n")
+	b.WriteString("[{Catalog} << /OutputIntents [ {OutputIntent_PDFA} ] >> /PUT pdfmark\n")
+	return []byte(b.String())
+}
+
+// writePDFA writes a PDF as PDF/A-2b or 3b. It adds an sRGB output intent
+// and a file identifier, then appends XMP metadata matching the final
+// document information dictionary as an incremental update. The content is
+// not changed, so fonts must already be embedded.
+func writePDFA(ctx context.Context, pdfData []byte, outputFile string, part int) error {
+	conf := newConfig()
+
+	doc, err := await(ctx, func() (*model.Context, error) {
+		return api.ReadContext(bytes.NewReader(pdfData), conf)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read generated PDF: %w", err)
+	}
+
+	if err := addOutputIntent(doc); err != nil {
+		return err
+	}
+
+	// Writing sets the producer, dates and file identifier
+	var written bytes.Buffer
+	if err := api.WriteContext(doc, &written); err != nil {
+		return fmt.Errorf("failed to write PDF/A: %w", err)
+	}
+
+	doc, err = await(ctx, func() (*model.Context, error) {
+		return api.ReadAndValidate(bytes.NewReader(written.Bytes()), conf)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reread PDF/A: %w", err)
+	}
+
+	if err := addXMPMetadata(doc, part, "B"); err != nil {
+		return err
+	}
+
+	return writeIncrement(doc, written.Bytes(), outputFile, conf)
+}
+
+// addOutputIntent attaches an sRGB PDF/A output intent to the document catalog
+func addOutputIntent(ctx *model.Context) error {
+	catalog, err := ctx.Catalog()
+	if err != nil {
+		return err
+	}
+
+	sd, err := ctx.NewStreamDictForBuf(sRGBICCProfile())
+	if err != nil {
+		return err
+	}
+	sd.InsertInt("N", 3)
+	if err := sd.Encode(); err != nil {
+		return err
+	}
+
+	profile, err := ctx.IndRefForNewObject(*sd)
+	if err != nil {
+		return err
+	}
+
+	intent := types.Dict{
+		"Type":                      types.Name("OutputIntent"),
+		"S":                         types.Name("GTS_PDFA1"),
+		"OutputConditionIdentifier": types.StringLiteral(sRGBDescription),
+		"Info":                      types.StringLiteral(sRGBDescription),
+		"RegistryName":              types.StringLiteral("http://www.color.org"),
+		"DestOutputProfile":         *profile,
+	}
+
+	catalog.Update("OutputIntents", types.Array{intent})
+	return nil
+}
+
+// addXMPMetadata sets the catalog's XMP metadata stream from the document
+// information dictionary and marks the changes for an incremental update.
+// A pdfaPart of 0 omits the PDF/A identification.
+func addXMPMetadata(ctx *model.Context, pdfaPart int, conformance string) error {
+	info := xmpInfo{PDFAPart: pdfaPart, PDFAConformance: conformance}
+
+	if ctx.Info != nil {
+		d, err := ctx.DereferenceDict(*ctx.Info)
+		if err != nil {
+			return err
+		}
+
+		for key, field := range map[string]*string{
+			"Title":    &info.Title,
+			"Author":   &info.Author,
+			"Subject":  &info.Subject,
+			"Keywords": &info.Keywords,
+			"Creator":  &info.Creator,
+			"Producer": &info.Producer,
+		} {
+			if obj, ok := d.Find(key); ok {
+				if *field, err = ctx.DereferenceText(obj); err != nil {
+					return err
+				}
+			}
+		}
+
+		info.CreateDate, _ = infoDate(ctx, d, "CreationDate")
+		info.ModifyDate, _ = infoDate(ctx, d, "ModDate")
+	}
+
+	return setXMPStream(ctx, buildXMP(info))
+}
+
+// setXMPStream makes an XMP packet the metadata stream of the catalog and
+// marks both for an incremental update
+func setXMPStream(ctx *model.Context, xmp []byte) error {
+	sd := types.StreamDict{Dict: types.NewDict(), Content: xmp}
+	sd.InsertName("Type", "Metadata")
+	sd.InsertName("Subtype", "XML")
+	if err := sd.Encode(); err != nil {
+		return err
+	}
+
+	metadata, err := ctx.IndRefForNewObject(sd)
+	if err != nil {
+		return err
+	}
+
+	catalog, err := ctx.Catalog()
+	if err != nil {
+		return err
+	}
+	catalog.Update("Metadata", *metadata)
+
+	ctx.Write.Increment = true
+	ctx.Write.Offset = ctx.Read.FileSize
+	ctx.Write.IncrementWithObjNr(ctx.Root.ObjectNumber.Value())
+	ctx.Write.IncrementWithObjNr(metadata.ObjectNumber.Value())
+	return nil
+}
+
+// infoDate parses a date entry of the document information dictionary
+func infoDate(ctx *model.Context, d types.Dict, key string) (time.Time, bool) {
+	obj, ok := d.Find(key)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	s, err := ctx.DereferenceText(obj)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return types.DateTime(s, true)
+}
@@ -0,0 +1,267 @@
+package pdftool
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/pdfcpu/pdfcpu/pkg/filter"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// PDFAReport lists the PDF/A requirements a file does not meet
+type PDFAReport struct {
+	File   string
+	Part   int
+	Issues []string
+}
+
+// forbiddenPDFAActions are the action types PDF/A does not allow
+var forbiddenPDFAActions = map[string]bool{
+	"JavaScript": true,
+	"Launch":     true,
+	"Sound":      true,
+	"Movie":      true,
+	"ResetForm":  true,
+	"ImportData": true,
+	"Hide":       true,
+	"Rendition":  true,
+	"Trans":      true,
+}
+
+// annotationPrintFlag is bit 3 of the annotation flags, which PDF/A requires
+const annotationPrintFlag = 1 << 2
+
+// CheckPDFA checks a PDF against the main requirements of PDF/A-1b, 2b or 3b:
+// identification, output intent, embedded fonts, forbidden actions, embedded
+// files, encryption, LZW compression, annotation appearances and, for
+// PDF/A-1, transparency. It is a quick check, not a certification; use a
+// validator such as veraPDF for that.
+func CheckPDFA(ctx context.Context, inputFile string, part int) (*PDFAReport, error) {
+	doc, err := readContext(ctx, inputFile)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &pdfaChecker{ctx: doc, part: part, found: make(map[string]bool)}
+	c.checkDocument()
+	for _, entry := range doc.Table {
+		if entry != nil && !entry.Free {
+			c.checkObject(entry.Object)
+		}
+	}
+	for page := 1; page <= doc.PageCount; page++ {
+		if err := contextError(ctx); err != nil {
+			return nil, err
+		}
+		if err := c.checkAnnotations(page); err != nil {
+			return nil, fmt.Errorf("failed to check page %d: %w", page, err)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, font := range doc.Optimize.FontObjects {
+		if !fontEmbedded(doc, font.FontDict) && !seen[font.FontName] {
+			seen[font.FontName] = true
+			c.report("font %s is not embedded", font.FontName)
+		}
+	}
+
+	sort.Strings(c.issues)
+	return &PDFAReport{File: inputFile, Part: part, Issues: c.issues}, nil
+}
+
+// pdfaChecker collects PDF/A violations, reporting each kind once
+type pdfaChecker struct {
+	ctx    *model.Context
+	part   int
+	issues []string
+	found  map[string]bool
+}
+
+// report records an issue unless the same one was found before
+func (c *pdfaChecker) report(format string, args ...any) {
+	issue := fmt.Sprintf(format, args...)
+	if !c.found[issue] {
+		c.found[issue] = true
+		c.issues = append(c.issues, issue)
+	}
+}
+
+// checkDocument checks the catalog, trailer and file structure
+func (c *pdfaChecker) checkDocument() {
+	ctx := c.ctx
+
+	if xmp := readXMP(ctx); xmp == nil {
+		c.report("no XMP metadata")
+	} else if part, conformance := xmpPDFAID(xmp); part == 0 {
+		c.report("XMP metadata has no PDF/A identification")
+	} else if part != c.part {
+		c.report("XMP metadata declares PDF/A-%d%s", part, conformance)
+	}
+
+	if !c.hasOutputIntent() {
+		c.report("no PDF/A output intent with an ICC profile")
+	}
+	if ctx.Encrypt != nil {
+		c.report("file is encrypted")
+	}
+	if len(ctx.ID) == 0 {
+		c.report("trailer has no file identifier")
+	}
+
+	if c.part == 1 {
+		if ctx.XRefTable.Version() > model.V14 {
+			c.report("PDF version %s is newer than 1.4", ctx.XRefTable.VersionString())
+		}
+		if ctx.Read.UsingObjectStreams || ctx.Read.UsingXRefStreams {
+			c.report("object or cross-reference streams are used")
+		}
+	}
+
+	if names, err := ctx.NamesDict(); err == nil && names != nil {
+		if names["JavaScript"] != nil {
+			c.report("document contains JavaScript")
+		}
+		switch {
+		case names["EmbeddedFiles"] == nil || c.part == 3:
+		case c.part == 1:
+			c.report("embedded files are not allowed in PDF/A-1")
+		default:
+			// Not checked further: PDF/A-2 allows attachments that are PDF/A
+			c.report("embedded files must be PDF/A themselves (use PDF/A-3 for other files)")
+		}
+	}
+}
+
+// hasOutputIntent reports whether the catalog has a GTS_PDFA1 output intent
+// with an embedded ICC profile
+func (c *pdfaChecker) hasOutputIntent() bool {
+	catalog, err := c.ctx.Catalog()
+	if err != nil {
+		return false
+	}
+	intents, err := c.ctx.DereferenceArray(catalog["OutputIntents"])
+	if err != nil {
+		return false
+	}
+	for _, obj := range intents {
+		intent, err := c.ctx.DereferenceDict(obj)
+		if err != nil || intent == nil {
+			continue
+		}
+		if s := intent.NameEntry("S"); s != nil && *s == "GTS_PDFA1" && intent["DestOutputProfile"] != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// checkObject checks an object for forbidden actions, filters, external
+// streams and, for PDF/A-1, transparency
+func (c *pdfaChecker) checkObject(obj types.Object) {
+	switch v := obj.(type) {
+	case types.Dict:
+		c.checkDict(v)
+	case types.StreamDict:
+		if v.Dict["F"] != nil {
+			c.report("stream data is read from an external file")
+		}
+		for _, f := range v.FilterPipeline {
+			if f.Name == filter.LZW {
+				c.report("LZW compression is used")
+			}
+		}
+		c.checkDict(v.Dict)
+	case types.Array:
+		for _, item := range v {
+			c.checkObject(item)
+		}
+	}
+}
+
+// checkDict checks a dictionary and the objects nested in it
+func (c *pdfaChecker) checkDict(d types.Dict) {
+	if s := d.NameEntry("S"); s != nil && forbiddenPDFAActions[*s] {
+		// Other dictionaries such as transparency groups have an S entry too
+		if typ := d.Type(); typ == nil || *typ == "Action" {
+			c.report("%s actions are not allowed", *s)
+		}
+	}
+	if c.part < 3 && d["AF"] != nil {
+		c.report("associated files are only allowed in PDF/A-3")
+	}
+
+	if c.part == 1 {
+		if subtype := d.Subtype(); subtype != nil && *subtype == "Image" && d["SMask"] != nil {
+			c.report("images with soft masks use transparency")
+		}
+		if typ := d.Type(); typ != nil && *typ == "ExtGState" {
+			for _, key := range []string{"CA", "ca"} {
+				if alpha, ok := numberValue(d[key]); ok && alpha < 1 {
+					c.report("constant opacity below 1 uses transparency")
+				}
+			}
+			if bm := d.NameEntry("BM"); bm != nil && *bm != "Normal" && *bm != "Compatible" {
+				c.report("blend mode %s uses transparency", *bm)
+			}
+			if smask := d.NameEntry("SMask"); d["SMask"] != nil && (smask == nil || *smask != "None") {
+				c.report("soft masks use transparency")
+			}
+		}
+		if group, err := c.ctx.DereferenceDict(d["Group"]); err == nil && group != nil {
+			if s := group.NameEntry("S"); s != nil && *s == "Transparency" {
+				c.report("transparency groups are used")
+			}
+		}
+	}
+
+	for _, v := range d {
+		c.checkObject(v)
+	}
+}
+
+// checkAnnotations checks that the annotations of a page are printed and
+// have appearance streams
+func (c *pdfaChecker) checkAnnotations(page int) error {
+	pageDict, _, _, err := c.ctx.PageDict(page, false)
+	if err != nil || pageDict == nil {
+		return err
+	}
+
+	annots, err := c.ctx.DereferenceArray(pageDict["Annots"])
+	if err != nil {
+		return err
+	}
+
+	for _, obj := range annots {
+		annot, err := c.ctx.DereferenceDict(obj)
+		if err != nil || annot == nil {
+			continue
+		}
+
+		subtype := ""
+		if t := annot.Subtype(); t != nil {
+			subtype = *t
+		}
+		if subtype == "Popup" {
+			continue
+		}
+		if unsafeAnnotations[subtype] && !(c.part == 3 && subtype == "FileAttachment") {
+			c.report("%s annotations are not allowed", subtype)
+		}
+
+		flags := 0
+		if f := annot.IntEntry("F"); f != nil {
+			flags = *f
+		}
+		if flags&annotationPrintFlag == 0 {
+			c.report("annotations without the print flag on page %d", page)
+		}
+		if c.part > 1 && subtype != "Link" && annot["AP"] == nil {
+			c.report("annotations without appearance streams on page %d", page)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package pdftool
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+// permission is a named group of PDF user access permission bits
+type permission struct {
+	name        string
+	flags       model.PermissionFlags
+	description string
+}
+
+// permissions lists the permissions that can be allowed or denied by name
+var permissions = []permission{
+	{"print", model.PermissionPrintRev2 | model.PermissionPrintRev3, "Print the document"},
+	{"modify", model.PermissionModify, "Change the page content"},
+	{"copy", model.PermissionExtract | model.PermissionExtractRev3, "Copy text and images"},
+	{"annotate", model.PermissionModAnnFillForm, "Add comments and fill in forms"},
+	{"fill", model.PermissionFillRev3, "Fill in form fields"},
+	{"assemble", model.PermissionAssembleRev3, "Insert, rotate or delete pages"},
+}
+
+// PermissionOptions describes a change to the permissions of a PDF
+type PermissionOptions struct {
+	Allow         []string // Permission names, or "all"
+	Deny          []string
+	UserPassword  string
+	OwnerPassword string // Required; also used to encrypt unencrypted PDFs
+}
+
+// Permission is a user access permission and whether a PDF grants it
+type Permission struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Allowed     bool   `json:"allowed"`
+}
+
+// PermissionReport lists the permissions a PDF grants to users without the
+// owner password. Unencrypted PDFs grant all of them.
+type PermissionReport struct {
+	File        string       `json:"file"`
+	Encrypted   bool         `json:"encrypted"`
+	Permissions []Permission `json:"permissions"`
+}
+
+// ListPermissions returns which permissions a PDF grants to users without
+// the owner password
+func ListPermissions(ctx context.Context, inputFile, password string) (*PermissionReport, error) {
+	doc, err := readEncryptedContext(ctx, inputFile, password, password)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &PermissionReport{File: inputFile, Encrypted: doc.E != nil}
+	flags := model.PermissionsAll
+	if doc.E != nil {
+		flags = model.PermissionFlags(doc.E.P)
+	}
+	for _, p := range permissions {
+		report.Permissions = append(report.Permissions, Permission{
+			Name:        p.name,
+			Description: p.description,
+			Allowed:     flags&p.flags == p.flags,
+		})
+	}
+	return report, nil
+}
+
+// SetPermissions allows and denies permissions of a PDF without changing its
+// content. An unencrypted PDF is encrypted with AES-256 and the given
+// passwords, because permissions only apply to encrypted documents.
+func SetPermissions(ctx context.Context, inputFile, outputFile string, opts PermissionOptions) error {
+	if len(opts.Allow) == 0 && len(opts.Deny) == 0 {
+		return fmt.Errorf("no permissions to allow or deny")
+	}
+	if opts.OwnerPassword == "" {
+		return fmt.Errorf("the owner password is required to set permissions")
+	}
+
+	allow, err := parsePermissions(opts.Allow)
+	if err != nil {
+		return err
+	}
+	deny, err := parsePermissions(opts.Deny)
+	if err != nil {
+		return err
+	}
+	if allow&deny != 0 {
+		return fmt.Errorf("the same permission cannot be both allowed and denied")
+	}
+
+	doc, err := readEncryptedContext(ctx, inputFile, opts.UserPassword, opts.OwnerPassword)
+	if err != nil {
+		return err
+	}
+
+	conf := newConfig()
+	conf.UserPW = opts.UserPassword
+	conf.OwnerPW = opts.OwnerPassword
+
+	if doc.E == nil {
+		conf.Permissions = (model.PermissionsAll &^ deny) | allow
+		conf.EncryptUsingAES = true
+		conf.EncryptKeyLength = 256
+		logf("PDF is not encrypted; encrypting it so that the permissions apply")
+
+		err := transformFile(ctx, inputFile, outputFile, func(rs io.ReadSeeker, w io.Writer) error {
+			return api.Encrypt(rs, w, conf)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to encrypt PDF: %w", err)
+		}
+	} else {
+		current := model.PermissionFlags(doc.E.P) & model.PermissionsAll
+		conf.Permissions = (current &^ deny) | allow
+
+		err := transformFile(ctx, inputFile, outputFile, func(rs io.ReadSeeker, w io.Writer) error {
+			return api.SetPermissions(rs, w, conf)
+		})
+		if err != nil {
+			if errors.Is(err, pdfcpu.ErrWrongPassword) {
+				return fmt.Errorf("changing permissions requires the user and owner passwords")
+			}
+			return fmt.Errorf("failed to set permissions: %w", err)
+		}
+	}
+
+	logf("Successfully updated permissions in %s", outputFile)
+	return nil
+}
+
+// parsePermissions combines the flags of permission names such as "print"
+func parsePermissions(names []string) (model.PermissionFlags, error) {
+	var flags model.PermissionFlags
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "all" {
+			for _, p := range permissions {
+				flags |= p.flags
+			}
+			continue
+		}
+
+		found := false
+		for _, p := range permissions {
+			if p.name == name {
+				flags |= p.flags
+				found = true
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("unknown permission: %s (expected print, modify, copy, annotate, fill, assemble or all)", name)
+		}
+	}
+	return flags, nil
+}
+
+// readEncryptedContext reads a possibly encrypted PDF with the given
+// passwords; doc.E is nil when the PDF is not encrypted
+func readEncryptedContext(ctx context.Context, inputFile, userPassword, ownerPassword string) (*model.Context, error) {
+	if err := checkInputFile(inputFile); err != nil {
+		return nil, err
+	}
+
+	conf := newConfig()
+	conf.UserPW = userPassword
+	conf.OwnerPW = ownerPassword
+
+	data, err := os.ReadFile(inputFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PDF: %w", err)
+	}
+
+	doc, err := await(ctx, func() (*model.Context, error) {
+		return api.ReadContext(bytes.NewReader(data), conf)
+	})
+	if errors.Is(err, pdfcpu.ErrWrongPassword) {
+		return nil, fmt.Errorf("%w: wrong password for %s", ErrEncrypted, inputFile)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", inputFile, readError(err))
+	}
+
+	return doc, nil
+}
@@ -0,0 +1,27 @@
+package pdftool
+
+// Progress stages
+const (
+	StageAnalyze  = "analyze"  // Reading the input
+	StageCompress = "compress" // Rewriting the document
+	StageVerify   = "verify"   // Checking the output
+)
+
+// ProgressEvent reports how far an operation has got
+type ProgressEvent struct {
+	Stage        string // StageAnalyze, StageCompress or StageVerify
+	Page         int    // Pages processed so far in this stage
+	Pages        int    // Page count of the document, 0 if unknown
+	BytesWritten int64  // Size of the output written so far
+}
+
+// ProgressFunc receives progress events. It is called from the goroutine
+// running the operation and should return quickly.
+type ProgressFunc func(ProgressEvent)
+
+// report calls f with an event, if f is set
+func (f ProgressFunc) report(event ProgressEvent) {
+	if f != nil {
+		f(event)
+	}
+}
@@ -0,0 +1,40 @@
+package pdftool
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// pureGo is set when external programs must not be run
+var pureGo atomic.Bool
+
+func init() {
+	pureGo.Store(pureGoBuild)
+}
+
+// SetPureGo turns pure-Go mode on or off. In pure-Go mode the library never
+// runs external programs like Ghostscript, Tesseract or ImageMagick:
+// compression uses pdfcpu, and features that need a program fail with
+// ErrPureGo. Builds with the purego tag, and js and wasip1 builds, are
+// always in pure-Go mode.
+func SetPureGo(enabled bool) {
+	pureGo.Store(enabled || pureGoBuild)
+}
+
+// PureGo reports whether pure-Go mode is on
+func PureGo() bool {
+	return pureGo.Load()
+}
+
+// programMissing returns the error for a feature whose program is not
+// installed, with a hint like "install it to use OCR", or ErrPureGo in
+// pure-Go mode. A missing Ghostscript wraps ErrGhostscriptNotFound.
+func programMissing(program, hint string) error {
+	switch {
+	case PureGo():
+		return fmt.Errorf("%s: %w", program, ErrPureGo)
+	case program == "ghostscript":
+		return fmt.Errorf("%w (%s)", ErrGhostscriptNotFound, hint)
+	}
+	return fmt.Errorf("%s not found (%s)", program, hint)
+}
@@ -0,0 +1,7 @@
+//go:build purego || js || wasip1
+
+package pdftool
+
+// pureGoBuild is true in builds where external programs cannot or must not
+// be run
+const pureGoBuild = true
@@ -0,0 +1,46 @@
+package pdftool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// qpdfWarningsExitCode is the exit code qpdf uses when it wrote its output
+// but warned about problems in the input
+const qpdfWarningsExitCode = 3
+
+// qpdfEngine compresses with qpdf, which keeps the content as is but
+// recompresses streams at the highest level and, for lower qualities, packs
+// objects into compressed object streams
+type qpdfEngine struct{}
+
+func (qpdfEngine) Name() string { return EngineQpdf }
+
+func (qpdfEngine) Available() bool {
+	_, err := lookPath("qpdf")
+	return err == nil
+}
+
+func (qpdfEngine) Compress(ctx context.Context, job CompressJob) error {
+	args := []string{
+		"--compress-streams=y",
+		"--recompress-flate",
+		"--compression-level=9",
+	}
+	if job.Quality < 80 {
+		args = append(args, "--object-streams=generate")
+	}
+	args = append(args, job.InputFile, job.OutputFile)
+
+	cmd := command(ctx, "qpdf", args...)
+	cmd.Stderr = os.Stderr
+
+	var exitErr *exec.ExitError
+	if err := cmd.Run(); err != nil && !(errors.As(err, &exitErr) && exitErr.ExitCode() == qpdfWarningsExitCode) {
+		return fmt.Errorf("qpdf compression failed: %w", commandError(ctx, err))
+	}
+	return nil
+}
@@ -0,0 +1,311 @@
+package pdftool
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/draw"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+// RasterizeOptions holds settings for rendering PDF pages to images
+type RasterizeOptions struct {
+	Format  string // "png" or "jpg"
+	DPI     int    // Rendering resolution
+	Pages   string // Page selection like "1-5,8"; empty for all pages
+	Quality int    // JPEG quality (1-100)
+}
+
+// Stitch directions for combining pages into one image
+const (
+	StitchVertical   = "vertical"
+	StitchHorizontal = "horizontal"
+)
+
+// maxJPEGDimension is the largest width or height a JPEG file can store
+const maxJPEGDimension = 65535
+
+// RasterizePDF renders the selected pages of a PDF to image files in
+// outputDir, named after the input and page number, and returns their paths.
+// Ghostscript renders any page; without it only scanned pages, which consist
+// of a single image, can be exported.
+func RasterizePDF(ctx context.Context, inputFile, outputDir string, opts RasterizeOptions) ([]string, error) {
+	if err := checkInputFile(inputFile); err != nil {
+		return nil, err
+	}
+
+	var ext, device string
+	switch strings.ToLower(opts.Format) {
+	case "png":
+		ext, device = ".png", "png16m"
+	case "jpg", "jpeg":
+		ext, device = ".jpg", "jpeg"
+	default:
+		return nil, fmt.Errorf("unsupported image format: %s (supported: png, jpg)", opts.Format)
+	}
+
+	pageCount, pages, err := selectPages(inputFile, opts.Pages)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	// Zero-pad page numbers so the files sort in page order
+	base := strings.TrimSuffix(filepath.Base(inputFile), filepath.Ext(inputFile))
+	digits := len(strconv.Itoa(pageCount))
+	outputFiles := make([]string, len(pages))
+	for i, page := range pages {
+		outputFiles[i] = filepath.Join(outputDir, fmt.Sprintf("%s-%0*d%s", base, digits, page, ext))
+	}
+
+	if isGhostscriptAvailable() {
+		logf("Using Ghostscript for rendering...")
+		err = rasterizeWithGhostscript(ctx, inputFile, outputDir, pages, outputFiles, device, opts)
+	} else {
+		logf("Ghostscript not found, exporting embedded page images (scanned pages only)...")
+		err = rasterizeScannedPages(ctx, inputFile, pages, outputFiles, ext, opts.Quality)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return outputFiles, nil
+}
+
+// RasterizePDFToZip renders the selected pages of a PDF like RasterizePDF,
+// but collects the images in a ZIP archive instead of a directory, and
+// returns their entry names
+func RasterizePDFToZip(ctx context.Context, inputFile, zipFile string, opts RasterizeOptions) ([]string, error) {
+	tmpDir, removeTmpDir, err := TempDir("", "pdf-tool-rasterize-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer removeTmpDir()
+
+	files, err := RasterizePDF(ctx, inputFile, tmpDir, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(files))
+	err = writeZip(zipFile, func(zw *zip.Writer) error {
+		for i, file := range files {
+			names[i] = filepath.Base(file)
+			if err := addFileToZip(zw, file, false); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return names, nil
+}
+
+// StitchPDF renders the selected pages of a PDF and joins them into a single
+// image file, top to bottom or left to right
+func StitchPDF(ctx context.Context, inputFile, outputFile, direction string, opts RasterizeOptions) error {
+	if direction != StitchVertical && direction != StitchHorizontal {
+		return fmt.Errorf("invalid stitch direction: %s (supported: vertical, horizontal)", direction)
+	}
+
+	ext := strings.ToLower(filepath.Ext(outputFile))
+	if ext != ".png" && ext != ".jpg" && ext != ".jpeg" {
+		return fmt.Errorf("unsupported output format: %s (supported: .png, .jpg, .jpeg)", ext)
+	}
+
+	tmpDir, removeTmpDir, err := TempDir("", "pdf-tool-stitch-")
+	if err != nil {
+		return fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer removeTmpDir()
+
+	// Render losslessly; the output format only matters for the final image
+	renderOpts := opts
+	renderOpts.Format = "png"
+	pageFiles, err := RasterizePDF(ctx, inputFile, tmpDir, renderOpts)
+	if err != nil {
+		return err
+	}
+
+	pages := make([]image.Image, len(pageFiles))
+	for i, pageFile := range pageFiles {
+		if pages[i], err = decodeImage(ctx, diskFS{}, pageFile); err != nil {
+			return err
+		}
+	}
+
+	stitched := stitchImages(pages, direction == StitchVertical)
+
+	size := stitched.Bounds().Size()
+	if ext != ".png" && (size.X > maxJPEGDimension || size.Y > maxJPEGDimension) {
+		return fmt.Errorf("stitched image is %dx%d pixels, too large for JPEG; use PNG or a lower --dpi", size.X, size.Y)
+	}
+
+	return writeImageFile(outputFile, stitched, ext, opts.Quality)
+}
+
+// stitchImages joins images into one, centering narrower images on a white
+// background
+func stitchImages(images []image.Image, vertical bool) image.Image {
+	var width, height int
+	for _, img := range images {
+		size := img.Bounds().Size()
+		if vertical {
+			width = max(width, size.X)
+			height += size.Y
+		} else {
+			width += size.X
+			height = max(height, size.Y)
+		}
+	}
+
+	canvas := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(canvas, canvas.Bounds(), image.White, image.Point{}, draw.Src)
+
+	offset := 0
+	for _, img := range images {
+		bounds := img.Bounds()
+		var at image.Point
+		if vertical {
+			at = image.Pt((width-bounds.Dx())/2, offset)
+			offset += bounds.Dy()
+		} else {
+			at = image.Pt(offset, (height-bounds.Dy())/2)
+			offset += bounds.Dx()
+		}
+		draw.Draw(canvas, bounds.Sub(bounds.Min).Add(at), img, bounds.Min, draw.Over)
+	}
+
+	return canvas
+}
+
+// selectPages returns the page count of a PDF and the pages matching a
+// selection in ascending order
+func selectPages(inputFile, selection string) (int, []int, error) {
+	pageCount, err := api.PageCountFile(inputFile)
+	if err != nil {
+		return 0, nil, readError(err)
+	}
+
+	pages, err := pagesForSelection(pageCount, selection)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return pageCount, pages, nil
+}
+
+// rasterizeWithGhostscript renders pages into a temporary directory, then moves
+// the numbered results to their final names
+func rasterizeWithGhostscript(ctx context.Context, inputFile, outputDir string, pages []int, outputFiles []string, device string, opts RasterizeOptions) error {
+	tmpDir, removeTmpDir, err := TempDir(outputDir, ".render-")
+	if err != nil {
+		return fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer removeTmpDir()
+
+	pageList := make([]string, len(pages))
+	for i, page := range pages {
+		pageList[i] = strconv.Itoa(page)
+	}
+
+	var extraArgs []string
+	if device == "jpeg" {
+		extraArgs = append(extraArgs, fmt.Sprintf("-dJPEGQ=%d", opts.Quality))
+	}
+
+	pattern := filepath.Join(tmpDir, "%06d"+filepath.Ext(outputFiles[0]))
+	if err := ghostscriptRender(ctx, inputFile, pattern, device, opts.DPI, strings.Join(pageList, ","), extraArgs...); err != nil {
+		return err
+	}
+
+	// Ghostscript numbers output files consecutively, in page order
+	for i, outputFile := range outputFiles {
+		rendered := filepath.Join(tmpDir, fmt.Sprintf("%06d%s", i+1, filepath.Ext(outputFile)))
+		if err := os.Rename(rendered, outputFile); err != nil {
+			return fmt.Errorf("page %d was not rendered: %w", pages[i], err)
+		}
+	}
+
+	return nil
+}
+
+// rasterizeScannedPages exports pages that consist of a single embedded image,
+// at the image's native resolution
+func rasterizeScannedPages(ctx context.Context, inputFile string, pages []int, outputFiles []string, ext string, quality int) error {
+	data, err := os.ReadFile(inputFile)
+	if err != nil {
+		return fmt.Errorf("failed to open PDF: %w", err)
+	}
+
+	conf := newConfig()
+	conf.Cmd = model.EXTRACTIMAGES
+
+	doc, err := await(ctx, func() (*model.Context, error) {
+		return api.ReadValidateAndOptimize(bytes.NewReader(data), conf)
+	})
+	if err != nil {
+		return readError(err)
+	}
+
+	for i, page := range pages {
+		if err := contextError(ctx); err != nil {
+			return err
+		}
+		images, err := pdfcpu.ExtractPageImages(doc, page, false)
+		if err != nil {
+			return fmt.Errorf("failed to extract images of page %d: %w", page, err)
+		}
+
+		var pageImages []model.Image
+		for _, img := range images {
+			if !img.Thumb {
+				pageImages = append(pageImages, img)
+			}
+		}
+		if len(pageImages) != 1 {
+			return fmt.Errorf("page %d is not a single scanned image; install Ghostscript to render it", page)
+		}
+
+		img, _, err := image.Decode(pageImages[0])
+		if err != nil {
+			return fmt.Errorf("failed to decode image of page %d (%s): %w", page, pageImages[0].FileType, err)
+		}
+
+		if err := writeImageFile(outputFiles[i], img, ext, quality); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeImageFile encodes an image to a PNG or JPEG file
+func writeImageFile(path string, img image.Image, ext string, quality int) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create image file: %w", err)
+	}
+	defer file.Close()
+
+	if err := encodeImageQuality(file, img, ext, quality); err != nil {
+		return fmt.Errorf("failed to encode image: %w", err)
+	}
+
+	return file.Close()
+}
@@ -0,0 +1,674 @@
+package pdftool
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// RedactOptions selects what to remove from a PDF
+type RedactOptions struct {
+	Patterns []string     // Regular expressions matched against each line of text
+	Areas    []RedactArea // Page regions removed entirely
+	Pages    string       // Pages searched for patterns; empty or "all" for every page
+}
+
+// RedactArea is a region of a page in points from the bottom-left corner
+type RedactArea struct {
+	Page int
+	Rect [4]float64 // x0, y0, x1, y1
+}
+
+// ParseRedactArea parses an area given as "page:x0,y0,x1,y1", with lengths
+// in points or with a unit such as mm
+func ParseRedactArea(s string) (RedactArea, error) {
+	pageSpec, rectSpec, ok := strings.Cut(s, ":")
+	if !ok {
+		return RedactArea{}, fmt.Errorf("invalid area: %s (expected page:x0,y0,x1,y1)", s)
+	}
+
+	page, err := strconv.Atoi(strings.TrimSpace(pageSpec))
+	if err != nil || page < 1 {
+		return RedactArea{}, fmt.Errorf("invalid page in area: %s", s)
+	}
+
+	values, err := parseLengths(rectSpec)
+	if err != nil {
+		return RedactArea{}, err
+	}
+	if len(values) != 4 {
+		return RedactArea{}, fmt.Errorf("invalid area: %s (expected page:x0,y0,x1,y1)", s)
+	}
+	if values[0] == values[2] || values[1] == values[3] {
+		return RedactArea{}, fmt.Errorf("empty area: %s", s)
+	}
+
+	return RedactArea{Page: page, Rect: [4]float64(values)}, nil
+}
+
+// RedactPDF permanently removes text matching the patterns and everything in
+// the given areas, then covers the redacted places with black boxes. Glyphs
+// are deleted from the content streams, images are blacked out pixel by
+// pixel, and form fields and annotations are flattened first so their text
+// is redacted too.
+func RedactPDF(ctx context.Context, inputFile, outputFile string, opts RedactOptions) error {
+	if len(opts.Patterns) == 0 && len(opts.Areas) == 0 {
+		return fmt.Errorf("specify at least one pattern or area to redact")
+	}
+
+	patterns := make([]*regexp.Regexp, len(opts.Patterns))
+	for i, pattern := range opts.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+		patterns[i] = re
+	}
+
+	doc, err := readContext(ctx, inputFile)
+	if err != nil {
+		return err
+	}
+
+	areas := make(map[int][]pageRect)
+	for _, area := range opts.Areas {
+		if area.Page > doc.PageCount {
+			return fmt.Errorf("area on page %d, but the PDF has %d pages", area.Page, doc.PageCount)
+		}
+		areas[area.Page] = append(areas[area.Page], newPageRect(area.Rect[0], area.Rect[1], area.Rect[2], area.Rect[3]))
+	}
+
+	searched := pageSet(nil)
+	if len(patterns) > 0 {
+		pages, err := stampSelection(doc.PageCount, opts.Pages)
+		if err != nil {
+			return err
+		}
+		searched = pageSet(pages)
+	}
+
+	matches, redactedPages := 0, 0
+	glyphs, masked, removed := 0, 0, 0
+	for page := 1; page <= doc.PageCount; page++ {
+		if err := contextError(ctx); err != nil {
+			return err
+		}
+		if _, _, err := flattenPage(doc, page); err != nil {
+			return fmt.Errorf("failed to flatten page %d: %w", page, err)
+		}
+
+		rects := areas[page]
+		if searched[page] {
+			chars, err := pageChars(doc, page)
+			if err != nil {
+				return fmt.Errorf("failed to read text of page %d: %w", page, err)
+			}
+			found := matchedRects(chars, patterns)
+			matches += len(found)
+			rects = append(rects, found...)
+		}
+		if len(rects) == 0 {
+			continue
+		}
+
+		r, err := redactPage(doc, page, rects)
+		if err != nil {
+			return fmt.Errorf("failed to redact page %d: %w", page, err)
+		}
+		glyphs += r.glyphs
+		masked += r.masked
+		removed += r.removed
+		redactedPages++
+	}
+
+	rootDict, err := doc.Catalog()
+	if err != nil {
+		return fmt.Errorf("failed to read catalog: %w", err)
+	}
+	rootDict.Delete("AcroForm")
+
+	if err := writeContextFile(ctx, doc, outputFile); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outputFile, err)
+	}
+
+	if len(patterns) > 0 && matches == 0 {
+		warnf("No text matched the patterns; scanned pages need ocr first")
+	}
+	logf("Redacted %d pages: removed %d characters, blacked out %d images and removed %d images, wrote %s",
+		redactedPages, glyphs, masked, removed, outputFile)
+	return nil
+}
+
+// pageRect is an axis-aligned rectangle in page space
+type pageRect struct {
+	x0, y0, x1, y1 float64
+}
+
+// newPageRect returns the rectangle spanned by two corners
+func newPageRect(x0, y0, x1, y1 float64) pageRect {
+	return pageRect{math.Min(x0, x1), math.Min(y0, y1), math.Max(x0, x1), math.Max(y0, y1)}
+}
+
+// boundingRect returns the bounds of points transformed by m
+func boundingRect(m matrix, points [][2]float64) pageRect {
+	r := pageRect{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)}
+	for _, p := range points {
+		x := m[0]*p[0] + m[2]*p[1] + m[4]
+		y := m[1]*p[0] + m[3]*p[1] + m[5]
+		r = pageRect{math.Min(r.x0, x), math.Min(r.y0, y), math.Max(r.x1, x), math.Max(r.y1, y)}
+	}
+	return r
+}
+
+// union returns the smallest rectangle containing both
+func (r pageRect) union(o pageRect) pageRect {
+	return pageRect{math.Min(r.x0, o.x0), math.Min(r.y0, o.y0), math.Max(r.x1, o.x1), math.Max(r.y1, o.y1)}
+}
+
+// minRedactOverlap is the overlap in points below which content merely
+// touching a redacted area, such as the neighbors of a matched word, is kept
+const minRedactOverlap = 0.1
+
+// overlaps reports whether two rectangles share more than a sliver
+func (r pageRect) overlaps(o pageRect) bool {
+	return math.Min(r.x1, o.x1)-math.Max(r.x0, o.x0) > minRedactOverlap &&
+		math.Min(r.y1, o.y1)-math.Max(r.y0, o.y0) > minRedactOverlap
+}
+
+// contains reports whether a point lies in the rectangle
+func (r pageRect) contains(x, y float64) bool {
+	return x >= r.x0 && x <= r.x1 && y >= r.y0 && y <= r.y1
+}
+
+// matchedRects returns the areas of the text lines matching the patterns.
+// Lines are joined the same way as for text extraction, so a pattern that
+// matches the extracted text matches here too.
+func matchedRects(chars []textChar, patterns []*regexp.Regexp) []pageRect {
+	var rects []pageRect
+	for _, line := range textLines(chars) {
+		var text strings.Builder
+		var owners []int // Character of each byte of text; -1 for inserted spaces
+		var prev *textChar
+
+		for i := range line.chars {
+			c := &line.chars[i]
+			if prev != nil {
+				if c.text == prev.text && math.Abs(c.x0-prev.x0) < 0.1*c.size {
+					continue // Fake bold; covered by the first copy
+				}
+				if c.x0-prev.x1 > 0.2*c.size && c.text != " " && prev.text != " " {
+					text.WriteByte(' ')
+					owners = append(owners, -1)
+				}
+			}
+			text.WriteString(c.text)
+			for range len(c.text) {
+				owners = append(owners, i)
+			}
+			prev = c
+		}
+
+		for _, re := range patterns {
+			for _, match := range re.FindAllStringIndex(text.String(), -1) {
+				var covered *pageRect
+				for _, owner := range owners[match[0]:match[1]] {
+					if owner < 0 {
+						continue
+					}
+					box := line.chars[owner].box
+					if covered != nil {
+						box = covered.union(box)
+					}
+					covered = &box
+				}
+				if covered != nil {
+					rects = append(rects, *covered)
+				}
+			}
+		}
+	}
+	return rects
+}
+
+// redactPage removes the content of a page inside rects and draws black
+// boxes over them
+func redactPage(ctx *model.Context, page int, rects []pageRect) (*redactor, error) {
+	pageDict, _, inherited, err := ctx.PageDict(page, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources types.Dict
+	if inherited != nil {
+		resources = inherited.Resources
+	}
+
+	r := &redactor{rects: rects}
+	r.ctx = ctx
+	r.fonts = make(map[string]*glyphDecoder)
+	r.state = textState{ctm: identity, scale: 1}
+
+	content, err := ctx.PageContent(pageDict, page)
+	if err != nil && err != model.ErrNoContent {
+		return nil, err
+	}
+	content, resources, changed, err := r.rewrite(content, resources, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if changed {
+		sd, err := ctx.NewStreamDictForBuf(content)
+		if err != nil {
+			return nil, err
+		}
+		if err := sd.Encode(); err != nil {
+			return nil, err
+		}
+		ref, err := ctx.IndRefForNewObject(*sd)
+		if err != nil {
+			return nil, err
+		}
+		pageDict["Contents"] = *ref
+		pageDict["Resources"] = resources
+	}
+
+	// The thumbnail would still show the redacted content
+	pageDict.Delete("Thumb")
+
+	var boxes strings.Builder
+	boxes.WriteString("0 g\n")
+	for _, rect := range rects {
+		fmt.Fprintf(&boxes, "%.2f %.2f %.2f %.2f re f\n", rect.x0, rect.y0, rect.x1-rect.x0, rect.y1-rect.y0)
+	}
+	if err := wrapPageContent(ctx, pageDict, boxes.String()); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// redactor rewrites content streams without what lies in the redacted
+// areas. Removed glyphs are replaced by spacing so the remaining text keeps
+// its place; images are blacked out where they overlap and inline images and
+// images that cannot be decoded are dropped.
+type redactor struct {
+	textReader
+	rects   []pageRect
+	glyphs  int // Glyphs removed
+	masked  int // Images blacked out
+	removed int // Images removed
+	names   int // XObjects added to resources
+}
+
+// rewrite returns a content stream with the redacted content removed, and
+// its resources. If XObjects were replaced or removed, the resources are
+// copied with only the XObjects still drawn, so the originals are no longer
+// referenced and are left out of the output.
+func (r *redactor) rewrite(content []byte, resources types.Dict, depth int) ([]byte, types.Dict, bool, error) {
+	lexer := contentLexer{data: content}
+	var out bytes.Buffer
+	var operands []any
+	var added types.Dict          // Redacted copies of replaced XObjects
+	used := make(map[string]bool) // XObjects drawn unchanged
+	start, changed, dropped := 0, false, false
+
+	for {
+		value, op, ok := lexer.next()
+		if !ok {
+			break
+		}
+		if op == "" {
+			operands = append(operands, value)
+			continue
+		}
+
+		chunk := content[start:lexer.pos]
+		start = lexer.pos
+
+		var replacement string
+		replaced := false
+		switch op {
+		case "Tj", "'", "\"", "TJ":
+			replacement, replaced = r.showText(op, operands)
+		case "BI":
+			if r.redacted(boundingRect(r.state.ctm, unitSquare)) {
+				replaced = true
+				r.removed++
+			}
+		case "Do":
+			ref, err := r.xobject(operands, resources, depth)
+			if err != nil {
+				return nil, nil, false, err
+			}
+			switch {
+			case ref == nil:
+				if len(operands) == 1 {
+					if name, ok := operands[0].(pdfName); ok {
+						used[string(name)] = true
+					}
+				}
+			case *ref == types.IndirectRef{}:
+				replaced, dropped = true, true
+			default:
+				replaced, dropped = true, true
+				if added == nil {
+					added = types.Dict{}
+				}
+				r.names++
+				name := fmt.Sprintf("Redacted%d", r.names)
+				added[name] = *ref
+				replacement = "/" + name + " Do"
+			}
+		default:
+			r.apply(op, operands, resources, depth)
+		}
+
+		if replaced {
+			out.WriteString("\n" + replacement + "\n")
+			changed = true
+		} else {
+			out.Write(chunk)
+		}
+		operands = operands[:0]
+	}
+	out.Write(content[start:])
+
+	if dropped {
+		xobjects := types.Dict{}
+		if existing, err := r.ctx.DereferenceDict(resources["XObject"]); err == nil {
+			for k, v := range existing {
+				if used[k] {
+					xobjects[k] = v
+				}
+			}
+		}
+		for k, v := range added {
+			xobjects[k] = v
+		}
+		resources = resources.Clone().(types.Dict)
+		resources["XObject"] = xobjects
+	}
+	return out.Bytes(), resources, changed, nil
+}
+
+// unitSquare is the area images are drawn into, before the CTM
+var unitSquare = [][2]float64{{0, 0}, {1, 0}, {0, 1}, {1, 1}}
+
+// redacted reports whether a box overlaps a redacted area
+func (r *redactor) redacted(box pageRect) bool {
+	for _, rect := range r.rects {
+		if rect.overlaps(box) {
+			return true
+		}
+	}
+	return false
+}
+
+// showText handles a text showing operator. If glyphs fall into a redacted
+// area, it returns an equivalent TJ operation without them.
+func (r *redactor) showText(op string, operands []any) (string, bool) {
+	s := &r.state
+	var prefix string
+	var items []any
+
+	switch op {
+	case "Tj":
+		if len(operands) == 1 {
+			items = operands
+		}
+	case "'":
+		r.moveLine(0, -s.leading)
+		prefix = "T* "
+		if len(operands) == 1 {
+			items = operands
+		}
+	case "\"":
+		if len(operands) == 3 {
+			s.wordSpace, _ = operands[0].(float64)
+			s.charSpace, _ = operands[1].(float64)
+			prefix = fmt.Sprintf("%s Tw %s Tc ", formatNumber(s.wordSpace), formatNumber(s.charSpace))
+		}
+		r.moveLine(0, -s.leading)
+		prefix += "T* "
+		if len(operands) == 3 {
+			items = operands[2:]
+		}
+	case "TJ":
+		if len(operands) == 1 {
+			items, _ = operands[0].([]any)
+		}
+	}
+
+	if s.font == nil {
+		// Without a font the glyphs cannot be placed; judge by the start
+		start := s.textMatrix.multiply(s.ctm)
+		if len(items) > 0 && r.redacted(pageRect{start[4] - 1, start[5] - 1, start[4] + 1, start[5] + 1}) {
+			r.glyphs++
+			return strings.TrimSpace(prefix), true
+		}
+		return "", false
+	}
+
+	var parts []any
+	removed := 0
+	for _, item := range items {
+		switch v := item.(type) {
+		case float64:
+			parts = appendSpacing(parts, v)
+			r.advance(-v / 1000 * s.size * s.scale)
+		case string:
+			for i, g := range s.font.decode(v) {
+				trm, tx := r.place(g)
+				r.advance(tx)
+				if r.redacted(glyphBox(trm, g.width)) {
+					removed++
+					if s.size != 0 && s.scale != 0 {
+						parts = appendSpacing(parts, -tx*1000/(s.size*s.scale))
+					}
+					continue
+				}
+				code := v[i*s.font.codeBytes : (i+1)*s.font.codeBytes]
+				if n := len(parts); n > 0 {
+					if last, ok := parts[n-1].(string); ok {
+						parts[n-1] = last + code
+						continue
+					}
+				}
+				parts = append(parts, code)
+			}
+		}
+	}
+
+	if removed == 0 {
+		return "", false
+	}
+	r.glyphs += removed
+
+	var b strings.Builder
+	b.WriteString(prefix + "[")
+	for _, part := range parts {
+		switch v := part.(type) {
+		case string:
+			b.WriteString("<" + hex.EncodeToString([]byte(v)) + ">")
+		case float64:
+			b.WriteString(" " + formatNumber(v) + " ")
+		}
+	}
+	b.WriteString("] TJ")
+	return b.String(), true
+}
+
+// appendSpacing adds a TJ position adjustment, merging it with a preceding one
+func appendSpacing(parts []any, n float64) []any {
+	if len(parts) > 0 {
+		if last, ok := parts[len(parts)-1].(float64); ok {
+			parts[len(parts)-1] = last + n
+			return parts
+		}
+	}
+	return append(parts, n)
+}
+
+// formatNumber formats a content stream number with up to three decimals
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(math.Round(n*1000)/1000, 'f', -1, 64)
+}
+
+// xobject handles a Do operator. It returns nil to keep the operation, an
+// empty reference to remove it or the reference of a redacted copy of the
+// XObject to draw instead.
+func (r *redactor) xobject(operands []any, resources types.Dict, depth int) (*types.IndirectRef, error) {
+	if len(operands) != 1 {
+		return nil, nil
+	}
+	name, ok := operands[0].(pdfName)
+	if !ok {
+		return nil, nil
+	}
+	xobjects, err := r.ctx.DereferenceDict(resources["XObject"])
+	if err != nil || xobjects == nil {
+		return nil, nil
+	}
+	ref, ok := xobjects[string(name)].(types.IndirectRef)
+	if !ok {
+		return nil, nil
+	}
+	sd, _, err := r.ctx.DereferenceStreamDict(ref)
+	if err != nil || sd == nil || sd.Subtype() == nil {
+		return nil, nil
+	}
+
+	switch *sd.Subtype() {
+	case "Form":
+		if depth >= maxFormDepth {
+			return nil, nil
+		}
+		return r.redactForm(sd, resources, depth)
+	case "Image":
+		if !r.redacted(boundingRect(r.state.ctm, unitSquare)) {
+			return nil, nil
+		}
+		copied, err := r.redactImage(sd, ref.ObjectNumber.Value())
+		if err != nil {
+			return nil, err
+		}
+		if copied == nil {
+			r.removed++
+			return &types.IndirectRef{}, nil
+		}
+		r.masked++
+		return copied, nil
+	}
+	return nil, nil
+}
+
+// redactForm rewrites the content of a form XObject drawn at the current
+// position and returns a redacted copy, or nil if nothing in it was redacted
+func (r *redactor) redactForm(sd *types.StreamDict, resources types.Dict, depth int) (*types.IndirectRef, error) {
+	if err := sd.Decode(); err != nil {
+		return nil, nil
+	}
+
+	formResources := resources
+	if d, err := r.ctx.DereferenceDict(sd.Dict["Resources"]); err == nil && d != nil {
+		formResources = d
+	}
+
+	saved, savedStack := r.state, r.stack
+	if m, err := r.ctx.DereferenceArray(sd.Dict["Matrix"]); err == nil && len(m) == 6 {
+		var fm matrix
+		for i, v := range m {
+			fm[i], _ = numberValue(v)
+		}
+		r.state.ctm = fm.multiply(r.state.ctm)
+	}
+	r.stack = nil
+	content, formResources, changed, err := r.rewrite(sd.Content, formResources, depth+1)
+	r.state, r.stack = saved, savedStack
+	if err != nil || !changed {
+		return nil, err
+	}
+
+	copied, err := r.ctx.NewStreamDictForBuf(content)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range sd.Dict {
+		switch k {
+		case "Length", "Filter", "DecodeParms", "DL":
+		default:
+			copied.Dict[k] = v
+		}
+	}
+	if formResources != nil {
+		copied.Dict["Resources"] = formResources
+	}
+	if err := copied.Encode(); err != nil {
+		return nil, err
+	}
+	return r.ctx.IndRefForNewObject(*copied)
+}
+
+// redactImage returns a copy of an image drawn at the current position with
+// the pixels in redacted areas painted black, or nil if the image cannot be
+// decoded
+func (r *redactor) redactImage(sd *types.StreamDict, objNr int) (*types.IndirectRef, error) {
+	if mask := sd.BooleanEntry("ImageMask"); mask != nil && *mask {
+		return nil, nil // Stencil masks paint with the fill color; drop them
+	}
+
+	extracted, err := pdfcpu.ExtractImage(r.ctx, sd, false, "", objNr, false)
+	if err != nil || extracted == nil {
+		return nil, nil
+	}
+	src, _, err := image.Decode(extracted)
+	if err != nil {
+		return nil, nil
+	}
+
+	bounds := src.Bounds()
+	img := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(img, img.Bounds(), src, bounds.Min, draw.Src)
+
+	// Image space is the unit square with the first row at the top
+	m := r.state.ctm
+	width, height := float64(img.Bounds().Dx()), float64(img.Bounds().Dy())
+	black := color.NRGBA{A: 255}
+	for py := 0; py < img.Bounds().Dy(); py++ {
+		v := 1 - (float64(py)+0.5)/height
+		for px := 0; px < img.Bounds().Dx(); px++ {
+			u := (float64(px) + 0.5) / width
+			x := m[0]*u + m[2]*v + m[4]
+			y := m[1]*u + m[3]*v + m[5]
+			for _, rect := range r.rects {
+				if rect.contains(x, y) {
+					img.SetNRGBA(px, py, black)
+					break
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode image: %w", err)
+	}
+	ref, _, _, err := model.CreateImageResource(r.ctx.XRefTable, &buf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create image: %w", err)
+	}
+	return ref, nil
+}
@@ -0,0 +1,117 @@
+package pdftool
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// imagePDF writes a one-page PDF showing a red image and returns its path
+func imagePDF(t *testing.T, dir string) string {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, 64, 32))
+	for y := range 32 {
+		for x := range 64 {
+			img.SetNRGBA(x, y, color.NRGBA{R: 255, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	pngFile := filepath.Join(dir, "red.png")
+	if err := os.WriteFile(pngFile, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	pdfFile := filepath.Join(dir, "image.pdf")
+	if err := api.ImportImagesFile([]string{pngFile}, pdfFile, nil, newConfig()); err != nil {
+		t.Fatal(err)
+	}
+	return pdfFile
+}
+
+// imageStreams returns the raw data of the image XObjects in a PDF, whether
+// or not a page draws them
+func imageStreams(t *testing.T, pdfFile string) [][]byte {
+	t.Helper()
+	doc, err := api.ReadContextFile(pdfFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var streams [][]byte
+	for objNr, entry := range doc.Table {
+		if entry == nil || entry.Free {
+			continue
+		}
+		sd, ok := entry.Object.(types.StreamDict)
+		if !ok || sd.Subtype() == nil || *sd.Subtype() != "Image" {
+			continue
+		}
+		if len(sd.Raw) == 0 {
+			t.Fatalf("image object %d has no data", objNr)
+		}
+		streams = append(streams, sd.Raw)
+	}
+	return streams
+}
+
+func TestRedactAreaReplacesImage(t *testing.T) {
+	dir := t.TempDir()
+	input := imagePDF(t, dir)
+	original := imageStreams(t, input)
+	if len(original) != 1 {
+		t.Fatalf("input has %d images, want 1", len(original))
+	}
+
+	dims, err := api.PageDimsFile(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	output := filepath.Join(dir, "redacted.pdf")
+	err = RedactPDF(context.Background(), input, output, RedactOptions{
+		Areas: []RedactArea{{Page: 1, Rect: [4]float64{0, 0, dims[0].Width, dims[0].Height}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, raw := range imageStreams(t, output) {
+		if bytes.Equal(raw, original[0]) {
+			t.Fatal("the original image stream is still in the redacted PDF")
+		}
+	}
+
+	extracted, err := ExtractImages(context.Background(), output, filepath.Join(dir, "images"), ExtractImagesOptions{Format: "png"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(extracted) != 1 {
+		t.Fatalf("extracted %d images from the redacted PDF, want the blacked out copy", len(extracted))
+	}
+	file, err := os.Open(extracted[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	img, _, err := image.Decode(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if r, g, b, _ := img.At(x, y).RGBA(); r|g|b != 0 {
+				t.Fatalf("pixel %d,%d of the redacted image is not black", x, y)
+			}
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package pdftool
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// renderPages rasterizes the pages of a PDF into PNG files in dir using
+// Ghostscript and returns the file names in page order
+func renderPages(ctx context.Context, inputFile, dir string, dpi int) ([]string, error) {
+	pattern := filepath.Join(dir, "page-%04d.png")
+	if err := ghostscriptRender(ctx, inputFile, pattern, "png16m", dpi, ""); err != nil {
+		return nil, err
+	}
+
+	pages, err := filepath.Glob(filepath.Join(dir, "page-*.png"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(pages) // Zero-padded, so name order is page order
+
+	return pages, nil
+}
+
+// ghostscriptRender renders PDF pages with a Ghostscript raster device.
+// outputPattern contains a printf verb for the output page counter, and
+// pageList optionally restricts rendering to pages like "1,3,5-7".
+func ghostscriptRender(ctx context.Context, inputFile, outputPattern, device string, dpi int, pageList string, extraArgs ...string) error {
+	if !isGhostscriptAvailable() {
+		return programMissing("ghostscript", "install it to render PDF pages")
+	}
+	if err := checkGhostscriptDevice(device); err != nil {
+		return err
+	}
+
+	args := []string{
+		"-q",
+		"-dNOPAUSE",
+		"-dBATCH",
+		"-dSAFER",
+		"-sDEVICE=" + device,
+		"-dTextAlphaBits=4",     // Anti-alias text
+		"-dGraphicsAlphaBits=4", // Anti-alias line art
+		fmt.Sprintf("-r%d", dpi),
+	}
+	if pageList != "" {
+		args = append(args, "-sPageList="+pageList)
+	}
+	args = append(args, extraArgs...)
+	args = append(args, "-sOutputFile="+outputPattern, inputFile)
+
+	gsCmd := command(ctx, ghostscriptCommand(), args...)
+	gsCmd.Stderr = os.Stderr
+
+	if err := gsCmd.Run(); err != nil {
+		return fmt.Errorf("ghostscript rendering failed: %w", commandError(ctx, err))
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package pdftool
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+// RepairPDF rewrites a damaged PDF, for example one with a broken or missing
+// cross-reference table, so that other tools can process it again. pdfcpu's
+// relaxed parser reconstructs what it can; if that fails, Ghostscript
+// re-distills the file, which also rewrites the page content.
+func RepairPDF(ctx context.Context, inputFile, outputFile string) error {
+	if err := checkInputFile(inputFile); err != nil {
+		return err
+	}
+
+	pageCount, err := repairWithPdfcpu(ctx, inputFile, outputFile)
+	if err == nil {
+		logf("Repaired %s with pdfcpu (%d pages), wrote %s", inputFile, pageCount, outputFile)
+		return nil
+	}
+	if err := contextError(ctx); err != nil {
+		return err
+	}
+	warnf("pdfcpu could not repair the file: %v", err)
+
+	if !isGhostscriptAvailable() {
+		return fmt.Errorf("pdfcpu could not repair %s and re-distilling it needs Ghostscript: %w", inputFile, programMissing("ghostscript", "install it to repair the file"))
+	}
+
+	logf("Re-distilling with Ghostscript...")
+	if err := repairWithGhostscript(ctx, inputFile, outputFile); err != nil {
+		return err
+	}
+	repaired, err := readContext(ctx, outputFile)
+	if err != nil {
+		return fmt.Errorf("ghostscript output is still damaged: %w", err)
+	}
+
+	logf("Repaired %s with Ghostscript (%d pages), wrote %s", inputFile, repaired.PageCount, outputFile)
+	return nil
+}
+
+// repairWithPdfcpu reads a PDF with pdfcpu's relaxed parser, which rebuilds a
+// damaged cross-reference table by scanning for objects, and writes it back.
+// The result is read again to make sure it is sound.
+func repairWithPdfcpu(ctx context.Context, inputFile, outputFile string) (int, error) {
+	doc, err := readContext(ctx, inputFile)
+	if err != nil {
+		return 0, err
+	}
+	if doc.PageCount == 0 {
+		return 0, fmt.Errorf("no pages found")
+	}
+
+	var out bytes.Buffer
+	if err := api.WriteContext(doc, &out); err != nil {
+		return 0, fmt.Errorf("failed to write PDF: %w", err)
+	}
+	_, err = await(ctx, func() (*model.Context, error) {
+		return api.ReadAndValidate(bytes.NewReader(out.Bytes()), newConfig())
+	})
+	if err != nil {
+		return 0, fmt.Errorf("rewritten PDF is invalid: %w", err)
+	}
+
+	if err := os.WriteFile(outputFile, out.Bytes(), 0644); err != nil {
+		return 0, fmt.Errorf("failed to write %s: %w", outputFile, err)
+	}
+	return doc.PageCount, nil
+}
+
+// repairWithGhostscript re-distills a PDF with Ghostscript's PDF interpreter,
+// which tolerates most damage, keeping images at their resolution
+func repairWithGhostscript(ctx context.Context, inputFile, outputFile string) error {
+	if err := ghostscriptDistill(ctx, inputFile, outputFile, "-dPDFSETTINGS=/prepress"); err != nil {
+		return fmt.Errorf("ghostscript repair failed: %w", err)
+	}
+	return nil
+}
+
+// ghostscriptDistill rewrites a PDF with Ghostscript's pdfwrite device
+// without downsampling images. extraArgs may end with PostScript files to run
+// before the input. Ghostscript's messages are part of the error.
+func ghostscriptDistill(ctx context.Context, inputFile, outputFile string, extraArgs ...string) error {
+	if err := checkGhostscriptDevice("pdfwrite"); err != nil {
+		return err
+	}
+
+	args := []string{
+		"-q",
+		"-dNOPAUSE",
+		"-dBATCH",
+		"-dSAFER",
+		"-sDEVICE=pdfwrite",
+		"-dDownsampleColorImages=false",
+		"-dDownsampleGrayImages=false",
+		"-dDownsampleMonoImages=false",
+		"-sOutputFile=" + outputFile,
+	}
+	args = append(args, extraArgs...)
+	args = append(args, inputFile)
+
+	var stderr bytes.Buffer
+	gsCmd := command(ctx, ghostscriptCommand(), args...)
+	gsCmd.Stderr = &stderr
+
+	if err := gsCmd.Run(); err != nil {
+		err = commandError(ctx, err)
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
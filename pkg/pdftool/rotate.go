@@ -0,0 +1,113 @@
+package pdftool
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/png"
+	"io/fs"
+	"strconv"
+	"strings"
+
+	"github.com/disintegration/imaging"
+)
+
+// RotateAuto is the rotation setting that detects each image's orientation
+const RotateAuto = -1
+
+// ParseRotation parses a rotation of 0, 90, 180 or 270 degrees clockwise, or
+// "auto" for RotateAuto
+func ParseRotation(s string) (int, error) {
+	if strings.EqualFold(s, "auto") {
+		return RotateAuto, nil
+	}
+
+	degrees, err := strconv.Atoi(s)
+	if err != nil || degrees%90 != 0 || degrees < 0 || degrees > 270 {
+		return 0, fmt.Errorf("invalid rotation: %s (supported: 90, 180, 270, auto)", s)
+	}
+	return degrees, nil
+}
+
+// rotateClockwise rotates an image by a multiple of 90 degrees clockwise
+func rotateClockwise(img image.Image, degrees int) image.Image {
+	switch degrees {
+	case 90:
+		return imaging.Rotate270(img) // imaging rotates counter-clockwise
+	case 180:
+		return imaging.Rotate180(img)
+	case 270:
+		return imaging.Rotate90(img)
+	default:
+		return img
+	}
+}
+
+// autoOrient corrects an image's orientation using its EXIF orientation tag
+// or, for scans without one, Tesseract's orientation detection when available
+func autoOrient(ctx context.Context, img image.Image, fsys fs.FS, inputFile string) image.Image {
+	if orientation := exifOrientation(fsys, inputFile); orientation != 1 {
+		return applyOrientation(img, orientation)
+	}
+
+	if isTesseractAvailable() {
+		return rotateClockwise(img, detectRotation(ctx, img))
+	}
+	return img
+}
+
+// applyOrientation transforms an image so that it displays upright for the
+// given EXIF orientation (1-8)
+func applyOrientation(img image.Image, orientation int) image.Image {
+	switch orientation {
+	case 2:
+		return imaging.FlipH(img)
+	case 3:
+		return imaging.Rotate180(img)
+	case 4:
+		return imaging.FlipV(img)
+	case 5:
+		return imaging.Transpose(img)
+	case 6:
+		return rotateClockwise(img, 90)
+	case 7:
+		return imaging.Transverse(img)
+	case 8:
+		return rotateClockwise(img, 270)
+	}
+	return img
+}
+
+// detectRotation returns the clockwise rotation in degrees that Tesseract's
+// orientation and script detection suggests, or 0 if detection fails
+func detectRotation(ctx context.Context, img image.Image) int {
+	var encoded bytes.Buffer
+	if err := png.Encode(&encoded, img); err != nil {
+		return 0
+	}
+
+	var stdout bytes.Buffer
+	cmd := command(ctx, "tesseract", "stdin", "stdout", "--psm", "0")
+	cmd.Stdin = &encoded
+	cmd.Stdout = &stdout
+
+	// Detection needs the osd language data and enough text; treat any
+	// failure as "leave as is"
+	if err := cmd.Run(); err != nil {
+		return 0
+	}
+
+	scanner := bufio.NewScanner(&stdout)
+	for scanner.Scan() {
+		if value, ok := strings.CutPrefix(scanner.Text(), "Rotate:"); ok {
+			degrees, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil || degrees%90 != 0 {
+				return 0
+			}
+			return degrees
+		}
+	}
+	return 0
+}
@@ -0,0 +1,71 @@
+package pdftool
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"sync/atomic"
+)
+
+// Runner starts the external programs the library uses, such as
+// Ghostscript, qpdf, mutool, Tesseract and ImageMagick. Tests can replace
+// it with SetRunner to check the exact arguments and simulate failures
+// without the programs installed, e.g. by returning a command that runs the
+// test binary as a helper process, or one with Cmd.Err set.
+type Runner interface {
+	// LookPath searches for a program like exec.LookPath
+	LookPath(file string) (string, error)
+	// Command returns the command running a program, like
+	// exec.CommandContext; the caller sets its input and output and runs it
+	Command(ctx context.Context, name string, args ...string) *exec.Cmd
+}
+
+// execRunner is the default Runner, using os/exec
+type execRunner struct{}
+
+func (execRunner) LookPath(file string) (string, error) {
+	return exec.LookPath(file)
+}
+
+func (execRunner) Command(ctx context.Context, name string, args ...string) *exec.Cmd {
+	return exec.CommandContext(ctx, name, args...)
+}
+
+// runner is the Runner set with SetRunner
+var runner atomic.Pointer[Runner]
+
+func init() {
+	SetRunner(nil)
+}
+
+// SetRunner sets how external programs are found and run; nil restores
+// the default, which uses os/exec. The installed Ghostscript is detected
+// again afterwards. Pure-Go mode still applies: no Runner is called while
+// it is on.
+func SetRunner(r Runner) {
+	if r == nil {
+		r = execRunner{}
+	}
+	runner.Store(&r)
+	resetGhostscriptDetection()
+}
+
+// lookPath searches for an external program with the Runner; in pure-Go
+// mode it fails with ErrPureGo
+func lookPath(file string) (string, error) {
+	if PureGo() {
+		return "", fmt.Errorf("%s: %w", file, ErrPureGo)
+	}
+	return (*runner.Load()).LookPath(file)
+}
+
+// command returns the Runner's command for an external program; in pure-Go
+// mode starting it fails with ErrPureGo
+func command(ctx context.Context, name string, args ...string) *exec.Cmd {
+	if PureGo() {
+		cmd := exec.CommandContext(ctx, name, args...)
+		cmd.Err = fmt.Errorf("%s: %w", name, ErrPureGo)
+		return cmd
+	}
+	return (*runner.Load()).Command(ctx, name, args...)
+}
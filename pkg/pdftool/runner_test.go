@@ -0,0 +1,327 @@
+package pdftool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRunner runs the test binary as a helper process in place of the
+// programs in installed, recording the arguments each one was run with
+type fakeRunner struct {
+	installed []string
+	exitCode  int  // Exit code of the helper process
+	hang      bool // Whether the helper process sleeps until it is killed
+
+	mu    sync.Mutex
+	calls [][]string
+}
+
+func (r *fakeRunner) LookPath(file string) (string, error) {
+	if !slices.Contains(r.installed, file) {
+		return "", &exec.Error{Name: file, Err: exec.ErrNotFound}
+	}
+	return "/usr/bin/" + file, nil
+}
+
+func (r *fakeRunner) Command(ctx context.Context, name string, args ...string) *exec.Cmd {
+	r.mu.Lock()
+	r.calls = append(r.calls, append([]string{filepath.Base(name)}, args...))
+	r.mu.Unlock()
+
+	helperArgs := append([]string{"-test.run=TestHelperProcess", "--", filepath.Base(name)}, args...)
+	cmd := exec.CommandContext(ctx, os.Args[0], helperArgs...)
+	cmd.Env = append(os.Environ(),
+		"PDFTOOL_HELPER_PROCESS=1",
+		"PDFTOOL_HELPER_EXIT="+strconv.Itoa(r.exitCode),
+		"PDFTOOL_HELPER_HANG="+strconv.FormatBool(r.hang),
+	)
+	return cmd
+}
+
+// lastCall returns the arguments of the last run of a program
+func (r *fakeRunner) lastCall(program string) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := len(r.calls) - 1; i >= 0; i-- {
+		if r.calls[i][0] == program {
+			return r.calls[i][1:]
+		}
+	}
+	return nil
+}
+
+// installRunner sets r as the Runner until the test ends
+func installRunner(t *testing.T, r Runner) {
+	t.Helper()
+	if pureGoBuild {
+		t.Skip("external programs are never run in pure-Go builds")
+	}
+	SetRunner(r)
+	t.Cleanup(func() { SetRunner(nil) })
+}
+
+// TestHelperProcess stands in for the programs run by fakeRunner. It writes
+// a placeholder to the -sOutputFile= or -o output, if given, and exits with
+// the requested code.
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("PDFTOOL_HELPER_PROCESS") != "1" {
+		return
+	}
+	args := os.Args
+	for len(args) > 0 && args[0] != "--" {
+		args = args[1:]
+	}
+	program, args := args[1], args[2:]
+
+	// Answer Ghostscript's detection probes, which list no devices
+	if program == "gs" && slices.Contains(args, "--version") {
+		fmt.Println("10.02.1")
+		os.Exit(0)
+	}
+	if program == "gs" && (slices.Contains(args, "-h") || slices.Contains(args, "-dNODISPLAY")) {
+		os.Exit(0)
+	}
+	if os.Getenv("PDFTOOL_HELPER_HANG") == "true" {
+		time.Sleep(time.Minute)
+	}
+
+	// Only an explicit output file is written; positional arguments may be
+	// anything, such as the input
+	var output string
+	for i, arg := range args {
+		if out, ok := strings.CutPrefix(arg, "-sOutputFile="); ok {
+			output = out
+		}
+		if arg == "-o" && i+1 < len(args) {
+			output = args[i+1]
+		}
+	}
+	code, _ := strconv.Atoi(os.Getenv("PDFTOOL_HELPER_EXIT"))
+	if code == 0 || code == qpdfWarningsExitCode {
+		if output != "" {
+			os.WriteFile(output, []byte("%PDF-1.4\n"), 0644)
+		}
+	} else {
+		fmt.Fprintf(os.Stderr, "%s: simulated failure\n", program)
+	}
+	os.Exit(code)
+}
+
+// compressJob returns a job writing to a temporary directory
+func compressJob(t *testing.T, quality int) CompressJob {
+	dir := t.TempDir()
+	return CompressJob{
+		InputFile:  filepath.Join(dir, "in.pdf"),
+		OutputFile: filepath.Join(dir, "out.pdf"),
+		Quality:    quality,
+	}
+}
+
+func TestGhostscriptArguments(t *testing.T) {
+	r := &fakeRunner{installed: []string{"gs"}}
+	installRunner(t, r)
+
+	job := compressJob(t, 50)
+	job.ImageDPI = 96
+	if err := (ghostscriptEngine{}).Compress(context.Background(), job); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"-q",
+		"-dNOPAUSE",
+		"-dBATCH",
+		"-dSAFER",
+		"-sDEVICE=pdfwrite",
+		"-dCompatibilityLevel=1.4",
+		"-dPDFSETTINGS=/ebook",
+		"-dEmbedAllFonts=true",
+		"-dSubsetFonts=true",
+		"-dColorImageDownsampleType=/Bicubic",
+		"-dColorImageResolution=96",
+		"-dGrayImageDownsampleType=/Bicubic",
+		"-dGrayImageResolution=96",
+		"-dMonoImageDownsampleType=/Bicubic",
+		"-dMonoImageResolution=96",
+		"-sOutputFile=" + job.OutputFile,
+		job.InputFile,
+	}
+	if got := r.lastCall("gs"); !slices.Equal(got, want) {
+		t.Errorf("gs arguments:\ngot  %q\nwant %q", got, want)
+	}
+	if _, err := os.Stat(job.OutputFile); err != nil {
+		t.Errorf("no output written: %v", err)
+	}
+}
+
+func TestQpdfArguments(t *testing.T) {
+	tests := []struct {
+		quality int
+		want    []string
+	}{
+		{90, []string{"--compress-streams=y", "--recompress-flate", "--compression-level=9"}},
+		{50, []string{"--compress-streams=y", "--recompress-flate", "--compression-level=9", "--object-streams=generate"}},
+	}
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.quality), func(t *testing.T) {
+			r := &fakeRunner{installed: []string{"qpdf"}}
+			installRunner(t, r)
+
+			job := compressJob(t, tt.quality)
+			if err := (qpdfEngine{}).Compress(context.Background(), job); err != nil {
+				t.Fatal(err)
+			}
+			want := append(tt.want, job.InputFile, job.OutputFile)
+			if got := r.lastCall("qpdf"); !slices.Equal(got, want) {
+				t.Errorf("qpdf arguments:\ngot  %q\nwant %q", got, want)
+			}
+		})
+	}
+}
+
+func TestMutoolArguments(t *testing.T) {
+	tests := []struct {
+		quality int
+		want    []string
+	}{
+		{80, []string{"clean", "-gggz"}},
+		{50, []string{"clean", "-gggz", "-s"}},
+	}
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.quality), func(t *testing.T) {
+			r := &fakeRunner{installed: []string{"mutool"}}
+			installRunner(t, r)
+
+			job := compressJob(t, tt.quality)
+			if err := (mutoolEngine{}).Compress(context.Background(), job); err != nil {
+				t.Fatal(err)
+			}
+			want := append(tt.want, job.InputFile, job.OutputFile)
+			if got := r.lastCall("mutool"); !slices.Equal(got, want) {
+				t.Errorf("mutool arguments:\ngot  %q\nwant %q", got, want)
+			}
+		})
+	}
+}
+
+func TestQpdfWarningsAreNotFailures(t *testing.T) {
+	installRunner(t, &fakeRunner{installed: []string{"qpdf"}, exitCode: qpdfWarningsExitCode})
+
+	if err := (qpdfEngine{}).Compress(context.Background(), compressJob(t, 90)); err != nil {
+		t.Errorf("exit code %d should only warn, got %v", qpdfWarningsExitCode, err)
+	}
+}
+
+func TestFailingExit(t *testing.T) {
+	engines := []Engine{ghostscriptEngine{}, qpdfEngine{}, mutoolEngine{}}
+	for _, e := range engines {
+		t.Run(e.Name(), func(t *testing.T) {
+			installRunner(t, &fakeRunner{installed: []string{"gs", "qpdf", "mutool"}, exitCode: 2})
+
+			err := e.Compress(context.Background(), compressJob(t, 50))
+			var exitErr *exec.ExitError
+			if !errors.As(err, &exitErr) || exitErr.ExitCode() != 2 {
+				t.Fatalf("got %v, want an *exec.ExitError with exit code 2", err)
+			}
+			if errors.Is(err, ErrTimeout) {
+				t.Errorf("failed run reported as a timeout: %v", err)
+			}
+		})
+	}
+}
+
+func TestKilledOnDeadline(t *testing.T) {
+	engines := []Engine{ghostscriptEngine{}, qpdfEngine{}, mutoolEngine{}}
+	for _, e := range engines {
+		t.Run(e.Name(), func(t *testing.T) {
+			installRunner(t, &fakeRunner{installed: []string{"gs", "qpdf", "mutool"}, hang: true})
+			ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+			defer cancel()
+			if err := e.Compress(ctx, compressJob(t, 50)); !errors.Is(err, ErrTimeout) {
+				t.Errorf("got %v, want ErrTimeout", err)
+			}
+		})
+	}
+}
+
+func TestMissingGhostscript(t *testing.T) {
+	r := &fakeRunner{}
+	installRunner(t, r)
+
+	if _, err := DetectGhostscript(); !errors.Is(err, ErrGhostscriptNotFound) {
+		t.Errorf("DetectGhostscript: got %v, want ErrGhostscriptNotFound", err)
+	}
+	job := compressJob(t, 50)
+	_, err := CompressPDF(context.Background(), writeFile(t, job.InputFile), job.OutputFile, CompressOptions{
+		Engine:  EngineGhostscript,
+		Quality: 50,
+	})
+	if !errors.Is(err, ErrGhostscriptNotFound) {
+		t.Errorf("CompressPDF: got %v, want ErrGhostscriptNotFound", err)
+	}
+	if len(r.calls) > 0 {
+		t.Errorf("programs run without being installed: %q", r.calls)
+	}
+}
+
+func TestMissingBinaryAtRun(t *testing.T) {
+	installRunner(t, missingRunner{})
+
+	err := (qpdfEngine{}).Compress(context.Background(), compressJob(t, 50))
+	if !errors.Is(err, exec.ErrNotFound) {
+		t.Errorf("got %v, want exec.ErrNotFound", err)
+	}
+}
+
+func TestPureGoRunsNothing(t *testing.T) {
+	r := &fakeRunner{installed: []string{"gs", "qpdf", "mutool"}}
+	installRunner(t, r)
+	SetPureGo(true)
+	t.Cleanup(func() { SetPureGo(false) })
+
+	if _, err := DetectGhostscript(); !errors.Is(err, ErrPureGo) {
+		t.Errorf("DetectGhostscript: got %v, want ErrPureGo", err)
+	}
+	for _, e := range []Engine{qpdfEngine{}, mutoolEngine{}} {
+		if err := e.Compress(context.Background(), compressJob(t, 50)); !errors.Is(err, ErrPureGo) {
+			t.Errorf("%s: got %v, want ErrPureGo", e.Name(), err)
+		}
+	}
+	if len(r.calls) > 0 {
+		t.Errorf("programs run in pure-Go mode: %q", r.calls)
+	}
+}
+
+// missingRunner finds every program but fails to start it, as when it is
+// removed after the lookup
+type missingRunner struct{}
+
+func (missingRunner) LookPath(file string) (string, error) {
+	return "/usr/bin/" + file, nil
+}
+
+func (missingRunner) Command(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Err = &exec.Error{Name: name, Err: exec.ErrNotFound}
+	return cmd
+}
+
+// writeFile creates a placeholder input file and returns its path
+func writeFile(t *testing.T, path string) string {
+	t.Helper()
+	if err := os.WriteFile(path, []byte("%PDF-1.4\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
@@ -0,0 +1,257 @@
+package pdftool
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// SanitizeOptions controls what SanitizePDF keeps
+type SanitizeOptions struct {
+	KeepLinks bool // Keep links to web pages (URI actions)
+}
+
+// unsafeActions are the action types that run code, open or submit to other
+// files and sites, or play media
+var unsafeActions = map[string]bool{
+	"JavaScript":       true,
+	"Launch":           true,
+	"URI":              true,
+	"SubmitForm":       true,
+	"ImportData":       true,
+	"GoToR":            true,
+	"GoToE":            true,
+	"RichMediaExecute": true,
+	"Rendition":        true,
+	"Movie":            true,
+	"Sound":            true,
+}
+
+// unsafeAnnotations are the annotation types that embed files or active
+// content
+var unsafeAnnotations = map[string]bool{
+	"FileAttachment": true,
+	"RichMedia":      true,
+	"Screen":         true,
+	"Movie":          true,
+	"Sound":          true,
+	"3D":             true,
+}
+
+// sanitizer removes active content from a PDF and counts what it removed
+type sanitizer struct {
+	ctx         *model.Context
+	keepLinks   bool
+	scripts     int
+	actions     int
+	files       int
+	annotations int
+	references  int
+}
+
+// SanitizePDF removes content that can run code or reach outside the
+// document: JavaScript, XFA forms, automatic and launch, URI, submit and
+// remote go-to actions, embedded files, media annotations and references to
+// external files. Links within the document keep working.
+func SanitizePDF(ctx context.Context, inputFile, outputFile string, opts SanitizeOptions) error {
+	doc, err := readContext(ctx, inputFile)
+	if err != nil {
+		return err
+	}
+
+	s := &sanitizer{ctx: doc, keepLinks: opts.KeepLinks}
+
+	rootDict, err := doc.Catalog()
+	if err != nil {
+		return fmt.Errorf("failed to read catalog: %w", err)
+	}
+	// pdfcpu caches name trees and writes them back unless removed from both
+	for _, tree := range []string{"JavaScript", "EmbeddedFiles"} {
+		names, err := doc.NamesDict()
+		if err != nil || names == nil || names[tree] == nil {
+			continue
+		}
+		if err := doc.RemoveNameTree(tree); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", tree, err)
+		}
+		delete(doc.Names, tree)
+		if tree == "JavaScript" {
+			s.scripts++
+		} else {
+			s.files++
+		}
+	}
+	rootDict.Delete("Collection") // Portfolio view of the embedded files
+	if acroForm, err := doc.DereferenceDict(rootDict["AcroForm"]); err == nil && acroForm != nil && acroForm["XFA"] != nil {
+		acroForm.Delete("XFA") // XFA forms carry their own scripts
+		s.scripts++
+	}
+
+	for _, entry := range doc.Table {
+		if entry != nil && !entry.Free {
+			s.sanitize(entry.Object)
+		}
+	}
+
+	for page := 1; page <= doc.PageCount; page++ {
+		if err := contextError(ctx); err != nil {
+			return err
+		}
+		if err := s.sanitizeAnnotations(page); err != nil {
+			return fmt.Errorf("failed to sanitize page %d: %w", page, err)
+		}
+	}
+
+	if err := writeContextFile(ctx, doc, outputFile); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outputFile, err)
+	}
+
+	if s.scripts+s.actions+s.files+s.annotations+s.references == 0 {
+		logf("No active content found, wrote %s", outputFile)
+		return nil
+	}
+	logf("Removed %d scripts, %d actions, %d embedded files, %d annotations and %d external references, wrote %s",
+		s.scripts, s.actions, s.files, s.annotations, s.references, outputFile)
+	return nil
+}
+
+// sanitize removes unsafe entries from an object and the dictionaries and
+// arrays nested in it. Indirect objects are handled on their own.
+func (s *sanitizer) sanitize(obj types.Object) {
+	switch v := obj.(type) {
+	case types.Dict:
+		s.sanitizeDict(v)
+	case types.StreamDict:
+		if v.Dict["F"] != nil {
+			// The stream data is read from an external file
+			v.Dict.Delete("F")
+			v.Dict.Delete("FFilter")
+			v.Dict.Delete("FDecodeParms")
+			s.references++
+		}
+		s.sanitizeDict(v.Dict)
+	case types.Array:
+		for _, item := range v {
+			s.sanitize(item)
+		}
+	}
+}
+
+// sanitizeDict removes unsafe actions, associated files and external
+// references from a dictionary
+func (s *sanitizer) sanitizeDict(d types.Dict) {
+	// Additional actions run on events such as opening a page or typing
+	// into a field; none of them are needed to view the document
+	if aa, err := s.ctx.DereferenceDict(d["AA"]); err == nil && aa != nil {
+		for _, action := range aa {
+			if !s.unsafe(action) {
+				s.actions++ // Counted by unsafe otherwise
+			}
+		}
+		d.Delete("AA")
+	}
+
+	for _, key := range []string{"A", "OpenAction", "PA"} {
+		if s.unsafe(d[key]) {
+			d.Delete(key)
+		}
+	}
+
+	// Actions run after this one
+	switch next := d["Next"].(type) {
+	case types.Array:
+		var kept types.Array
+		for _, action := range next {
+			if !s.unsafe(action) {
+				kept = append(kept, action)
+			}
+		}
+		if len(kept) > 0 {
+			d["Next"] = kept
+		} else {
+			d.Delete("Next")
+		}
+	default:
+		if s.unsafe(next) {
+			d.Delete("Next")
+		}
+	}
+
+	if d["AF"] != nil {
+		d.Delete("AF") // Associated files
+		s.files++
+	}
+
+	// Reference XObjects import a page of another PDF
+	if subtype := d.Subtype(); subtype != nil && *subtype == "Form" && d["Ref"] != nil {
+		d.Delete("Ref")
+		s.references++
+	}
+
+	for _, v := range d {
+		s.sanitize(v)
+	}
+}
+
+// unsafe reports whether obj is an unsafe action and counts it
+func (s *sanitizer) unsafe(obj types.Object) bool {
+	action, err := s.ctx.DereferenceDict(obj)
+	if err != nil || action == nil {
+		return false
+	}
+	typ := action.NameEntry("S")
+	if typ == nil || !unsafeActions[*typ] || (s.keepLinks && *typ == "URI") {
+		return false
+	}
+
+	switch *typ {
+	case "JavaScript":
+		s.scripts++
+	case "GoToR", "GoToE", "Launch":
+		s.references++
+	default:
+		s.actions++
+	}
+	return true
+}
+
+// sanitizeAnnotations removes the annotations of a page that embed files or
+// active content, and links left without a target
+func (s *sanitizer) sanitizeAnnotations(page int) error {
+	pageDict, _, _, err := s.ctx.PageDict(page, false)
+	if err != nil || pageDict == nil {
+		return err
+	}
+
+	annots, err := s.ctx.DereferenceArray(pageDict["Annots"])
+	if err != nil || len(annots) == 0 {
+		return err
+	}
+
+	var kept types.Array
+	for _, obj := range annots {
+		annot, err := s.ctx.DereferenceDict(obj)
+		if err != nil || annot == nil {
+			continue
+		}
+
+		subtype := ""
+		if t := annot.Subtype(); t != nil {
+			subtype = *t
+		}
+		if unsafeAnnotations[subtype] || subtype == "Link" && annot["A"] == nil && annot["Dest"] == nil {
+			s.annotations++
+			continue
+		}
+		kept = append(kept, obj)
+	}
+
+	if len(kept) > 0 {
+		pageDict["Annots"] = kept
+	} else {
+		pageDict.Delete("Annots")
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package pdftool
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// ScaleOptions controls how pages are resized
+type ScaleOptions struct {
+	To    string  // Paper size such as A4 or Letter; pages keep their orientation
+	Scale float64 // Scale factor when To is empty, e.g. 0.5
+	Pages string  // Empty or "all" scales every page
+}
+
+// ScalePages resizes the selected pages and their content, either to a paper
+// size or by a factor. Content is scaled to fit and centered; landscape pages
+// become landscape pages of the target size.
+func ScalePages(ctx context.Context, inputFile, outputFile string, opts ScaleOptions) error {
+	res := &model.Resize{Unit: types.POINTS}
+	switch {
+	case opts.To != "" && opts.Scale != 0:
+		return fmt.Errorf("specify either a paper size or a scale factor")
+	case opts.To != "":
+		name, dim, ok := paperSize(opts.To)
+		if !ok {
+			return fmt.Errorf("unknown paper size: %s (e.g. A4, A5, Letter, Legal)", opts.To)
+		}
+		res.PageSize = name
+		res.PageDim = dim
+	case opts.Scale > 0:
+		res.Scale = opts.Scale
+	default:
+		return fmt.Errorf("specify a paper size or a positive scale factor")
+	}
+
+	doc, err := readContext(ctx, inputFile)
+	if err != nil {
+		return err
+	}
+
+	pages, err := stampSelection(doc.PageCount, opts.Pages)
+	if err != nil {
+		return err
+	}
+
+	if err := pdfcpu.Resize(doc, pageSet(pages), res); err != nil {
+		return fmt.Errorf("failed to scale pages: %w", err)
+	}
+
+	if err := writeContextFile(ctx, doc, outputFile); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outputFile, err)
+	}
+
+	logf("Scaled %d of %d pages, wrote %s", len(pages), doc.PageCount, outputFile)
+	return nil
+}
+
+// paperSize looks up a paper size by name, ignoring case
+func paperSize(name string) (string, *types.Dim, bool) {
+	for key, dim := range types.PaperSize {
+		if strings.EqualFold(key, name) {
+			d := *dim
+			return key, &d, true
+		}
+	}
+	return "", nil, false
+}
@@ -0,0 +1,440 @@
+package pdftool
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/font"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"golang.org/x/crypto/pkcs12"
+)
+
+// SignOptions controls how a PDF is signed
+type SignOptions struct {
+	PKCS12   string // File with the certificate, its chain and the private key
+	Password string // Password of the PKCS#12 file
+	Reason   string
+	Location string
+	Contact  string
+	Page     int    // Page of a visible signature; 0 signs invisibly
+	Rect     string // Area of a visible signature as "x0,y0,x1,y1"
+}
+
+// defaultSignatureRect is the area of a visible signature without --rect:
+// the bottom-left corner of the page
+const defaultSignatureRect = "36,36,252,96"
+
+// signatureReserve is the space reserved in the PDF for the signature on
+// top of the certificates it embeds
+const signatureReserve = 4096
+
+// byteRangePlaceholder marks the ByteRange of a signature until the final
+// offsets are known; the numbers are wide enough for any file size
+var byteRangePlaceholder = types.Array{types.Integer(0), types.Integer(9999999999), types.Integer(9999999999), types.Integer(9999999999)}
+
+// SignPDF signs a PDF with the certificate and private key of a PKCS#12 file,
+// producing a PAdES baseline (B-B) signature. The signature is appended as an
+// incremental update, so earlier signatures stay valid.
+func SignPDF(ctx context.Context, inputFile, outputFile string, opts SignOptions) error {
+	key, cert, chain, err := loadPKCS12(opts.PKCS12, opts.Password)
+	if err != nil {
+		return err
+	}
+	if now := time.Now(); now.After(cert.NotAfter) || now.Before(cert.NotBefore) {
+		warnf("The certificate is only valid from %s to %s", cert.NotBefore.Format("2006-01-02"), cert.NotAfter.Format("2006-01-02"))
+	}
+
+	page := opts.Page
+	if page == 0 && opts.Rect != "" {
+		page = 1
+	}
+	var rect []float64
+	if page > 0 {
+		spec := opts.Rect
+		if spec == "" {
+			spec = defaultSignatureRect
+		}
+		if rect, err = parseLengths(spec); err != nil {
+			return err
+		}
+		if len(rect) != 4 || rect[0] == rect[2] || rect[1] == rect[3] {
+			return fmt.Errorf("invalid signature area: %s (expected x0,y0,x1,y1)", spec)
+		}
+	}
+
+	if err := checkInputFile(inputFile); err != nil {
+		return err
+	}
+	original, err := os.ReadFile(inputFile)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", inputFile, err)
+	}
+
+	doc, err := await(ctx, func() (*model.Context, error) {
+		return api.ReadAndValidate(bytes.NewReader(original), newConfig())
+	})
+	if err != nil {
+		return readError(err)
+	}
+	if doc.Encrypt != nil {
+		return fmt.Errorf("cannot sign encrypted PDFs, decrypt %s first", inputFile)
+	}
+	if page > doc.PageCount {
+		return fmt.Errorf("page %d out of range (document has %d pages)", page, doc.PageCount)
+	}
+
+	// Write the changes as an increment after the unmodified original
+	doc.Write.Increment = true
+	doc.Write.Offset = int64(len(original))
+	doc.WriteObjectStream = false
+	doc.WriteXRefStream = doc.Read.UsingXRefStreams
+
+	var out bytes.Buffer
+	out.Write(original)
+	if !bytes.HasSuffix(original, []byte("\n")) && !bytes.HasSuffix(original, []byte("\r")) {
+		out.WriteString("\n")
+		doc.Write.Offset++
+	}
+
+	contentsSize := signatureReserve + len(cert.Raw)
+	for _, c := range chain {
+		contentsSize += len(c.Raw)
+	}
+
+	if err := addSignatureField(doc, cert, opts, page, rect, contentsSize); err != nil {
+		return fmt.Errorf("failed to add signature: %w", err)
+	}
+	if err := api.WriteIncrement(doc, &out); err != nil {
+		return fmt.Errorf("failed to write signature: %w", err)
+	}
+
+	signed := out.Bytes()
+	if err := embedSignature(signed, contentsSize, key, cert, chain); err != nil {
+		return err
+	}
+	if err := os.WriteFile(outputFile, signed, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outputFile, err)
+	}
+
+	logf("Signed %s as %s, wrote %s", inputFile, cert.Subject.CommonName, outputFile)
+	return nil
+}
+
+// loadPKCS12 reads the private key, certificate and CA chain of a PKCS#12
+// (.p12 or .pfx) file
+func loadPKCS12(file, password string) (crypto.Signer, *x509.Certificate, []*x509.Certificate, error) {
+	if file == "" {
+		return nil, nil, nil, fmt.Errorf("no PKCS#12 file given")
+	}
+	if err := checkInputFile(file); err != nil {
+		return nil, nil, nil, err
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+
+	blocks, err := pkcs12.ToPEM(data, password)
+	if errors.Is(err, pkcs12.ErrIncorrectPassword) {
+		return nil, nil, nil, fmt.Errorf("wrong password for %s", file)
+	}
+	if err != nil {
+		// OpenSSL 3 encrypts with AES by default, which the decoder lacks
+		return nil, nil, nil, fmt.Errorf("failed to read %s: %w (files from OpenSSL 3 may need to be exported with -legacy)", file, err)
+	}
+
+	var key crypto.Signer
+	var certs []*x509.Certificate
+	for _, block := range blocks {
+		switch block.Type {
+		case "CERTIFICATE":
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("invalid certificate in %s: %w", file, err)
+			}
+			certs = append(certs, cert)
+		case "PRIVATE KEY":
+			if k, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+				key = k
+			} else if k, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+				key = k
+			}
+		}
+	}
+	if key == nil {
+		return nil, nil, nil, fmt.Errorf("no supported private key in %s", file)
+	}
+
+	// The signing certificate is the one for the key; the rest is its chain
+	public, _ := key.Public().(interface{ Equal(crypto.PublicKey) bool })
+	for i, cert := range certs {
+		if public != nil && public.Equal(cert.PublicKey) {
+			chain := append(append([]*x509.Certificate{}, certs[:i]...), certs[i+1:]...)
+			return key, cert, chain, nil
+		}
+	}
+	return nil, nil, nil, fmt.Errorf("no certificate for the private key in %s", file)
+}
+
+// addSignatureField adds a signature field whose value is a signature
+// dictionary with placeholders for the byte range and the signature, and
+// marks all new and changed objects for the increment. Invisible signatures
+// get an empty widget on the first page.
+func addSignatureField(ctx *model.Context, cert *x509.Certificate, opts SignOptions, page int, rect []float64, contentsSize int) error {
+	signingTime := time.Now()
+	sigDict := types.Dict{
+		"Type":      types.Name("Sig"),
+		"Filter":    types.Name("Adobe.PPKLite"),
+		"SubFilter": types.Name("ETSI.CAdES.detached"),
+		"ByteRange": byteRangePlaceholder,
+		"Contents":  types.HexLiteral(strings.Repeat("0", 2*contentsSize)),
+		"M":         types.StringLiteral(types.DateString(signingTime)),
+	}
+	for key, value := range map[string]string{"Name": cert.Subject.CommonName, "Reason": opts.Reason, "Location": opts.Location, "ContactInfo": opts.Contact} {
+		if value == "" {
+			continue
+		}
+		s, err := pdfTextString(value)
+		if err != nil {
+			return err
+		}
+		sigDict[key] = s
+	}
+	sigRef, err := ctx.IndRefForNewObject(sigDict)
+	if err != nil {
+		return err
+	}
+	ctx.Write.IncrementWithObjNr(sigRef.ObjectNumber.Value())
+
+	visible := page > 0
+	if !visible {
+		page = 1
+		rect = []float64{0, 0, 0, 0}
+	}
+	pageRef, err := ctx.PageDictIndRef(page)
+	if err != nil {
+		return err
+	}
+	pageDict, err := ctx.DereferenceDict(*pageRef)
+	if err != nil {
+		return err
+	}
+
+	rootDict, err := ctx.Catalog()
+	if err != nil {
+		return err
+	}
+	form, formObjNr, err := acroForm(ctx, rootDict)
+	if err != nil {
+		return err
+	}
+	fields, err := ctx.DereferenceArray(form["Fields"])
+	if err != nil {
+		return err
+	}
+
+	field := types.Dict{
+		"FT":      types.Name("Sig"),
+		"T":       types.StringLiteral(signatureFieldName(ctx, fields)),
+		"V":       *sigRef,
+		"Type":    types.Name("Annot"),
+		"Subtype": types.Name("Widget"),
+		"Rect":    types.NewNumberArray(rect...),
+		"P":       *pageRef,
+		"F":       types.Integer(132), // Print, Locked
+	}
+	if visible {
+		ap, err := signatureAppearance(ctx, cert, opts, signingTime, rect)
+		if err != nil {
+			return err
+		}
+		field["AP"] = types.Dict{"N": *ap}
+	}
+	fieldRef, err := ctx.IndRefForNewObject(field)
+	if err != nil {
+		return err
+	}
+	ctx.Write.IncrementWithObjNr(fieldRef.ObjectNumber.Value())
+
+	annots, err := ctx.DereferenceArray(pageDict["Annots"])
+	if err != nil {
+		return err
+	}
+	pageDict["Annots"] = append(append(types.Array{}, annots...), *fieldRef)
+	ctx.Write.IncrementWithObjNr(pageRef.ObjectNumber.Value())
+
+	form["Fields"] = append(append(types.Array{}, fields...), *fieldRef)
+	form["SigFlags"] = types.Integer(3) // SignaturesExist, AppendOnly
+	ctx.Write.IncrementWithObjNr(formObjNr)
+	return nil
+}
+
+// acroForm returns the interactive form dictionary of a document, creating
+// it if needed, and the number of the object to rewrite when changing it
+func acroForm(ctx *model.Context, rootDict types.Dict) (types.Dict, int, error) {
+	if ref, ok := rootDict["AcroForm"].(types.IndirectRef); ok {
+		form, err := ctx.DereferenceDict(ref)
+		if err != nil {
+			return nil, 0, err
+		}
+		if form != nil {
+			return form, ref.ObjectNumber.Value(), nil
+		}
+	}
+
+	form, err := ctx.DereferenceDict(rootDict["AcroForm"])
+	if err != nil {
+		return nil, 0, err
+	}
+	if form == nil {
+		form = types.Dict{"Fields": types.Array{}}
+	}
+	rootDict["AcroForm"] = form
+	return form, ctx.Root.ObjectNumber.Value(), nil
+}
+
+// signatureFieldName returns the first name Signature1, Signature2, ... not
+// used by a top-level form field
+func signatureFieldName(ctx *model.Context, fields types.Array) string {
+	used := make(map[string]bool)
+	for _, obj := range fields {
+		field, err := ctx.DereferenceDict(obj)
+		if err != nil || field == nil {
+			continue
+		}
+		if name, err := ctx.DereferenceStringOrHexLiteral(field["T"], model.V10, nil); err == nil {
+			used[name] = true
+		}
+	}
+
+	for i := 1; ; i++ {
+		if name := fmt.Sprintf("Signature%d", i); !used[name] {
+			return name
+		}
+	}
+}
+
+// signatureAppearance creates the appearance stream of a visible signature:
+// a frame with the signer, date, reason and location in Helvetica
+func signatureAppearance(ctx *model.Context, cert *x509.Certificate, opts SignOptions, signingTime time.Time, rect []float64) (*types.IndirectRef, error) {
+	width, height := math.Abs(rect[2]-rect[0]), math.Abs(rect[3]-rect[1])
+
+	lines := []string{"Digitally signed by " + cert.Subject.CommonName, "Date: " + signingTime.Format("2006-01-02 15:04:05 -07:00")}
+	if opts.Reason != "" {
+		lines = append(lines, "Reason: "+opts.Reason)
+	}
+	if opts.Location != "" {
+		lines = append(lines, "Location: "+opts.Location)
+	}
+
+	// Largest size up to 10pt at which all lines fit
+	const padding = 4.0
+	size := math.Min(10, (height-2*padding)/(1.2*float64(len(lines))))
+	for _, line := range lines {
+		if w := font.TextWidth(line, "Helvetica", 1000) / 1000; w > 0 {
+			size = math.Min(size, (width-2*padding)/w)
+		}
+	}
+	size = math.Max(size, 1)
+
+	var content strings.Builder
+	fmt.Fprintf(&content, "q 0.5 w 0 G 0.25 0.25 %.2f %.2f re S Q\n", width-0.5, height-0.5)
+	fmt.Fprintf(&content, "BT /Helv %.2f Tf 0 g %.2f TL %.2f %.2f Td\n", size, 1.2*size, padding, height-padding-size)
+	for _, line := range lines {
+		escaped, err := types.Escape(winAnsiString(line))
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&content, "(%s) Tj T*\n", *escaped)
+	}
+	content.WriteString("ET\n")
+
+	fontRef, err := ctx.IndRefForNewObject(types.Dict{
+		"Type":     types.Name("Font"),
+		"Subtype":  types.Name("Type1"),
+		"BaseFont": types.Name("Helvetica"),
+		"Encoding": types.Name("WinAnsiEncoding"),
+	})
+	if err != nil {
+		return nil, err
+	}
+	ctx.Write.IncrementWithObjNr(fontRef.ObjectNumber.Value())
+
+	sd, err := ctx.NewStreamDictForBuf([]byte(content.String()))
+	if err != nil {
+		return nil, err
+	}
+	sd.InsertName("Type", "XObject")
+	sd.InsertName("Subtype", "Form")
+	sd.Insert("BBox", types.NewNumberArray(0, 0, width, height))
+	sd.Insert("Resources", types.Dict{"Font": types.Dict{"Helv": *fontRef}})
+	if err := sd.Encode(); err != nil {
+		return nil, err
+	}
+
+	ref, err := ctx.IndRefForNewObject(*sd)
+	if err != nil {
+		return nil, err
+	}
+	ctx.Write.IncrementWithObjNr(ref.ObjectNumber.Value())
+	return ref, nil
+}
+
+// winAnsiString converts text to WinAnsi bytes for a core font, replacing
+// characters outside of Latin-1 with a question mark
+func winAnsiString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r > 0xff || (r >= 0x80 && r < 0xa0) {
+			r = '?'
+		}
+		b.WriteByte(byte(r))
+	}
+	return b.String()
+}
+
+// embedSignature fills in the byte range of a written signature and the CMS
+// signature over it
+func embedSignature(pdf []byte, contentsSize int, key crypto.Signer, cert *x509.Certificate, chain []*x509.Certificate) error {
+	placeholder := []byte(byteRangePlaceholder.PDFString())
+	if bytes.Count(pdf, placeholder) != 1 {
+		return fmt.Errorf("failed to locate the signature byte range")
+	}
+	contents := []byte("<" + strings.Repeat("0", 2*contentsSize) + ">")
+	start := bytes.Index(pdf, contents)
+	if start < 0 {
+		return fmt.Errorf("failed to locate the signature contents")
+	}
+	end := start + len(contents)
+
+	// The signature covers everything but the hex string holding it
+	byteRange := fmt.Sprintf("[0 %d %d %d]", start, end, len(pdf)-end)
+	byteRange += strings.Repeat(" ", len(placeholder)-len(byteRange))
+	copy(pdf[bytes.Index(pdf, placeholder):], byteRange)
+
+	h := sha256.New()
+	h.Write(pdf[:start])
+	h.Write(pdf[end:])
+
+	signature, err := signCMS(h.Sum(nil), key, cert, chain)
+	if err != nil {
+		return fmt.Errorf("failed to create signature: %w", err)
+	}
+	if len(signature) > contentsSize {
+		return fmt.Errorf("signature too large: %d bytes, %d reserved", len(signature), contentsSize)
+	}
+	hex.Encode(pdf[start+1:], signature)
+	return nil
+}
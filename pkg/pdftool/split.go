@@ -0,0 +1,243 @@
+package pdftool
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+// SplitOptions selects how a PDF is split; without any, every page becomes
+// its own file
+type SplitOptions struct {
+	Pages   string // Comma-separated page ranges like "1-3,7,10-", one file each
+	Every   int    // Number of pages per file
+	MaxSize int64  // Largest size of a file in bytes; parts are sequential
+}
+
+// SplitPDF splits a PDF into several files in outputDir, named after the
+// input and their page range, and returns their paths
+func SplitPDF(ctx context.Context, inputFile, outputDir string, opts SplitOptions) ([]string, error) {
+	doc, spans, err := splitSpans(ctx, inputFile, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	outputFiles := make([]string, len(spans))
+	for i, pages := range spans {
+		outputFiles[i] = filepath.Join(outputDir, spanFileName(inputFile, pages))
+		if err := writePages(ctx, doc, pages, outputFiles[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return outputFiles, nil
+}
+
+// SplitPDFToZip splits a PDF like SplitPDF, but writes the parts straight
+// into a ZIP archive instead of a directory, and returns their entry names
+func SplitPDFToZip(ctx context.Context, inputFile, zipFile string, opts SplitOptions) ([]string, error) {
+	doc, spans, err := splitSpans(ctx, inputFile, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(spans))
+	err = writeZip(zipFile, func(zw *zip.Writer) error {
+		for i, pages := range spans {
+			if err := contextError(ctx); err != nil {
+				return err
+			}
+			names[i] = spanFileName(inputFile, pages)
+			part, err := pdfcpu.ExtractPages(doc, pages, false)
+			if err != nil {
+				return fmt.Errorf("failed to extract pages: %w", err)
+			}
+			w, err := createZipEntry(zw, names[i], true)
+			if err != nil {
+				return err
+			}
+			if err := api.WriteContext(part, w); err != nil {
+				return fmt.Errorf("failed to write %s: %w", names[i], err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return names, nil
+}
+
+// splitSpans reads a PDF and divides its pages into the parts selected by
+// the split options
+func splitSpans(ctx context.Context, inputFile string, opts SplitOptions) (*model.Context, [][]int, error) {
+	modes := 0
+	for _, set := range []bool{opts.Pages != "", opts.Every > 0, opts.MaxSize > 0} {
+		if set {
+			modes++
+		}
+	}
+	if modes > 1 {
+		return nil, nil, fmt.Errorf("only one of page ranges, page count or maximum size can be used")
+	}
+	if opts.Every < 0 || opts.MaxSize < 0 {
+		return nil, nil, fmt.Errorf("page count and maximum size must be positive")
+	}
+
+	doc, err := readContext(ctx, inputFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var spans [][]int
+	switch {
+	case opts.Pages != "":
+		spans, err = rangeSpans(doc.PageCount, opts.Pages)
+	case opts.MaxSize > 0:
+		spans, err = sizeSpans(ctx, doc, opts.MaxSize)
+	default:
+		spans = fixedSpans(doc.PageCount, max(opts.Every, 1))
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return doc, spans, nil
+}
+
+// rangeSpans resolves comma-separated page ranges into one page list each
+func rangeSpans(pageCount int, ranges string) ([][]int, error) {
+	var spans [][]int
+	for _, part := range strings.Split(ranges, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		pages, err := pagesForSelection(pageCount, part)
+		if err != nil {
+			return nil, err
+		}
+		spans = append(spans, pages)
+	}
+
+	if len(spans) == 0 {
+		return nil, fmt.Errorf("no page ranges given")
+	}
+	return spans, nil
+}
+
+// fixedSpans divides pages into consecutive groups of size pages
+func fixedSpans(pageCount, size int) [][]int {
+	var spans [][]int
+	for from := 1; from <= pageCount; from += size {
+		spans = append(spans, pageRange(from, min(from+size-1, pageCount)))
+	}
+	return spans
+}
+
+// sizeSpans divides pages into consecutive groups whose PDF stays within
+// maxSize bytes, binary-searching the last page of each group. A page that
+// exceeds the limit on its own becomes a group by itself.
+func sizeSpans(ctx context.Context, doc *model.Context, maxSize int64) ([][]int, error) {
+	var spans [][]int
+	for from := 1; from <= doc.PageCount; {
+		if err := contextError(ctx); err != nil {
+			return nil, err
+		}
+		// Find the largest thru whose part fits; from alone always counts
+		lo, hi := from, doc.PageCount
+		for lo < hi {
+			mid := (lo + hi + 1) / 2
+			size, err := pagesSize(doc, pageRange(from, mid))
+			if err != nil {
+				return nil, err
+			}
+			if size <= maxSize {
+				lo = mid
+			} else {
+				hi = mid - 1
+			}
+		}
+
+		if lo == from {
+			size, err := pagesSize(doc, []int{from})
+			if err != nil {
+				return nil, err
+			}
+			if size > maxSize {
+				warnf("Page %d alone is %s, over the size limit", from, FormatSize(size))
+			}
+		}
+
+		spans = append(spans, pageRange(from, lo))
+		from = lo + 1
+	}
+	return spans, nil
+}
+
+// pagesSize returns the size in bytes of a PDF holding the given pages
+func pagesSize(ctx *model.Context, pages []int) (int64, error) {
+	part, err := pdfcpu.ExtractPages(ctx, pages, false)
+	if err != nil {
+		return 0, fmt.Errorf("failed to extract pages: %w", err)
+	}
+
+	var counter byteCounter
+	if err := api.WriteContext(part, &counter); err != nil {
+		return 0, fmt.Errorf("failed to measure pages: %w", err)
+	}
+	return int64(counter), nil
+}
+
+// byteCounter is a writer that only counts the bytes written to it
+type byteCounter int64
+
+func (c *byteCounter) Write(p []byte) (int, error) {
+	*c += byteCounter(len(p))
+	return len(p), nil
+}
+
+// pageRange returns the page numbers from through thru
+func pageRange(from, thru int) []int {
+	pages := make([]int, 0, thru-from+1)
+	for page := from; page <= thru; page++ {
+		pages = append(pages, page)
+	}
+	return pages
+}
+
+// spanFileName names a part after the input file and its first and last page
+func spanFileName(inputFile string, pages []int) string {
+	base := strings.TrimSuffix(filepath.Base(inputFile), filepath.Ext(inputFile))
+	from, thru := pages[0], pages[len(pages)-1]
+	if from == thru {
+		return base + "_" + strconv.Itoa(from) + ".pdf"
+	}
+	return fmt.Sprintf("%s_%d-%d.pdf", base, from, thru)
+}
+
+// writePages writes the given pages of a document to a new PDF file
+func writePages(ctx context.Context, doc *model.Context, pages []int, outputFile string) error {
+	part, err := pdfcpu.ExtractPages(doc, pages, false)
+	if err != nil {
+		return fmt.Errorf("failed to extract pages: %w", err)
+	}
+
+	if err := writeContextFile(ctx, part, outputFile); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outputFile, err)
+	}
+	return nil
+}
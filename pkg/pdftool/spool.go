@@ -0,0 +1,54 @@
+package pdftool
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// withSpooledFiles runs an operation that only works on files for a reader
+// and writer: r is copied to an input file in a new temporary directory, fn
+// writes an output file next to it, and the output is copied to w. The
+// directory is removed afterwards.
+func withSpooledFiles(r io.Reader, w io.Writer, tmpPrefix string, fn func(inputFile, outputFile string) error) error {
+	tmpDir, removeTmpDir, err := TempDir("", tmpPrefix)
+	if err != nil {
+		return fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer removeTmpDir()
+
+	inputFile := filepath.Join(tmpDir, "input.pdf")
+	outputFile := filepath.Join(tmpDir, "output.pdf")
+	if err := writeFileFrom(inputFile, r); err != nil {
+		return fmt.Errorf("failed to spool input: %w", err)
+	}
+
+	if err := fn(inputFile, outputFile); err != nil {
+		return err
+	}
+
+	out, err := os.Open(outputFile)
+	if err != nil {
+		return fmt.Errorf("failed to read output: %w", err)
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(w, out); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	return nil
+}
+
+// writeFileFrom creates a file with the contents of r
+func writeFileFrom(file string, r io.Reader) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,60 @@
+package pdftool
+
+import (
+	"os"
+	"sync"
+)
+
+// tempDirs holds the temporary directories made by TempDir, so that
+// RemoveTempFiles can delete them when the process is interrupted and the
+// deferred cleanup of the operations never runs
+var tempDirs = struct {
+	sync.Mutex
+	dirs map[string]struct{}
+}{dirs: map[string]struct{}{}}
+
+// TempDir creates a directory with a unique name starting with prefix in
+// parent, or in os.TempDir() if parent is empty. The returned function
+// removes the directory and everything in it; it is safe to call more than
+// once. Until then RemoveTempFiles deletes it too, so programs use it for
+// their own temporary files as well.
+func TempDir(parent, prefix string) (string, func(), error) {
+	dir, err := os.MkdirTemp(parent, prefix)
+	if err != nil {
+		return "", nil, err
+	}
+
+	tempDirs.Lock()
+	tempDirs.dirs[dir] = struct{}{}
+	tempDirs.Unlock()
+
+	remove := func() {
+		tempDirs.Lock()
+		delete(tempDirs.dirs, dir)
+		tempDirs.Unlock()
+		os.RemoveAll(dir)
+	}
+	return dir, remove, nil
+}
+
+// RemoveTempFiles deletes the temporary files of all running operations.
+// Programs call it before exiting on a signal, since deferred cleanup does
+// not run then; the interrupted operations may fail afterwards.
+func RemoveTempFiles() {
+	tempDirs.Lock()
+	defer tempDirs.Unlock()
+
+	for dir := range tempDirs.dirs {
+		os.RemoveAll(dir)
+		delete(tempDirs.dirs, dir)
+	}
+}
+
+// KeepTempDir stops RemoveTempFiles from deleting a directory made by
+// TempDir, e.g. once it holds a queued job that outlives the process
+func KeepTempDir(dir string) {
+	tempDirs.Lock()
+	defer tempDirs.Unlock()
+
+	delete(tempDirs.dirs, dir)
+}
@@ -0,0 +1,571 @@
+package pdftool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// ExtractTextOptions holds settings for extracting the text of a PDF
+type ExtractTextOptions struct {
+	Pages   string // Page selection like "1-10"; empty for all pages
+	Layout  bool   // Keep the horizontal and vertical placement of the text
+	PerPage bool   // Write one file per page into the output directory
+}
+
+// ExtractText writes the text of a PDF to output and returns the files
+// written. Pages are separated by form feeds; with PerPage, output is a
+// directory that receives one file per page. Scanned pages have no text;
+// run ocr on them first.
+func ExtractText(ctx context.Context, inputFile, output string, opts ExtractTextOptions) ([]string, error) {
+	doc, err := readContext(ctx, inputFile)
+	if err != nil {
+		return nil, err
+	}
+
+	pages, err := pagesForSelection(doc.PageCount, opts.Pages)
+	if err != nil {
+		return nil, err
+	}
+
+	texts := make([]string, len(pages))
+	empty := 0
+	for i, page := range pages {
+		if err := contextError(ctx); err != nil {
+			return nil, err
+		}
+		if texts[i], err = pageText(doc, page, opts.Layout); err != nil {
+			return nil, fmt.Errorf("failed to extract text from page %d: %w", page, err)
+		}
+		if strings.TrimSpace(texts[i]) == "" {
+			empty++
+		}
+	}
+
+	var outputFiles []string
+	if opts.PerPage {
+		if err := os.MkdirAll(output, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create output directory: %w", err)
+		}
+
+		base := strings.TrimSuffix(filepath.Base(inputFile), filepath.Ext(inputFile))
+		digits := len(strconv.Itoa(doc.PageCount))
+		for i, page := range pages {
+			outputFile := filepath.Join(output, fmt.Sprintf("%s-p%0*d.txt", base, digits, page))
+			if err := os.WriteFile(outputFile, []byte(texts[i]), 0644); err != nil {
+				return outputFiles, fmt.Errorf("failed to write text file: %w", err)
+			}
+			outputFiles = append(outputFiles, outputFile)
+		}
+	} else {
+		if err := os.WriteFile(output, []byte(strings.Join(texts, "\f")), 0644); err != nil {
+			return nil, fmt.Errorf("failed to write text file: %w", err)
+		}
+		outputFiles = append(outputFiles, output)
+	}
+
+	if empty > 0 {
+		warnf("%d of %d pages have no text; scanned pages need ocr first", empty, len(pages))
+	}
+	logf("Extracted text from %d pages of %s", len(pages), inputFile)
+	return outputFiles, nil
+}
+
+// pageText returns the text of one page, one line per text line
+func pageText(ctx *model.Context, page int, layout bool) (string, error) {
+	chars, err := pageChars(ctx, page)
+	if err != nil {
+		return "", err
+	}
+
+	lines := textLines(chars)
+	if layout {
+		return layoutText(lines), nil
+	}
+	return plainText(lines), nil
+}
+
+// pageChars returns the characters shown on a page in content stream order
+func pageChars(ctx *model.Context, page int) ([]textChar, error) {
+	pageDict, _, inherited, err := ctx.PageDict(page, false)
+	if err != nil {
+		return nil, err
+	}
+	if pageDict == nil {
+		return nil, nil
+	}
+
+	content, err := ctx.PageContent(pageDict, page)
+	if errors.Is(err, model.ErrNoContent) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var resources types.Dict
+	if inherited != nil {
+		resources = inherited.Resources
+	}
+
+	r := &textReader{ctx: ctx, fonts: make(map[string]*glyphDecoder)}
+	r.state = textState{ctm: identity, scale: 1}
+	r.run(content, resources, 0)
+	return r.chars, nil
+}
+
+// matrix is a PDF transformation matrix [a b c d e f]
+type matrix [6]float64
+
+var identity = matrix{1, 0, 0, 1, 0, 0}
+
+// multiply returns m × n
+func (m matrix) multiply(n matrix) matrix {
+	return matrix{
+		m[0]*n[0] + m[1]*n[2],
+		m[0]*n[1] + m[1]*n[3],
+		m[2]*n[0] + m[3]*n[2],
+		m[2]*n[1] + m[3]*n[3],
+		m[4]*n[0] + m[5]*n[2] + n[4],
+		m[4]*n[1] + m[5]*n[3] + n[5],
+	}
+}
+
+// textState is the part of the graphics state used for text positioning
+type textState struct {
+	ctm        matrix
+	font       *glyphDecoder
+	size       float64
+	charSpace  float64
+	wordSpace  float64
+	scale      float64 // Horizontal scaling, 1 is 100%
+	leading    float64
+	rise       float64
+	textMatrix matrix
+	lineMatrix matrix
+}
+
+// textChar is a character placed on the page
+type textChar struct {
+	text   string
+	x0, x1 float64
+	y      float64
+	size   float64
+	box    pageRect // Approximate glyph outline bounds
+}
+
+// textReader interprets content streams and collects the characters shown
+type textReader struct {
+	ctx   *model.Context
+	fonts map[string]*glyphDecoder
+	state textState
+	stack []textState
+	chars []textChar
+}
+
+// maxFormDepth limits the nesting of form XObjects followed
+const maxFormDepth = 5
+
+// run interprets a content stream with the given resources
+func (r *textReader) run(content []byte, resources types.Dict, depth int) {
+	lexer := contentLexer{data: content}
+	var operands []any
+
+	for {
+		value, op, ok := lexer.next()
+		if !ok {
+			return
+		}
+		if op == "" {
+			operands = append(operands, value)
+			continue
+		}
+
+		r.apply(op, operands, resources, depth)
+		operands = operands[:0]
+	}
+}
+
+// apply executes one content stream operator
+func (r *textReader) apply(op string, operands []any, resources types.Dict, depth int) {
+	s := &r.state
+	nums := numberOperands(operands)
+
+	switch op {
+	case "q":
+		r.stack = append(r.stack, r.state)
+	case "Q":
+		if n := len(r.stack); n > 0 {
+			r.state = r.stack[n-1]
+			r.stack = r.stack[:n-1]
+		}
+	case "cm":
+		if len(nums) == 6 {
+			s.ctm = matrix(nums).multiply(s.ctm)
+		}
+	case "BT":
+		s.textMatrix, s.lineMatrix = identity, identity
+	case "Tf":
+		if len(operands) == 2 {
+			if name, ok := operands[0].(pdfName); ok {
+				s.font = r.font(resources, string(name))
+			}
+			if size, ok := operands[1].(float64); ok {
+				s.size = size
+			}
+		}
+	case "Tc":
+		if len(nums) == 1 {
+			s.charSpace = nums[0]
+		}
+	case "Tw":
+		if len(nums) == 1 {
+			s.wordSpace = nums[0]
+		}
+	case "Tz":
+		if len(nums) == 1 {
+			s.scale = nums[0] / 100
+		}
+	case "TL":
+		if len(nums) == 1 {
+			s.leading = nums[0]
+		}
+	case "Ts":
+		if len(nums) == 1 {
+			s.rise = nums[0]
+		}
+	case "Td":
+		if len(nums) == 2 {
+			r.moveLine(nums[0], nums[1])
+		}
+	case "TD":
+		if len(nums) == 2 {
+			s.leading = -nums[1]
+			r.moveLine(nums[0], nums[1])
+		}
+	case "Tm":
+		if len(nums) == 6 {
+			s.textMatrix, s.lineMatrix = matrix(nums), matrix(nums)
+		}
+	case "T*":
+		r.moveLine(0, -s.leading)
+	case "Tj":
+		if len(operands) == 1 {
+			r.show(operands[0])
+		}
+	case "'":
+		r.moveLine(0, -s.leading)
+		if len(operands) == 1 {
+			r.show(operands[0])
+		}
+	case "\"":
+		if len(operands) == 3 {
+			s.wordSpace, _ = operands[0].(float64)
+			s.charSpace, _ = operands[1].(float64)
+			r.moveLine(0, -s.leading)
+			r.show(operands[2])
+		}
+	case "TJ":
+		if len(operands) == 1 {
+			if items, ok := operands[0].([]any); ok {
+				for _, item := range items {
+					if n, ok := item.(float64); ok {
+						r.advance(-n / 1000 * s.size * s.scale)
+					} else {
+						r.show(item)
+					}
+				}
+			}
+		}
+	case "Do":
+		if len(operands) == 1 && depth < maxFormDepth {
+			if name, ok := operands[0].(pdfName); ok {
+				r.form(resources, string(name), depth)
+			}
+		}
+	}
+}
+
+// numberOperands returns the operands if they are all numbers
+func numberOperands(operands []any) []float64 {
+	nums := make([]float64, 0, len(operands))
+	for _, operand := range operands {
+		n, ok := operand.(float64)
+		if !ok {
+			return nil
+		}
+		nums = append(nums, n)
+	}
+	return nums
+}
+
+// moveLine starts a new line offset from the start of the current one
+func (r *textReader) moveLine(tx, ty float64) {
+	s := &r.state
+	s.lineMatrix = matrix{1, 0, 0, 1, tx, ty}.multiply(s.lineMatrix)
+	s.textMatrix = s.lineMatrix
+}
+
+// advance moves the text position horizontally by tx in text space
+func (r *textReader) advance(tx float64) {
+	s := &r.state
+	s.textMatrix = matrix{1, 0, 0, 1, tx, 0}.multiply(s.textMatrix)
+}
+
+// show records the characters of a shown string and advances past them
+func (r *textReader) show(operand any) {
+	str, ok := operand.(string)
+	s := &r.state
+	if !ok || s.font == nil {
+		return
+	}
+
+	for _, g := range s.font.decode(str) {
+		trm, tx := r.place(g)
+		width := g.width / 1000 * s.size * s.scale
+		end := matrix{1, 0, 0, 1, width, 0}.multiply(s.textMatrix).multiply(s.ctm)
+		r.advance(tx)
+
+		if g.text == "" {
+			continue
+		}
+		r.chars = append(r.chars, textChar{
+			text: g.text,
+			x0:   trm[4],
+			x1:   end[4],
+			y:    trm[5],
+			size: math.Hypot(trm[2], trm[3]),
+			box:  glyphBox(trm, g.width),
+		})
+	}
+}
+
+// place returns the text rendering matrix of a glyph shown at the current
+// position and the distance to the next glyph in text space
+func (r *textReader) place(g glyph) (matrix, float64) {
+	s := &r.state
+	trm := matrix{s.size * s.scale, 0, 0, s.size, 0, s.rise}.multiply(s.textMatrix).multiply(s.ctm)
+
+	tx := g.width/1000*s.size + s.charSpace
+	if s.font.codeBytes == 1 && g.code == 32 {
+		tx += s.wordSpace
+	}
+	return trm, tx * s.scale
+}
+
+// Extent of glyphs above and below the baseline as a fraction of the font size
+const (
+	glyphAscent  = 0.9
+	glyphDescent = 0.25
+)
+
+// glyphBox returns the bounds on the page of a glyph placed with the text
+// rendering matrix trm, from its width and typical ascent and descent
+func glyphBox(trm matrix, width float64) pageRect {
+	w := width / 1000
+	return boundingRect(trm, [][2]float64{{0, -glyphDescent}, {w, -glyphDescent}, {0, glyphAscent}, {w, glyphAscent}})
+}
+
+// font returns the font with the given resource name, loading it on first use
+func (r *textReader) font(resources types.Dict, name string) *glyphDecoder {
+	fonts, err := r.ctx.DereferenceDict(resources["Font"])
+	if err != nil || fonts == nil {
+		return nil
+	}
+
+	key := name
+	if ref, ok := fonts[name].(types.IndirectRef); ok {
+		key = ref.String()
+	} else {
+		key = fmt.Sprintf("%p/%s", fonts, name)
+	}
+	if f, ok := r.fonts[key]; ok {
+		return f
+	}
+
+	d, err := r.ctx.DereferenceDict(fonts[name])
+	if err != nil || d == nil {
+		return nil
+	}
+	f := loadGlyphDecoder(r.ctx, d)
+	r.fonts[key] = f
+	return f
+}
+
+// form interprets a form XObject drawn with Do
+func (r *textReader) form(resources types.Dict, name string, depth int) {
+	xobjects, err := r.ctx.DereferenceDict(resources["XObject"])
+	if err != nil || xobjects == nil {
+		return
+	}
+
+	sd, _, err := r.ctx.DereferenceStreamDict(xobjects[name])
+	if err != nil || sd == nil {
+		return
+	}
+	if subtype := sd.Subtype(); subtype == nil || *subtype != "Form" {
+		return
+	}
+	if err := sd.Decode(); err != nil {
+		return
+	}
+
+	formResources := resources
+	if d, err := r.ctx.DereferenceDict(sd.Dict["Resources"]); err == nil && d != nil {
+		formResources = d
+	}
+
+	saved, savedStack := r.state, r.stack
+	if m, err := r.ctx.DereferenceArray(sd.Dict["Matrix"]); err == nil && len(m) == 6 {
+		var fm matrix
+		for i, v := range m {
+			fm[i], _ = numberValue(v)
+		}
+		r.state.ctm = fm.multiply(r.state.ctm)
+	}
+	r.stack = nil
+	r.run(sd.Content, formResources, depth+1)
+	r.state, r.stack = saved, savedStack
+}
+
+// textLine is a row of characters sharing a baseline, ordered left to right
+type textLine struct {
+	y     float64
+	size  float64
+	chars []textChar
+}
+
+// textLines groups characters into lines from the top of the page down
+func textLines(chars []textChar) []textLine {
+	sorted := make([]textChar, len(chars))
+	copy(sorted, chars)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].y > sorted[j].y })
+
+	var lines []textLine
+	for _, c := range sorted {
+		if c.size <= 0 {
+			c.size = 1
+		}
+		if n := len(lines); n > 0 && math.Abs(lines[n-1].y-c.y) < 0.5*math.Max(lines[n-1].size, c.size) {
+			lines[n-1].chars = append(lines[n-1].chars, c)
+			lines[n-1].size = math.Max(lines[n-1].size, c.size)
+			continue
+		}
+		lines = append(lines, textLine{y: c.y, size: c.size, chars: []textChar{c}})
+	}
+
+	for i := range lines {
+		sort.SliceStable(lines[i].chars, func(a, b int) bool { return lines[i].chars[a].x0 < lines[i].chars[b].x0 })
+	}
+	return lines
+}
+
+// lineText joins the characters of a line, inserting spaces at word gaps.
+// Characters drawn twice at the same spot to fake bold text appear once.
+func lineText(chars []textChar) string {
+	var b strings.Builder
+	var prev *textChar
+
+	for i := range chars {
+		c := &chars[i]
+		if prev != nil {
+			if c.text == prev.text && math.Abs(c.x0-prev.x0) < 0.1*c.size {
+				continue
+			}
+			if c.x0-prev.x1 > 0.2*c.size && c.text != " " && prev.text != " " {
+				b.WriteByte(' ')
+			}
+		}
+		b.WriteString(c.text)
+		prev = c
+	}
+	return strings.TrimRight(b.String(), " ")
+}
+
+// plainText returns the lines as running text, with a blank line where the
+// vertical gap suggests a new paragraph
+func plainText(lines []textLine) string {
+	var b strings.Builder
+	for i, line := range lines {
+		if i > 0 && lines[i-1].y-line.y > 1.8*line.size {
+			b.WriteByte('\n')
+		}
+		b.WriteString(lineText(line.chars))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// layoutText returns the lines with words placed in columns by their
+// horizontal position and blank lines standing in for vertical gaps
+func layoutText(lines []textLine) string {
+	var widths []float64
+	left := math.Inf(1)
+	for _, line := range lines {
+		for _, c := range line.chars {
+			if c.text != " " && c.x1 > c.x0 {
+				widths = append(widths, (c.x1-c.x0)/float64(len([]rune(c.text))))
+			}
+			left = math.Min(left, c.x0)
+		}
+	}
+	if len(widths) == 0 {
+		return plainText(lines)
+	}
+	charWidth := median(widths)
+
+	var b strings.Builder
+	for i, line := range lines {
+		if i > 0 {
+			blank := int(math.Round((lines[i-1].y-line.y)/(1.2*line.size))) - 1
+			for j := 0; j < blank && j < 3; j++ {
+				b.WriteByte('\n')
+			}
+		}
+
+		var row []rune
+		var prev *textChar
+		for k := range line.chars {
+			c := &line.chars[k]
+			if prev != nil && c.text == prev.text && math.Abs(c.x0-prev.x0) < 0.1*c.size {
+				continue
+			}
+
+			// Words start at the column matching their position; the letters
+			// of a word follow each other whatever their width
+			if prev == nil || c.x0-prev.x1 > 0.2*c.size {
+				column := int(math.Round((c.x0 - left) / charWidth))
+				if prev != nil && len(row) >= column && c.text != " " && prev.text != " " {
+					column = len(row) + 1
+				}
+				for len(row) < column {
+					row = append(row, ' ')
+				}
+			}
+			row = append(row, []rune(c.text)...)
+			prev = c
+		}
+		b.WriteString(strings.TrimRight(string(row), " "))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// median returns the middle value of values, or 0 for none
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	return sorted[len(sorted)/2]
+}
@@ -0,0 +1,78 @@
+package pdftool
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/disintegration/imaging"
+)
+
+// ThumbnailOptions holds settings for rendering a page thumbnail
+type ThumbnailOptions struct {
+	Page    int // Page to render, starting at 1
+	Width   int // Thumbnail width in pixels; the height follows the page
+	Quality int // JPEG quality (1-100)
+}
+
+// thumbnailOversampling renders pages at a multiple of the thumbnail size
+// before scaling down, which gives smoother text than rendering directly
+const thumbnailOversampling = 2
+
+// CreateThumbnail renders a page of a PDF as a PNG or JPEG thumbnail of the
+// given width, e.g. a cover image for galleries or document management
+// systems. The format follows the output file's extension.
+func CreateThumbnail(ctx context.Context, inputFile, outputFile string, opts ThumbnailOptions) error {
+	ext := strings.ToLower(filepath.Ext(outputFile))
+	if ext != ".png" && ext != ".jpg" && ext != ".jpeg" {
+		return fmt.Errorf("unsupported output format: %s (supported: .png, .jpg, .jpeg)", ext)
+	}
+	if opts.Width <= 0 {
+		return fmt.Errorf("width must be positive, got: %d", opts.Width)
+	}
+	if err := checkInputFile(inputFile); err != nil {
+		return err
+	}
+
+	doc, err := readContext(ctx, inputFile)
+	if err != nil {
+		return err
+	}
+	if opts.Page < 1 || opts.Page > doc.PageCount {
+		return fmt.Errorf("page %d out of range (document has %d pages)", opts.Page, doc.PageCount)
+	}
+
+	dims, err := doc.PageDims()
+	if err != nil {
+		return fmt.Errorf("failed to read page sizes: %w", err)
+	}
+	dpi := int(math.Ceil(float64(opts.Width*thumbnailOversampling) * 72 / dims[opts.Page-1].Width))
+
+	tmpDir, removeTmpDir, err := TempDir("", "pdf-tool-thumbnail-")
+	if err != nil {
+		return fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer removeTmpDir()
+
+	rendered, err := RasterizePDF(ctx, inputFile, tmpDir, RasterizeOptions{Format: "png", DPI: max(dpi, 1), Pages: strconv.Itoa(opts.Page)})
+	if err != nil {
+		return err
+	}
+
+	img, err := decodeImage(ctx, diskFS{}, rendered[0])
+	if err != nil {
+		return err
+	}
+	thumb := imaging.Resize(img, opts.Width, 0, imaging.Lanczos)
+
+	if err := writeImageFile(outputFile, thumb, ext, opts.Quality); err != nil {
+		return err
+	}
+
+	size := thumb.Bounds().Size()
+	logf("Created %dx%d thumbnail of page %d, wrote %s", size.X, size.Y, opts.Page, outputFile)
+	return nil
+}
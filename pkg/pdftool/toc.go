@@ -0,0 +1,262 @@
+package pdftool
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// DefaultTOCTitle is the heading of a generated table of contents
+const DefaultTOCTitle = "Contents"
+
+// Table of contents layout, in points
+const (
+	tocMargin      = 72
+	tocTitleSize   = 20
+	tocFontSize    = 11
+	tocLineHeight  = 20
+	tocIndent      = 18
+	tocNumberWidth = 36 // Space reserved for page numbers
+)
+
+// TOCOptions holds settings for generating a table of contents
+type TOCOptions struct {
+	Title    string // Heading of the table of contents page
+	MaxDepth int    // Deepest bookmark level listed, starting at 1; 0 lists all
+}
+
+// tocEntry is a bookmark listed in the table of contents
+type tocEntry struct {
+	title string
+	level int
+	page  int
+}
+
+// tocLink is the area of a table of contents line that links to a page
+type tocLink struct {
+	tocPage int
+	rect    types.Array
+	page    int
+}
+
+// AddTableOfContents prepends table of contents pages, listing the bookmarks
+// of a PDF with their page numbers, to the document. Each line links to its
+// page. Page numbers count the table of contents pages as well.
+func AddTableOfContents(ctx context.Context, inputFile, outputFile string, opts TOCOptions) error {
+	if opts.Title == "" {
+		opts.Title = DefaultTOCTitle
+	}
+	if opts.MaxDepth < 0 {
+		return fmt.Errorf("depth cannot be negative, got: %d", opts.MaxDepth)
+	}
+
+	doc, err := readContext(ctx, inputFile)
+	if err != nil {
+		return err
+	}
+
+	tree, err := pdfcpu.ExportBookmarks(doc, filepath.Base(inputFile))
+	if err != nil {
+		return fmt.Errorf("failed to read bookmarks: %w", err)
+	}
+	if tree == nil {
+		return fmt.Errorf("no bookmarks found in %s (add them with bookmarks import or merge --bookmark-per-file)", inputFile)
+	}
+	entries := tocEntries(tree.Bookmarks, 1, opts.MaxDepth)
+
+	dims, err := doc.PageDims()
+	if err != nil {
+		return fmt.Errorf("failed to read page sizes: %w", err)
+	}
+
+	// The page numbers shown depend on the length of the table of contents,
+	// so lay it out once to count its pages
+	_, _, tocPages, err := renderTOC(entries, opts.Title, dims[0].Width, dims[0].Height, 0)
+	if err != nil {
+		return err
+	}
+	data, links, tocPages, err := renderTOC(entries, opts.Title, dims[0].Width, dims[0].Height, tocPages)
+	if err != nil {
+		return err
+	}
+
+	// Look the target pages up before the page tree changes
+	pageRefs := make(map[int]types.IndirectRef)
+	for _, link := range links {
+		_, ref, _, err := doc.PageDict(link.page, false)
+		if err != nil || ref == nil {
+			return fmt.Errorf("failed to find page %d: %w", link.page, err)
+		}
+		pageRefs[link.page] = *ref
+	}
+
+	if err := prependPages(doc, data); err != nil {
+		return err
+	}
+
+	for _, link := range links {
+		if err := addPageLink(doc, link.tocPage, link.rect, pageRefs[link.page]); err != nil {
+			return err
+		}
+	}
+
+	if err := writeContextFile(ctx, doc, outputFile); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outputFile, err)
+	}
+
+	logf("Added %d-page table of contents with %d entries, wrote %s", tocPages, len(entries), outputFile)
+	return nil
+}
+
+// tocEntries flattens bookmarks in outline order, down to maxDepth levels
+func tocEntries(bookmarks []pdfcpu.Bookmark, level, maxDepth int) []tocEntry {
+	var entries []tocEntry
+	for _, bm := range bookmarks {
+		entries = append(entries, tocEntry{title: bm.Title, level: level, page: bm.PageFrom})
+		if maxDepth == 0 || level < maxDepth {
+			entries = append(entries, tocEntries(bm.Kids, level+1, maxDepth)...)
+		}
+	}
+	return entries
+}
+
+// renderTOC lays out table of contents pages of the given size, adding
+// offset to the page numbers shown, and returns the PDF, the link area of
+// each entry and the number of pages
+func renderTOC(entries []tocEntry, title string, pageWidth, pageHeight float64, offset int) ([]byte, []tocLink, int, error) {
+	pdf := fpdf.NewCustom(&fpdf.InitType{UnitStr: "pt", Size: fpdf.SizeType{Wd: pageWidth, Ht: pageHeight}})
+	pdf.SetAutoPageBreak(false, 0) // Pages are laid out explicitly
+	pdf.SetMargins(tocMargin, tocMargin, tocMargin)
+	pdf.AddPage()
+
+	useTextFont(pdf, tocTitleSize)
+	pdf.SetXY(tocMargin, tocMargin)
+	pdf.CellFormat(pageWidth-2*tocMargin, tocTitleSize*1.5, title, "", 0, "L", false, 0, "")
+	y := tocMargin + tocTitleSize*1.5 + tocLineHeight
+
+	useTextFont(pdf, tocFontSize)
+	right := pageWidth - tocMargin
+	var links []tocLink
+	for _, entry := range entries {
+		if y+tocLineHeight > pageHeight-tocMargin {
+			pdf.AddPage()
+			y = tocMargin
+		}
+
+		x := tocMargin + float64(min(entry.level-1, 6))*tocIndent
+		number := strconv.Itoa(entry.page + offset)
+		text := fitText(pdf, entry.title, right-tocNumberWidth-x)
+		textWidth := pdf.GetStringWidth(text)
+
+		pdf.SetXY(x, y)
+		pdf.CellFormat(textWidth, tocLineHeight, text, "", 0, "L", false, 0, "")
+
+		// Dot leaders between the title and the right-aligned page number
+		numberWidth := pdf.GetStringWidth(number)
+		if dotWidth := pdf.GetStringWidth(" ."); dotWidth > 0 {
+			gap := right - numberWidth - (x + textWidth) - pdf.GetStringWidth(" ")
+			if dots := int(gap / dotWidth); dots > 0 {
+				leader := strings.Repeat(" .", dots)
+				pdf.SetXY(right-numberWidth-pdf.GetStringWidth(leader+" "), y)
+				pdf.CellFormat(pdf.GetStringWidth(leader), tocLineHeight, leader, "", 0, "L", false, 0, "")
+			}
+		}
+		pdf.SetXY(right-numberWidth, y)
+		pdf.CellFormat(numberWidth, tocLineHeight, number, "", 0, "R", false, 0, "")
+
+		// PDF coordinates start at the bottom left
+		links = append(links, tocLink{
+			tocPage: pdf.PageNo(),
+			rect:    types.NewNumberArray(x, pageHeight-y-tocLineHeight, right, pageHeight-y),
+			page:    entry.page,
+		})
+		y += tocLineHeight
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, nil, 0, fmt.Errorf("failed to generate table of contents: %w", err)
+	}
+	return buf.Bytes(), links, pdf.PageCount(), nil
+}
+
+// fitText shortens text with an ellipsis until it fits the width in the
+// current font
+func fitText(pdf *fpdf.Fpdf, text string, width float64) string {
+	if pdf.GetStringWidth(text) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && pdf.GetStringWidth(string(runes)+"…") > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
+
+// prependPages inserts the pages of a PDF before the first page of a
+// document. Outlines and links keep pointing to the same pages.
+func prependPages(ctx *model.Context, data []byte) error {
+	ctxPages, err := api.ReadAndValidate(bytes.NewReader(data), newConfig())
+	if err != nil {
+		return fmt.Errorf("failed to read generated pages: %w", err)
+	}
+
+	// Merging appends the pages, with both page trees below a new root
+	ctx.Configuration.CreateBookmarks = false
+	if err := pdfcpu.MergeXRefTables("", ctxPages, ctx, false, false); err != nil {
+		return fmt.Errorf("failed to insert pages: %w", err)
+	}
+
+	pagesRef, err := ctx.Pages()
+	if err != nil {
+		return err
+	}
+	pages, err := ctx.DereferenceDict(*pagesRef)
+	if err != nil {
+		return err
+	}
+	kids, err := ctx.DereferenceArray(pages["Kids"])
+	if err != nil || len(kids) != 2 {
+		return fmt.Errorf("unexpected page tree after inserting pages")
+	}
+	pages["Kids"] = types.Array{kids[1], kids[0]}
+	return nil
+}
+
+// addPageLink adds a link annotation covering rect on a page that goes to
+// the page referenced by target
+func addPageLink(ctx *model.Context, page int, rect types.Array, target types.IndirectRef) error {
+	pageDict, _, _, err := ctx.PageDict(page, false)
+	if err != nil || pageDict == nil {
+		return fmt.Errorf("failed to read page %d: %w", page, err)
+	}
+
+	link := types.Dict{
+		"Type":    types.Name("Annot"),
+		"Subtype": types.Name("Link"),
+		"Rect":    rect,
+		"Border":  types.NewIntegerArray(0, 0, 0),
+		"F":       types.Integer(annotationPrintFlag),
+		"Dest":    types.Array{target, types.Name("XYZ"), nil, nil, nil},
+	}
+	ref, err := ctx.IndRefForNewObject(link)
+	if err != nil {
+		return err
+	}
+
+	annots, err := ctx.DereferenceArray(pageDict["Annots"])
+	if err != nil {
+		return err
+	}
+	pageDict["Annots"] = append(annots, *ref)
+	return nil
+}
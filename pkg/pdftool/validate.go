@@ -0,0 +1,86 @@
+package pdftool
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+// Validation modes
+const (
+	ValidationStrict  = "strict"
+	ValidationRelaxed = "relaxed"
+)
+
+// ValidationResult is the outcome of checking the structure of a PDF
+type ValidationResult struct {
+	File      string `json:"file"`
+	Mode      string `json:"mode"`
+	Valid     bool   `json:"valid"`
+	Version   string `json:"version,omitempty"`
+	PageCount int    `json:"pageCount,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// ValidatePDF checks a PDF against the PDF specification with pdfcpu. Strict
+// mode reports every violation; relaxed mode tolerates the minor ones common
+// in real-world files, as the other commands do. A PDF that fails validation
+// is reported in the result, not as an error. The password is only needed
+// for encrypted PDFs.
+func ValidatePDF(ctx context.Context, inputFile, mode, password string) (*ValidationResult, error) {
+	conf := newConfig()
+	switch strings.ToLower(mode) {
+	case ValidationStrict:
+		conf.ValidationMode = model.ValidationStrict
+	case ValidationRelaxed:
+	default:
+		return nil, fmt.Errorf("invalid validation mode: %s (expected strict or relaxed)", mode)
+	}
+	conf.Cmd = model.VALIDATE
+	conf.UserPW = password
+	conf.OwnerPW = password
+
+	if err := checkInputFile(inputFile); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(inputFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PDF: %w", err)
+	}
+
+	result := &ValidationResult{File: inputFile, Mode: strings.ToLower(mode)}
+
+	doc, err := await(ctx, func() (*model.Context, error) {
+		return api.ReadContext(bytes.NewReader(data), conf)
+	})
+	if errors.Is(err, pdfcpu.ErrWrongPassword) {
+		return nil, fmt.Errorf("wrong password for %s", inputFile)
+	}
+	if err == nil {
+		result.Version = doc.VersionString()
+		err = awaitErr(ctx, func() error {
+			return api.ValidateContext(doc)
+		})
+		if err != nil && contextError(ctx) == nil {
+			err = fmt.Errorf("object %d: %w", doc.CurObj, err)
+		}
+		result.PageCount = doc.PageCount
+	}
+	if err := contextError(ctx); err != nil {
+		return nil, err
+	}
+	if err != nil {
+		result.Error = strings.TrimSpace(err.Error())
+		return result, nil
+	}
+
+	result.Valid = true
+	return result, nil
+}
@@ -0,0 +1,166 @@
+package pdftool
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// SignatureInfo describes a digital signature of a PDF and the result of
+// validating it
+type SignatureInfo struct {
+	Field          string     `json:"field"`
+	Type           string     `json:"type"` // form, page, usage rights or timestamp
+	Signer         string     `json:"signer"`
+	Issuer         string     `json:"issuer,omitempty"`
+	SigningTime    *time.Time `json:"signingTime,omitempty"`
+	Reason         string     `json:"reason,omitempty"`
+	Location       string     `json:"location,omitempty"`
+	Format         string     `json:"format"`              // SubFilter, e.g. ETSI.CAdES.detached
+	Status         string     `json:"status"`              // valid, invalid or unknown
+	StatusReason   string     `json:"statusReason"`        // Why the status is not valid
+	Intact         bool       `json:"intact"`              // The signed bytes are unchanged
+	CoversDocument bool       `json:"coversDocument"`      // Nothing was appended after signing
+	BytesAfter     int64      `json:"bytesAfterSigning"`   // Size of later incremental updates
+	Page           int        `json:"page,omitempty"`      // Page of a visible signature
+	Certified      bool       `json:"certified,omitempty"` // Certification (DocMDP) signature
+	Problems       []string   `json:"problems,omitempty"`
+}
+
+// signatureTypes names pdfcpu's signature types
+var signatureTypes = map[int]string{
+	model.SigTypeForm: "form",
+	model.SigTypePage: "page",
+	model.SigTypeUR:   "usage rights",
+	model.SigTypeDTS:  "timestamp",
+}
+
+// VerifySignatures validates the digital signatures of a PDF and returns
+// them in the order they were applied. A signature is valid when the signed
+// bytes are unchanged and the signer's certificate chains to a trusted root;
+// signatures by unknown or self-signed certificates have the status unknown.
+func VerifySignatures(ctx context.Context, inputFile string) ([]SignatureInfo, error) {
+	if err := checkInputFile(inputFile); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(inputFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PDF: %w", err)
+	}
+	fileSize := int64(len(data))
+
+	conf := newConfig()
+	conf.Cmd = model.VALIDATESIGNATURE
+
+	doc, err := await(ctx, func() (*model.Context, error) {
+		return api.ReadValidateAndOptimize(bytes.NewReader(data), conf)
+	})
+	if err != nil {
+		return nil, readError(err)
+	}
+	if len(doc.Signatures) == 0 && !doc.SignatureExist && !doc.AppendOnly {
+		return []SignatureInfo{}, nil
+	}
+
+	if _, err := api.LoadCertificates(); err != nil {
+		return nil, fmt.Errorf("failed to load trusted certificates: %w", err)
+	}
+	results, err := pdfcpu.ValidateSignatures(bytes.NewReader(data), doc, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate signatures: %s", strings.TrimSpace(err.Error()))
+	}
+
+	// pdfcpu reports the latest signature first
+	signedSizes := make(map[*model.SignatureValidationResult]int64, len(results))
+	for _, result := range results {
+		signedSizes[result] = signatureCoverage(doc, result.ObjNr)
+	}
+	sort.SliceStable(results, func(i, j int) bool { return signedSizes[results[i]] < signedSizes[results[j]] })
+
+	signatures := make([]SignatureInfo, 0, len(results))
+	for _, result := range results {
+		signatures = append(signatures, signatureInfo(result, signedSizes[result], fileSize))
+	}
+	return signatures, nil
+}
+
+// signatureInfo converts a pdfcpu validation result
+func signatureInfo(result *model.SignatureValidationResult, signedSize, fileSize int64) SignatureInfo {
+	details := result.Details
+	info := SignatureInfo{
+		Field:          details.FieldName,
+		Type:           signatureTypes[result.Type],
+		Signer:         details.SignerName,
+		Reason:         details.Reason,
+		Location:       details.Location,
+		Format:         details.SubFilter,
+		Status:         "unknown",
+		StatusReason:   result.Reason.String(),
+		Intact:         result.DocModified == model.False,
+		CoversDocument: signedSize == fileSize,
+		Certified:      result.Signature.Certified,
+		Problems:       result.Problems,
+	}
+	if signedSize > 0 && signedSize < fileSize {
+		info.BytesAfter = fileSize - signedSize
+	}
+	if result.Visible {
+		info.Page = result.PageNr
+	}
+	if !details.SigningTime.IsZero() {
+		t := details.SigningTime
+		info.SigningTime = &t
+	}
+
+	switch result.Status {
+	case model.SignatureStatusValid:
+		info.Status = "valid"
+		info.StatusReason = ""
+	case model.SignatureStatusInvalid:
+		info.Status = "invalid"
+	}
+
+	for _, signer := range details.Signers {
+		if signer.Certificate != nil && signer.Certificate.Leaf {
+			if info.Signer == "" {
+				info.Signer = signer.Certificate.Subject
+			}
+			info.Issuer = signer.Certificate.Issuer
+		}
+		info.Problems = append(info.Problems, signer.Problems...)
+	}
+	return info
+}
+
+// signatureCoverage returns the end of the byte range signed by the
+// signature in a signature field, or 0 if it cannot be determined
+func signatureCoverage(ctx *model.Context, fieldObjNr int) int64 {
+	field, err := ctx.DereferenceDict(*types.NewIndirectRef(fieldObjNr, 0))
+	if err != nil || field == nil {
+		return 0
+	}
+
+	// Usage rights signatures are referenced directly
+	sigDict := field
+	if v, err := ctx.DereferenceDict(field["V"]); err == nil && v != nil {
+		sigDict = v
+	}
+
+	byteRange, err := ctx.DereferenceArray(sigDict["ByteRange"])
+	if err != nil || len(byteRange) != 4 {
+		return 0
+	}
+	start, _ := numberValue(byteRange[2])
+	length, _ := numberValue(byteRange[3])
+	return int64(start + length)
+}
@@ -0,0 +1,249 @@
+package pdftool
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image/color"
+	"image/png"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// WatermarkOptions controls how a text watermark is stamped onto pages
+type WatermarkOptions struct {
+	Text     string
+	Font     string  // Core PDF font, e.g. Helvetica or Times-Bold
+	FontSize int     // Points; 0 scales the text to the page width
+	Scale    float64 // Width relative to the page when FontSize is 0
+	Color    string  // #rrggbb
+	Opacity  float64
+	Rotation float64 // Counter-clockwise degrees, -180 to 180
+	Position string  // See stampAnchors
+	Pages    string  // Empty or "all" stamps every page
+	Behind   bool    // Place the text under the page content
+}
+
+// stampAnchors maps position names to pdfcpu anchor positions
+var stampAnchors = map[string]string{
+	"top-left":      "tl",
+	"top-center":    "tc",
+	"top-right":     "tr",
+	"left":          "l",
+	"center":        "c",
+	"right":         "r",
+	"bottom-left":   "bl",
+	"bottom-center": "bc",
+	"bottom-right":  "br",
+}
+
+// stampAnchor returns the pdfcpu anchor for a position name such as
+// "bottom-right"
+func stampAnchor(position string) (string, error) {
+	anchor, ok := stampAnchors[strings.ToLower(position)]
+	if !ok {
+		return "", fmt.Errorf("invalid position: %s (expected top-left, top-center, top-right, left, center, right, bottom-left, bottom-center or bottom-right)", position)
+	}
+	return anchor, nil
+}
+
+// stampOffset returns the pdfcpu offset that keeps a stamp at an anchor the
+// given margin in points away from the page edges
+func stampOffset(anchor string, margin float64) string {
+	var dx, dy float64
+	if strings.HasSuffix(anchor, "l") {
+		dx = margin
+	} else if strings.HasSuffix(anchor, "r") {
+		dx = -margin
+	}
+	if strings.HasPrefix(anchor, "b") {
+		dy = margin
+	} else if strings.HasPrefix(anchor, "t") {
+		dy = -margin
+	}
+	return fmt.Sprintf("%g %g", dx, dy)
+}
+
+// pdfcpuColor formats a #rrggbb or #rgb color for a pdfcpu description
+func pdfcpuColor(s string) (string, error) {
+	c, err := ParseHexColor(s)
+	if err != nil {
+		return "", err
+	}
+	rgba := c.(color.RGBA)
+	return fmt.Sprintf("#%02X%02X%02X", rgba.R, rgba.G, rgba.B), nil
+}
+
+// WatermarkPDF stamps a line of text across the selected pages of a PDF
+func WatermarkPDF(ctx context.Context, inputFile, outputFile string, opts WatermarkOptions) error {
+	if strings.TrimSpace(opts.Text) == "" {
+		return fmt.Errorf("watermark text cannot be empty")
+	}
+	if opts.Opacity <= 0 || opts.Opacity > 1 {
+		return fmt.Errorf("invalid opacity: %g (must be between 0 and 1)", opts.Opacity)
+	}
+	if opts.Rotation < -180 || opts.Rotation > 180 {
+		return fmt.Errorf("invalid rotation: %g (must be between -180 and 180)", opts.Rotation)
+	}
+
+	anchor, err := stampAnchor(opts.Position)
+	if err != nil {
+		return err
+	}
+
+	fill, err := pdfcpuColor(opts.Color)
+	if err != nil {
+		return err
+	}
+
+	desc := []string{
+		"fontname:" + opts.Font,
+		"fillcolor:" + fill,
+		fmt.Sprintf("opacity:%g", opts.Opacity),
+		fmt.Sprintf("rotation:%g", opts.Rotation),
+		"position:" + anchor,
+	}
+	if opts.FontSize > 0 {
+		desc = append(desc, fmt.Sprintf("points:%d", opts.FontSize), "scalefactor:1 abs")
+	} else {
+		if opts.Scale <= 0 || opts.Scale > 1 {
+			return fmt.Errorf("invalid scale: %g (must be between 0 and 1)", opts.Scale)
+		}
+		desc = append(desc, fmt.Sprintf("scalefactor:%g rel", opts.Scale))
+	}
+
+	wm, err := api.TextWatermark(opts.Text, strings.Join(desc, ", "), !opts.Behind, false, types.POINTS)
+	if err != nil {
+		return fmt.Errorf("invalid watermark: %s", strings.TrimSpace(err.Error()))
+	}
+
+	stamped, total, err := stampPages(ctx, inputFile, outputFile, opts.Pages, wm)
+	if err != nil {
+		return err
+	}
+
+	logf("Watermarked %d of %d pages, wrote %s", stamped, total, outputFile)
+	return nil
+}
+
+// StampOptions controls how an image such as a logo is stamped onto pages
+type StampOptions struct {
+	Scale    float64 // Width relative to the page
+	Opacity  float64
+	Rotation float64 // Counter-clockwise degrees, -180 to 180
+	Position string  // See stampAnchors
+	Margin   float64 // Distance from the page edges in points
+	Pages    string  // Empty or "all" stamps every page
+	Behind   bool    // Place the image under the page content
+}
+
+// StampImage stamps a PNG or JPEG image onto the selected pages of a PDF.
+// PNG transparency is kept.
+func StampImage(ctx context.Context, inputFile, imageFile, outputFile string, opts StampOptions) error {
+	if err := checkImageFile(diskFS{}, imageFile); err != nil {
+		return err
+	}
+	if opts.Scale <= 0 || opts.Scale > 1 {
+		return fmt.Errorf("invalid scale: %g (must be between 0 and 1)", opts.Scale)
+	}
+	if opts.Opacity <= 0 || opts.Opacity > 1 {
+		return fmt.Errorf("invalid opacity: %g (must be between 0 and 1)", opts.Opacity)
+	}
+	if opts.Rotation < -180 || opts.Rotation > 180 {
+		return fmt.Errorf("invalid rotation: %g (must be between -180 and 180)", opts.Rotation)
+	}
+
+	anchor, err := stampAnchor(opts.Position)
+	if err != nil {
+		return err
+	}
+
+	// Decode and re-encode so that CMYK, EXIF-rotated and other JPEG variants
+	// end up as an upright image pdfcpu can embed
+	img, err := decodeImage(ctx, diskFS{}, imageFile)
+	if err != nil {
+		return err
+	}
+	img = applyOrientation(img, exifOrientation(diskFS{}, imageFile))
+
+	var encoded bytes.Buffer
+	if err := png.Encode(&encoded, img); err != nil {
+		return fmt.Errorf("failed to encode stamp image: %w", err)
+	}
+
+	desc := strings.Join([]string{
+		fmt.Sprintf("scalefactor:%g rel", opts.Scale),
+		fmt.Sprintf("opacity:%g", opts.Opacity),
+		fmt.Sprintf("rotation:%g", opts.Rotation),
+		"position:" + anchor,
+		"offset:" + stampOffset(anchor, opts.Margin),
+	}, ", ")
+
+	wm, err := api.ImageWatermarkForReader(&encoded, desc, !opts.Behind, false, types.POINTS)
+	if err != nil {
+		return fmt.Errorf("invalid stamp: %s", strings.TrimSpace(err.Error()))
+	}
+
+	stamped, total, err := stampPages(ctx, inputFile, outputFile, opts.Pages, wm)
+	if err != nil {
+		return err
+	}
+
+	logf("Stamped %s onto %d of %d pages, wrote %s", imageFile, stamped, total, outputFile)
+	return nil
+}
+
+// stampPages applies a pdfcpu watermark or stamp to the selected pages and
+// writes the result, returning the number of stamped pages and the page count
+func stampPages(ctx context.Context, inputFile, outputFile, selection string, wm *model.Watermark) (int, int, error) {
+	doc, err := readContext(ctx, inputFile)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	pages, err := stampSelection(doc.PageCount, selection)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	err = awaitErr(ctx, func() error {
+		return pdfcpu.AddWatermarks(doc, pageSet(pages), wm)
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to stamp pages: %w", err)
+	}
+
+	if err := writeContextFile(ctx, doc, outputFile); err != nil {
+		return 0, 0, fmt.Errorf("failed to write %s: %w", outputFile, err)
+	}
+
+	return len(pages), doc.PageCount, nil
+}
+
+// stampSelection returns the pages to stamp; an empty selection or "all"
+// selects every page
+func stampSelection(pageCount int, selection string) ([]int, error) {
+	if strings.EqualFold(selection, "all") {
+		selection = ""
+	}
+	return pagesForSelection(pageCount, selection)
+}
+
+// addStamps applies the stamps listed for each page in m and writes the result
+func addStamps(ctx context.Context, doc *model.Context, m map[int][]*model.Watermark, outputFile string) error {
+	err := awaitErr(ctx, func() error {
+		return pdfcpu.AddWatermarksSliceMap(doc, m)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to stamp pages: %w", err)
+	}
+
+	if err := writeContextFile(ctx, doc, outputFile); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outputFile, err)
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package pdftool
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// xmpInfo holds the document properties written to an XMP metadata packet.
+// For PDF/A they must match the document information dictionary.
+type xmpInfo struct {
+	Title      string
+	Author     string
+	Subject    string
+	Keywords   string
+	Creator    string
+	Producer   string
+	CreateDate time.Time
+	ModifyDate time.Time
+
+	// PDF/A identification; omitted when PDFAPart is 0
+	PDFAPart        int
+	PDFAConformance string
+}
+
+// XMP packet wrapper, which lets applications find and update the packet
+// without parsing the PDF
+const (
+	xmpPacketHeader  = "<?xpacket begin=\"\xEF\xBB\xBF\" id=\"W5M0MpCehiHzreSzNTczkc9d\"?>\n"
+	xmpPacketTrailer = "<?xpacket end=\"w\"?>"
+)
+
+// XMP namespaces of the properties mirrored in the document information
+// dictionary
+const (
+	xmpNamespaceRDF = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	xmpNamespaceDC  = "http://purl.org/dc/elements/1.1/"
+	xmpNamespaceXMP = "http://ns.adobe.com/xap/1.0/"
+	xmpNamespacePDF = "http://ns.adobe.com/pdf/1.3/"
+)
+
+// buildXMP renders an XMP metadata packet
+func buildXMP(info xmpInfo) []byte {
+	var b bytes.Buffer
+
+	b.WriteString(xmpPacketHeader)
+	b.WriteString("<x:xmpmeta xmlns:x=\"adobe:ns:meta/\">\n")
+	b.WriteString(" <rdf:RDF xmlns:rdf=\"http://www.w3.org/1999/02/22-rdf-syntax-ns#\">\n")
+	b.WriteString("  <rdf:Description rdf:about=\"\"\n")
+	b.WriteString("    xmlns:dc=\"http://purl.org/dc/elements/1.1/\"\n")
+	b.WriteString("    xmlns:xmp=\"http://ns.adobe.com/xap/1.0/\"\n")
+	b.WriteString("    xmlns:pdf=\"http://ns.adobe.com/pdf/1.3/\"\n")
+	b.WriteString("    xmlns:pdfaid=\"http://www.aiim.org/pdfa/ns/id/\">\n")
+	b.WriteString("   <dc:format>application/pdf</dc:format>\n")
+
+	if info.Title != "" {
+		fmt.Fprintf(&b, "   <dc:title><rdf:Alt><rdf:li xml:lang=\"x-default\">%s</rdf:li></rdf:Alt></dc:title>\n", xmlEscape(info.Title))
+	}
+	if info.Author != "" {
+		fmt.Fprintf(&b, "   <dc:creator><rdf:Seq><rdf:li>%s</rdf:li></rdf:Seq></dc:creator>\n", xmlEscape(info.Author))
+	}
+	if info.Subject != "" {
+		fmt.Fprintf(&b, "   <dc:description><rdf:Alt><rdf:li xml:lang=\"x-default\">%s</rdf:li></rdf:Alt></dc:description>\n", xmlEscape(info.Subject))
+	}
+	if info.Keywords != "" {
+		fmt.Fprintf(&b, "   <pdf:Keywords>%s</pdf:Keywords>\n", xmlEscape(info.Keywords))
+	}
+	if info.Producer != "" {
+		fmt.Fprintf(&b, "   <pdf:Producer>%s</pdf:Producer>\n", xmlEscape(info.Producer))
+	}
+	if info.Creator != "" {
+		fmt.Fprintf(&b, "   <xmp:CreatorTool>%s</xmp:CreatorTool>\n", xmlEscape(info.Creator))
+	}
+	if !info.CreateDate.IsZero() {
+		fmt.Fprintf(&b, "   <xmp:CreateDate>%s</xmp:CreateDate>\n", info.CreateDate.Format(time.RFC3339))
+	}
+	if !info.ModifyDate.IsZero() {
+		fmt.Fprintf(&b, "   <xmp:ModifyDate>%s</xmp:ModifyDate>\n", info.ModifyDate.Format(time.RFC3339))
+	}
+	if info.PDFAPart > 0 {
+		fmt.Fprintf(&b, "   <pdfaid:part>%d</pdfaid:part>\n", info.PDFAPart)
+		fmt.Fprintf(&b, "   <pdfaid:conformance>%s</pdfaid:conformance>\n", strings.ToUpper(info.PDFAConformance))
+	}
+
+	b.WriteString("  </rdf:Description>\n")
+	b.WriteString(" </rdf:RDF>\n")
+	b.WriteString("</x:xmpmeta>\n")
+	b.WriteString(xmpPacketTrailer)
+
+	return b.Bytes()
+}
+
+// parseXMP reads the document properties of an XMP packet, given either as
+// elements or as attributes of rdf:Description. Of language alternatives
+// the first is used; several creators are joined with commas.
+func parseXMP(data []byte) (xmpInfo, error) {
+	var info xmpInfo
+	set := func(name xml.Name, values []string) {
+		if len(values) == 0 {
+			return
+		}
+		first := values[0]
+		switch name {
+		case xml.Name{Space: xmpNamespaceDC, Local: "title"}:
+			info.Title = first
+		case xml.Name{Space: xmpNamespaceDC, Local: "creator"}:
+			info.Author = strings.Join(values, ", ")
+		case xml.Name{Space: xmpNamespaceDC, Local: "description"}:
+			info.Subject = first
+		case xml.Name{Space: xmpNamespacePDF, Local: "Keywords"}:
+			info.Keywords = first
+		case xml.Name{Space: xmpNamespaceDC, Local: "subject"}:
+			if info.Keywords == "" {
+				info.Keywords = strings.Join(values, ", ")
+			}
+		case xml.Name{Space: xmpNamespacePDF, Local: "Producer"}:
+			info.Producer = first
+		case xml.Name{Space: xmpNamespaceXMP, Local: "CreatorTool"}:
+			info.Creator = first
+		case xml.Name{Space: xmpNamespaceXMP, Local: "CreateDate"}:
+			info.CreateDate, _ = parseMetadataDate(first)
+		case xml.Name{Space: xmpNamespaceXMP, Local: "ModifyDate"}:
+			info.ModifyDate, _ = parseMetadataDate(first)
+		}
+	}
+
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var stack []xml.Name
+	var property xml.Name // Property element being read, a child of rdf:Description
+	var items []string    // rdf:li values of the property
+	var text strings.Builder
+	foundRDF := false
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return info, fmt.Errorf("invalid XMP: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			parent := xml.Name{}
+			if len(stack) > 0 {
+				parent = stack[len(stack)-1]
+			}
+			stack = append(stack, t.Name)
+
+			switch {
+			case t.Name == xml.Name{Space: xmpNamespaceRDF, Local: "RDF"}:
+				foundRDF = true
+			case t.Name == xml.Name{Space: xmpNamespaceRDF, Local: "Description"}:
+				for _, attr := range t.Attr {
+					set(attr.Name, []string{strings.TrimSpace(attr.Value)})
+				}
+			case parent == xml.Name{Space: xmpNamespaceRDF, Local: "Description"}:
+				property, items = t.Name, nil
+			}
+			text.Reset()
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+			switch {
+			case t.Name == xml.Name{Space: xmpNamespaceRDF, Local: "li"}:
+				items = append(items, strings.TrimSpace(text.String()))
+			case t.Name == property:
+				if len(items) == 0 {
+					items = []string{strings.TrimSpace(text.String())}
+				}
+				set(property, items)
+				property = xml.Name{}
+			}
+			text.Reset()
+		}
+	}
+
+	if !foundRDF {
+		return info, fmt.Errorf("invalid XMP: no rdf:RDF element found")
+	}
+	return info, nil
+}
+
+// xmlEscape escapes text for use in XML character data
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,161 @@
+package pdftoolpb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ChunkSize is the size of the file chunks the helpers and the server send
+const ChunkSize = 64 << 10
+
+// Input is a file sent to Convert or Merge
+type Input struct {
+	Name   string // File name; its extension selects the image format for Convert
+	Reader io.Reader
+}
+
+// CompressFile compresses a PDF read from r, writing the result to w
+func CompressFile(ctx context.Context, c PDFToolClient, r io.Reader, w io.Writer, opts *CompressOptions) (*CompressResult, error) {
+	stream, err := c.Compress(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = func() error {
+		if err := stream.Send(&CompressRequest{Payload: &CompressRequest_Options{Options: opts}}); err != nil {
+			return err
+		}
+		return sendChunks(r, func(chunk []byte) error {
+			return stream.Send(&CompressRequest{Payload: &CompressRequest_Chunk{Chunk: chunk}})
+		})
+	}()
+	if err := sendDone(err, stream.CloseSend); err != nil {
+		return nil, err
+	}
+
+	var result *CompressResult
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if res := resp.GetResult(); res != nil {
+			result = res
+		}
+		if _, err := w.Write(resp.GetChunk()); err != nil {
+			return nil, err
+		}
+	}
+	if result == nil {
+		return nil, fmt.Errorf("server sent no result")
+	}
+	return result, nil
+}
+
+// ConvertImages converts PNG or JPEG images to a PDF written to w
+func ConvertImages(ctx context.Context, c PDFToolClient, inputs []Input, w io.Writer, opts *ConvertOptions) error {
+	stream, err := c.Convert(ctx)
+	if err != nil {
+		return err
+	}
+	err = func() error {
+		if err := stream.Send(&ConvertRequest{Payload: &ConvertRequest_Options{Options: opts}}); err != nil {
+			return err
+		}
+		for _, input := range inputs {
+			if err := stream.Send(&ConvertRequest{Payload: &ConvertRequest_File{File: &FileStart{Name: input.Name}}}); err != nil {
+				return err
+			}
+			err := sendChunks(input.Reader, func(chunk []byte) error {
+				return stream.Send(&ConvertRequest{Payload: &ConvertRequest_Chunk{Chunk: chunk}})
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}()
+	if err := sendDone(err, stream.CloseSend); err != nil {
+		return err
+	}
+	return receiveChunks(stream.Recv, w)
+}
+
+// MergeFiles concatenates two or more PDFs into one written to w
+func MergeFiles(ctx context.Context, c PDFToolClient, inputs []Input, w io.Writer, opts *MergeOptions) error {
+	stream, err := c.Merge(ctx)
+	if err != nil {
+		return err
+	}
+	err = func() error {
+		if err := stream.Send(&MergeRequest{Payload: &MergeRequest_Options{Options: opts}}); err != nil {
+			return err
+		}
+		for _, input := range inputs {
+			if err := stream.Send(&MergeRequest{Payload: &MergeRequest_File{File: &FileStart{Name: input.Name}}}); err != nil {
+				return err
+			}
+			err := sendChunks(input.Reader, func(chunk []byte) error {
+				return stream.Send(&MergeRequest{Payload: &MergeRequest_Chunk{Chunk: chunk}})
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}()
+	if err := sendDone(err, stream.CloseSend); err != nil {
+		return err
+	}
+	return receiveChunks(stream.Recv, w)
+}
+
+// sendDone finishes sending a request stream. A Send fails with io.EOF when
+// the server ended the call early; the call's status is then returned by
+// the next Recv, so only other errors are returned here.
+func sendDone(err error, closeSend func() error) error {
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return closeSend()
+}
+
+// sendChunks reads r to the end, passing it to send in chunks of up to
+// ChunkSize
+func sendChunks(r io.Reader, send func(chunk []byte) error) error {
+	buf := make([]byte, ChunkSize)
+	for {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			if err := send(buf[:n]); err != nil {
+				return err
+			}
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read input: %w", err)
+		}
+	}
+}
+
+// receiveChunks writes the chunks of a response stream to w until it ends
+func receiveChunks(recv func() (*Chunk, error), w io.Writer) error {
+	for {
+		chunk, err := recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(chunk.GetData()); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,12 @@
+// Package pdftoolpb is the gRPC API of pdf-tool serve --grpc, generated
+// from pdftool.proto, with helpers that stream files to and from it.
+//
+//	conn, err := grpc.NewClient("pdf-service:9090", grpc.WithTransportCredentials(insecure.NewCredentials()))
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	client := pdftoolpb.NewPDFToolClient(conn)
+//	result, err := pdftoolpb.CompressFile(ctx, client, in, out, &pdftoolpb.CompressOptions{Quality: 40})
+package pdftoolpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative pdftool.proto
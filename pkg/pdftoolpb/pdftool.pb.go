@@ -0,0 +1,954 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: pdftool.proto
+
+package pdftoolpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// CompressOptions mirror the flags of pdf-tool compress
+type CompressOptions struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Quality       int32                  `protobuf:"varint,1,opt,name=quality,proto3" json:"quality,omitempty"`                                  // 1 (smallest) to 100 (best); 50 if unset
+	Engine        string                 `protobuf:"bytes,2,opt,name=engine,proto3" json:"engine,omitempty"`                                     // e.g. "qpdf" or "qpdf,pdfcpu"; "auto" if unset
+	Dpi           int32                  `protobuf:"varint,3,opt,name=dpi,proto3" json:"dpi,omitempty"`                                          // Downsample images to this resolution
+	StripMetadata bool                   `protobuf:"varint,4,opt,name=strip_metadata,json=stripMetadata,proto3" json:"strip_metadata,omitempty"` // Remove document properties and XMP metadata
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompressOptions) Reset() {
+	*x = CompressOptions{}
+	mi := &file_pdftool_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompressOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompressOptions) ProtoMessage() {}
+
+func (x *CompressOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_pdftool_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompressOptions.ProtoReflect.Descriptor instead.
+func (*CompressOptions) Descriptor() ([]byte, []int) {
+	return file_pdftool_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CompressOptions) GetQuality() int32 {
+	if x != nil {
+		return x.Quality
+	}
+	return 0
+}
+
+func (x *CompressOptions) GetEngine() string {
+	if x != nil {
+		return x.Engine
+	}
+	return ""
+}
+
+func (x *CompressOptions) GetDpi() int32 {
+	if x != nil {
+		return x.Dpi
+	}
+	return 0
+}
+
+func (x *CompressOptions) GetStripMetadata() bool {
+	if x != nil {
+		return x.StripMetadata
+	}
+	return false
+}
+
+type CompressRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*CompressRequest_Options
+	//	*CompressRequest_Chunk
+	Payload       isCompressRequest_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompressRequest) Reset() {
+	*x = CompressRequest{}
+	mi := &file_pdftool_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompressRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompressRequest) ProtoMessage() {}
+
+func (x *CompressRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pdftool_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompressRequest.ProtoReflect.Descriptor instead.
+func (*CompressRequest) Descriptor() ([]byte, []int) {
+	return file_pdftool_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CompressRequest) GetPayload() isCompressRequest_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *CompressRequest) GetOptions() *CompressOptions {
+	if x != nil {
+		if x, ok := x.Payload.(*CompressRequest_Options); ok {
+			return x.Options
+		}
+	}
+	return nil
+}
+
+func (x *CompressRequest) GetChunk() []byte {
+	if x != nil {
+		if x, ok := x.Payload.(*CompressRequest_Chunk); ok {
+			return x.Chunk
+		}
+	}
+	return nil
+}
+
+type isCompressRequest_Payload interface {
+	isCompressRequest_Payload()
+}
+
+type CompressRequest_Options struct {
+	Options *CompressOptions `protobuf:"bytes,1,opt,name=options,proto3,oneof"` // First message only
+}
+
+type CompressRequest_Chunk struct {
+	Chunk []byte `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"` // Next part of the PDF
+}
+
+func (*CompressRequest_Options) isCompressRequest_Payload() {}
+
+func (*CompressRequest_Chunk) isCompressRequest_Payload() {}
+
+// CompressResult describes a finished compression
+type CompressResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	InputSize     int64                  `protobuf:"varint,1,opt,name=input_size,json=inputSize,proto3" json:"input_size,omitempty"`    // Bytes
+	OutputSize    int64                  `protobuf:"varint,2,opt,name=output_size,json=outputSize,proto3" json:"output_size,omitempty"` // Bytes
+	Engine        string                 `protobuf:"bytes,3,opt,name=engine,proto3" json:"engine,omitempty"`                            // Engine that compressed
+	Warnings      []string               `protobuf:"bytes,4,rep,name=warnings,proto3" json:"warnings,omitempty"`                        // Problems that did not stop it
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompressResult) Reset() {
+	*x = CompressResult{}
+	mi := &file_pdftool_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompressResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompressResult) ProtoMessage() {}
+
+func (x *CompressResult) ProtoReflect() protoreflect.Message {
+	mi := &file_pdftool_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompressResult.ProtoReflect.Descriptor instead.
+func (*CompressResult) Descriptor() ([]byte, []int) {
+	return file_pdftool_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CompressResult) GetInputSize() int64 {
+	if x != nil {
+		return x.InputSize
+	}
+	return 0
+}
+
+func (x *CompressResult) GetOutputSize() int64 {
+	if x != nil {
+		return x.OutputSize
+	}
+	return 0
+}
+
+func (x *CompressResult) GetEngine() string {
+	if x != nil {
+		return x.Engine
+	}
+	return ""
+}
+
+func (x *CompressResult) GetWarnings() []string {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+type CompressResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*CompressResponse_Result
+	//	*CompressResponse_Chunk
+	Payload       isCompressResponse_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompressResponse) Reset() {
+	*x = CompressResponse{}
+	mi := &file_pdftool_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompressResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompressResponse) ProtoMessage() {}
+
+func (x *CompressResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pdftool_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompressResponse.ProtoReflect.Descriptor instead.
+func (*CompressResponse) Descriptor() ([]byte, []int) {
+	return file_pdftool_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CompressResponse) GetPayload() isCompressResponse_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *CompressResponse) GetResult() *CompressResult {
+	if x != nil {
+		if x, ok := x.Payload.(*CompressResponse_Result); ok {
+			return x.Result
+		}
+	}
+	return nil
+}
+
+func (x *CompressResponse) GetChunk() []byte {
+	if x != nil {
+		if x, ok := x.Payload.(*CompressResponse_Chunk); ok {
+			return x.Chunk
+		}
+	}
+	return nil
+}
+
+type isCompressResponse_Payload interface {
+	isCompressResponse_Payload()
+}
+
+type CompressResponse_Result struct {
+	Result *CompressResult `protobuf:"bytes,1,opt,name=result,proto3,oneof"` // First message only
+}
+
+type CompressResponse_Chunk struct {
+	Chunk []byte `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"` // Next part of the compressed PDF
+}
+
+func (*CompressResponse_Result) isCompressResponse_Payload() {}
+
+func (*CompressResponse_Chunk) isCompressResponse_Payload() {}
+
+// ConvertOptions mirror the flags of pdf-tool convert
+type ConvertOptions struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PageSize      string                 `protobuf:"bytes,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"` // e.g. "A4" (default) or "Letter"
+	Landscape     bool                   `protobuf:"varint,2,opt,name=landscape,proto3" json:"landscape,omitempty"`
+	Fit           string                 `protobuf:"bytes,3,opt,name=fit,proto3" json:"fit,omitempty"`         // "auto" (default), "contain" or "actual"
+	Dpi           int32                  `protobuf:"varint,4,opt,name=dpi,proto3" json:"dpi,omitempty"`        // Resolution of the images' natural size; 300 if unset
+	Margin        float64                `protobuf:"fixed64,5,opt,name=margin,proto3" json:"margin,omitempty"` // Points
+	Grayscale     bool                   `protobuf:"varint,6,opt,name=grayscale,proto3" json:"grayscale,omitempty"`
+	Nup           string                 `protobuf:"bytes,7,opt,name=nup,proto3" json:"nup,omitempty"`         // Grid of images per page, e.g. "2x2"
+	Caption       string                 `protobuf:"bytes,8,opt,name=caption,proto3" json:"caption,omitempty"` // e.g. "{filename}"
+	Pdfa          bool                   `protobuf:"varint,9,opt,name=pdfa,proto3" json:"pdfa,omitempty"`      // Produce PDF/A-2b
+	Title         string                 `protobuf:"bytes,10,opt,name=title,proto3" json:"title,omitempty"`
+	Author        string                 `protobuf:"bytes,11,opt,name=author,proto3" json:"author,omitempty"`
+	Subject       string                 `protobuf:"bytes,12,opt,name=subject,proto3" json:"subject,omitempty"`
+	Keywords      string                 `protobuf:"bytes,13,opt,name=keywords,proto3" json:"keywords,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConvertOptions) Reset() {
+	*x = ConvertOptions{}
+	mi := &file_pdftool_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConvertOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConvertOptions) ProtoMessage() {}
+
+func (x *ConvertOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_pdftool_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConvertOptions.ProtoReflect.Descriptor instead.
+func (*ConvertOptions) Descriptor() ([]byte, []int) {
+	return file_pdftool_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ConvertOptions) GetPageSize() string {
+	if x != nil {
+		return x.PageSize
+	}
+	return ""
+}
+
+func (x *ConvertOptions) GetLandscape() bool {
+	if x != nil {
+		return x.Landscape
+	}
+	return false
+}
+
+func (x *ConvertOptions) GetFit() string {
+	if x != nil {
+		return x.Fit
+	}
+	return ""
+}
+
+func (x *ConvertOptions) GetDpi() int32 {
+	if x != nil {
+		return x.Dpi
+	}
+	return 0
+}
+
+func (x *ConvertOptions) GetMargin() float64 {
+	if x != nil {
+		return x.Margin
+	}
+	return 0
+}
+
+func (x *ConvertOptions) GetGrayscale() bool {
+	if x != nil {
+		return x.Grayscale
+	}
+	return false
+}
+
+func (x *ConvertOptions) GetNup() string {
+	if x != nil {
+		return x.Nup
+	}
+	return ""
+}
+
+func (x *ConvertOptions) GetCaption() string {
+	if x != nil {
+		return x.Caption
+	}
+	return ""
+}
+
+func (x *ConvertOptions) GetPdfa() bool {
+	if x != nil {
+		return x.Pdfa
+	}
+	return false
+}
+
+func (x *ConvertOptions) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *ConvertOptions) GetAuthor() string {
+	if x != nil {
+		return x.Author
+	}
+	return ""
+}
+
+func (x *ConvertOptions) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *ConvertOptions) GetKeywords() string {
+	if x != nil {
+		return x.Keywords
+	}
+	return ""
+}
+
+// FileStart begins the next input file; the following chunks belong to it
+type FileStart struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"` // File name; its extension selects the image format
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileStart) Reset() {
+	*x = FileStart{}
+	mi := &file_pdftool_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileStart) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileStart) ProtoMessage() {}
+
+func (x *FileStart) ProtoReflect() protoreflect.Message {
+	mi := &file_pdftool_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileStart.ProtoReflect.Descriptor instead.
+func (*FileStart) Descriptor() ([]byte, []int) {
+	return file_pdftool_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *FileStart) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type ConvertRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*ConvertRequest_Options
+	//	*ConvertRequest_File
+	//	*ConvertRequest_Chunk
+	Payload       isConvertRequest_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConvertRequest) Reset() {
+	*x = ConvertRequest{}
+	mi := &file_pdftool_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConvertRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConvertRequest) ProtoMessage() {}
+
+func (x *ConvertRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pdftool_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConvertRequest.ProtoReflect.Descriptor instead.
+func (*ConvertRequest) Descriptor() ([]byte, []int) {
+	return file_pdftool_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ConvertRequest) GetPayload() isConvertRequest_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *ConvertRequest) GetOptions() *ConvertOptions {
+	if x != nil {
+		if x, ok := x.Payload.(*ConvertRequest_Options); ok {
+			return x.Options
+		}
+	}
+	return nil
+}
+
+func (x *ConvertRequest) GetFile() *FileStart {
+	if x != nil {
+		if x, ok := x.Payload.(*ConvertRequest_File); ok {
+			return x.File
+		}
+	}
+	return nil
+}
+
+func (x *ConvertRequest) GetChunk() []byte {
+	if x != nil {
+		if x, ok := x.Payload.(*ConvertRequest_Chunk); ok {
+			return x.Chunk
+		}
+	}
+	return nil
+}
+
+type isConvertRequest_Payload interface {
+	isConvertRequest_Payload()
+}
+
+type ConvertRequest_Options struct {
+	Options *ConvertOptions `protobuf:"bytes,1,opt,name=options,proto3,oneof"` // First message only
+}
+
+type ConvertRequest_File struct {
+	File *FileStart `protobuf:"bytes,2,opt,name=file,proto3,oneof"`
+}
+
+type ConvertRequest_Chunk struct {
+	Chunk []byte `protobuf:"bytes,3,opt,name=chunk,proto3,oneof"`
+}
+
+func (*ConvertRequest_Options) isConvertRequest_Payload() {}
+
+func (*ConvertRequest_File) isConvertRequest_Payload() {}
+
+func (*ConvertRequest_Chunk) isConvertRequest_Payload() {}
+
+// MergeOptions mirror the flags of pdf-tool merge
+type MergeOptions struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Bookmarks     bool                   `protobuf:"varint,1,opt,name=bookmarks,proto3" json:"bookmarks,omitempty"` // Add a bookmark for each input file
+	Toc           bool                   `protobuf:"varint,2,opt,name=toc,proto3" json:"toc,omitempty"`             // Prepend a table of contents
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MergeOptions) Reset() {
+	*x = MergeOptions{}
+	mi := &file_pdftool_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MergeOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MergeOptions) ProtoMessage() {}
+
+func (x *MergeOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_pdftool_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MergeOptions.ProtoReflect.Descriptor instead.
+func (*MergeOptions) Descriptor() ([]byte, []int) {
+	return file_pdftool_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *MergeOptions) GetBookmarks() bool {
+	if x != nil {
+		return x.Bookmarks
+	}
+	return false
+}
+
+func (x *MergeOptions) GetToc() bool {
+	if x != nil {
+		return x.Toc
+	}
+	return false
+}
+
+type MergeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*MergeRequest_Options
+	//	*MergeRequest_File
+	//	*MergeRequest_Chunk
+	Payload       isMergeRequest_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MergeRequest) Reset() {
+	*x = MergeRequest{}
+	mi := &file_pdftool_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MergeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MergeRequest) ProtoMessage() {}
+
+func (x *MergeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pdftool_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MergeRequest.ProtoReflect.Descriptor instead.
+func (*MergeRequest) Descriptor() ([]byte, []int) {
+	return file_pdftool_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *MergeRequest) GetPayload() isMergeRequest_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *MergeRequest) GetOptions() *MergeOptions {
+	if x != nil {
+		if x, ok := x.Payload.(*MergeRequest_Options); ok {
+			return x.Options
+		}
+	}
+	return nil
+}
+
+func (x *MergeRequest) GetFile() *FileStart {
+	if x != nil {
+		if x, ok := x.Payload.(*MergeRequest_File); ok {
+			return x.File
+		}
+	}
+	return nil
+}
+
+func (x *MergeRequest) GetChunk() []byte {
+	if x != nil {
+		if x, ok := x.Payload.(*MergeRequest_Chunk); ok {
+			return x.Chunk
+		}
+	}
+	return nil
+}
+
+type isMergeRequest_Payload interface {
+	isMergeRequest_Payload()
+}
+
+type MergeRequest_Options struct {
+	Options *MergeOptions `protobuf:"bytes,1,opt,name=options,proto3,oneof"` // First message only
+}
+
+type MergeRequest_File struct {
+	File *FileStart `protobuf:"bytes,2,opt,name=file,proto3,oneof"`
+}
+
+type MergeRequest_Chunk struct {
+	Chunk []byte `protobuf:"bytes,3,opt,name=chunk,proto3,oneof"`
+}
+
+func (*MergeRequest_Options) isMergeRequest_Payload() {}
+
+func (*MergeRequest_File) isMergeRequest_Payload() {}
+
+func (*MergeRequest_Chunk) isMergeRequest_Payload() {}
+
+// Chunk is the next part of an output PDF
+type Chunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Chunk) Reset() {
+	*x = Chunk{}
+	mi := &file_pdftool_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Chunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Chunk) ProtoMessage() {}
+
+func (x *Chunk) ProtoReflect() protoreflect.Message {
+	mi := &file_pdftool_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Chunk.ProtoReflect.Descriptor instead.
+func (*Chunk) Descriptor() ([]byte, []int) {
+	return file_pdftool_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Chunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+var File_pdftool_proto protoreflect.FileDescriptor
+
+const file_pdftool_proto_rawDesc = "" +
+	"\n" +
+	"\rpdftool.proto\x12\n" +
+	"pdftool.v1\"|\n" +
+	"\x0fCompressOptions\x12\x18\n" +
+	"\aquality\x18\x01 \x01(\x05R\aquality\x12\x16\n" +
+	"\x06engine\x18\x02 \x01(\tR\x06engine\x12\x10\n" +
+	"\x03dpi\x18\x03 \x01(\x05R\x03dpi\x12%\n" +
+	"\x0estrip_metadata\x18\x04 \x01(\bR\rstripMetadata\"m\n" +
+	"\x0fCompressRequest\x127\n" +
+	"\aoptions\x18\x01 \x01(\v2\x1b.pdftool.v1.CompressOptionsH\x00R\aoptions\x12\x16\n" +
+	"\x05chunk\x18\x02 \x01(\fH\x00R\x05chunkB\t\n" +
+	"\apayload\"\x84\x01\n" +
+	"\x0eCompressResult\x12\x1d\n" +
+	"\n" +
+	"input_size\x18\x01 \x01(\x03R\tinputSize\x12\x1f\n" +
+	"\voutput_size\x18\x02 \x01(\x03R\n" +
+	"outputSize\x12\x16\n" +
+	"\x06engine\x18\x03 \x01(\tR\x06engine\x12\x1a\n" +
+	"\bwarnings\x18\x04 \x03(\tR\bwarnings\"k\n" +
+	"\x10CompressResponse\x124\n" +
+	"\x06result\x18\x01 \x01(\v2\x1a.pdftool.v1.CompressResultH\x00R\x06result\x12\x16\n" +
+	"\x05chunk\x18\x02 \x01(\fH\x00R\x05chunkB\t\n" +
+	"\apayload\"\xc9\x02\n" +
+	"\x0eConvertOptions\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\tR\bpageSize\x12\x1c\n" +
+	"\tlandscape\x18\x02 \x01(\bR\tlandscape\x12\x10\n" +
+	"\x03fit\x18\x03 \x01(\tR\x03fit\x12\x10\n" +
+	"\x03dpi\x18\x04 \x01(\x05R\x03dpi\x12\x16\n" +
+	"\x06margin\x18\x05 \x01(\x01R\x06margin\x12\x1c\n" +
+	"\tgrayscale\x18\x06 \x01(\bR\tgrayscale\x12\x10\n" +
+	"\x03nup\x18\a \x01(\tR\x03nup\x12\x18\n" +
+	"\acaption\x18\b \x01(\tR\acaption\x12\x12\n" +
+	"\x04pdfa\x18\t \x01(\bR\x04pdfa\x12\x14\n" +
+	"\x05title\x18\n" +
+	" \x01(\tR\x05title\x12\x16\n" +
+	"\x06author\x18\v \x01(\tR\x06author\x12\x18\n" +
+	"\asubject\x18\f \x01(\tR\asubject\x12\x1a\n" +
+	"\bkeywords\x18\r \x01(\tR\bkeywords\"\x1f\n" +
+	"\tFileStart\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"\x98\x01\n" +
+	"\x0eConvertRequest\x126\n" +
+	"\aoptions\x18\x01 \x01(\v2\x1a.pdftool.v1.ConvertOptionsH\x00R\aoptions\x12+\n" +
+	"\x04file\x18\x02 \x01(\v2\x15.pdftool.v1.FileStartH\x00R\x04file\x12\x16\n" +
+	"\x05chunk\x18\x03 \x01(\fH\x00R\x05chunkB\t\n" +
+	"\apayload\">\n" +
+	"\fMergeOptions\x12\x1c\n" +
+	"\tbookmarks\x18\x01 \x01(\bR\tbookmarks\x12\x10\n" +
+	"\x03toc\x18\x02 \x01(\bR\x03toc\"\x94\x01\n" +
+	"\fMergeRequest\x124\n" +
+	"\aoptions\x18\x01 \x01(\v2\x18.pdftool.v1.MergeOptionsH\x00R\aoptions\x12+\n" +
+	"\x04file\x18\x02 \x01(\v2\x15.pdftool.v1.FileStartH\x00R\x04file\x12\x16\n" +
+	"\x05chunk\x18\x03 \x01(\fH\x00R\x05chunkB\t\n" +
+	"\apayload\"\x1b\n" +
+	"\x05Chunk\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data2\xcc\x01\n" +
+	"\aPDFTool\x12I\n" +
+	"\bCompress\x12\x1b.pdftool.v1.CompressRequest\x1a\x1c.pdftool.v1.CompressResponse(\x010\x01\x12<\n" +
+	"\aConvert\x12\x1a.pdftool.v1.ConvertRequest\x1a\x11.pdftool.v1.Chunk(\x010\x01\x128\n" +
+	"\x05Merge\x12\x18.pdftool.v1.MergeRequest\x1a\x11.pdftool.v1.Chunk(\x010\x01B+Z)github.com/ansrivas/pdftool/pkg/pdftoolpbb\x06proto3"
+
+var (
+	file_pdftool_proto_rawDescOnce sync.Once
+	file_pdftool_proto_rawDescData []byte
+)
+
+func file_pdftool_proto_rawDescGZIP() []byte {
+	file_pdftool_proto_rawDescOnce.Do(func() {
+		file_pdftool_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_pdftool_proto_rawDesc), len(file_pdftool_proto_rawDesc)))
+	})
+	return file_pdftool_proto_rawDescData
+}
+
+var file_pdftool_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_pdftool_proto_goTypes = []any{
+	(*CompressOptions)(nil),  // 0: pdftool.v1.CompressOptions
+	(*CompressRequest)(nil),  // 1: pdftool.v1.CompressRequest
+	(*CompressResult)(nil),   // 2: pdftool.v1.CompressResult
+	(*CompressResponse)(nil), // 3: pdftool.v1.CompressResponse
+	(*ConvertOptions)(nil),   // 4: pdftool.v1.ConvertOptions
+	(*FileStart)(nil),        // 5: pdftool.v1.FileStart
+	(*ConvertRequest)(nil),   // 6: pdftool.v1.ConvertRequest
+	(*MergeOptions)(nil),     // 7: pdftool.v1.MergeOptions
+	(*MergeRequest)(nil),     // 8: pdftool.v1.MergeRequest
+	(*Chunk)(nil),            // 9: pdftool.v1.Chunk
+}
+var file_pdftool_proto_depIdxs = []int32{
+	0, // 0: pdftool.v1.CompressRequest.options:type_name -> pdftool.v1.CompressOptions
+	2, // 1: pdftool.v1.CompressResponse.result:type_name -> pdftool.v1.CompressResult
+	4, // 2: pdftool.v1.ConvertRequest.options:type_name -> pdftool.v1.ConvertOptions
+	5, // 3: pdftool.v1.ConvertRequest.file:type_name -> pdftool.v1.FileStart
+	7, // 4: pdftool.v1.MergeRequest.options:type_name -> pdftool.v1.MergeOptions
+	5, // 5: pdftool.v1.MergeRequest.file:type_name -> pdftool.v1.FileStart
+	1, // 6: pdftool.v1.PDFTool.Compress:input_type -> pdftool.v1.CompressRequest
+	6, // 7: pdftool.v1.PDFTool.Convert:input_type -> pdftool.v1.ConvertRequest
+	8, // 8: pdftool.v1.PDFTool.Merge:input_type -> pdftool.v1.MergeRequest
+	3, // 9: pdftool.v1.PDFTool.Compress:output_type -> pdftool.v1.CompressResponse
+	9, // 10: pdftool.v1.PDFTool.Convert:output_type -> pdftool.v1.Chunk
+	9, // 11: pdftool.v1.PDFTool.Merge:output_type -> pdftool.v1.Chunk
+	9, // [9:12] is the sub-list for method output_type
+	6, // [6:9] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_pdftool_proto_init() }
+func file_pdftool_proto_init() {
+	if File_pdftool_proto != nil {
+		return
+	}
+	file_pdftool_proto_msgTypes[1].OneofWrappers = []any{
+		(*CompressRequest_Options)(nil),
+		(*CompressRequest_Chunk)(nil),
+	}
+	file_pdftool_proto_msgTypes[3].OneofWrappers = []any{
+		(*CompressResponse_Result)(nil),
+		(*CompressResponse_Chunk)(nil),
+	}
+	file_pdftool_proto_msgTypes[6].OneofWrappers = []any{
+		(*ConvertRequest_Options)(nil),
+		(*ConvertRequest_File)(nil),
+		(*ConvertRequest_Chunk)(nil),
+	}
+	file_pdftool_proto_msgTypes[8].OneofWrappers = []any{
+		(*MergeRequest_Options)(nil),
+		(*MergeRequest_File)(nil),
+		(*MergeRequest_Chunk)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pdftool_proto_rawDesc), len(file_pdftool_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_pdftool_proto_goTypes,
+		DependencyIndexes: file_pdftool_proto_depIdxs,
+		MessageInfos:      file_pdftool_proto_msgTypes,
+	}.Build()
+	File_pdftool_proto = out.File
+	file_pdftool_proto_goTypes = nil
+	file_pdftool_proto_depIdxs = nil
+}
@@ -0,0 +1,201 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: pdftool.proto
+
+package pdftoolpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	PDFTool_Compress_FullMethodName = "/pdftool.v1.PDFTool/Compress"
+	PDFTool_Convert_FullMethodName  = "/pdftool.v1.PDFTool/Convert"
+	PDFTool_Merge_FullMethodName    = "/pdftool.v1.PDFTool/Merge"
+)
+
+// PDFToolClient is the client API for PDFTool service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// PDFTool compresses, converts and merges PDFs for other services. Files are
+// streamed in chunks of up to 64 KiB in both directions, so their size is
+// not limited by the maximum message size. Each request stream starts with
+// the options; the response stream ends when the whole output was sent.
+type PDFToolClient interface {
+	// Compress receives the options and a PDF and returns the compression
+	// result followed by the compressed PDF
+	Compress(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[CompressRequest, CompressResponse], error)
+	// Convert receives the options and PNG or JPEG images and returns a PDF
+	// with one image per page
+	Convert(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ConvertRequest, Chunk], error)
+	// Merge receives the options and two or more PDFs and returns them
+	// concatenated into one
+	Merge(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[MergeRequest, Chunk], error)
+}
+
+type pDFToolClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPDFToolClient(cc grpc.ClientConnInterface) PDFToolClient {
+	return &pDFToolClient{cc}
+}
+
+func (c *pDFToolClient) Compress(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[CompressRequest, CompressResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PDFTool_ServiceDesc.Streams[0], PDFTool_Compress_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[CompressRequest, CompressResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PDFTool_CompressClient = grpc.BidiStreamingClient[CompressRequest, CompressResponse]
+
+func (c *pDFToolClient) Convert(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ConvertRequest, Chunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PDFTool_ServiceDesc.Streams[1], PDFTool_Convert_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ConvertRequest, Chunk]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PDFTool_ConvertClient = grpc.BidiStreamingClient[ConvertRequest, Chunk]
+
+func (c *pDFToolClient) Merge(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[MergeRequest, Chunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PDFTool_ServiceDesc.Streams[2], PDFTool_Merge_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[MergeRequest, Chunk]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PDFTool_MergeClient = grpc.BidiStreamingClient[MergeRequest, Chunk]
+
+// PDFToolServer is the server API for PDFTool service.
+// All implementations must embed UnimplementedPDFToolServer
+// for forward compatibility.
+//
+// PDFTool compresses, converts and merges PDFs for other services. Files are
+// streamed in chunks of up to 64 KiB in both directions, so their size is
+// not limited by the maximum message size. Each request stream starts with
+// the options; the response stream ends when the whole output was sent.
+type PDFToolServer interface {
+	// Compress receives the options and a PDF and returns the compression
+	// result followed by the compressed PDF
+	Compress(grpc.BidiStreamingServer[CompressRequest, CompressResponse]) error
+	// Convert receives the options and PNG or JPEG images and returns a PDF
+	// with one image per page
+	Convert(grpc.BidiStreamingServer[ConvertRequest, Chunk]) error
+	// Merge receives the options and two or more PDFs and returns them
+	// concatenated into one
+	Merge(grpc.BidiStreamingServer[MergeRequest, Chunk]) error
+	mustEmbedUnimplementedPDFToolServer()
+}
+
+// UnimplementedPDFToolServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPDFToolServer struct{}
+
+func (UnimplementedPDFToolServer) Compress(grpc.BidiStreamingServer[CompressRequest, CompressResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Compress not implemented")
+}
+func (UnimplementedPDFToolServer) Convert(grpc.BidiStreamingServer[ConvertRequest, Chunk]) error {
+	return status.Errorf(codes.Unimplemented, "method Convert not implemented")
+}
+func (UnimplementedPDFToolServer) Merge(grpc.BidiStreamingServer[MergeRequest, Chunk]) error {
+	return status.Errorf(codes.Unimplemented, "method Merge not implemented")
+}
+func (UnimplementedPDFToolServer) mustEmbedUnimplementedPDFToolServer() {}
+func (UnimplementedPDFToolServer) testEmbeddedByValue()                 {}
+
+// UnsafePDFToolServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PDFToolServer will
+// result in compilation errors.
+type UnsafePDFToolServer interface {
+	mustEmbedUnimplementedPDFToolServer()
+}
+
+func RegisterPDFToolServer(s grpc.ServiceRegistrar, srv PDFToolServer) {
+	// If the following call pancis, it indicates UnimplementedPDFToolServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&PDFTool_ServiceDesc, srv)
+}
+
+func _PDFTool_Compress_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(PDFToolServer).Compress(&grpc.GenericServerStream[CompressRequest, CompressResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PDFTool_CompressServer = grpc.BidiStreamingServer[CompressRequest, CompressResponse]
+
+func _PDFTool_Convert_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(PDFToolServer).Convert(&grpc.GenericServerStream[ConvertRequest, Chunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PDFTool_ConvertServer = grpc.BidiStreamingServer[ConvertRequest, Chunk]
+
+func _PDFTool_Merge_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(PDFToolServer).Merge(&grpc.GenericServerStream[MergeRequest, Chunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PDFTool_MergeServer = grpc.BidiStreamingServer[MergeRequest, Chunk]
+
+// PDFTool_ServiceDesc is the grpc.ServiceDesc for PDFTool service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PDFTool_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pdftool.v1.PDFTool",
+	HandlerType: (*PDFToolServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Compress",
+			Handler:       _PDFTool_Compress_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "Convert",
+			Handler:       _PDFTool_Convert_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "Merge",
+			Handler:       _PDFTool_Merge_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "pdftool.proto",
+}
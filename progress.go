@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+)
+
+// progressBarWidth is the number of cells of the progress bar
+const progressBarWidth = 30
+
+// progressBar returns a progress callback that draws a bar on stderr, or nil
+// if stderr is not a terminal, so that logs and pipes stay clean
+func progressBar() pdftool.ProgressFunc {
+	info, err := os.Stderr.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return nil
+	}
+
+	return func(event pdftool.ProgressEvent) {
+		switch event.Stage {
+		case pdftool.StageAnalyze:
+			return // Quick, and followed by the engine message
+		case pdftool.StageVerify:
+			fmt.Fprint(os.Stderr, "\r\033[K") // Clear the bar before the results
+			return
+		}
+		if event.Pages == 0 {
+			fmt.Fprintf(os.Stderr, "\r\033[K   %s...", event.Stage)
+			return
+		}
+
+		filled := progressBarWidth * min(event.Page, event.Pages) / event.Pages
+		bar := strings.Repeat("█", filled) + strings.Repeat("░", progressBarWidth-filled)
+		fmt.Fprintf(os.Stderr, "\r\033[K   %-8s %s %d/%d pages", event.Stage, bar, event.Page, event.Pages)
+		if event.BytesWritten > 0 {
+			fmt.Fprintf(os.Stderr, ", %.0f KB written", float64(event.BytesWritten)/1024)
+		}
+	}
+}
@@ -0,0 +1,474 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Job states in the queue
+const (
+	jobQueued   = "queued"
+	jobRunning  = "running"
+	jobDone     = "done"
+	jobFailed   = "failed"
+	jobCanceled = "canceled"
+)
+
+// Kinds of queued jobs
+const (
+	jobCompress = "compress"
+	jobConvert  = "convert"
+	jobMerge    = "merge" // Only in metrics; merges are not queued
+)
+
+const (
+	queueMaxAttempts  = 5                // Attempts before a job fails for good
+	queuePollInterval = time.Second      // How often idle workers look for jobs
+	queueLockTimeout  = 10 * time.Second // Wait for other processes using the queue
+)
+
+// jobsBucket holds the jobs, keyed by their big-endian ID
+var jobsBucket = []byte("jobs")
+
+// errJobNotFound is returned for IDs that are not in the queue
+var errJobNotFound = errors.New("job not found")
+
+// queueJob is a compression or conversion in the job queue
+type queueJob struct {
+	ID          uint64    `json:"id"`
+	Kind        string    `json:"kind"`
+	InputFile   string    `json:"inputFile"`
+	OutputFile  string    `json:"outputFile"`
+	Quality     int       `json:"quality,omitempty"`
+	Engine      string    `json:"engine,omitempty"`
+	DeleteInput bool      `json:"deleteInput,omitempty"` // Remove the input once done
+	Status      string    `json:"status"`
+	Attempts    int       `json:"attempts"`
+	Error       string    `json:"error,omitempty"` // Of the last attempt
+	InputSize   int64     `json:"inputSize,omitempty"`
+	OutputSize  int64     `json:"outputSize,omitempty"`
+	Created     time.Time `json:"created"`
+	Updated     time.Time `json:"updated"`
+	NextAttempt time.Time `json:"nextAttempt,omitzero"` // Of a job waiting to be retried
+}
+
+// jobQueue is a durable job queue in a bbolt database in a directory. The
+// database is only opened for each operation, so the jobs command can
+// inspect and change the queue while serve or watch process it.
+type jobQueue struct {
+	dir string
+	mu  sync.Mutex // Serializes this process's operations
+}
+
+// openQueue opens the job queue in a directory, creating it if needed
+func openQueue(dir string) (*jobQueue, error) {
+	if err := os.MkdirAll(filepath.Join(dir, "files"), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create queue: %w", err)
+	}
+	q := &jobQueue{dir: dir}
+	if err := q.update(func(*bolt.Bucket) error { return nil }); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// filesDir returns the directory for files the queue keeps, such as uploads
+func (q *jobQueue) filesDir() string {
+	return filepath.Join(q.dir, "files")
+}
+
+// update runs fn in a read-write transaction on the jobs bucket
+func (q *jobQueue) update(fn func(b *bolt.Bucket) error) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	db, err := bolt.Open(filepath.Join(q.dir, "jobs.db"), 0o600, &bolt.Options{Timeout: queueLockTimeout})
+	if err != nil {
+		return fmt.Errorf("failed to open queue: %w", err)
+	}
+	defer db.Close()
+
+	return db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(jobsBucket)
+		if err != nil {
+			return err
+		}
+		return fn(b)
+	})
+}
+
+// jobKey returns the database key of a job ID
+func jobKey(id uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, id)
+}
+
+// getJob reads a job from the bucket
+func getJob(b *bolt.Bucket, id uint64) (*queueJob, error) {
+	data := b.Get(jobKey(id))
+	if data == nil {
+		return nil, fmt.Errorf("%w: %d", errJobNotFound, id)
+	}
+	var job queueJob
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, fmt.Errorf("job %d is corrupt: %w", id, err)
+	}
+	return &job, nil
+}
+
+// putJob writes a job to the bucket, updating its modification time
+func putJob(b *bolt.Bucket, job *queueJob) error {
+	job.Updated = time.Now()
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return b.Put(jobKey(job.ID), data)
+}
+
+// eachJob calls fn for every job in ID order until it returns false
+func eachJob(b *bolt.Bucket, fn func(job *queueJob) (bool, error)) error {
+	c := b.Cursor()
+	for k, v := c.First(); k != nil; k, v = c.Next() {
+		var job queueJob
+		if err := json.Unmarshal(v, &job); err != nil {
+			return fmt.Errorf("job %d is corrupt: %w", binary.BigEndian.Uint64(k), err)
+		}
+		if more, err := fn(&job); err != nil || !more {
+			return err
+		}
+	}
+	return nil
+}
+
+// Add queues a job and sets its ID. A job for the same input that is still
+// queued or running is returned instead of adding a duplicate.
+func (q *jobQueue) Add(job *queueJob) (*queueJob, error) {
+	err := q.update(func(b *bolt.Bucket) error {
+		var existing *queueJob
+		err := eachJob(b, func(j *queueJob) (bool, error) {
+			if j.InputFile == job.InputFile && j.Kind == job.Kind && (j.Status == jobQueued || j.Status == jobRunning) {
+				existing = j
+				return false, nil
+			}
+			return true, nil
+		})
+		if err != nil || existing != nil {
+			job = existing
+			return err
+		}
+
+		if job.ID, err = b.NextSequence(); err != nil {
+			return err
+		}
+		job.Status = jobQueued
+		job.Created = time.Now()
+		return putJob(b, job)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// Get returns a job
+func (q *jobQueue) Get(id uint64) (*queueJob, error) {
+	var job *queueJob
+	err := q.update(func(b *bolt.Bucket) error {
+		var err error
+		job, err = getJob(b, id)
+		return err
+	})
+	return job, err
+}
+
+// List returns all jobs in the order they were added
+func (q *jobQueue) List() ([]*queueJob, error) {
+	var jobs []*queueJob
+	err := q.update(func(b *bolt.Bucket) error {
+		return eachJob(b, func(job *queueJob) (bool, error) {
+			jobs = append(jobs, job)
+			return true, nil
+		})
+	})
+	return jobs, err
+}
+
+// counts returns the number of jobs by status
+func (q *jobQueue) counts() (map[string]int, error) {
+	counts := map[string]int{}
+	err := q.update(func(b *bolt.Bucket) error {
+		return eachJob(b, func(job *queueJob) (bool, error) {
+			counts[job.Status]++
+			return true, nil
+		})
+	})
+	return counts, err
+}
+
+// claim marks the oldest queued job that is due as running and returns it,
+// or nil if there is none
+func (q *jobQueue) claim() (*queueJob, error) {
+	var claimed *queueJob
+	now := time.Now()
+	err := q.update(func(b *bolt.Bucket) error {
+		err := eachJob(b, func(job *queueJob) (bool, error) {
+			if job.Status == jobQueued && !job.NextAttempt.After(now) {
+				claimed = job
+				return false, nil
+			}
+			return true, nil
+		})
+		if err != nil || claimed == nil {
+			return err
+		}
+		claimed.Status = jobRunning
+		claimed.Attempts++
+		return putJob(b, claimed)
+	})
+	return claimed, err
+}
+
+// finish records the outcome of a job's attempt. Failures are retried with
+// growing delays unless retrying cannot help or the job ran out of
+// attempts. Jobs canceled while running stay canceled.
+func (q *jobQueue) finish(id uint64, result *pdftool.Result, jobErr error) (*queueJob, error) {
+	var job *queueJob
+	err := q.update(func(b *bolt.Bucket) error {
+		var err error
+		if job, err = getJob(b, id); err != nil || job.Status == jobCanceled {
+			return err
+		}
+
+		switch {
+		case jobErr == nil:
+			job.Status = jobDone
+			job.Error = ""
+			if result != nil {
+				job.InputSize, job.OutputSize = result.InputSize, result.OutputSize
+			}
+		case pdftool.IsPermanent(jobErr) || job.Attempts >= queueMaxAttempts:
+			job.Status = jobFailed
+			job.Error = jobErr.Error()
+		default:
+			job.Status = jobQueued
+			job.Error = jobErr.Error()
+			job.NextAttempt = time.Now().Add(queueBackoff(job.Attempts))
+		}
+		return putJob(b, job)
+	})
+	return job, err
+}
+
+// queueBackoff returns the delay before retrying a job after a failed
+// attempt: 10s, 20s, 40s and so on, at most 10 minutes
+func queueBackoff(attempts int) time.Duration {
+	return min(10*time.Second<<(attempts-1), 10*time.Minute)
+}
+
+// requeue puts a running job back in the queue without counting the
+// attempt, e.g. when it was interrupted by a shutdown
+func (q *jobQueue) requeue(id uint64) error {
+	return q.update(func(b *bolt.Bucket) error {
+		job, err := getJob(b, id)
+		if err != nil || job.Status != jobRunning {
+			return err
+		}
+		job.Status = jobQueued
+		job.Attempts--
+		return putJob(b, job)
+	})
+}
+
+// recoverRunning requeues the jobs left running by a process that stopped
+// without finishing them
+func (q *jobQueue) recoverRunning() (int, error) {
+	var recovered int
+	err := q.update(func(b *bolt.Bucket) error {
+		var running []*queueJob
+		err := eachJob(b, func(job *queueJob) (bool, error) {
+			if job.Status == jobRunning {
+				running = append(running, job)
+			}
+			return true, nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, job := range running {
+			job.Status = jobQueued
+			if err := putJob(b, job); err != nil {
+				return err
+			}
+		}
+		recovered = len(running)
+		return nil
+	})
+	return recovered, err
+}
+
+// Retry queues a failed or canceled job again with fresh attempts
+func (q *jobQueue) Retry(id uint64) error {
+	return q.update(func(b *bolt.Bucket) error {
+		job, err := getJob(b, id)
+		if err != nil {
+			return err
+		}
+		if job.Status != jobFailed && job.Status != jobCanceled {
+			return fmt.Errorf("job %d is %s; only failed or canceled jobs can be retried", id, job.Status)
+		}
+		job.Status = jobQueued
+		job.Attempts = 0
+		job.Error = ""
+		job.NextAttempt = time.Time{}
+		return putJob(b, job)
+	})
+}
+
+// Cancel stops a queued job from running. A running job finishes its
+// current attempt, but its outcome is not recorded.
+func (q *jobQueue) Cancel(id uint64) error {
+	return q.update(func(b *bolt.Bucket) error {
+		job, err := getJob(b, id)
+		if err != nil {
+			return err
+		}
+		if job.Status != jobQueued && job.Status != jobRunning {
+			return fmt.Errorf("job %d is already %s", id, job.Status)
+		}
+		job.Status = jobCanceled
+		return putJob(b, job)
+	})
+}
+
+// runQueue processes queued jobs with a number of workers until ctx is
+// canceled, calling done after every attempt with its start, result and
+// error. Jobs left running by an earlier process are queued again first;
+// only one process should work on a queue at a time.
+func runQueue(ctx context.Context, q *jobQueue, workers int, done func(job *queueJob, start time.Time, result *pdftool.Result, err error)) error {
+	recovered, err := q.recoverRunning()
+	if err != nil {
+		return err
+	}
+	if recovered > 0 {
+		rootLogger.Info(fmt.Sprintf("Resuming %d interrupted jobs", recovered))
+	}
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				job, err := q.claim()
+				if err != nil {
+					rootLogger.Warn(err.Error())
+				}
+				if job == nil {
+					select {
+					case <-ctx.Done():
+					case <-time.After(queuePollInterval):
+					}
+					continue
+				}
+
+				start := time.Now()
+				result, jobErr := runQueueJob(ctx, job)
+				if ctx.Err() != nil {
+					// Interrupted by the shutdown; run it again next time
+					if err := q.requeue(job.ID); err != nil {
+						rootLogger.Warn(err.Error())
+					}
+					return
+				}
+
+				finished, err := q.finish(job.ID, result, jobErr)
+				if err != nil {
+					rootLogger.Warn(err.Error())
+					continue
+				}
+				// Remote inputs belong to their bucket or server and are kept
+				if finished.Status == jobDone && finished.DeleteInput && !isRemote(finished.InputFile) {
+					if err := os.Remove(finished.InputFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+						rootLogger.Warn(err.Error())
+					}
+				}
+				done(finished, start, result, jobErr)
+			}
+		}()
+	}
+	wg.Wait()
+	return nil
+}
+
+// runQueueJob runs one attempt of a job. Its input and output may be
+// remote URIs.
+func runQueueJob(ctx context.Context, job *queueJob) (*pdftool.Result, error) {
+	switch job.Kind {
+	case jobCompress:
+		return compressURI(ctx, job.InputFile, job.OutputFile, func(ctx context.Context, inputFile, outputFile string) (*pdftool.Result, error) {
+			return pdftool.CompressPDF(ctx, inputFile, outputFile, pdftool.CompressOptions{
+				Quality: job.Quality,
+				Engine:  job.Engine,
+			})
+		})
+	case jobConvert:
+		inputFiles, cleanup, err := localInputs(ctx, []string{job.InputFile})
+		if err != nil {
+			return nil, err
+		}
+		defer cleanup()
+		outputFile, commit, cleanupOutput, err := localOutput(ctx, job.OutputFile)
+		if err != nil {
+			return nil, err
+		}
+		defer cleanupOutput()
+
+		if err := pdftool.ConvertImageToPDF(ctx, inputFiles[0], outputFile, pdftool.ConvertOptions{}); err != nil {
+			return nil, err
+		}
+		result := fileSizes(inputFiles[0], outputFile)
+		if err := commit(); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+	return nil, fmt.Errorf("unknown job kind: %s", job.Kind)
+}
+
+// fileSizes returns a Result with the sizes of an input and output file
+func fileSizes(inputFile, outputFile string) *pdftool.Result {
+	result := &pdftool.Result{}
+	if info, err := os.Stat(inputFile); err == nil {
+		result.InputSize = info.Size()
+	}
+	if info, err := os.Stat(outputFile); err == nil {
+		result.OutputSize = info.Size()
+	}
+	return result
+}
+
+// logQueueJob logs the outcome of a job's attempt
+func logQueueJob(job *queueJob) {
+	name := remoteBase(job.InputFile)
+	switch job.Status {
+	case jobDone:
+		rootLogger.Info(fmt.Sprintf("Job %d (%s %s): done, %.2f KB -> %.2f KB", job.ID, job.Kind, name,
+			float64(job.InputSize)/1024, float64(job.OutputSize)/1024))
+	case jobQueued:
+		rootLogger.Warn(fmt.Sprintf("Job %d (%s %s): attempt %d failed, retrying in %s: %s", job.ID, job.Kind, name,
+			job.Attempts, time.Until(job.NextAttempt).Round(time.Second), job.Error))
+	case jobFailed:
+		rootLogger.Error(fmt.Sprintf("Job %d (%s %s): failed after %d attempts: %s", job.ID, job.Kind, name, job.Attempts, job.Error))
+	}
+}
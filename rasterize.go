@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+
+	"github.com/spf13/cobra"
+)
+
+var rasterizeOpts pdftool.RasterizeOptions
+
+var (
+	rasterizeStitch string
+	rasterizeZip    string
+)
+
+var rasterizeCmd = &cobra.Command{
+	Use:   "rasterize [input.pdf] [output-dir | output.png/jpg]",
+	Short: "Render PDF pages to PNG or JPEG images",
+	Long: `Render PDF pages to PNG or JPEG images, one file per page, e.g. for previews
+and thumbnails. Files are named after the input and page number.
+
+With --stitch vertical or horizontal, the pages are joined into one long image
+instead, written to the given output file, e.g. for chat tools that don't
+preview PDFs.
+
+With --zip pages.zip instead of an output directory, the images are collected
+in a ZIP archive.
+
+Rendering uses Ghostscript. Without it, only scanned pages consisting of a
+single image can be exported, at their original resolution.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputFile := args[0]
+		outputDir, err := outputDirOrZip(args, rasterizeZip)
+		if err != nil {
+			return err
+		}
+
+		if rasterizeStitch != "" {
+			if rasterizeZip != "" {
+				return fmt.Errorf("--zip cannot be combined with --stitch")
+			}
+			fmt.Printf("🔄 Stitching PDF pages: %s -> %s (%s, %d DPI)\n", inputFile, outputDir, rasterizeStitch, rasterizeOpts.DPI)
+
+			if err := pdftool.StitchPDF(cmd.Context(), inputFile, outputDir, rasterizeStitch, rasterizeOpts); err != nil {
+				return fmt.Errorf("stitching failed: %w", err)
+			}
+
+			fmt.Println("✅ Page stitching completed successfully!")
+			return nil
+		}
+
+		var files []string
+		if rasterizeZip != "" {
+			fmt.Printf("🔄 Rasterizing PDF: %s -> %s (%s, %d DPI)\n", inputFile, rasterizeZip, rasterizeOpts.Format, rasterizeOpts.DPI)
+			files, err = pdftool.RasterizePDFToZip(cmd.Context(), inputFile, rasterizeZip, rasterizeOpts)
+		} else {
+			fmt.Printf("🔄 Rasterizing PDF: %s -> %s (%s, %d DPI)\n", inputFile, outputDir, rasterizeOpts.Format, rasterizeOpts.DPI)
+			files, err = pdftool.RasterizePDF(cmd.Context(), inputFile, outputDir, rasterizeOpts)
+		}
+		if err != nil {
+			return fmt.Errorf("rasterization failed: %w", err)
+		}
+
+		fmt.Printf("✅ Rasterized %d pages successfully!\n", len(files))
+		return nil
+	},
+}
+
+func init() {
+	rasterizeCmd.Flags().StringVar(&rasterizeOpts.Format, "format", "png", "Image format: png or jpg")
+	rasterizeCmd.Flags().IntVar(&rasterizeOpts.DPI, "dpi", 150, "Rendering resolution")
+	rasterizeCmd.Flags().StringVar(&rasterizeOpts.Pages, "pages", "", "Pages to render, e.g. 1-5,8 (default: all)")
+	rasterizeCmd.Flags().StringVar(&rasterizeStitch, "stitch", "", "Join pages into one image: vertical or horizontal")
+	rasterizeCmd.Flags().IntVar(&rasterizeOpts.Quality, "quality", 90, "JPEG quality (1-100)")
+	rasterizeCmd.Flags().StringVar(&rasterizeZip, "zip", "", "Write the images into this ZIP archive instead of a directory")
+
+	rootCmd.AddCommand(rasterizeCmd)
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	redactOpts  pdftool.RedactOptions
+	redactAreas []string
+)
+
+var redactCmd = &cobra.Command{
+	Use:   "redact [input.pdf] [output.pdf]",
+	Short: "Permanently remove text and areas from a PDF",
+	Long: `Permanently remove sensitive text and areas from a PDF and cover them with
+black boxes. The text is deleted from the page content, not just hidden, so it
+cannot be copied, searched or extracted afterwards. Images under a redacted
+area are blacked out pixel by pixel.
+
+--pattern is a regular expression matched against each line of text, e.g.
+'\d{3}-\d{2}-\d{4}' for US social security numbers. --area removes a region
+given as page:x0,y0,x1,y1 in points from the bottom-left corner of the page,
+or with units such as 20mm. Both can be repeated.
+
+Form fields and annotations are flattened first so their text is redacted
+as well. Scanned pages have no text; run ocr on them first. Document
+metadata, bookmarks and attachments are not searched.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputFile := args[0]
+		outputFile := args[1]
+
+		redactOpts.Areas = nil
+		for _, spec := range redactAreas {
+			area, err := pdftool.ParseRedactArea(spec)
+			if err != nil {
+				return err
+			}
+			redactOpts.Areas = append(redactOpts.Areas, area)
+		}
+
+		fmt.Printf("🔄 Redacting PDF: %s -> %s\n", inputFile, outputFile)
+
+		if err := pdftool.RedactPDF(cmd.Context(), inputFile, outputFile, redactOpts); err != nil {
+			return fmt.Errorf("redaction failed: %w", err)
+		}
+
+		fmt.Println("✅ Redaction completed successfully!")
+		return nil
+	},
+}
+
+func init() {
+	redactCmd.Flags().StringArrayVar(&redactOpts.Patterns, "pattern", nil, "Regular expression of text to remove (repeatable)")
+	redactCmd.Flags().StringArrayVar(&redactAreas, "area", nil, "Region to remove as page:x0,y0,x1,y1 (repeatable)")
+	redactCmd.Flags().StringVar(&redactOpts.Pages, "pages", "all", "Pages searched for --pattern, e.g. 1-3,7 or all")
+
+	rootCmd.AddCommand(redactCmd)
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+)
+
+func init() {
+	registerRemote("azblob", newAzblobStore)
+}
+
+// azblobStore reads and writes azblob://container/path URIs in one storage
+// account
+type azblobStore struct {
+	client *azblob.Client
+}
+
+// newAzblobStore returns a Blob Storage store for the account in
+// AZURE_STORAGE_CONNECTION_STRING, e.g. for Azurite, or else for
+// AZURE_STORAGE_ACCOUNT with the default Azure credentials: environment
+// variables, workload or managed identity, or the az login.
+func newAzblobStore(ctx context.Context) (remoteStore, error) {
+	if conn := os.Getenv("AZURE_STORAGE_CONNECTION_STRING"); conn != "" {
+		client, err := azblob.NewClientFromConnectionString(conn, nil)
+		if err != nil {
+			return nil, err
+		}
+		return &azblobStore{client: client}, nil
+	}
+
+	account := os.Getenv("AZURE_STORAGE_ACCOUNT")
+	if account == "" {
+		return nil, errors.New("set AZURE_STORAGE_ACCOUNT or AZURE_STORAGE_CONNECTION_STRING to the storage account")
+	}
+	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		return nil, err
+	}
+	client, err := azblob.NewClient(fmt.Sprintf("https://%s.blob.core.windows.net/", account), cred, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &azblobStore{client: client}, nil
+}
+
+// azblobName returns the blob name of a URI
+func azblobName(u *url.URL) string {
+	return strings.TrimPrefix(u.Path, "/")
+}
+
+// azblobError shortens the multi-line response errors of the SDK to their
+// status and error code, e.g. "404 Not Found: BlobNotFound"
+func azblobError(err error) error {
+	var respErr *azcore.ResponseError
+	if errors.As(err, &respErr) && respErr.RawResponse != nil {
+		return fmt.Errorf("%s: %s", respErr.RawResponse.Status, respErr.ErrorCode)
+	}
+	return err
+}
+
+func (s *azblobStore) Open(ctx context.Context, u *url.URL) (io.ReadCloser, error) {
+	resp, err := s.client.DownloadStream(ctx, u.Host, azblobName(u), nil)
+	if err != nil {
+		return nil, azblobError(err)
+	}
+	return resp.Body, nil
+}
+
+func (s *azblobStore) Upload(ctx context.Context, u *url.URL, file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	contentType := remoteContentType(u)
+	_, err = s.client.UploadFile(ctx, u.Host, azblobName(u), f, &azblob.UploadFileOptions{
+		HTTPHeaders: &blob.HTTPHeaders{BlobContentType: &contentType},
+	})
+	return azblobError(err)
+}
+
+func (s *azblobStore) List(ctx context.Context, u *url.URL) ([]*url.URL, error) {
+	var objects []*url.URL
+	prefix := azblobName(u)
+	pager := s.client.ServiceClient().NewContainerClient(u.Host).NewListBlobsHierarchyPager("/", &container.ListBlobsHierarchyOptions{
+		Prefix: &prefix,
+	})
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, azblobError(err)
+		}
+		for _, item := range page.Segment.BlobItems {
+			if item.Name == nil || strings.HasSuffix(*item.Name, "/") {
+				continue // Folder marker
+			}
+			objects = append(objects, &url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/" + *item.Name})
+		}
+	}
+	return objects, nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
+)
+
+func init() {
+	registerRemote("gs", newGCSStore)
+}
+
+// gcsStore reads and writes gs://bucket/object URIs with the Cloud Storage
+// client
+type gcsStore struct {
+	client *storage.Client
+}
+
+// newGCSStore returns a Cloud Storage store with the Application Default
+// Credentials: GOOGLE_APPLICATION_CREDENTIALS, the gcloud login or the
+// metadata server. STORAGE_EMULATOR_HOST selects an emulator.
+func newGCSStore(ctx context.Context) (remoteStore, error) {
+	client, err := storage.NewClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &gcsStore{client: client}, nil
+}
+
+// gcsObject returns the object handle of a URI
+func (s *gcsStore) gcsObject(u *url.URL) *storage.ObjectHandle {
+	return s.client.Bucket(u.Host).Object(strings.TrimPrefix(u.Path, "/"))
+}
+
+func (s *gcsStore) Open(ctx context.Context, u *url.URL) (io.ReadCloser, error) {
+	return s.gcsObject(u).NewReader(ctx)
+}
+
+func (s *gcsStore) Upload(ctx context.Context, u *url.URL, file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// Canceling abandons the upload, so failures leave no partial object
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	w := s.gcsObject(u).NewWriter(ctx)
+	w.ContentType = remoteContentType(u)
+	if _, err := io.Copy(w, f); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+func (s *gcsStore) List(ctx context.Context, u *url.URL) ([]*url.URL, error) {
+	var objects []*url.URL
+	it := s.client.Bucket(u.Host).Objects(ctx, &storage.Query{
+		Prefix:    strings.TrimPrefix(u.Path, "/"),
+		Delimiter: "/",
+	})
+	for {
+		attrs, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			return objects, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if attrs.Prefix != "" || strings.HasSuffix(attrs.Name, "/") {
+			continue // Subfolder or folder marker
+		}
+		objects = append(objects, &url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/" + attrs.Name})
+	}
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"os/user"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+func init() {
+	registerRemote("sftp", newSFTPStore)
+}
+
+// sftpStore reads and writes sftp://user@host/path URIs, keeping one
+// connection per user and server
+type sftpStore struct {
+	mu      sync.Mutex
+	clients map[string]*sftp.Client
+}
+
+func newSFTPStore(ctx context.Context) (remoteStore, error) {
+	return &sftpStore{clients: map[string]*sftp.Client{}}, nil
+}
+
+// sftpPath returns the file path of a URI: absolute, or relative to the
+// login folder when it starts with /~/ as in sftp://scanner@host/~/inbox/
+func sftpPath(u *url.URL) string {
+	if rest, ok := strings.CutPrefix(u.Path, "/~/"); ok {
+		return rest
+	}
+	return u.Path
+}
+
+// client returns the connection for a URI's user and server, connecting
+// when there is none yet or the last one was closed
+func (s *sftpStore) client(ctx context.Context, u *url.URL) (*sftp.Client, error) {
+	addr := u.Host
+	if u.Port() == "" {
+		addr = net.JoinHostPort(u.Hostname(), "22")
+	}
+	login := u.User.Username()
+	if login == "" {
+		current, err := user.Current()
+		if err != nil {
+			return nil, err
+		}
+		login = current.Username
+	}
+	key := login + "@" + addr
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if client, ok := s.clients[key]; ok {
+		return client, nil
+	}
+
+	config, err := sshConfig(u, login)
+	if err != nil {
+		return nil, err
+	}
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, addr, config)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	sshClient := ssh.NewClient(sshConn, chans, reqs)
+	client, err := sftp.NewClient(sshClient)
+	if err != nil {
+		sshClient.Close()
+		return nil, err
+	}
+	s.clients[key] = client
+
+	// Forget the connection once the server closes it, e.g. when idle
+	go func() {
+		client.Wait()
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.clients[key] == client {
+			delete(s.clients, key)
+		}
+	}()
+	return client, nil
+}
+
+// sshConfig returns the SSH settings to log in as login. Servers must be
+// listed in ~/.ssh/known_hosts, or the file in PDF_TOOL_SFTP_KNOWN_HOSTS.
+// Logins try the password of the URI or PDF_TOOL_SFTP_PASSWORD, the key in
+// PDF_TOOL_SFTP_KEY or else the unencrypted default keys in ~/.ssh, and the
+// keys of the SSH agent.
+func sshConfig(u *url.URL, login string) (*ssh.ClientConfig, error) {
+	home, _ := os.UserHomeDir()
+	knownHosts := os.Getenv("PDF_TOOL_SFTP_KNOWN_HOSTS")
+	if knownHosts == "" {
+		knownHosts = filepath.Join(home, ".ssh", "known_hosts")
+	}
+	hostKeys, err := knownhosts.New(knownHosts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load known hosts (add the server with ssh-keyscan): %w", err)
+	}
+	config := &ssh.ClientConfig{
+		User:            login,
+		HostKeyCallback: hostKeys,
+		Timeout:         30 * time.Second,
+	}
+
+	password, ok := u.User.Password()
+	if !ok {
+		password = os.Getenv("PDF_TOOL_SFTP_PASSWORD")
+	}
+	if password != "" {
+		config.Auth = append(config.Auth, ssh.Password(password))
+	}
+
+	var signers []ssh.Signer
+	if keyFile := os.Getenv("PDF_TOOL_SFTP_KEY"); keyFile != "" {
+		data, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, err
+		}
+		signer, err := ssh.ParsePrivateKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read key %s: %w", keyFile, err)
+		}
+		signers = append(signers, signer)
+	} else {
+		for _, name := range []string{"id_ed25519", "id_ecdsa", "id_rsa"} {
+			data, err := os.ReadFile(filepath.Join(home, ".ssh", name))
+			if err != nil {
+				continue
+			}
+			// Keys with a passphrase are left to the agent
+			if signer, err := ssh.ParsePrivateKey(data); err == nil {
+				signers = append(signers, signer)
+			}
+		}
+	}
+	if socket := os.Getenv("SSH_AUTH_SOCK"); socket != "" {
+		if conn, err := net.Dial("unix", socket); err == nil {
+			defer conn.Close()
+			if agentSigners, err := agent.NewClient(conn).Signers(); err == nil {
+				signers = append(signers, agentSigners...)
+			}
+		}
+	}
+	if len(signers) > 0 {
+		config.Auth = append(config.Auth, ssh.PublicKeys(signers...))
+	}
+	return config, nil
+}
+
+func (s *sftpStore) Open(ctx context.Context, u *url.URL) (io.ReadCloser, error) {
+	client, err := s.client(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	return client.Open(sftpPath(u))
+}
+
+func (s *sftpStore) Upload(ctx context.Context, u *url.URL, file string) error {
+	client, err := s.client(ctx, u)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// Written under a temporary name first, so that programs watching the
+	// folder never pick up half-written files
+	target := sftpPath(u)
+	partial := target + ".part"
+	if err := client.MkdirAll(path.Dir(target)); err != nil {
+		return err
+	}
+	w, err := client.Create(partial)
+	if err != nil {
+		return err
+	}
+	_, err = w.ReadFrom(f)
+	if closeErr := w.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		if _, ok := client.HasExtension("posix-rename@openssh.com"); ok {
+			err = client.PosixRename(partial, target)
+		} else {
+			// Plain renames fail when the target exists
+			client.Remove(target)
+			err = client.Rename(partial, target)
+		}
+	}
+	if err != nil {
+		client.Remove(partial)
+		return err
+	}
+	return nil
+}
+
+func (s *sftpStore) List(ctx context.Context, u *url.URL) ([]*url.URL, error) {
+	client, err := s.client(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := client.ReadDir(sftpPath(u))
+	if err != nil {
+		return nil, err
+	}
+
+	var files []*url.URL
+	for _, entry := range entries {
+		if !entry.Mode().IsRegular() {
+			continue
+		}
+		file := *u
+		file.Path, file.RawPath = path.Join(u.Path, entry.Name()), ""
+		files = append(files, &file)
+	}
+	return files, nil
+}
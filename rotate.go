@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	rotateAngle int
+	rotatePages string
+	rotateAll   bool
+)
+
+var rotateCmd = &cobra.Command{
+	Use:   "rotate [input.pdf] [output.pdf]",
+	Short: "Rotate PDF pages",
+	Long: `Rotate selected PDF pages clockwise by 90, 180 or 270 degrees (negative
+angles rotate counter-clockwise).
+
+Select pages with --pages 2,4-6 or rotate every page with --all.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputFile := args[0]
+		outputFile := args[1]
+
+		if rotatePages == "" && !rotateAll {
+			return fmt.Errorf("select pages with --pages or use --all")
+		}
+		if rotatePages != "" && rotateAll {
+			return fmt.Errorf("--pages and --all cannot be combined")
+		}
+
+		fmt.Printf("🔄 Rotating pages: %s -> %s (%d°)\n", inputFile, outputFile, rotateAngle)
+
+		if err := pdftool.RotatePages(cmd.Context(), inputFile, outputFile, rotateAngle, rotatePages); err != nil {
+			return fmt.Errorf("rotation failed: %w", err)
+		}
+
+		fmt.Println("✅ Page rotation completed successfully!")
+		return nil
+	},
+}
+
+func init() {
+	rotateCmd.Flags().IntVar(&rotateAngle, "angle", 90, "Clockwise rotation in degrees: 90, 180 or 270")
+	rotateCmd.Flags().StringVar(&rotatePages, "pages", "", "Pages to rotate, e.g. 2,4-6")
+	rotateCmd.Flags().BoolVar(&rotateAll, "all", false, "Rotate all pages")
+
+	rootCmd.AddCommand(rotateCmd)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+
+	"github.com/spf13/cobra"
+)
+
+var sanitizeOpts pdftool.SanitizeOptions
+
+var sanitizeCmd = &cobra.Command{
+	Use:   "sanitize [input.pdf] [output.pdf]",
+	Short: "Remove JavaScript, actions and embedded files from a PDF",
+	Long: `Remove content that can run code or reach outside the document, so that PDFs
+from untrusted sources can be let into internal systems:
+  - JavaScript and XFA forms
+  - actions run automatically on opening the document or a page
+  - actions that launch programs, open web pages or other files, or submit
+    form data
+  - embedded files and attachments
+  - media annotations and references to external files
+
+Links within the document keep working. Use --keep-links to keep links to
+web pages.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputFile := args[0]
+		outputFile := args[1]
+
+		fmt.Printf("🔄 Sanitizing PDF: %s -> %s\n", inputFile, outputFile)
+
+		if err := pdftool.SanitizePDF(cmd.Context(), inputFile, outputFile, sanitizeOpts); err != nil {
+			return fmt.Errorf("sanitize failed: %w", err)
+		}
+
+		fmt.Println("✅ Sanitize completed successfully!")
+		return nil
+	},
+}
+
+func init() {
+	sanitizeCmd.Flags().BoolVar(&sanitizeOpts.KeepLinks, "keep-links", false, "Keep links to web pages")
+
+	rootCmd.AddCommand(sanitizeCmd)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+
+	"github.com/spf13/cobra"
+)
+
+var scaleOpts pdftool.ScaleOptions
+
+var scaleCmd = &cobra.Command{
+	Use:   "scale [input.pdf] [output.pdf]",
+	Short: "Resize PDF pages and their content",
+	Long: `Resize pages and their content to a paper size such as A4 or Letter, for
+example to normalize a merged bundle so that it prints consistently, or by a
+factor with --scale.
+
+Content is scaled to fit and centered. Landscape pages become landscape pages
+of the target size.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputFile := args[0]
+		outputFile := args[1]
+
+		fmt.Printf("🔄 Scaling PDF: %s -> %s\n", inputFile, outputFile)
+
+		if err := pdftool.ScalePages(cmd.Context(), inputFile, outputFile, scaleOpts); err != nil {
+			return fmt.Errorf("scale failed: %w", err)
+		}
+
+		fmt.Println("✅ Scale completed successfully!")
+		return nil
+	},
+}
+
+func init() {
+	scaleCmd.Flags().StringVar(&scaleOpts.To, "to", "", "Target paper size, e.g. A4, A5, Letter, Legal")
+	scaleCmd.Flags().Float64Var(&scaleOpts.Scale, "scale", 0, "Scale factor instead of a paper size, e.g. 0.5")
+	scaleCmd.Flags().StringVar(&scaleOpts.Pages, "pages", "all", "Pages to scale, e.g. 1-3,7 or all")
+	scaleCmd.MarkFlagsMutuallyExclusive("to", "scale")
+
+	rootCmd.AddCommand(scaleCmd)
+}
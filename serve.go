@@ -0,0 +1,690 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveListen     string
+	serveGRPC       string
+	serveMaxUpload  int64
+	serveWorkers    int
+	serveQueue      string
+	serveUI         bool
+	serveWebhook    webhookFlags
+	servePublicURL  string
+	serveMaxBacklog int
+	serveAllowed    []string
+	serveTimeout    time.Duration
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run an HTTP or gRPC API for compressing, converting and merging PDFs",
+	Long: `Serve the main commands over HTTP, so a team can run pdf-tool as a small
+internal PDF service instead of installing it everywhere. Files are sent as
+multipart/form-data and the resulting PDF is streamed back. Colleagues who
+prefer a browser open the server's address for a page that compresses,
+converts and merges dropped files; --ui=false turns it off.
+
+Endpoints:
+  POST /compress  file=<pdf>, quality (1-100, default 50), engine, dpi,
+                  strip_metadata
+  POST /convert   files=<image>... and page_size, landscape, fit, dpi,
+                  margin, grayscale, nup, caption, pdfa, title, author,
+                  subject, keywords
+  POST /merge     files=<pdf>... (two or more), bookmarks, toc
+
+Failures are answered with a JSON body like {"error": "..."}: 400 for bad
+requests, 413 for uploads over --max-upload, 422 for invalid or encrypted
+PDFs, 501 for features needing a program that is not installed, and 504
+for requests processed longer than --request-timeout.
+
+With --queue, PDFs and images can also be handed in as jobs that are
+processed in the background, survive restarts and are retried when they
+fail:
+  POST /jobs/compress   file=<pdf>, quality, engine; answered with 202 and
+                        the job as JSON
+  POST /jobs/convert    file=<image>
+                        Instead of file, both take input=<uri> of a remote
+                        object like s3://bucket/key, gs://bucket/object,
+                        azblob://container/path, sftp://user@host/path or
+                        ftp://user@host/path, and with output=<uri> the
+                        result is written there. The server's credentials
+                        are used, so only URIs under an --allow-remote
+                        prefix are accepted
+  GET  /jobs            all jobs
+  GET  /jobs/{id}       the job's status
+  GET  /jobs/{id}/output
+                        the result once the job is done
+
+GET /healthz answers 200 while the server runs, and GET /readyz 200 only
+while Ghostscript is installed (unless in pure-Go mode), temporary files can
+be written and the queue holds at most --max-backlog waiting jobs; 503
+otherwise. Both are meant for liveness and readiness probes.
+
+GET /metrics reports Prometheus metrics: jobs processed by operation and
+API, failures by reason, durations by engine, bytes saved and the queue's
+backlog.
+
+--webhook posts each finished job as JSON, with its download URL under
+--public-url, so other systems are notified without polling.
+
+With --grpc the same operations are also offered as the gRPC service
+pdftool.v1.PDFTool, which streams files in chunks in both directions; the
+Go client is in github.com/ansrivas/pdftool/pkg/pdftoolpb.
+
+Example:
+  curl -F file=@large.pdf -F quality=40 http://localhost:8080/compress -o small.pdf`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if serveWorkers < 1 {
+			serveWorkers = runtime.NumCPU()
+		}
+
+		if serveListen == "" && serveGRPC == "" {
+			return fmt.Errorf("nothing to serve: set --listen or --grpc")
+		}
+
+		allowed, err := parseAllowedRemotes(serveAllowed)
+		if err != nil {
+			return err
+		}
+		s := &server{
+			maxUpload:  serveMaxUpload << 20,
+			slots:      make(chan struct{}, serveWorkers),
+			ui:         serveUI,
+			maxBacklog: serveMaxBacklog,
+			allowed:    allowed,
+			timeout:    serveTimeout,
+		}
+		if serveQueue != "" {
+			if s.queue, err = openQueue(serveQueue); err != nil {
+				return err
+			}
+		} else if serveWebhook.url != "" {
+			return fmt.Errorf("--webhook requires --queue")
+		}
+		publicURL := servePublicURL
+		if publicURL == "" && serveListen != "" {
+			publicURL = "http://" + serveListen
+			if strings.HasPrefix(serveListen, ":") {
+				publicURL = "http://localhost" + serveListen
+			}
+		}
+		s.metrics = newMetrics(s)
+		hook := newWebhook(serveWebhook.url, serveWebhook.secret, publicURL)
+		httpSrv := &http.Server{
+			Addr:              serveListen,
+			Handler:           s.routes(),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		grpcSrv := s.newGRPCServer()
+
+		errs := make(chan error, 3)
+		if serveListen != "" {
+			fmt.Printf("🌐 Serving HTTP on %s (%d workers)\n", serveListen, serveWorkers)
+			go func() {
+				if err := httpSrv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+					errs <- fmt.Errorf("HTTP server failed: %w", err)
+				}
+			}()
+		}
+		if serveGRPC != "" {
+			lis, err := net.Listen("tcp", serveGRPC)
+			if err != nil {
+				return fmt.Errorf("gRPC server failed: %w", err)
+			}
+			fmt.Printf("🌐 Serving gRPC on %s (%d workers)\n", serveGRPC, serveWorkers)
+			go func() {
+				if err := grpcSrv.Serve(lis); err != nil {
+					errs <- fmt.Errorf("gRPC server failed: %w", err)
+				}
+			}()
+		}
+
+		queueCtx, stopQueue := context.WithCancel(context.Background())
+		defer stopQueue()
+		queueDone := make(chan struct{})
+		go func() {
+			defer close(queueDone)
+			if s.queue == nil {
+				return
+			}
+			fmt.Printf("📥 Processing jobs from %s\n", serveQueue)
+			done := func(job *queueJob, start time.Time, result *pdftool.Result, err error) {
+				logQueueJob(job)
+				hook.notify(job)
+				if job.Status != jobQueued {
+					s.metrics.observe(job.Kind, "queue", start, result, err)
+				}
+			}
+			if err := runQueue(queueCtx, s.queue, serveWorkers, done); err != nil {
+				errs <- fmt.Errorf("job queue failed: %w", err)
+			}
+		}()
+
+		// Finish running requests on Ctrl+C before exiting; interrupted jobs
+		// are queued again
+		var serveErr error
+		select {
+		case serveErr = <-errs:
+		case <-cmd.Context().Done():
+		}
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		go func() {
+			<-shutdownCtx.Done()
+			grpcSrv.Stop()
+		}()
+		grpcSrv.GracefulStop()
+		if err := httpSrv.Shutdown(shutdownCtx); err != nil && serveErr == nil {
+			serveErr = fmt.Errorf("shutdown failed: %w", err)
+		}
+		stopQueue()
+		<-queueDone
+		hook.wait()
+		if serveErr != nil {
+			return serveErr
+		}
+
+		fmt.Println("✅ Server stopped")
+		return nil
+	},
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveListen, "listen", ":8080", "Address to serve the HTTP API on; empty to disable it")
+	serveCmd.Flags().StringVar(&serveGRPC, "grpc", "", "Address to serve the gRPC API on, e.g. :9090")
+	serveCmd.Flags().Int64Var(&serveMaxUpload, "max-upload", 200, "Maximum size of a request's uploads in MB")
+	serveCmd.Flags().IntVar(&serveWorkers, "workers", 0, "Requests processed at once; others wait (default: number of CPUs)")
+	serveCmd.Flags().DurationVar(&serveTimeout, "request-timeout", 0, "Abort requests processed longer than this with 504, e.g. 5m (default: no limit)")
+	serveWebhook.register(serveCmd)
+	serveCmd.Flags().StringVar(&servePublicURL, "public-url", "", "Base URL of the server in webhook download URLs (default: from --listen)")
+	serveCmd.Flags().IntVar(&serveMaxBacklog, "max-backlog", 100, "Queued jobs above which /readyz reports the server as not ready; 0 for no limit")
+	serveCmd.Flags().BoolVar(&serveUI, "ui", true, "Serve the browser UI at /")
+	serveCmd.Flags().StringArrayVar(&serveAllowed, "allow-remote", nil, "Remote prefix that jobs may read and write with the server's credentials, e.g. s3://scans/inbox/ or sftp://files.example.com/srv/ (repeatable); other URIs are refused")
+	serveCmd.Flags().StringVar(&serveQueue, "queue", os.Getenv("PDF_TOOL_QUEUE"), "Folder of a job queue for the /jobs endpoints (env: PDF_TOOL_QUEUE)")
+
+	rootCmd.AddCommand(serveCmd)
+}
+
+// server handles the HTTP API of the serve command
+type server struct {
+	maxUpload  int64         // Bytes
+	slots      chan struct{} // Limits the requests processed at once
+	queue      *jobQueue     // Of the /jobs endpoints; nil without --queue
+	ui         bool          // Serve the browser UI
+	metrics    *metrics
+	maxBacklog int           // Queued jobs above which /readyz fails; 0 for no limit
+	allowed    []*url.URL    // Remote prefixes that jobs may read and write
+	timeout    time.Duration // Processing time allowed per request; 0 for no limit
+}
+
+// routes returns the handler for all endpoints
+func (s *server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("POST /compress", s.handle(s.compress))
+	mux.Handle("POST /convert", s.handle(s.convert))
+	mux.Handle("POST /merge", s.handle(s.merge))
+	mux.Handle("GET /metrics", s.metrics.handler())
+	mux.HandleFunc("GET /healthz", s.healthz)
+	mux.HandleFunc("GET /readyz", s.readyz)
+	if s.ui {
+		mux.Handle("GET /{$}", webUI())
+	}
+	if s.queue != nil {
+		mux.Handle("POST /jobs/compress", s.handleQuick(s.addCompressJob))
+		mux.Handle("POST /jobs/convert", s.handleQuick(s.addConvertJob))
+		mux.Handle("GET /jobs", s.handleQuick(s.listJobs))
+		mux.Handle("GET /jobs/{id}", s.handleQuick(s.getJob))
+		mux.Handle("GET /jobs/{id}/output", s.handleQuick(s.jobOutput))
+	}
+	return mux
+}
+
+// requestError is a problem with a request, reported with an HTTP status
+type requestError struct {
+	status int
+	err    error
+}
+
+func (e *requestError) Error() string { return e.err.Error() }
+func (e *requestError) Unwrap() error { return e.err }
+
+// badRequest returns a requestError with status 400
+func badRequest(format string, args ...any) error {
+	return &requestError{status: http.StatusBadRequest, err: fmt.Errorf(format, args...)}
+}
+
+// statusCodes maps failure causes to HTTP statuses; other errors are
+// reported as 500
+var statusCodes = []struct {
+	err    error
+	status int
+}{
+	{pdftool.ErrInvalidPDF, http.StatusUnprocessableEntity},
+	{pdftool.ErrEncrypted, http.StatusUnprocessableEntity},
+	{pdftool.ErrOutputLarger, http.StatusUnprocessableEntity},
+	{pdftool.ErrGhostscriptNotFound, http.StatusNotImplemented},
+	{pdftool.ErrPureGo, http.StatusNotImplemented},
+	{pdftool.ErrInvalidOption, http.StatusBadRequest},
+	{pdftool.ErrTimeout, http.StatusGatewayTimeout},
+	{context.DeadlineExceeded, http.StatusGatewayTimeout},
+	{errJobNotFound, http.StatusNotFound},
+}
+
+// statusCode returns the HTTP status for an error
+func statusCode(err error) int {
+	var reqErr *requestError
+	if errors.As(err, &reqErr) {
+		return reqErr.status
+	}
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return http.StatusRequestEntityTooLarge
+	}
+	for _, e := range statusCodes {
+		if errors.Is(err, e.err) {
+			return e.status
+		}
+	}
+	return http.StatusInternalServerError
+}
+
+// uploadError reports errors of operations that mostly fail because of
+// their uploads, like unreadable images, with status 422 unless their
+// cause has a status of its own
+func uploadError(err error) error {
+	if statusCode(err) == http.StatusInternalServerError {
+		return &requestError{status: http.StatusUnprocessableEntity, err: err}
+	}
+	return err
+}
+
+// statusWriter records the status of a response
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// handle wraps an endpoint: it limits the upload size and the requests
+// processed at once, answers errors as JSON and logs every request
+func (s *server) handle(fn func(w http.ResponseWriter, r *http.Request) error) http.Handler {
+	return s.wrap(fn, true)
+}
+
+// handleQuick wraps an endpoint like handle, but without waiting for a
+// processing slot, for endpoints that do not process PDFs themselves
+func (s *server) handleQuick(fn func(w http.ResponseWriter, r *http.Request) error) http.Handler {
+	return s.wrap(fn, false)
+}
+
+// wrap implements handle and handleQuick
+func (s *server) wrap(fn func(w http.ResponseWriter, r *http.Request) error, limited bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		sw := &statusWriter{ResponseWriter: w}
+		r.Body = http.MaxBytesReader(w, r.Body, s.maxUpload)
+
+		var err error
+		if !limited {
+			err = fn(sw, r)
+		} else if err = s.acquire(r.Context()); err == nil {
+			if s.timeout > 0 {
+				// The deadline starts once the request is processed, not
+				// while it waits for a slot
+				ctx, cancel := context.WithTimeout(r.Context(), s.timeout)
+				defer cancel()
+				r = r.WithContext(ctx)
+			}
+			err = fn(sw, r)
+			<-s.slots
+		}
+		if r.MultipartForm != nil {
+			r.MultipartForm.RemoveAll()
+		}
+
+		if err != nil && sw.status == 0 {
+			// Headers are still unsent, so the error can replace the PDF
+			sw.Header().Del("Content-Disposition")
+			sw.Header().Set("Content-Type", "application/json")
+			sw.WriteHeader(statusCode(err))
+			json.NewEncoder(sw).Encode(map[string]string{"error": err.Error()})
+		}
+
+		msg := fmt.Sprintf("%s %s: %d in %s", r.Method, r.URL.Path, sw.status, time.Since(start).Round(time.Millisecond))
+		if err != nil {
+			rootLogger.Warn(msg + ": " + err.Error())
+		} else {
+			rootLogger.Info(msg)
+		}
+	})
+}
+
+// acquire waits for a free processing slot
+func (s *server) acquire(ctx context.Context) error {
+	select {
+	case s.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// parseForm reads a multipart request, keeping up to 32 MB in memory and
+// the rest in temporary files
+func parseForm(r *http.Request) error {
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return err
+		}
+		return badRequest("invalid multipart form: %v", err)
+	}
+	return nil
+}
+
+// formFiles returns the uploads of a form field, at least min of them
+func formFiles(r *http.Request, field string, min int) ([]*multipart.FileHeader, error) {
+	files := r.MultipartForm.File[field]
+	if len(files) < min {
+		if min == 1 {
+			return nil, badRequest("missing %s upload", field)
+		}
+		return nil, badRequest("at least %d uploads in %s are required", min, field)
+	}
+	return files, nil
+}
+
+// formInt returns an integer form value, or def if it is not set
+func formInt(r *http.Request, field string, def int) (int, error) {
+	v := r.FormValue(field)
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, badRequest("invalid %s: %s", field, v)
+	}
+	return n, nil
+}
+
+// formFloat returns a number form value, or 0 if it is not set
+func formFloat(r *http.Request, field string) (float64, error) {
+	v := r.FormValue(field)
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, badRequest("invalid %s: %s", field, v)
+	}
+	return n, nil
+}
+
+// formBool returns a boolean form value like "true" or "1", or false if it
+// is not set
+func formBool(r *http.Request, field string) (bool, error) {
+	v := r.FormValue(field)
+	if v == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, badRequest("invalid %s: %s", field, v)
+	}
+	return b, nil
+}
+
+// sendPDF streams a PDF file as the response, offered for download under
+// name
+func sendPDF(w http.ResponseWriter, file, name string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Length", strconv.FormatInt(info.Size(), 10))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// pdfName returns the download name for a result made from an upload
+func pdfName(upload, suffix string) string {
+	base := filepath.Base(upload)
+	return base[:len(base)-len(filepath.Ext(base))] + suffix + ".pdf"
+}
+
+// compress handles POST /compress
+func (s *server) compress(w http.ResponseWriter, r *http.Request) (err error) {
+	var result *pdftool.Result
+	start := time.Now()
+	defer func() { s.metrics.observe(jobCompress, "http", start, result, err) }()
+
+	if err := parseForm(r); err != nil {
+		return err
+	}
+	uploads, err := formFiles(r, "file", 1)
+	if err != nil {
+		return err
+	}
+
+	var opts pdftool.CompressOptions
+	if opts.Quality, err = formInt(r, "quality", 50); err != nil {
+		return err
+	}
+	if opts.Quality < 1 || opts.Quality > 100 {
+		return badRequest("quality must be between 1 and 100, got: %d", opts.Quality)
+	}
+	if opts.ImageDPI, err = formInt(r, "dpi", 0); err != nil {
+		return err
+	}
+	if opts.StripMetadata, err = formBool(r, "strip_metadata"); err != nil {
+		return err
+	}
+	opts.Engine = r.FormValue("engine")
+
+	upload, err := uploads[0].Open()
+	if err != nil {
+		return err
+	}
+	defer upload.Close()
+
+	tmpDir, removeTmpDir, err := pdftool.TempDir("", "pdftool-serve-")
+	if err != nil {
+		return err
+	}
+	defer removeTmpDir()
+
+	output, err := os.Create(filepath.Join(tmpDir, "output.pdf"))
+	if err != nil {
+		return err
+	}
+	defer output.Close()
+
+	result, err = pdftool.Compress(r.Context(), upload, output, opts)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("X-Pdf-Tool-Engine", result.Engine)
+	w.Header().Set("X-Pdf-Tool-Input-Size", strconv.FormatInt(result.InputSize, 10))
+	w.Header().Set("X-Pdf-Tool-Output-Size", strconv.FormatInt(result.OutputSize, 10))
+	return sendPDF(w, output.Name(), pdfName(uploads[0].Filename, "_compressed"))
+}
+
+// convert handles POST /convert
+func (s *server) convert(w http.ResponseWriter, r *http.Request) (err error) {
+	start := time.Now()
+	defer func() { s.metrics.observe(jobConvert, "http", start, nil, err) }()
+
+	if err := parseForm(r); err != nil {
+		return err
+	}
+	uploads, err := formFiles(r, "files", 1)
+	if err != nil {
+		return err
+	}
+
+	opts := pdftool.ConvertOptions{
+		PageSize: r.FormValue("page_size"),
+		Fit:      r.FormValue("fit"),
+		Caption:  r.FormValue("caption"),
+		Title:    r.FormValue("title"),
+		Author:   r.FormValue("author"),
+		Subject:  r.FormValue("subject"),
+		Keywords: r.FormValue("keywords"),
+	}
+	if opts.Landscape, err = formBool(r, "landscape"); err != nil {
+		return err
+	}
+	if opts.Grayscale, err = formBool(r, "grayscale"); err != nil {
+		return err
+	}
+	if opts.PDFA, err = formBool(r, "pdfa"); err != nil {
+		return err
+	}
+	if opts.DPI, err = formInt(r, "dpi", 0); err != nil {
+		return err
+	}
+	if opts.Margin, err = formFloat(r, "margin"); err != nil {
+		return err
+	}
+	if nup := r.FormValue("nup"); nup != "" {
+		if opts.NupColumns, opts.NupRows, err = pdftool.ParseGrid(nup); err != nil {
+			return &requestError{status: http.StatusBadRequest, err: err}
+		}
+	}
+
+	inputs := make([]pdftool.ImageSource, len(uploads))
+	for i, upload := range uploads {
+		f, err := upload.Open()
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		inputs[i] = pdftool.ImageSource{Name: upload.Filename, Reader: f}
+	}
+
+	// ConvertImages only writes once the PDF is complete, so errors can
+	// still be reported with their own status
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", pdfName(uploads[0].Filename, "")))
+	if err := pdftool.ConvertImages(r.Context(), inputs, w, opts); err != nil {
+		return uploadError(err)
+	}
+	return nil
+}
+
+// merge handles POST /merge
+func (s *server) merge(w http.ResponseWriter, r *http.Request) (err error) {
+	start := time.Now()
+	defer func() { s.metrics.observe(jobMerge, "http", start, nil, err) }()
+
+	if err := parseForm(r); err != nil {
+		return err
+	}
+	uploads, err := formFiles(r, "files", 2)
+	if err != nil {
+		return err
+	}
+
+	var opts pdftool.MergeOptions
+	if opts.BookmarkPerFile, err = formBool(r, "bookmarks"); err != nil {
+		return err
+	}
+	if opts.TOC, err = formBool(r, "toc"); err != nil {
+		return err
+	}
+
+	tmpDir, removeTmpDir, err := pdftool.TempDir("", "pdftool-serve-")
+	if err != nil {
+		return err
+	}
+	defer removeTmpDir()
+
+	// Keep the upload names, which become the bookmark titles, in numbered
+	// directories so equal names don't collide
+	inputFiles := make([]string, len(uploads))
+	for i, upload := range uploads {
+		dir := filepath.Join(tmpDir, strconv.Itoa(i))
+		if err := os.Mkdir(dir, 0o755); err != nil {
+			return err
+		}
+		name := filepath.Base(upload.Filename)
+		if name == "." || name == string(filepath.Separator) {
+			name = fmt.Sprintf("%d.pdf", i+1)
+		}
+		inputFiles[i] = filepath.Join(dir, name)
+		if err := saveUpload(upload, inputFiles[i]); err != nil {
+			return err
+		}
+	}
+
+	outputFile := filepath.Join(tmpDir, "merged.pdf")
+	if err := pdftool.MergePDFs(r.Context(), inputFiles, outputFile, opts); err != nil {
+		return uploadError(err)
+	}
+	return sendPDF(w, outputFile, "merged.pdf")
+}
+
+// saveUpload copies an upload to a file
+func saveUpload(upload *multipart.FileHeader, file string) error {
+	src, err := upload.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+)
+
+// jobStatus is a queued job as reported by the /jobs endpoints, without the
+// server's file paths
+type jobStatus struct {
+	ID          uint64    `json:"id"`
+	Kind        string    `json:"kind"`
+	Name        string    `json:"name"`
+	Output      string    `json:"output,omitempty"` // Remote URI the result was written to
+	Status      string    `json:"status"`
+	Attempts    int       `json:"attempts"`
+	Error       string    `json:"error,omitempty"`
+	InputSize   int64     `json:"inputSize,omitempty"`
+	OutputSize  int64     `json:"outputSize,omitempty"`
+	Created     time.Time `json:"created"`
+	Updated     time.Time `json:"updated"`
+	NextAttempt time.Time `json:"nextAttempt,omitzero"`
+}
+
+// newJobStatus returns the API view of a job
+func newJobStatus(job *queueJob) jobStatus {
+	status := jobStatus{
+		ID:          job.ID,
+		Kind:        job.Kind,
+		Name:        remoteBase(job.InputFile),
+		Status:      job.Status,
+		Attempts:    job.Attempts,
+		Error:       job.Error,
+		InputSize:   job.InputSize,
+		OutputSize:  job.OutputSize,
+		Created:     job.Created,
+		Updated:     job.Updated,
+		NextAttempt: job.NextAttempt,
+	}
+	if isRemote(job.OutputFile) {
+		status.Output = redactURI(job.OutputFile)
+	}
+	return status
+}
+
+// writeJSON sends a JSON response with a status
+func writeJSON(w http.ResponseWriter, status int, v any) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	return json.NewEncoder(w).Encode(v)
+}
+
+// addJob queues a job for the upload of a request, which is saved in the
+// queue's folder, or for the remote URI in its input field. The result is
+// written to the remote URI in the output field, or else to the queue's
+// folder, named by suffix.
+func (s *server) addJob(w http.ResponseWriter, r *http.Request, job *queueJob, suffix string) error {
+	input, output := r.FormValue("input"), r.FormValue("output")
+	for _, field := range []struct{ name, uri string }{{"input", input}, {"output", output}} {
+		if field.uri == "" {
+			continue
+		}
+		if !isRemote(field.uri) {
+			return badRequest("%s must be a URI like s3://bucket/key or sftp://user@host/path, got: %s", field.name, field.uri)
+		}
+		if !s.remoteAllowed(field.uri) {
+			return badRequest("%s is not under a prefix allowed with --allow-remote: %s", field.name, redactURI(field.uri))
+		}
+	}
+
+	var upload *multipart.FileHeader
+	name := remoteBase(input)
+	if input == "" {
+		uploads, err := formFiles(r, "file", 1)
+		if err != nil {
+			return err
+		}
+		upload = uploads[0]
+		name = filepath.Base(upload.Filename)
+		if name == "." || name == string(filepath.Separator) {
+			return badRequest("upload has no file name")
+		}
+	}
+
+	job.InputFile, job.OutputFile = input, output
+	var dir string
+	removeDir := func() {}
+	if upload != nil || output == "" {
+		var err error
+		if dir, removeDir, err = pdftool.TempDir(s.queue.filesDir(), "job-"); err != nil {
+			return err
+		}
+	}
+	if output == "" {
+		job.OutputFile = filepath.Join(dir, pdfName(name, suffix))
+	}
+	if upload != nil {
+		job.InputFile = filepath.Join(dir, name)
+		job.DeleteInput = true
+		if err := saveUpload(upload, job.InputFile); err != nil {
+			removeDir()
+			return err
+		}
+	}
+
+	job, err := s.queue.Add(job)
+	if err != nil {
+		removeDir()
+		return err
+	}
+	pdftool.KeepTempDir(dir) // The queue removes it with the job
+	w.Header().Set("Location", fmt.Sprintf("/jobs/%d", job.ID))
+	return writeJSON(w, http.StatusAccepted, newJobStatus(job))
+}
+
+// parseAllowedRemotes parses the prefixes of --allow-remote
+func parseAllowedRemotes(prefixes []string) ([]*url.URL, error) {
+	allowed := make([]*url.URL, len(prefixes))
+	for i, prefix := range prefixes {
+		u, ok := remoteURL(prefix)
+		if !ok || u.Host == "" {
+			return nil, fmt.Errorf("invalid --allow-remote %q: want a URI like s3://bucket/ or sftp://host/path/", prefix)
+		}
+		allowed[i] = u
+	}
+	return allowed, nil
+}
+
+// remoteAllowed reports whether a remote URI is under one of the allowed
+// prefixes: the same scheme and bucket or host, the prefix's user if it has
+// one, and a path inside the prefix's. Paths with .. are never allowed, as
+// servers like SFTP would resolve them.
+func (s *server) remoteAllowed(uri string) bool {
+	u, ok := remoteURL(uri)
+	if !ok || slices.Contains(strings.Split(u.Path, "/"), "..") {
+		return false
+	}
+	for _, prefix := range s.allowed {
+		if prefix.Scheme != u.Scheme || !strings.EqualFold(prefix.Host, u.Host) {
+			continue
+		}
+		if prefix.User != nil && prefix.User.Username() != u.User.Username() {
+			continue
+		}
+		dir := strings.TrimSuffix(prefix.Path, "/") + "/"
+		if dir == "/" || u.Path == prefix.Path || strings.HasPrefix(u.Path, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// addCompressJob handles POST /jobs/compress
+func (s *server) addCompressJob(w http.ResponseWriter, r *http.Request) error {
+	if err := parseForm(r); err != nil {
+		return err
+	}
+	quality, err := formInt(r, "quality", 50)
+	if err != nil {
+		return err
+	}
+	if quality < 1 || quality > 100 {
+		return badRequest("quality must be between 1 and 100, got: %d", quality)
+	}
+	job := &queueJob{Kind: jobCompress, Quality: quality, Engine: r.FormValue("engine")}
+	return s.addJob(w, r, job, "_compressed")
+}
+
+// addConvertJob handles POST /jobs/convert
+func (s *server) addConvertJob(w http.ResponseWriter, r *http.Request) error {
+	if err := parseForm(r); err != nil {
+		return err
+	}
+	return s.addJob(w, r, &queueJob{Kind: jobConvert}, "")
+}
+
+// listJobs handles GET /jobs
+func (s *server) listJobs(w http.ResponseWriter, r *http.Request) error {
+	jobs, err := s.queue.List()
+	if err != nil {
+		return err
+	}
+	statuses := make([]jobStatus, len(jobs))
+	for i, job := range jobs {
+		statuses[i] = newJobStatus(job)
+	}
+	return writeJSON(w, http.StatusOK, statuses)
+}
+
+// pathJob returns the job named by the id in the request path
+func (s *server) pathJob(r *http.Request) (*queueJob, error) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	if err != nil {
+		return nil, badRequest("invalid job id: %s", r.PathValue("id"))
+	}
+	return s.queue.Get(id)
+}
+
+// getJob handles GET /jobs/{id}
+func (s *server) getJob(w http.ResponseWriter, r *http.Request) error {
+	job, err := s.pathJob(r)
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, newJobStatus(job))
+}
+
+// jobOutput handles GET /jobs/{id}/output
+func (s *server) jobOutput(w http.ResponseWriter, r *http.Request) error {
+	job, err := s.pathJob(r)
+	if err != nil {
+		return err
+	}
+	if job.Status != jobDone {
+		return &requestError{status: http.StatusConflict, err: fmt.Errorf("job %d is %s", job.ID, job.Status)}
+	}
+	if isRemote(job.OutputFile) {
+		return &requestError{status: http.StatusConflict, err: fmt.Errorf("job %d wrote its result to %s", job.ID, redactURI(job.OutputFile))}
+	}
+	return sendPDF(w, job.OutputFile, filepath.Base(job.OutputFile))
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+
+	"github.com/spf13/cobra"
+)
+
+var signOpts pdftool.SignOptions
+
+var signCmd = &cobra.Command{
+	Use:   "sign [input.pdf] [output.pdf]",
+	Short: "Digitally sign a PDF with a PKCS#12 certificate",
+	Long: `Sign a PDF with the certificate and private key of a PKCS#12 (.p12/.pfx)
+file, producing a PAdES baseline signature. The signature is appended as an
+incremental update, so existing signatures stay valid.
+
+The signature is invisible unless --page or --rect is given; --rect takes the
+area of the visible signature as x0,y0,x1,y1 from the bottom-left corner of the
+page, in points unless the values end in mm, cm or in.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputFile := args[0]
+		outputFile := args[1]
+
+		fmt.Printf("🔄 Signing PDF: %s -> %s\n", inputFile, outputFile)
+
+		if err := pdftool.SignPDF(cmd.Context(), inputFile, outputFile, signOpts); err != nil {
+			return fmt.Errorf("signing failed: %w", err)
+		}
+
+		fmt.Println("✅ Signing completed successfully!")
+		return nil
+	},
+}
+
+func init() {
+	signCmd.Flags().StringVar(&signOpts.PKCS12, "pkcs12", "", "PKCS#12 file with the certificate and private key (required)")
+	signCmd.Flags().StringVar(&signOpts.Password, "password", "", "Password of the PKCS#12 file")
+	signCmd.Flags().StringVar(&signOpts.Reason, "reason", "", "Reason for signing, e.g. Approved")
+	signCmd.Flags().StringVar(&signOpts.Location, "location", "", "Place of signing")
+	signCmd.Flags().StringVar(&signOpts.Contact, "contact", "", "Contact information of the signer")
+	signCmd.Flags().IntVar(&signOpts.Page, "page", 0, "Page of a visible signature (default: invisible)")
+	signCmd.Flags().StringVar(&signOpts.Rect, "rect", "", "Area of a visible signature as x0,y0,x1,y1 (default 36,36,252,96)")
+	signCmd.MarkFlagRequired("pkcs12")
+
+	rootCmd.AddCommand(signCmd)
+}
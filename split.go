@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+
+	"github.com/spf13/cobra"
+)
+
+var splitOpts pdftool.SplitOptions
+
+var (
+	splitMaxSize string
+	splitZip     string
+)
+
+var splitCmd = &cobra.Command{
+	Use:   "split [input.pdf] [output-dir]",
+	Short: "Split a PDF into several files",
+	Long: `Split a PDF into several files, named after the input and their pages.
+
+  --pages 1-3,7,10-   one file per range ("10-" runs to the last page)
+  --every 5           consecutive files of 5 pages each
+  --max-size 10MB     consecutive files each under the size limit, e.g. for
+                      email attachments
+
+Without either, every page becomes its own file.
+
+With --zip parts.zip instead of an output directory, the files are written
+straight into a ZIP archive.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputFile := args[0]
+		outputDir, err := outputDirOrZip(args, splitZip)
+		if err != nil {
+			return err
+		}
+
+		if splitMaxSize != "" {
+			size, err := pdftool.ParseSize(splitMaxSize)
+			if err != nil {
+				return err
+			}
+			splitOpts.MaxSize = size
+		}
+
+		var files []string
+		if splitZip != "" {
+			fmt.Printf("🔄 Splitting PDF: %s -> %s\n", inputFile, splitZip)
+			files, err = pdftool.SplitPDFToZip(cmd.Context(), inputFile, splitZip, splitOpts)
+		} else {
+			fmt.Printf("🔄 Splitting PDF: %s -> %s\n", inputFile, outputDir)
+			files, err = pdftool.SplitPDF(cmd.Context(), inputFile, outputDir, splitOpts)
+		}
+		if err != nil {
+			return fmt.Errorf("split failed: %w", err)
+		}
+
+		for _, file := range files {
+			fmt.Printf("   %s\n", file)
+		}
+
+		fmt.Printf("✅ Split into %d files successfully!\n", len(files))
+		return nil
+	},
+}
+
+func init() {
+	splitCmd.Flags().StringVar(&splitOpts.Pages, "pages", "", "Page ranges, one output file each, e.g. 1-3,7,10-")
+	splitCmd.Flags().IntVar(&splitOpts.Every, "every", 0, "Number of pages per output file")
+	splitCmd.Flags().StringVar(&splitMaxSize, "max-size", "", "Largest output file size, e.g. 10MB")
+	splitCmd.Flags().StringVar(&splitZip, "zip", "", "Write the files into this ZIP archive instead of a directory")
+
+	rootCmd.AddCommand(splitCmd)
+}
+
+// outputDirOrZip returns the output directory argument of a command that can
+// write into a ZIP archive instead, checking that exactly one is given
+func outputDirOrZip(args []string, zipFile string) (string, error) {
+	switch {
+	case len(args) == 2 && zipFile != "":
+		return "", fmt.Errorf("give either an output directory or --zip, not both")
+	case len(args) < 2 && zipFile == "":
+		return "", fmt.Errorf("an output directory or --zip is required")
+	case len(args) == 2:
+		return args[1], nil
+	}
+	return "", nil
+}
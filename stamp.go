@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	stampImage string
+	stampOpts  pdftool.StampOptions
+)
+
+var stampCmd = &cobra.Command{
+	Use:   "stamp [input.pdf] [output.pdf]",
+	Short: "Stamp an image such as a logo onto PDF pages",
+	Long: `Stamp a PNG or JPEG image, such as a logo or signature, onto the selected
+pages of a PDF. The image is scaled to a fraction of the page width (--scale)
+and PNG transparency is kept.
+
+Positions: top-left, top-center, top-right, left, center, right, bottom-left,
+bottom-center, bottom-right.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputFile := args[0]
+		outputFile := args[1]
+
+		fmt.Printf("🔄 Stamping PDF: %s -> %s\n", inputFile, outputFile)
+
+		if err := pdftool.StampImage(cmd.Context(), inputFile, stampImage, outputFile, stampOpts); err != nil {
+			return fmt.Errorf("stamp failed: %w", err)
+		}
+
+		fmt.Println("✅ Stamp completed successfully!")
+		return nil
+	},
+}
+
+func init() {
+	stampCmd.Flags().StringVar(&stampImage, "image", "", "PNG or JPEG image to stamp (required)")
+	stampCmd.Flags().Float64Var(&stampOpts.Scale, "scale", 0.2, "Image width relative to the page")
+	stampCmd.Flags().Float64Var(&stampOpts.Opacity, "opacity", 1, "Opacity from 0 to 1")
+	stampCmd.Flags().Float64Var(&stampOpts.Rotation, "rotate", 0, "Counter-clockwise rotation in degrees")
+	stampCmd.Flags().StringVar(&stampOpts.Position, "position", "bottom-right", "Position on the page")
+	stampCmd.Flags().Float64Var(&stampOpts.Margin, "margin", 20, "Distance from the page edges in points")
+	stampCmd.Flags().StringVar(&stampOpts.Pages, "pages", "all", "Pages to stamp, e.g. 1-3,7 or all")
+	stampCmd.Flags().BoolVar(&stampOpts.Behind, "behind", false, "Place the image behind the page content")
+	stampCmd.MarkFlagRequired("image")
+
+	rootCmd.AddCommand(stampCmd)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+
+	"github.com/spf13/cobra"
+)
+
+var thumbnailOpts pdftool.ThumbnailOptions
+
+var thumbnailCmd = &cobra.Command{
+	Use:   "thumbnail [input.pdf] [thumb.png/jpg]",
+	Short: "Create a thumbnail image of a PDF page",
+	Long: `Render a page of a PDF, the cover by default, as a small PNG or JPEG image,
+e.g. for galleries or document management systems. The image has the given
+width and keeps the page's aspect ratio; the format follows the output file's
+extension.
+
+Rendering uses Ghostscript. Without it, only scanned pages consisting of a
+single image can be used.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputFile := args[0]
+		outputFile := args[1]
+
+		fmt.Printf("🔄 Creating thumbnail: %s -> %s (page %d, %dpx wide)\n", inputFile, outputFile, thumbnailOpts.Page, thumbnailOpts.Width)
+
+		if err := pdftool.CreateThumbnail(cmd.Context(), inputFile, outputFile, thumbnailOpts); err != nil {
+			return fmt.Errorf("thumbnail creation failed: %w", err)
+		}
+
+		fmt.Println("✅ Thumbnail created successfully!")
+		return nil
+	},
+}
+
+func init() {
+	thumbnailCmd.Flags().IntVar(&thumbnailOpts.Page, "page", 1, "Page to render")
+	thumbnailCmd.Flags().IntVar(&thumbnailOpts.Width, "width", 320, "Thumbnail width in pixels")
+	thumbnailCmd.Flags().IntVar(&thumbnailOpts.Quality, "quality", 85, "JPEG quality (1-100)")
+
+	rootCmd.AddCommand(thumbnailCmd)
+}
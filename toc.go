@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+
+	"github.com/spf13/cobra"
+)
+
+var tocOpts pdftool.TOCOptions
+
+var tocCmd = &cobra.Command{
+	Use:   "toc [input.pdf] [output.pdf]",
+	Short: "Prepend a table of contents built from the bookmarks",
+	Long: `Render the bookmarks (outline) of a PDF as a table of contents and insert it
+before the first page. Each entry shows its page number, counting the table
+of contents pages, and links to its page.
+
+For merged bundles, use merge --toc, which adds a bookmark and a table of
+contents entry for each input file.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputFile := args[0]
+		outputFile := args[1]
+
+		fmt.Printf("🔄 Adding table of contents: %s -> %s\n", inputFile, outputFile)
+
+		if err := pdftool.AddTableOfContents(cmd.Context(), inputFile, outputFile, tocOpts); err != nil {
+			return fmt.Errorf("table of contents failed: %w", err)
+		}
+
+		fmt.Println("✅ Table of contents added successfully!")
+		return nil
+	},
+}
+
+func init() {
+	tocCmd.Flags().StringVar(&tocOpts.Title, "title", pdftool.DefaultTOCTitle, "Heading of the table of contents")
+	tocCmd.Flags().IntVar(&tocOpts.MaxDepth, "depth", 0, "Deepest bookmark level to list (default: all)")
+
+	rootCmd.AddCommand(tocCmd)
+}
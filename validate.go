@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	validateMode     string
+	validateJSON     bool
+	validatePassword string
+)
+
+var validateCmd = &cobra.Command{
+	Use:   "validate [input.pdf]",
+	Short: "Check the structure of a PDF",
+	Long: `Check a PDF against the PDF specification, for example to gate a pipeline
+on structural correctness before or after other operations. The command fails
+if the PDF is invalid.
+
+--mode strict reports every violation; relaxed tolerates the minor ones common
+in real-world files, which the other commands accept as well.
+
+Use --json for machine-readable output.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		result, err := pdftool.ValidatePDF(cmd.Context(), args[0], validateMode, validatePassword)
+		if err != nil {
+			return fmt.Errorf("validation failed: %w", err)
+		}
+
+		if validateJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(result); err != nil {
+				return err
+			}
+		} else if result.Valid {
+			fmt.Printf("✅ %s is valid (PDF %s, %d pages, %s mode)\n", result.File, result.Version, result.PageCount, result.Mode)
+		}
+
+		if !result.Valid {
+			return fmt.Errorf("%s is invalid (%s mode): %s", result.File, result.Mode, result.Error)
+		}
+		return nil
+	},
+}
+
+func init() {
+	validateCmd.Flags().StringVar(&validateMode, "mode", pdftool.ValidationRelaxed, "Validation mode: strict or relaxed")
+	validateCmd.Flags().BoolVar(&validateJSON, "json", false, "Print the result as JSON")
+	validateCmd.Flags().StringVar(&validatePassword, "password", "", "Password for encrypted PDFs")
+
+	rootCmd.AddCommand(validateCmd)
+}
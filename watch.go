@@ -0,0 +1,294 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/cobra"
+)
+
+var (
+	watchOutput         string
+	watchDeleteOriginal bool
+	watchSettle         time.Duration
+	watchCompressOpts   pdftool.CompressOptions
+	watchQueue          string
+	watchWebhook        webhookFlags
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch [input-dir]",
+	Short: "Process PDFs and images as they appear in a folder",
+	Long: `Watch a folder and process every file that appears in it: PDFs are
+compressed and PNG or JPEG images converted to PDF, with the results written
+to the --output folder under the same name. This is the hot-folder workflow
+of scanners and multifunction printers that drop their files on a share.
+
+Files already in the folder are processed at startup. A file is only
+processed once it has not changed for --settle, so files that are still
+being written are not picked up half-finished. Failed files stay in place
+and are tried again when they change. Stop watching with Ctrl+C.
+
+With --queue, settled files are added to a job queue in that folder
+instead of being processed right away. Jobs interrupted by a restart are
+resumed, and failed jobs are retried with growing delays; inspect them with
+pdf-tool jobs. --webhook posts each finished job as JSON.
+
+Example:
+  pdf-tool watch ./incoming --output ./done --quality 40 --delete-original`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputDir := args[0]
+		if watchOutput == "" {
+			return fmt.Errorf("--output is required")
+		}
+		if watchCompressOpts.Quality < 1 || watchCompressOpts.Quality > 100 {
+			return fmt.Errorf("quality must be between 1 and 100, got: %d", watchCompressOpts.Quality)
+		}
+		if sameDir(inputDir, watchOutput) {
+			return fmt.Errorf("output folder must differ from the watched folder")
+		}
+		if err := os.MkdirAll(watchOutput, 0o755); err != nil {
+			return fmt.Errorf("failed to create output folder: %w", err)
+		}
+
+		watcher, err := fsnotify.NewWatcher()
+		if err != nil {
+			return fmt.Errorf("failed to watch %s: %w", inputDir, err)
+		}
+		defer watcher.Close()
+		if err := watcher.Add(inputDir); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", inputDir, err)
+		}
+
+		// Files waiting to settle, with the time they last changed
+		pending := map[string]time.Time{}
+		entries, err := os.ReadDir(inputDir)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", inputDir, err)
+		}
+		for _, entry := range entries {
+			if file := filepath.Join(inputDir, entry.Name()); entry.Type().IsRegular() && watchable(file) {
+				pending[file] = time.Time{}
+			}
+		}
+
+		ctx := cmd.Context()
+		var queue *jobQueue
+		queueErr := make(chan error, 1)
+		if watchQueue != "" {
+			if queue, err = openQueue(watchQueue); err != nil {
+				return err
+			}
+			hook := newWebhook(watchWebhook.url, watchWebhook.secret, "")
+			defer hook.wait()
+			done := func(job *queueJob, _ time.Time, _ *pdftool.Result, _ error) {
+				printQueueJob(job)
+				hook.notify(job)
+			}
+			go func() { queueErr <- runQueue(ctx, queue, 1, done) }()
+		} else if watchWebhook.url != "" {
+			return fmt.Errorf("--webhook requires --queue")
+		}
+
+		fmt.Printf("👀 Watching %s -> %s (Quality: %d%%)\n", inputDir, watchOutput, watchCompressOpts.Quality)
+
+		ticker := time.NewTicker(max(watchSettle/4, 100*time.Millisecond))
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				if queue != nil {
+					<-queueErr
+				}
+				fmt.Println("✅ Stopped watching")
+				return nil
+
+			case err := <-queueErr:
+				if err != nil {
+					return fmt.Errorf("job queue failed: %w", err)
+				}
+				fmt.Println("✅ Stopped watching")
+				return nil
+
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return fmt.Errorf("watching %s stopped", inputDir)
+				}
+				switch {
+				case event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename):
+					delete(pending, event.Name)
+				case (event.Has(fsnotify.Create) || event.Has(fsnotify.Write)) && watchable(event.Name):
+					pending[event.Name] = time.Now()
+				}
+
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return fmt.Errorf("watching %s stopped", inputDir)
+				}
+				fmt.Printf("⚠️  Watching %s: %v\n", inputDir, err)
+
+			case now := <-ticker.C:
+				var settled []string
+				for file, changed := range pending {
+					if now.Sub(changed) >= watchSettle {
+						settled = append(settled, file)
+					}
+				}
+				sort.Strings(settled)
+
+				for _, file := range settled {
+					if ctx.Err() != nil {
+						break
+					}
+					delete(pending, file)
+					if queue != nil {
+						queueWatched(queue, file)
+					} else {
+						processWatched(ctx, file)
+					}
+				}
+			}
+		}
+	},
+}
+
+func init() {
+	watchCmd.Flags().StringVarP(&watchOutput, "output", "o", "", "Folder for the processed files (required)")
+	watchCmd.Flags().IntVar(&watchCompressOpts.Quality, "quality", 50, "Compression quality percentage (1-100)")
+	watchCmd.Flags().StringVar(&watchCompressOpts.Engine, "engine", pdftool.EngineAuto, "Compression engine: auto, ghostscript, mutool, qpdf, pdfcpu, or a fallback chain like qpdf,pdfcpu")
+	watchCmd.Flags().BoolVar(&watchDeleteOriginal, "delete-original", false, "Delete files from the watched folder once they were processed")
+	watchCmd.Flags().DurationVar(&watchSettle, "settle", 2*time.Second, "Time a file must stay unchanged before it is processed")
+	watchCmd.Flags().StringVar(&watchQueue, "queue", os.Getenv("PDF_TOOL_QUEUE"), "Folder of a job queue to process the files through (env: PDF_TOOL_QUEUE)")
+
+	watchWebhook.register(watchCmd)
+
+	rootCmd.AddCommand(watchCmd)
+}
+
+// watchable reports whether a file is a PDF or image the watch command
+// processes
+func watchable(file string) bool {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".pdf", ".png", ".jpg", ".jpeg":
+		return !strings.HasPrefix(filepath.Base(file), ".")
+	}
+	return false
+}
+
+// sameDir reports whether two paths name the same directory
+func sameDir(a, b string) bool {
+	infoA, errA := os.Stat(a)
+	infoB, errB := os.Stat(b)
+	if errA != nil || errB != nil {
+		return filepath.Clean(a) == filepath.Clean(b)
+	}
+	return os.SameFile(infoA, infoB)
+}
+
+// watchedOutput returns the output file for a file from the watched folder
+func watchedOutput(file string) string {
+	name := filepath.Base(file)
+	return filepath.Join(watchOutput, strings.TrimSuffix(name, filepath.Ext(name))+".pdf")
+}
+
+// queueWatched adds a job for a file from the watched folder to the queue
+func queueWatched(queue *jobQueue, file string) {
+	name := filepath.Base(file)
+	if _, err := os.Stat(file); err != nil {
+		return // Removed while settling
+	}
+	inputFile, err := filepath.Abs(file)
+	if err != nil {
+		fmt.Printf("❌ %s: %v\n", name, err)
+		return
+	}
+	outputFile, err := filepath.Abs(watchedOutput(file))
+	if err != nil {
+		fmt.Printf("❌ %s: %v\n", name, err)
+		return
+	}
+
+	job := &queueJob{
+		Kind:        jobConvert,
+		InputFile:   inputFile,
+		OutputFile:  outputFile,
+		DeleteInput: watchDeleteOriginal,
+	}
+	if strings.EqualFold(filepath.Ext(file), ".pdf") {
+		job.Kind = jobCompress
+		job.Quality = watchCompressOpts.Quality
+		job.Engine = watchCompressOpts.Engine
+	}
+	if job, err = queue.Add(job); err != nil {
+		fmt.Printf("❌ %s: %v\n", name, err)
+		return
+	}
+	fmt.Printf("📥 %s: queued as job %d\n", name, job.ID)
+}
+
+// printQueueJob prints the outcome of an attempt of a job queued by the
+// watch command
+func printQueueJob(job *queueJob) {
+	name := filepath.Base(job.InputFile)
+	switch job.Status {
+	case jobDone:
+		if job.Kind == jobCompress {
+			printJobResult(pdftool.JobResult{
+				Job:      pdftool.Job{InputFile: job.InputFile, OutputFile: job.OutputFile},
+				Result:   &pdftool.Result{InputSize: job.InputSize, OutputSize: job.OutputSize, Ratio: float64(job.OutputSize) / float64(max(job.InputSize, 1))},
+				Attempts: job.Attempts,
+			})
+		} else {
+			fmt.Printf("   %s -> %s\n", name, filepath.Base(job.OutputFile))
+		}
+	case jobQueued:
+		fmt.Printf("⚠️  %s: %s, retrying in %s\n", name, job.Error, time.Until(job.NextAttempt).Round(time.Second))
+	case jobFailed:
+		fmt.Printf("❌ %s: %s after %d attempts\n", name, job.Error, job.Attempts)
+	}
+}
+
+// processWatched compresses or converts a file from the watched folder into
+// the output folder and prints the outcome
+func processWatched(ctx context.Context, file string) {
+	name := filepath.Base(file)
+	if _, err := os.Stat(file); err != nil {
+		return // Removed while settling
+	}
+	outputFile := watchedOutput(file)
+
+	var err error
+	if strings.EqualFold(filepath.Ext(file), ".pdf") {
+		var result *pdftool.Result
+		result, err = pdftool.CompressPDF(ctx, file, outputFile, watchCompressOpts)
+		printJobResult(pdftool.JobResult{
+			Job:      pdftool.Job{InputFile: file, OutputFile: outputFile},
+			Result:   result,
+			Err:      err,
+			Attempts: 1,
+		})
+	} else {
+		err = pdftool.ConvertImageToPDF(ctx, file, outputFile, pdftool.ConvertOptions{})
+		if err != nil {
+			fmt.Printf("❌ %s: %v\n", name, err)
+		} else {
+			fmt.Printf("   %s -> %s\n", name, filepath.Base(outputFile))
+		}
+	}
+
+	if err == nil && watchDeleteOriginal {
+		if err := os.Remove(file); err != nil {
+			fmt.Printf("⚠️  %s: %v\n", name, err)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// webhookDelays are the waits before retrying a failed webhook delivery
+var webhookDelays = []time.Duration{time.Second, 5 * time.Second, 30 * time.Second}
+
+// webhookFlags are the webhook flags shared by serve and watch
+type webhookFlags struct {
+	url    string
+	secret string
+}
+
+// register adds the flags to a command
+func (f *webhookFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.url, "webhook", "", "URL to POST every finished job to as JSON (requires --queue)")
+	cmd.Flags().StringVar(&f.secret, "webhook-secret", os.Getenv("PDF_TOOL_WEBHOOK_SECRET"), "Key to sign webhook payloads with in X-Pdf-Tool-Signature (env: PDF_TOOL_WEBHOOK_SECRET)")
+}
+
+// webhook posts the outcome of finished jobs to a URL
+type webhook struct {
+	url       string
+	secret    string // Signs the payloads if set
+	publicURL string // Base of download URLs; none are sent if empty
+	client    *http.Client
+	wg        sync.WaitGroup
+}
+
+// webhookPayload is the JSON body posted for a finished job
+type webhookPayload struct {
+	jobStatus
+	DownloadURL string `json:"downloadUrl,omitempty"`
+}
+
+// newWebhook returns a webhook posting to url, or nil if url is empty
+func newWebhook(url, secret, publicURL string) *webhook {
+	if url == "" {
+		return nil
+	}
+	return &webhook{
+		url:       url,
+		secret:    secret,
+		publicURL: strings.TrimSuffix(publicURL, "/"),
+		client:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// notify posts a job in the background if it has finished: it is done,
+// failed for good or was canceled
+func (h *webhook) notify(job *queueJob) {
+	if h == nil || (job.Status != jobDone && job.Status != jobFailed && job.Status != jobCanceled) {
+		return
+	}
+	payload := webhookPayload{jobStatus: newJobStatus(job)}
+	if job.Status == jobDone && h.publicURL != "" && !isRemote(job.OutputFile) {
+		payload.DownloadURL = fmt.Sprintf("%s/jobs/%d/output", h.publicURL, job.ID)
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		rootLogger.Warn(fmt.Sprintf("Webhook for job %d: %v", job.ID, err))
+		return
+	}
+
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+		err := h.post(body)
+		for _, delay := range webhookDelays {
+			if err == nil {
+				return
+			}
+			time.Sleep(delay)
+			err = h.post(body)
+		}
+		if err != nil {
+			rootLogger.Warn(fmt.Sprintf("Webhook for job %d failed: %v", job.ID, err))
+		}
+	}()
+}
+
+// post sends one payload. With a secret, the X-Pdf-Tool-Signature header
+// carries "sha256=" and the hex HMAC-SHA256 of the body, so receivers can
+// check where it came from.
+func (h *webhook) post(body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, h.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "pdf-tool")
+	if h.secret != "" {
+		mac := hmac.New(sha256.New, []byte(h.secret))
+		mac.Write(body)
+		req.Header.Set("X-Pdf-Tool-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s answered %s", h.url, resp.Status)
+	}
+	return nil
+}
+
+// wait waits for deliveries still in progress
+func (h *webhook) wait() {
+	if h != nil {
+		h.wg.Wait()
+	}
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+// webFiles holds the browser UI of the serve command
+//
+//go:embed web
+var webFiles embed.FS
+
+// webUI returns the handler serving the browser UI, a single page using the
+// HTTP API
+func webUI() http.Handler {
+	sub, err := fs.Sub(webFiles, "web")
+	if err != nil {
+		panic(err)
+	}
+	return http.FileServerFS(sub)
+}
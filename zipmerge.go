@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ansrivas/pdftool/pkg/pdftool"
+
+	"github.com/spf13/cobra"
+)
+
+var zipMergeReverseSecond bool
+
+var zipMergeCmd = &cobra.Command{
+	Use:   "zip-merge [fronts.pdf] [backs.pdf] [output.pdf]",
+	Short: "Interleave the pages of two PDFs",
+	Long: `Interleave the pages of two PDFs, one page of each in turn, e.g. to combine
+the fronts and backs of double-sided documents scanned with a single-sided
+scanner.
+
+Scanning the turned-over stack produces the backs last page first; use
+--reverse-second to put them back in order. The second file may have one page
+less than the first, for a last page without a back.`,
+	Args: cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fronts := args[0]
+		backs := args[1]
+		outputFile := args[2]
+
+		fmt.Printf("🔄 Interleaving %s and %s -> %s\n", fronts, backs, outputFile)
+
+		if err := pdftool.ZipMergePDFs(cmd.Context(), fronts, backs, outputFile, zipMergeReverseSecond); err != nil {
+			return fmt.Errorf("zip-merge failed: %w", err)
+		}
+
+		fmt.Println("✅ Zip-merge completed successfully!")
+		return nil
+	},
+}
+
+func init() {
+	zipMergeCmd.Flags().BoolVar(&zipMergeReverseSecond, "reverse-second", false, "Take the pages of the second file in reverse order")
+
+	rootCmd.AddCommand(zipMergeCmd)
+}